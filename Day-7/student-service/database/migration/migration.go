@@ -14,6 +14,28 @@ var tables = []interface{}{
 	&model.Class{},
 	&model.Major{},
 	&model.Student{},
+	&model.IdempotencyKey{},
+	&model.Term{},
+	&model.Course{},
+	&model.Enrollment{},
+	&model.Grade{},
+	&model.Assessment{},
+	&model.ClassMembership{},
+	&model.ClassSession{},
+	&model.Attendance{},
+	&model.Room{},
+	&model.RoomFacility{},
+	&model.BookingSeries{},
+	&model.Booking{},
+	&model.StudentNumberSequence{},
+	&model.Attachment{},
+	&model.WebhookSubscription{},
+	&model.WebhookDelivery{},
+	&model.OutboxEvent{},
+	&model.Lease{},
+	&model.Job{},
+	&model.TaskRun{},
+	&model.Statistic{},
 }
 
 func Migrate() {
@@ -21,6 +43,11 @@ func Migrate() {
 	conn := database.GetConnection()
 
 	conn.AutoMigrate(tables...)
+
+	// replaced by idx_students_fulltext, which leaves out phone and address
+	if conn.Migrator().HasIndex(&model.Student{}, "idx_students_search") {
+		conn.Migrator().DropIndex(&model.Student{}, "idx_students_search")
+	}
 }
 
 func Rollback() {
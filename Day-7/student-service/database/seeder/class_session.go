@@ -0,0 +1,44 @@
+package seeder
+
+import (
+	"log"
+	"time"
+
+	"student-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// classSessionSeeder seeds two marked sessions of class B in the active term.
+func classSessionSeeder(db *gorm.DB) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	var sessions = []model.ClassSession{
+		{
+			ClassID: 2,
+			TermID:  2,
+			Date:    today.AddDate(0, 0, -14),
+			Topic:   "Budgeting",
+			Attendances: []model.Attendance{
+				{StudentID: 2, Status: "present"},
+				{StudentID: 3, Status: "late"},
+			},
+			Common: model.Common{ID: 1, CreatedAt: now, UpdatedAt: now},
+		},
+		{
+			ClassID: 2,
+			TermID:  2,
+			Date:    today.AddDate(0, 0, -7),
+			Topic:   "Cash flow",
+			Attendances: []model.Attendance{
+				{StudentID: 2, Status: "absent"},
+				{StudentID: 3, Status: "excused", Note: "Sick leave"},
+			},
+			Common: model.Common{ID: 2, CreatedAt: now, UpdatedAt: now},
+		},
+	}
+	if err := db.Create(&sessions).Error; err != nil {
+		log.Printf("cannot seed data class sessions, with error %v\n", err)
+	}
+	log.Println("success seed data class sessions")
+}
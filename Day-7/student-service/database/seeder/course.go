@@ -0,0 +1,44 @@
+package seeder
+
+import (
+	"log"
+	"time"
+
+	"student-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+func courseSeeder(db *gorm.DB) {
+	now := time.Now()
+	var courses = []model.Course{
+		{
+			Code:     "FIN101",
+			Name:     "Introduction to Finance",
+			MajorID:  1,
+			Credits:  3,
+			Capacity: 30,
+			Common:   model.Common{ID: 1, CreatedAt: now, UpdatedAt: now},
+		},
+		{
+			Code:     "FIN201",
+			Name:     "Corporate Finance",
+			MajorID:  1,
+			Credits:  4,
+			Capacity: 2,
+			Common:   model.Common{ID: 2, CreatedAt: now, UpdatedAt: now},
+		},
+		{
+			Code:     "IT101",
+			Name:     "Introduction to Programming",
+			MajorID:  2,
+			Credits:  3,
+			Capacity: 40,
+			Common:   model.Common{ID: 3, CreatedAt: now, UpdatedAt: now},
+		},
+	}
+	if err := db.Create(&courses).Error; err != nil {
+		log.Printf("cannot seed data courses, with error %v\n", err)
+	}
+	log.Println("success seed data courses")
+}
@@ -0,0 +1,55 @@
+package seeder
+
+import (
+	"log"
+	"time"
+
+	"student-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// roomSeeder seeds two rooms, the first is booked tomorrow from 09:00 to 11:00.
+func roomSeeder(db *gorm.DB) {
+	now := time.Now()
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	var rooms = []model.Room{
+		{
+			Name:     "Meeting Room 1",
+			Capacity: 6,
+			Facilities: []model.RoomFacility{
+				{Name: "tv"},
+				{Name: "whiteboard"},
+			},
+			Common: model.Common{ID: 1, CreatedAt: now, UpdatedAt: now},
+		},
+		{
+			Name:     "Auditorium",
+			Capacity: 120,
+			Facilities: []model.RoomFacility{
+				{Name: "projector"},
+				{Name: "sound system"},
+				{Name: "whiteboard"},
+			},
+			Common: model.Common{ID: 2, CreatedAt: now, UpdatedAt: now},
+		},
+	}
+	if err := db.Create(&rooms).Error; err != nil {
+		log.Printf("cannot seed data rooms, with error %v\n", err)
+	}
+	log.Println("success seed data rooms")
+
+	studentID := uint(2)
+	booking := model.Booking{
+		RoomID:    1,
+		StudentID: &studentID,
+		StartsAt:  tomorrow.Add(9 * time.Hour),
+		EndsAt:    tomorrow.Add(11 * time.Hour),
+		Purpose:   "Group study",
+		Common:    model.Common{ID: 1, CreatedAt: now, UpdatedAt: now},
+	}
+	if err := db.Create(&booking).Error; err != nil {
+		log.Printf("cannot seed data bookings, with error %v\n", err)
+	}
+	log.Println("success seed data bookings")
+}
@@ -18,10 +18,36 @@ func (s *seed) SeedAll() {
 	classSeeder(s.DB)
 	majorSeeder(s.DB)
 	studentSeeder(s.DB)
+	termSeeder(s.DB)
+	courseSeeder(s.DB)
+	classSessionSeeder(s.DB)
+	roomSeeder(s.DB)
 }
 
 func (s *seed) DeleteAll() {
+	s.DB.Exec("DELETE FROM statistics")
+	s.DB.Exec("DELETE FROM task_runs")
+	s.DB.Exec("DELETE FROM jobs")
+	s.DB.Exec("DELETE FROM outbox_events")
+	s.DB.Exec("DELETE FROM leases")
+	s.DB.Exec("DELETE FROM webhook_deliveries")
+	s.DB.Exec("DELETE FROM webhook_subscriptions")
+	s.DB.Exec("DELETE FROM idempotency_keys")
+	s.DB.Exec("DELETE FROM attachments")
+	s.DB.Exec("DELETE FROM bookings")
+	s.DB.Exec("DELETE FROM booking_series")
+	s.DB.Exec("DELETE FROM room_facilities")
+	s.DB.Exec("DELETE FROM rooms")
+	s.DB.Exec("DELETE FROM class_memberships")
+	s.DB.Exec("DELETE FROM attendances")
+	s.DB.Exec("DELETE FROM class_sessions")
+	s.DB.Exec("DELETE FROM assessments")
+	s.DB.Exec("DELETE FROM grades")
+	s.DB.Exec("DELETE FROM enrollments")
+	s.DB.Exec("DELETE FROM courses")
+	s.DB.Exec("DELETE FROM terms")
 	s.DB.Exec("DELETE FROM students")
+	s.DB.Exec("DELETE FROM student_number_sequences")
 	s.DB.Exec("DELETE FROM majors")
 	s.DB.Exec("DELETE FROM classes")
 }
@@ -5,40 +5,70 @@ import (
 	"time"
 
 	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	"student-service/pkg/studentnumber"
 
 	"gorm.io/gorm"
 )
 
+// studentSeeder seeds three students of the 2025 intake, numbered with the default pattern.
 func studentSeeder(db *gorm.DB) {
 	now := time.Now()
+	classA, classB := uint(1), uint(2)
+	numbers, _ := studentnumber.Parse(studentnumber.DefaultPatternString)
+	number := func(majorID, seq uint) *string {
+		n, _ := numbers.Format(2025, majorID, seq)
+		return &n
+	}
+	birthDate := time.Date(2005, time.March, 14, 0, 0, 0, 0, time.Local)
 	var students = []model.Student{
 		{
-			Fullname: "Vincent L. Hubbard",
-			Email:    "vincentlhubbard@edu.ac.id",
-			Password: "$2a$10$rfpS/jJ.a5J9seBM5sNPTeMQ0iVcAjoox3TDZqLE7omptkVQfaRwW", // 123abcABC!
-			ClassID:  1,
-			MajorID:  1,
-			Common:   model.Common{ID: 1, CreatedAt: now, UpdatedAt: now},
+			Fullname:      "Vincent L. Hubbard",
+			Email:         "vincentlhubbard@edu.ac.id",
+			Password:      "$2a$10$rfpS/jJ.a5J9seBM5sNPTeMQ0iVcAjoox3TDZqLE7omptkVQfaRwW", // 123abcABC!
+			ClassID:       &classA,
+			MajorID:       1,
+			StudentNumber: number(1, 1),
+			IntakeYear:    2025,
+			Common:        model.Common{ID: 1, CreatedAt: now, UpdatedAt: now},
 		},
 		{
-			Fullname: "Devon C. Thomas",
-			Email:    "devoncthomas@edu.ac.id",
-			Password: "$2a$10$rfpS/jJ.a5J9seBM5sNPTeMQ0iVcAjoox3TDZqLE7omptkVQfaRwW", // 123abcABC!
-			ClassID:  2,
-			MajorID:  1,
-			Common:   model.Common{ID: 2, CreatedAt: now, UpdatedAt: now},
+			Fullname:      "Devon C. Thomas",
+			Email:         "devoncthomas@edu.ac.id",
+			Password:      "$2a$10$rfpS/jJ.a5J9seBM5sNPTeMQ0iVcAjoox3TDZqLE7omptkVQfaRwW", // 123abcABC!
+			ClassID:       &classB,
+			MajorID:       1,
+			StudentNumber: number(1, 2),
+			IntakeYear:    2025,
+			Phone:         "+6281234567890",
+			BirthDate:     &birthDate,
+			Gender:        string(enum.Male),
+			Address:       "Jl. Merdeka No. 10, Bandung",
+			Common:        model.Common{ID: 2, CreatedAt: now, UpdatedAt: now},
 		},
 		{
-			Fullname: "Bettina M. Easter",
-			Email:    "bettinameaster@edu.ac.id",
-			Password: "$2a$10$rfpS/jJ.a5J9seBM5sNPTeMQ0iVcAjoox3TDZqLE7omptkVQfaRwW", // 123abcABC!
-			ClassID:  2,
-			MajorID:  2,
-			Common:   model.Common{ID: 3, CreatedAt: now, UpdatedAt: now},
+			Fullname:      "Bettina M. Easter",
+			Email:         "bettinameaster@edu.ac.id",
+			Password:      "$2a$10$rfpS/jJ.a5J9seBM5sNPTeMQ0iVcAjoox3TDZqLE7omptkVQfaRwW", // 123abcABC!
+			ClassID:       &classB,
+			MajorID:       2,
+			StudentNumber: number(2, 1),
+			IntakeYear:    2025,
+			Gender:        string(enum.Female),
+			Common:        model.Common{ID: 3, CreatedAt: now, UpdatedAt: now},
 		},
 	}
 	if err := db.Create(&students).Error; err != nil {
 		log.Printf("cannot seed data students, with error %v\n", err)
 	}
 	log.Println("success seed data students")
+
+	var sequences = []model.StudentNumberSequence{
+		{Scope: numbers.Scope(2025, 1), Last: 2},
+		{Scope: numbers.Scope(2025, 2), Last: 1},
+	}
+	if err := db.Create(&sequences).Error; err != nil {
+		log.Printf("cannot seed data student number sequences, with error %v\n", err)
+	}
+	log.Println("success seed data student number sequences")
 }
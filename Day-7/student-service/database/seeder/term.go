@@ -0,0 +1,42 @@
+package seeder
+
+import (
+	"log"
+	"time"
+
+	"student-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// termSeeder seeds a closed term, the active term and an upcoming term. Their dates are
+// relative to today so the second term is always the active one.
+func termSeeder(db *gorm.DB) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	var terms = []model.Term{
+		{
+			Name:      "Previous Semester",
+			StartDate: today.AddDate(0, -8, 0),
+			EndDate:   today.AddDate(0, -2, -1),
+			Closed:    true,
+			Common:    model.Common{ID: 1, CreatedAt: now, UpdatedAt: now},
+		},
+		{
+			Name:      "Current Semester",
+			StartDate: today.AddDate(0, -2, 0),
+			EndDate:   today.AddDate(0, 4, 0),
+			Common:    model.Common{ID: 2, CreatedAt: now, UpdatedAt: now},
+		},
+		{
+			Name:      "Next Semester",
+			StartDate: today.AddDate(0, 4, 1),
+			EndDate:   today.AddDate(0, 10, 0),
+			Common:    model.Common{ID: 3, CreatedAt: now, UpdatedAt: now},
+		},
+	}
+	if err := db.Create(&terms).Error; err != nil {
+		log.Printf("cannot seed data terms, with error %v\n", err)
+	}
+	log.Println("success seed data terms")
+}
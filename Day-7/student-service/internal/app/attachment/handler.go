@@ -0,0 +1,213 @@
+package attachment
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/middleware"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/pkg/i18n"
+	res "student-service/pkg/util/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// multipartOverhead is allowed on top of the file size for the other form fields and the
+// multipart boundaries.
+const multipartOverhead = 64 << 10
+
+type handler struct {
+	service     Service
+	maxSize     int64
+	idempotency echo.MiddlewareFunc
+}
+
+func NewHandler(f *factory.Factory) *handler {
+	return &handler{
+		service:     NewService(f),
+		maxSize:     maxSize(),
+		idempotency: middleware.IdempotencyMiddleware(f.IdempotencyKeyRepository),
+	}
+}
+
+// Get lists the attachments of the authenticated student. Only class A can list those of other
+// students.
+func (h *handler) Get(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(dto.SearchAttachmentRequest)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	if jwtClaims.ClassID != uint(enum.A) {
+		payload.StudentID = &jwtClaims.BID
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	result, err := h.service.Find(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	return res.CustomSuccessBuilder(http.StatusOK, result, i18n.GetAttachmentsSuccess, nil).Send(c)
+}
+
+func (h *handler) GetById(c echo.Context) error {
+	payload, err := h.byIDRequest(c)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	result, err := h.service.FindByID(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	c.Response().Header().Set(res.HeaderETag, res.ETag(result.Version))
+	return res.SuccessResponse(result).Send(c)
+}
+
+// Create uploads a file as multipart/form-data, for the authenticated student when no student
+// is set. Only class A can upload files of other students.
+func (h *handler) Create(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(dto.UploadAttachmentRequest)
+	if err := c.Bind(payload); err != nil {
+		if isTooLarge(err) {
+			return res.ErrorResponse(fileTooLargeError(h.maxSize)).Send(c)
+		}
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	payload.File, err = c.FormFile("file")
+	if err != nil && !errors.Is(err, http.ErrMissingFile) {
+		if isTooLarge(err) {
+			return res.ErrorResponse(fileTooLargeError(h.maxSize)).Send(c)
+		}
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	if payload.StudentID == nil {
+		payload.StudentID = &jwtClaims.BID
+	}
+	if jwtClaims.ClassID != uint(enum.A) && *payload.StudentID != jwtClaims.BID {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, errors.New("uploading a file of someone else")).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	result, err := h.service.Store(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	return res.SuccessResponse(result).Send(c)
+}
+
+func (h *handler) DeleteById(c echo.Context) error {
+	payload, err := h.byIDRequest(c)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+	payload.IfMatch = c.Request().Header.Get(res.HeaderIfMatch)
+
+	result, err := h.service.DeleteById(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	return res.SuccessResponse(result).Send(c)
+}
+
+// Download sends the file of a signed download URL. It needs no token, the signature proves the
+// URL was handed out by this service and has not expired.
+func (h *handler) Download(c echo.Context) error {
+	return h.download(c, false)
+}
+
+// DownloadThumbnail sends the thumbnail of a photo, like Download.
+func (h *handler) DownloadThumbnail(c echo.Context) error {
+	return h.download(c, true)
+}
+
+func (h *handler) download(c echo.Context, thumb bool) error {
+	payload := new(dto.DownloadAttachmentRequest)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.InvalidDownloadURL, err).Send(c)
+	}
+
+	attachment, file, err := h.service.Open(c.Request().Context(), payload, thumb)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+	defer file.Close()
+
+	contentType, disposition := attachment.ContentType, "attachment"
+	if thumb {
+		contentType, disposition = "image/jpeg", "inline"
+	} else {
+		c.Response().Header().Set(echo.HeaderContentLength, strconv.FormatInt(attachment.Size, 10))
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}))
+	c.Response().Header().Set(echo.HeaderXContentTypeOptions, "nosniff")
+	c.Response().Header().Set("Cache-Control", "private, no-store")
+	return c.Stream(http.StatusOK, contentType, file)
+}
+
+// byIDRequest binds the id of the attachment and restricts it to the authenticated student
+// unless they are in class A.
+func (h *handler) byIDRequest(c echo.Context) (*dto.AttachmentByIDRequest, error) {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if err != nil {
+		return nil, res.ErrorBuilder(res.ErrorConstant.Unauthorized, err)
+	}
+
+	payload := new(dto.AttachmentByIDRequest)
+	if err := c.Bind(payload); err != nil {
+		return nil, res.ErrorBuilder(res.ErrorConstant.BadRequest, err)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return nil, res.ValidationErrorBuilder(err)
+	}
+	if jwtClaims.ClassID != uint(enum.A) {
+		payload.OwnerStudentID = &jwtClaims.BID
+	}
+	return payload, nil
+}
+
+// limitBody rejects uploads larger than the maximum file size before the body is read, by the
+// idempotency middleware or by the multipart parser.
+func (h *handler) limitBody(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if c.Request().ContentLength > h.maxSize+multipartOverhead {
+			return res.ErrorResponse(fileTooLargeError(h.maxSize)).Send(c)
+		}
+		c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, h.maxSize+multipartOverhead)
+		return next(c)
+	}
+}
+
+// isTooLarge reports whether err comes from reading past the http.MaxBytesReader limit.
+func isTooLarge(err error) bool {
+	return strings.Contains(err.Error(), "request body too large")
+}
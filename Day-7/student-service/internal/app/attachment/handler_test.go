@@ -0,0 +1,372 @@
+package attachment
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"student-service/database"
+	"student-service/database/seeder"
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/middleware"
+	"student-service/internal/mocks"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	"student-service/pkg/storage"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	adminClaims = util.CreateJWTClaims("vincentlhubbard@edu.ac.id", uint(1), uint(enum.A), uint(enum.Finance))
+	userClaims  = util.CreateJWTClaims("devoncthomas@edu.ac.id", uint(2), uint(enum.B), uint(enum.Finance))
+	otherClaims = util.CreateJWTClaims("bettinameaster@edu.ac.id", uint(3), uint(enum.B), uint(enum.Finance))
+	db          = database.GetConnection()
+	echoMock    = mocks.EchoMock{E: echo.New()}
+	storageDir  = filepath.Join(os.TempDir(), "student-service-attachments")
+	f           = factory.Factory{
+		AttachmentRepository:     repository.NewAttachmentRepository(db),
+		Storage:                  storage.NewLocalStorage(storageDir),
+		IdempotencyKeyRepository: repository.NewIdempotencyKeyRepository(db),
+	}
+	attachmentHandler = NewHandler(&f)
+)
+
+func pngImage(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+// hugePNG returns the header of a PNG image claiming 30000x30000 pixels, without the pixels.
+func hugePNG(t *testing.T) []byte {
+	header := pngImage(t, 1, 1)[:33]
+	binary.BigEndian.PutUint32(header[16:], 30000)
+	binary.BigEndian.PutUint32(header[20:], 30000)
+	binary.BigEndian.PutUint32(header[29:], crc32.ChecksumIEEE(header[12:29]))
+	return header
+}
+
+func authorize(t *testing.T, c echo.Context, claims dto.JWTClaims) {
+	token, err := util.CreateJWTToken(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+}
+
+// uploadRequest sends the fields and, when filename is set, the file as multipart/form-data.
+func uploadRequest(t *testing.T, claims dto.JWTClaims, fields map[string]string, filename string, content []byte) (echo.Context, func() (int, string)) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if filename != "" {
+		part, err := writer.CreateFormFile("file", filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := part.Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	c, rec := echoMock.RequestMock(http.MethodPost, "/", &body)
+	c.Request().Header.Set("Content-Type", writer.FormDataContentType())
+	authorize(t, c, claims)
+	return c, func() (int, string) { return rec.Code, rec.Body.String() }
+}
+
+func attachmentRequest(t *testing.T, claims dto.JWTClaims, method string, id uint) (echo.Context, func() (int, string)) {
+	c, rec := echoMock.RequestMock(method, "/", nil)
+	c.SetParamNames("id")
+	c.SetParamValues(strconv.FormatUint(uint64(id), 10))
+	authorize(t, c, claims)
+	return c, func() (int, string) { return rec.Code, rec.Body.String() }
+}
+
+// downloadRequest requests a signed URL of a response, which are relative to the server.
+func downloadRequest(t *testing.T, signedURL string, id uint) (echo.Context, *httptest.ResponseRecorder) {
+	u, err := url.Parse(signedURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, rec := echoMock.RequestMock(http.MethodGet, u.RequestURI(), nil)
+	c.SetParamNames("id")
+	c.SetParamValues(strconv.FormatUint(uint64(id), 10))
+	return c, rec
+}
+
+func upload(t *testing.T, claims dto.JWTClaims, kind, filename string, content []byte) dto.AttachmentResponse {
+	c, rec := uploadRequest(t, claims, map[string]string{"kind": kind}, filename, content)
+	if err := attachmentHandler.Create(c); err != nil {
+		t.Fatal(err)
+	}
+	code, body := rec()
+	if code != http.StatusOK {
+		t.Fatalf("upload returned %d: %s", code, body)
+	}
+	var result struct {
+		Data dto.AttachmentResponse `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(body), &result); err != nil {
+		t.Fatal(err)
+	}
+	return result.Data
+}
+
+func TestAttachmentHandlerCreate(t *testing.T) {
+	photo := pngImage(t, 40, 20)
+	cases := []struct {
+		name     string
+		claims   dto.JWTClaims
+		fields   map[string]string
+		filename string
+		content  []byte
+		code     int
+		contains string
+	}{
+		{"photo", userClaims, map[string]string{"kind": "photo"}, "me.png", photo, 200, `"student_id":2,"kind":"photo","filename":"me.png","content_type":"image/png"`},
+		{"type is sniffed", userClaims, map[string]string{"kind": "id_scan"}, "scan.pdf", photo, 200, `"filename":"scan.pdf","content_type":"image/png"`},
+		{"pdf certificate", userClaims, map[string]string{"kind": "certificate"}, "certificate.pdf", []byte("%PDF-1.4\n%%EOF\n"), 200, `"content_type":"application/pdf"`},
+		{"admin for another student", adminClaims, map[string]string{"kind": "certificate", "student_id": "3"}, "certificate.png", photo, 200, `"student_id":3`},
+		{"photo with too many pixels", userClaims, map[string]string{"kind": "photo"}, "me.png", hugePNG(t), 413, "File is larger than allowed"},
+		{"pdf photo", userClaims, map[string]string{"kind": "photo"}, "me.png", []byte("%PDF-1.4\n%%EOF\n"), 415, "File type is not allowed"},
+		{"text certificate", userClaims, map[string]string{"kind": "certificate"}, "certificate.pdf", []byte("not a certificate"), 415, "File type is not allowed"},
+		{"unknown kind", userClaims, map[string]string{"kind": "diploma"}, "me.png", photo, 400, `"field":"kind"`},
+		{"no file", userClaims, map[string]string{"kind": "photo"}, "", nil, 400, `"field":"file"`},
+		{"for another student", userClaims, map[string]string{"kind": "photo", "student_id": "3"}, "me.png", photo, 401, "unauthorized"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			seeder.NewSeeder().SeedAll()
+
+			c, rec := uploadRequest(t, tc.claims, tc.fields, tc.filename, tc.content)
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(attachmentHandler.Create(c)) {
+				code, body := rec()
+				asserts.Equal(tc.code, code)
+				asserts.Contains(body, tc.contains)
+			}
+		})
+	}
+}
+
+func TestAttachmentHandlerCreateTooLarge(t *testing.T) {
+	small := &handler{
+		service: &service{
+			AttachmentRepository: f.AttachmentRepository,
+			Storage:              f.Storage,
+			Signer:               storage.NewURLSigner([]byte("secret")),
+			MaxSize:              1000,
+		},
+		maxSize:     1000,
+		idempotency: middleware.IdempotencyMiddleware(f.IdempotencyKeyRepository),
+	}
+	// the route's middlewares, the body is limited before the idempotency middleware reads it
+	create := small.limitBody(small.idempotency(small.Create))
+
+	cases := []struct {
+		name          string
+		size          int
+		contentLength bool
+		contains      string
+	}{
+		{"file larger than allowed", 2000, true, "File is larger than allowed"},
+		{"body larger than allowed", 200 << 10, true, "File is larger than allowed"},
+		{"body of unknown length larger than allowed", 200 << 10, false, "Request body is larger than allowed"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			seeder.NewSeeder().SeedAll()
+			c, rec := uploadRequest(t, userClaims, map[string]string{"kind": "photo"}, "me.png", bytes.Repeat([]byte{0xff}, tc.size))
+			c.Request().Header.Set(middleware.HeaderIdempotencyKey, "upload-"+strconv.Itoa(tc.size))
+			if !tc.contentLength {
+				c.Request().ContentLength = -1
+			}
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(create(c)) {
+				code, body := rec()
+				asserts.Equal(413, code)
+				asserts.Contains(body, tc.contains)
+			}
+			var count int64
+			db.Table("attachments").Count(&count)
+			asserts.Zero(count)
+		})
+	}
+}
+
+func TestAttachmentHandlerPhotoIsReplaced(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	asserts := assert.New(t)
+
+	first := upload(t, userClaims, "photo", "first.png", pngImage(t, 600, 300))
+	var stored model.Attachment
+	if err := db.First(&stored, first.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	keys := []string{stored.StorageKey, *stored.ThumbnailKey}
+	for _, key := range keys {
+		asserts.FileExists(filepath.Join(storageDir, key))
+	}
+
+	second := upload(t, userClaims, "photo", "second.png", pngImage(t, 60, 30))
+	var count int64
+	db.Table("attachments").Where("student_id = ? AND kind = ?", 2, "photo").Count(&count)
+	asserts.Equal(int64(1), count)
+	for _, key := range keys {
+		asserts.NoFileExists(filepath.Join(storageDir, key))
+	}
+
+	c, rec := attachmentRequest(t, userClaims, http.MethodGet, first.ID)
+	if asserts.NoError(attachmentHandler.GetById(c)) {
+		code, _ := rec()
+		asserts.Equal(404, code)
+	}
+	c, rec = attachmentRequest(t, userClaims, http.MethodGet, second.ID)
+	if asserts.NoError(attachmentHandler.GetById(c)) {
+		code, body := rec()
+		asserts.Equal(200, code)
+		asserts.Contains(body, `"filename":"second.png"`)
+	}
+}
+
+func TestAttachmentHandlerDownload(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	asserts := assert.New(t)
+
+	content := pngImage(t, 600, 300)
+	photo := upload(t, userClaims, "photo", "me.png", content)
+	if !asserts.NotNil(photo.ThumbnailURL) {
+		return
+	}
+
+	c, rec := downloadRequest(t, photo.URL, photo.ID)
+	if asserts.NoError(attachmentHandler.Download(c)) {
+		resp := rec.Result()
+		body, _ := io.ReadAll(resp.Body)
+		asserts.Equal(200, resp.StatusCode)
+		asserts.Equal("image/png", resp.Header.Get("Content-Type"))
+		asserts.Equal(`attachment; filename=me.png`, resp.Header.Get("Content-Disposition"))
+		asserts.Equal(content, body)
+	}
+
+	c, rec = downloadRequest(t, *photo.ThumbnailURL, photo.ID)
+	if asserts.NoError(attachmentHandler.DownloadThumbnail(c)) {
+		resp := rec.Result()
+		asserts.Equal(200, resp.StatusCode)
+		asserts.Equal("image/jpeg", resp.Header.Get("Content-Type"))
+		thumb, _, err := image.Decode(resp.Body)
+		if asserts.NoError(err) {
+			asserts.Equal(image.Rect(0, 0, 256, 128), thumb.Bounds())
+		}
+	}
+
+	// the signature of the file does not open the thumbnail, nor another attachment
+	c, rec = downloadRequest(t, photo.URL, photo.ID)
+	if asserts.NoError(attachmentHandler.DownloadThumbnail(c)) {
+		asserts.Equal(403, rec.Result().StatusCode)
+	}
+	c, rec = downloadRequest(t, photo.URL, photo.ID+1)
+	if asserts.NoError(attachmentHandler.Download(c)) {
+		asserts.Equal(403, rec.Result().StatusCode)
+	}
+	c, rec = downloadRequest(t, "/?expires=1&signature=abc", photo.ID)
+	if asserts.NoError(attachmentHandler.Download(c)) {
+		asserts.Equal(403, rec.Result().StatusCode)
+	}
+}
+
+func TestAttachmentHandlerOwnership(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	asserts := assert.New(t)
+
+	certificate := upload(t, userClaims, "certificate", "certificate.pdf", []byte("%PDF-1.4\n%%EOF\n"))
+	upload(t, otherClaims, "certificate", "certificate.pdf", []byte("%PDF-1.4\n%%EOF\n"))
+
+	// students only list their own attachments, class A lists everyone's
+	c, rec := echoMock.RequestMock(http.MethodGet, "/", nil)
+	authorize(t, c, userClaims)
+	if asserts.NoError(attachmentHandler.Get(c)) {
+		code, body := rec.Code, rec.Body.String()
+		asserts.Equal(200, code)
+		asserts.Contains(body, `"student_id":2`)
+		asserts.NotContains(body, `"student_id":3`)
+	}
+	c, rec = echoMock.RequestMock(http.MethodGet, "/", nil)
+	authorize(t, c, adminClaims)
+	if asserts.NoError(attachmentHandler.Get(c)) {
+		asserts.Contains(rec.Body.String(), `"student_id":3`)
+	}
+
+	c, get := attachmentRequest(t, otherClaims, http.MethodGet, certificate.ID)
+	if asserts.NoError(attachmentHandler.GetById(c)) {
+		code, _ := get()
+		asserts.Equal(401, code)
+	}
+	c, del := attachmentRequest(t, otherClaims, http.MethodDelete, certificate.ID)
+	if asserts.NoError(attachmentHandler.DeleteById(c)) {
+		code, _ := del()
+		asserts.Equal(401, code)
+	}
+
+	c, del = attachmentRequest(t, userClaims, http.MethodDelete, certificate.ID)
+	c.Request().Header.Set("If-Match", `"2"`)
+	if asserts.NoError(attachmentHandler.DeleteById(c)) {
+		code, _ := del()
+		asserts.Equal(412, code)
+	}
+	c, del = attachmentRequest(t, userClaims, http.MethodDelete, certificate.ID)
+	c.Request().Header.Set("If-Match", `"1"`)
+	if asserts.NoError(attachmentHandler.DeleteById(c)) {
+		code, _ := del()
+		asserts.Equal(200, code)
+	}
+
+	c, rec2 := downloadRequest(t, certificate.URL, certificate.ID)
+	if asserts.NoError(attachmentHandler.Download(c)) {
+		asserts.Equal(404, rec2.Result().StatusCode)
+	}
+}
@@ -0,0 +1,21 @@
+package attachment
+
+import (
+	"student-service/internal/dto"
+	"student-service/internal/middleware"
+	"student-service/internal/pkg/util"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) Route(g *echo.Group) {
+	// download URLs are signed instead of authenticated, so they work in <img> tags and links
+	g.GET("/:id/download", h.Download)
+	g.GET("/:id/thumbnail", h.DownloadThumbnail)
+
+	authenticated := g.Group("", middleware.JWTMiddleware(dto.JWTClaims{}, util.JWT_SECRET))
+	authenticated.GET("", h.Get)
+	authenticated.GET("/:id", h.GetById)
+	authenticated.DELETE("/:id", h.DeleteById)
+	authenticated.POST("", h.Create, h.limitBody, h.idempotency)
+}
@@ -0,0 +1,330 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	"student-service/pkg/storage"
+	"student-service/pkg/thumbnail"
+	pkgutil "student-service/pkg/util"
+	res "student-service/pkg/util/response"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// downloadPath and thumbnailPath are the signed paths of the download routes.
+	downloadPath  = "/api/v1/attachments/%d/download"
+	thumbnailPath = "/api/v1/attachments/%d/thumbnail"
+
+	thumbnailSize = 256
+)
+
+// allowedTypes are the sniffed content types allowed for each kind of attachment.
+var allowedTypes = map[enum.AttachmentKind]map[string]string{
+	enum.IDScan:      {"application/pdf": ".pdf", "image/jpeg": ".jpg", "image/png": ".png"},
+	enum.Certificate: {"application/pdf": ".pdf", "image/jpeg": ".jpg", "image/png": ".png"},
+	enum.Photo:       {"image/jpeg": ".jpg", "image/png": ".png"},
+}
+
+type service struct {
+	AttachmentRepository repository.Attachment
+	Storage              storage.Storage
+	Signer               *storage.URLSigner
+	MaxSize              int64
+	URLTTL               time.Duration
+}
+
+type Service interface {
+	Find(ctx context.Context, payload *dto.SearchAttachmentRequest) ([]dto.AttachmentResponse, error)
+	FindByID(ctx context.Context, payload *dto.AttachmentByIDRequest) (*dto.AttachmentResponse, error)
+	Store(ctx context.Context, payload *dto.UploadAttachmentRequest) (*dto.AttachmentResponse, error)
+	DeleteById(ctx context.Context, payload *dto.AttachmentByIDRequest) (*dto.AttachmentResponse, error)
+	Open(ctx context.Context, payload *dto.DownloadAttachmentRequest, thumb bool) (*model.Attachment, io.ReadCloser, error)
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		AttachmentRepository: f.AttachmentRepository,
+		Storage:              f.Storage,
+		Signer:               storage.NewURLSigner(urlSecret()),
+		MaxSize:              maxSize(),
+		URLTTL:               urlTTL(),
+	}
+}
+
+func (s *service) Find(ctx context.Context, payload *dto.SearchAttachmentRequest) ([]dto.AttachmentResponse, error) {
+	attachments, err := s.AttachmentRepository.FindAll(ctx, payload)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	data := []dto.AttachmentResponse{}
+	for i := range attachments {
+		data = append(data, s.newAttachmentResponse(&attachments[i]))
+	}
+	return data, nil
+}
+
+func (s *service) FindByID(ctx context.Context, payload *dto.AttachmentByIDRequest) (*dto.AttachmentResponse, error) {
+	attachment, err := s.find(ctx, payload)
+	if err != nil {
+		return &dto.AttachmentResponse{}, err
+	}
+
+	result := s.newAttachmentResponse(&attachment)
+	return &result, nil
+}
+
+// Store checks the size and the sniffed content type of the file, then stores it with a
+// thumbnail when it is a photo. The files are deleted again when the attachment cannot be
+// saved, and those of the replaced photo once it is.
+func (s *service) Store(ctx context.Context, payload *dto.UploadAttachmentRequest) (*dto.AttachmentResponse, error) {
+	if payload.File.Size > s.MaxSize {
+		return &dto.AttachmentResponse{}, fileTooLargeError(s.MaxSize)
+	}
+
+	file, err := payload.File.Open()
+	if err != nil {
+		return &dto.AttachmentResponse{}, res.ErrorBuilder(res.ErrorConstant.BadRequest, err)
+	}
+	defer file.Close()
+
+	kind := enum.AttachmentKind(payload.Kind)
+	contentType, err := sniff(file)
+	if err != nil {
+		return &dto.AttachmentResponse{}, res.ErrorBuilder(res.ErrorConstant.BadRequest, err)
+	}
+	ext, ok := allowedTypes[kind][contentType]
+	if !ok {
+		return &dto.AttachmentResponse{}, res.ErrorBuilder(res.ErrorConstant.UnsupportedFileType, fmt.Errorf("%s is not allowed for %s", contentType, kind))
+	}
+
+	attachment := model.Attachment{
+		StudentID:   *payload.StudentID,
+		Kind:        payload.Kind,
+		Filename:    filepath.Base(payload.File.Filename),
+		ContentType: contentType,
+		Size:        payload.File.Size,
+	}
+	key, err := newKey(attachment.StudentID)
+	if err != nil {
+		return &dto.AttachmentResponse{}, res.ErrorBuilder(res.ErrorConstant.InternalServerError, err)
+	}
+	attachment.StorageKey = key + ext
+
+	var thumb []byte
+	if kind == enum.Photo {
+		thumb, err = thumbnail.Generate(file, thumbnailSize)
+		if errors.Is(err, thumbnail.ErrTooLarge) {
+			return &dto.AttachmentResponse{}, res.ErrorBuilder(res.ErrorConstant.FileTooLarge, err)
+		}
+		if err != nil {
+			return &dto.AttachmentResponse{}, res.ErrorBuilder(res.ErrorConstant.UnsupportedFileType, fmt.Errorf("decode photo: %w", err))
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return &dto.AttachmentResponse{}, res.ErrorBuilder(res.ErrorConstant.InternalServerError, err)
+		}
+	}
+
+	if err := s.Storage.Put(ctx, attachment.StorageKey, file, attachment.Size, contentType); err != nil {
+		return &dto.AttachmentResponse{}, res.ErrorBuilder(res.ErrorConstant.InternalServerError, err)
+	}
+	if thumb != nil {
+		thumbnailKey := key + "-thumb.jpg"
+		err := s.Storage.Put(ctx, thumbnailKey, bytes.NewReader(thumb), int64(len(thumb)), thumbnail.ContentType)
+		if err != nil {
+			s.deleteFiles(ctx, &attachment)
+			return &dto.AttachmentResponse{}, res.ErrorBuilder(res.ErrorConstant.InternalServerError, err)
+		}
+		attachment.ThumbnailKey = &thumbnailKey
+	}
+
+	replaced, err := s.AttachmentRepository.Save(ctx, &attachment)
+	if err != nil {
+		s.deleteFiles(ctx, &attachment)
+		return &dto.AttachmentResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	for i := range replaced {
+		s.deleteFiles(ctx, &replaced[i])
+	}
+
+	result := s.newAttachmentResponse(&attachment)
+	return &result, nil
+}
+
+func (s *service) DeleteById(ctx context.Context, payload *dto.AttachmentByIDRequest) (*dto.AttachmentResponse, error) {
+	attachment, err := s.find(ctx, payload)
+	if err != nil {
+		return &dto.AttachmentResponse{}, err
+	}
+	if !res.IfMatch(payload.IfMatch, attachment.Version) {
+		return &dto.AttachmentResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("attachment version does not match If-Match"))
+	}
+
+	if _, err := s.AttachmentRepository.Destroy(ctx, &attachment); err != nil {
+		return &dto.AttachmentResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	s.deleteFiles(ctx, &attachment)
+
+	result := s.newAttachmentResponse(&attachment)
+	result.URL, result.ThumbnailURL = "", nil
+	return &result, nil
+}
+
+// Open checks the signature of a download URL and returns the attachment with its file, or its
+// thumbnail. The caller must close the file.
+func (s *service) Open(ctx context.Context, payload *dto.DownloadAttachmentRequest, thumb bool) (*model.Attachment, io.ReadCloser, error) {
+	path := fmt.Sprintf(downloadPath, payload.ID)
+	if thumb {
+		path = fmt.Sprintf(thumbnailPath, payload.ID)
+	}
+	if err := s.Signer.Verify(path, payload.Expires, payload.Signature); err != nil {
+		return nil, nil, res.ErrorBuilder(res.ErrorConstant.InvalidDownloadURL, err)
+	}
+
+	attachment, err := s.AttachmentRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return nil, nil, util.RepositoryErrorBuilder(err)
+	}
+	key := attachment.StorageKey
+	if thumb {
+		if attachment.ThumbnailKey == nil {
+			return nil, nil, res.ErrorBuilder(res.ErrorConstant.NotFound, fmt.Errorf("attachment %d has no thumbnail", attachment.ID))
+		}
+		key = *attachment.ThumbnailKey
+	}
+
+	file, err := s.Storage.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, res.ErrorBuilder(res.ErrorConstant.NotFound, err)
+	}
+	if err != nil {
+		return nil, nil, res.ErrorBuilder(res.ErrorConstant.InternalServerError, err)
+	}
+	return &attachment, file, nil
+}
+
+// find returns the attachment, which must belong to payload.OwnerStudentID when it is set.
+func (s *service) find(ctx context.Context, payload *dto.AttachmentByIDRequest) (model.Attachment, error) {
+	attachment, err := s.AttachmentRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return attachment, util.RepositoryErrorBuilder(err)
+	}
+	if payload.OwnerStudentID != nil && *payload.OwnerStudentID != attachment.StudentID {
+		return attachment, res.ErrorBuilder(res.ErrorConstant.Unauthorized, fmt.Errorf("attachment %d belongs to another student", attachment.ID))
+	}
+	return attachment, nil
+}
+
+// deleteFiles deletes the files of the attachment. Failures only leave orphaned files behind,
+// so they are logged rather than returned.
+func (s *service) deleteFiles(ctx context.Context, attachment *model.Attachment) {
+	keys := []string{attachment.StorageKey}
+	if attachment.ThumbnailKey != nil {
+		keys = append(keys, *attachment.ThumbnailKey)
+	}
+	for _, key := range keys {
+		if err := s.Storage.Delete(ctx, key); err != nil {
+			logrus.Warnf("delete attachment file %s: %v", key, err)
+		}
+	}
+}
+
+func (s *service) newAttachmentResponse(attachment *model.Attachment) dto.AttachmentResponse {
+	expiresAt := time.Now().Add(s.URLTTL).Truncate(time.Second)
+	result := dto.AttachmentResponse{
+		ID:           attachment.ID,
+		StudentID:    attachment.StudentID,
+		Kind:         attachment.Kind,
+		Filename:     attachment.Filename,
+		ContentType:  attachment.ContentType,
+		Size:         attachment.Size,
+		URL:          s.Signer.Sign(fmt.Sprintf(downloadPath, attachment.ID), expiresAt),
+		URLExpiresAt: expiresAt,
+		CreatedAt:    attachment.CreatedAt,
+		Version:      attachment.Version,
+	}
+	if attachment.ThumbnailKey != nil {
+		url := s.Signer.Sign(fmt.Sprintf(thumbnailPath, attachment.ID), expiresAt)
+		result.ThumbnailURL = &url
+	}
+	return result
+}
+
+// sniff detects the content type of the file from its first bytes, ignoring the name and the
+// content type sent by the client, and rewinds it.
+func sniff(file multipart.File) (string, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	contentType := http.DetectContentType(head[:n])
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return contentType, nil
+}
+
+// newKey returns a random storage key, without extension, for a file of the student.
+func newKey(studentID uint) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("students/%d/%s", studentID, hex.EncodeToString(b)), nil
+}
+
+func fileTooLargeError(maxSize int64) error {
+	return res.ErrorBuilder(res.ErrorConstant.FileTooLarge, fmt.Errorf("file is larger than %d bytes", maxSize))
+}
+
+// maxSize returns the largest file size in bytes, ATTACHMENT_MAX_SIZE, 5 MiB by default.
+func maxSize() int64 {
+	size, err := strconv.ParseInt(pkgutil.Getenv("ATTACHMENT_MAX_SIZE", "5242880"), 10, 64)
+	if err != nil || size <= 0 {
+		logrus.Warnf("invalid ATTACHMENT_MAX_SIZE, using 5242880: %v", err)
+		return 5 << 20
+	}
+	return size
+}
+
+// urlTTL returns how long download URLs are valid, STORAGE_URL_TTL, 15 minutes by default.
+func urlTTL() time.Duration {
+	ttl, err := time.ParseDuration(pkgutil.Getenv("STORAGE_URL_TTL", "15m"))
+	if err != nil || ttl <= 0 {
+		logrus.Warnf("invalid STORAGE_URL_TTL, using 15m: %v", err)
+		return 15 * time.Minute
+	}
+	return ttl
+}
+
+// urlSecret returns the key download URLs are signed with, STORAGE_URL_SECRET or the JWT
+// secret when it is not set.
+func urlSecret() []byte {
+	if secret := pkgutil.Getenv("STORAGE_URL_SECRET", ""); secret != "" {
+		return []byte(secret)
+	}
+	return util.JWT_SECRET
+}
@@ -3,28 +3,32 @@ package auth
 import (
 	"student-service/internal/dto"
 	"student-service/internal/factory"
+	"student-service/internal/middleware"
+	"student-service/internal/pkg/util"
 	res "student-service/pkg/util/response"
 
 	"github.com/labstack/echo/v4"
 )
 
 type handler struct {
-	service Service
+	service     Service
+	idempotency echo.MiddlewareFunc
 }
 
 func NewHandler(f *factory.Factory) *handler {
 	return &handler{
-		service: NewService(f),
+		service:     NewService(f),
+		idempotency: middleware.IdempotencyMiddleware(f.IdempotencyKeyRepository),
 	}
 }
 
 func (h *handler) LoginByEmailAndPassword(c echo.Context) error {
 	payload := new(dto.ByEmailAndPasswordRequest)
 	if err := c.Bind(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.BadRequest, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
 	}
-	if err := c.Validate(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.Validation, err).Send(c)
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
 	}
 
 	student, err := h.service.LoginByEmailAndPassword(c.Request().Context(), payload)
@@ -38,10 +42,10 @@ func (h *handler) LoginByEmailAndPassword(c echo.Context) error {
 func (h *handler) RegisterByEmailAndPassword(c echo.Context) error {
 	payload := new(dto.RegisterStudentRequestBody)
 	if err := c.Bind(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.BadRequest, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
 	}
-	if err := c.Validate(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.Validation, err).Send(c)
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
 	}
 	payload.FillDefaults()
 
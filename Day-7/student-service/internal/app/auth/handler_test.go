@@ -2,16 +2,20 @@ package auth
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"student-service/database"
 	"student-service/database/seeder"
 	"student-service/internal/dto"
 	"student-service/internal/factory"
 	"student-service/internal/mocks"
+	"student-service/internal/model"
 	"student-service/internal/repository"
+	"student-service/pkg/studentnumber"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -32,7 +36,7 @@ func TestAuthHandlerLoginByEmailAndPasswordInvalidPayload(t *testing.T) {
 	// setup handler
 	asserts := assert.New(t)
 	db := database.GetConnection()
-	factory := factory.Factory{StudentRepository: repository.NewStudentRepository(db)}
+	factory := factory.Factory{StudentRepository: repository.NewStudentRepository(db), Transactor: repository.NewTransactor(db)}
 	authHandler := NewHandler(&factory)
 
 	// testing
@@ -40,7 +44,14 @@ func TestAuthHandlerLoginByEmailAndPasswordInvalidPayload(t *testing.T) {
 		asserts.Equal(400, rec.Code)
 
 		body := rec.Body.String()
-		asserts.JSONEq(`{"meta": {"success": false,"message": "Invalid parameters or payload","info": null},"error": "bad_request"}`, body)
+		asserts.JSONEq(`{
+			"meta": {"success": false,"message": "Invalid parameters or payload","info": null},
+			"error": "bad_request",
+			"errors": [
+				{"field": "email", "tag": "required", "message": "email is required"},
+				{"field": "password", "tag": "required", "message": "password is required"}
+			]
+		}`, body)
 	}
 }
 
@@ -68,7 +79,7 @@ func TestAuthHandlerLoginByEmailAndPasswordUnmatchedEmailAndPassword(t *testing.
 	// setup handler
 	asserts := assert.New(t)
 	db := database.GetConnection()
-	factory := factory.Factory{StudentRepository: repository.NewStudentRepository(db)}
+	factory := factory.Factory{StudentRepository: repository.NewStudentRepository(db), Transactor: repository.NewTransactor(db)}
 	authHandler := NewHandler(&factory)
 
 	// testing
@@ -104,7 +115,7 @@ func TestAuthHandlerLoginByEmailAndPasswordSuccess(t *testing.T) {
 	// setup handler
 	asserts := assert.New(t)
 	db := database.GetConnection()
-	factory := factory.Factory{StudentRepository: repository.NewStudentRepository(db)}
+	factory := factory.Factory{StudentRepository: repository.NewStudentRepository(db), Transactor: repository.NewTransactor(db)}
 	authHandler := NewHandler(&factory)
 
 	// testing
@@ -151,7 +162,7 @@ func TestAuthHandlerRegisterByEmailAndPasswordBAlreadyExist(t *testing.T) {
 	// setup handler
 	asserts := assert.New(t)
 	db := database.GetConnection()
-	factory := factory.Factory{StudentRepository: repository.NewStudentRepository(db)}
+	factory := factory.Factory{StudentRepository: repository.NewStudentRepository(db), Transactor: repository.NewTransactor(db)}
 	authHandler := NewHandler(&factory)
 
 	// testing
@@ -193,7 +204,7 @@ func TestAuthHandlerRegisterByEmailAndPasswordInvalidPayload(t *testing.T) {
 	// setup handler
 	asserts := assert.New(t)
 	db := database.GetConnection()
-	factory := factory.Factory{StudentRepository: repository.NewStudentRepository(db)}
+	factory := factory.Factory{StudentRepository: repository.NewStudentRepository(db), Transactor: repository.NewTransactor(db)}
 	authHandler := NewHandler(&factory)
 
 	// testing
@@ -237,7 +248,7 @@ func TestAuthHandlerRegisterByEmailAndPasswordSuccess(t *testing.T) {
 	// setup handler
 	asserts := assert.New(t)
 	db := database.GetConnection()
-	factory := factory.Factory{StudentRepository: repository.NewStudentRepository(db)}
+	factory := factory.Factory{StudentRepository: repository.NewStudentRepository(db), Transactor: repository.NewTransactor(db)}
 	authHandler := NewHandler(&factory)
 
 	// testing
@@ -251,3 +262,95 @@ func TestAuthHandlerRegisterByEmailAndPasswordSuccess(t *testing.T) {
 		asserts.Contains(body, "jwt")
 	}
 }
+
+func TestAuthHandlerRegisterByEmailAndPasswordMajorNotExist(t *testing.T) {
+	// setup database
+	database.GetConnection()
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	// setup context
+	var (
+		fullname = "Azka"
+		email    = "azka@edu.ac.id"
+		password = "123abcABC!"
+		majorID  = uint(99)
+	)
+	emailAndPassword := dto.RegisterStudentRequestBody{
+		Fullname: fullname,
+		Email:    email,
+		Password: password,
+		MajorID:  &majorID,
+	}
+	e := echo.New()
+	echoMock := mocks.EchoMock{E: e}
+	payload, err := json.Marshal(emailAndPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, rec := echoMock.RequestMock(http.MethodPost, "/", bytes.NewBuffer(payload))
+	c.Request().Header.Set("Content-Type", "application/json")
+	c.SetPath("/api/v1/auth/signup")
+
+	// setup handler
+	asserts := assert.New(t)
+	db := database.GetConnection()
+	factory := factory.Factory{StudentRepository: repository.NewStudentRepository(db), Transactor: repository.NewTransactor(db)}
+	authHandler := NewHandler(&factory)
+
+	// testing
+	if asserts.NoError(authHandler.RegisterByEmailAndPassword(c)) {
+		asserts.Equal(400, rec.Code)
+
+		body := rec.Body.String()
+		asserts.Contains(body, "Invalid parameters or payload")
+		asserts.Contains(body, `"field":"major_id"`)
+		asserts.Contains(body, `"tag":"exists"`)
+	}
+}
+
+func TestAuthHandlerRegisterByEmailAndPasswordStudentNumbersExhausted(t *testing.T) {
+	// setup database
+	db := database.GetConnection()
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	// the one digit sequence of the major is used up
+	t.Setenv("STUDENT_NUMBER_PATTERN", "{year}{major:2}{seq:1}")
+	majorID := uint(2)
+	sequence := model.StudentNumberSequence{Scope: studentnumber.DefaultPattern().Scope(time.Now().Year(), majorID), Last: 9}
+	if err := db.Create(&sequence).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// setup context
+	payload, err := json.Marshal(dto.RegisterStudentRequestBody{
+		Fullname: "Azka",
+		Email:    "azka@edu.ac.id",
+		Password: "123abcABC!",
+		MajorID:  &majorID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	echoMock := mocks.EchoMock{E: echo.New()}
+	c, rec := echoMock.RequestMock(http.MethodPost, "/", bytes.NewBuffer(payload))
+	c.Request().Header.Set("Content-Type", "application/json")
+	c.SetPath("/api/v1/auth/signup")
+
+	// setup handler
+	asserts := assert.New(t)
+	factory := factory.Factory{StudentRepository: repository.NewStudentRepository(db), Transactor: repository.NewTransactor(db)}
+	authHandler := NewHandler(&factory)
+
+	// testing
+	if asserts.NoError(authHandler.RegisterByEmailAndPassword(c)) {
+		asserts.Equal(409, rec.Code)
+		asserts.Contains(rec.Body.String(), "No student number is left for the major and intake year")
+	}
+	email := "azka@edu.ac.id"
+	exists, err := repository.NewStudentRepository(db).ExistByEmail(context.Background(), &email)
+	if asserts.NoError(err) {
+		asserts.False(exists)
+	}
+}
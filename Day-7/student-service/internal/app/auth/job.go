@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"context"
+
+	"student-service/internal/app/job"
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/pkg/i18n"
+	"student-service/pkg/mailer"
+)
+
+// WelcomeEmailHandler returns the handler of the email.welcome jobs, it sends the welcome email
+// of a registered student in the language of the registration.
+func WelcomeEmailHandler(f *factory.Factory) job.Handler {
+	return job.Typed(func(ctx context.Context, payload dto.WelcomeEmailJob) error {
+		if f.Mailer == nil {
+			return nil
+		}
+		lang := i18n.Match(payload.Language)
+		return f.Mailer.Send(ctx, mailer.Message{
+			To:      payload.Email,
+			Subject: i18n.Translate(lang, i18n.WelcomeEmailSubject),
+			Body:    i18n.Translate(lang, i18n.WelcomeEmailBody, payload.Fullname, payload.Email),
+		})
+	})
+}
@@ -4,17 +4,28 @@ import (
 	"context"
 	"errors"
 
+	"student-service/internal/app/job"
+	"student-service/internal/app/outbox"
 	"student-service/internal/dto"
 	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
 	"student-service/internal/pkg/util"
 	"student-service/internal/repository"
-	"student-service/pkg/constant"
+	"student-service/pkg/cache"
+	"student-service/pkg/i18n"
+	"student-service/pkg/studentnumber"
 	pkgutil "student-service/pkg/util"
 	res "student-service/pkg/util/response"
 )
 
 type service struct {
 	StudentRepository repository.Student
+	StudentNumbers    *studentnumber.Pattern
+	Transactor        repository.Transactor
+	Cache             cache.Cache
+	Events            outbox.Recorder
+	Jobs              job.Queue
 }
 
 type Service interface {
@@ -25,6 +36,11 @@ type Service interface {
 func NewService(f *factory.Factory) Service {
 	return &service{
 		StudentRepository: f.StudentRepository,
+		StudentNumbers:    studentnumber.DefaultPattern(),
+		Transactor:        f.Transactor,
+		Cache:             f.Cache,
+		Events:            outbox.NewRecorder(f),
+		Jobs:              job.NewQueue(f),
 	}
 }
 
@@ -33,33 +49,31 @@ func (s *service) LoginByEmailAndPassword(ctx context.Context, payload *dto.ByEm
 
 	data, err := s.StudentRepository.FindByEmail(ctx, &payload.Email)
 	if err != nil {
-		if err == constant.RECORD_NOT_FOUND {
-			return result, res.ErrorBuilder(&res.ErrorConstant.NotFound, err)
-		}
-		return result, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return result, util.RepositoryErrorBuilder(err)
 	}
 
 	if !(pkgutil.CompareHashPassword(payload.Password, data.Password)) {
 		return result, res.ErrorBuilder(
-			&res.ErrorConstant.EmailOrPasswordIncorrect,
-			errors.New(res.ErrorConstant.EmailOrPasswordIncorrect.Response.Meta.Message),
+			res.ErrorConstant.EmailOrPasswordIncorrect,
+			errors.New(res.ErrorConstant.EmailOrPasswordIncorrect.Message()),
 		)
 	}
 
-	claims := util.CreateJWTClaims(data.Email, data.ID, data.ClassID, data.MajorID)
+	claims := util.CreateJWTClaims(data.Email, data.ID, classIDOrZero(data.ClassID), data.MajorID)
 	token, err := util.CreateJWTToken(claims)
 	if err != nil {
 		return result, res.ErrorBuilder(
-			&res.ErrorConstant.InternalServerError,
+			res.ErrorConstant.InternalServerError,
 			errors.New("error when generating token"),
 		)
 	}
 
 	result = &dto.StudentWithJWTResponse{
 		StudentResponse: dto.StudentResponse{
-			ID:       data.ID,
-			Fullname: data.Fullname,
-			Email:    data.Email,
+			ID:            data.ID,
+			StudentNumber: data.StudentNumber,
+			Fullname:      data.Fullname,
+			Email:         data.Email,
 		},
 		JWT: token,
 	}
@@ -71,40 +85,80 @@ func (s *service) RegisterByEmailAndPassword(ctx context.Context, payload *dto.R
 	var result *dto.StudentWithJWTResponse
 	isExist, err := s.StudentRepository.ExistByEmail(ctx, &payload.Email)
 	if err != nil {
-		return result, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return result, util.RepositoryErrorBuilder(err)
 	}
 	if isExist {
-		return result, res.ErrorBuilder(&res.ErrorConstant.Duplicate, errors.New("student already exists"))
+		return result, res.ErrorBuilder(res.ErrorConstant.Duplicate, errors.New("student already exists"))
+	}
+	if err := util.CheckBirthDate(payload.BirthDate); err != nil {
+		return result, err
 	}
 
 	hashedPassword, err := pkgutil.HashPassword(payload.Password)
 	if err != nil {
-		return result, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return result, res.ErrorBuilder(res.ErrorConstant.InternalServerError, err)
 	}
 	payload.Password = hashedPassword
 
-	data, err := s.StudentRepository.Save(ctx, payload)
+	var data model.Student
+	err = s.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		data, err = s.StudentRepository.Save(ctx, payload, s.StudentNumbers)
+		if err != nil {
+			return err
+		}
+
+		err = s.Events.Record(ctx, enum.StudentRegistered, data.ID, dto.StudentResponse{
+			ID:            data.ID,
+			StudentNumber: data.StudentNumber,
+			Fullname:      data.Fullname,
+			Email:         data.Email,
+			Version:       data.Version,
+		})
+		if err != nil {
+			return err
+		}
+
+		// the welcome email is sent in the language of the request, out of it
+		return s.Jobs.Enqueue(ctx, enum.JobWelcomeEmail, dto.WelcomeEmailJob{
+			Fullname: data.Fullname,
+			Email:    data.Email,
+			Language: i18n.FromContext(ctx).String(),
+		})
+	})
+	if errors.Is(err, studentnumber.ErrSequenceExhausted) {
+		return result, res.ErrorBuilder(res.ErrorConstant.StudentNumbersExhausted, err)
+	}
 	if err != nil {
-		return result, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return result, util.RepositoryErrorBuilder(err)
 	}
+	cache.Invalidate(ctx, s.Cache, util.StudentCachePrefix)
 
-	claims := util.CreateJWTClaims(data.Email, data.ID, data.ClassID, data.MajorID)
+	claims := util.CreateJWTClaims(data.Email, data.ID, classIDOrZero(data.ClassID), data.MajorID)
 	token, err := util.CreateJWTToken(claims)
 	if err != nil {
 		return result, res.ErrorBuilder(
-			&res.ErrorConstant.InternalServerError,
+			res.ErrorConstant.InternalServerError,
 			errors.New("error when generating token"),
 		)
 	}
 
 	result = &dto.StudentWithJWTResponse{
 		StudentResponse: dto.StudentResponse{
-			ID:       data.ID,
-			Fullname: data.Fullname,
-			Email:    data.Email,
+			ID:            data.ID,
+			StudentNumber: data.StudentNumber,
+			Fullname:      data.Fullname,
+			Email:         data.Email,
 		},
 		JWT: token,
 	}
 
 	return result, nil
 }
+
+// classIDOrZero returns the class id of a student, or 0 when no class is assigned.
+func classIDOrZero(classID *uint) uint {
+	if classID == nil {
+		return 0
+	}
+	return *classID
+}
@@ -2,17 +2,37 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"student-service/database"
 	"student-service/database/seeder"
+	"student-service/internal/app/job"
 	"student-service/internal/dto"
 	"student-service/internal/factory"
+	"student-service/internal/pkg/enum"
+	"student-service/pkg/i18n"
+	"student-service/pkg/mailer"
+	"student-service/pkg/studentnumber"
+	res "student-service/pkg/util/response"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
 )
 
+type testMailer struct {
+	messages chan mailer.Message
+}
+
+func (m *testMailer) Send(ctx context.Context, message mailer.Message) error {
+	m.messages <- message
+	return nil
+}
+
 func TestAuthServiceLoginByEmailAndPasswordSuccess(t *testing.T) {
 	database.GetConnection()
 	seeder.NewSeeder().DeleteAll()
@@ -120,3 +140,144 @@ func TestAuthServiceRegisterByEmailAndPasswordBExist(t *testing.T) {
 		asserts.Equal(err.Error(), "error code 409")
 	}
 }
+
+func TestAuthServiceRegisterByEmailAndPasswordSendsLocalizedWelcomeEmail(t *testing.T) {
+	database.GetConnection()
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	asserts := assert.New(t)
+	var (
+		f       = factory.NewFactory()
+		mail    = &testMailer{messages: make(chan mailer.Message, 1)}
+		ctx     = i18n.WithLanguage(context.Background(), language.Indonesian)
+		majorID = uint(1)
+		payload = dto.RegisterStudentRequestBody{
+			Fullname: "Azka Fadhli Ramadhan",
+			Email:    "azkaframadhan@edu.ac.id",
+			Password: "123abcABC!",
+			MajorID:  &majorID,
+		}
+	)
+	f.Mailer = mail
+	authService := NewService(f)
+	payload.FillDefaults()
+	if _, err := authService.RegisterByEmailAndPassword(ctx, &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	// the email is sent by a job, in the language of the registration
+	worker := job.NewWorker(f)
+	worker.Handle(enum.JobWelcomeEmail, WelcomeEmailHandler(f))
+	asserts.Equal(1, worker.RunDue(context.Background()))
+
+	select {
+	case message := <-mail.messages:
+		asserts.Equal(payload.Email, message.To)
+		asserts.Equal("Selamat datang di Student Service", message.Subject)
+		asserts.Contains(message.Body, "Halo Azka Fadhli Ramadhan")
+	case <-time.After(time.Second):
+		t.Fatal("welcome email was not sent")
+	}
+}
+
+func TestAuthServiceRegisterByEmailAndPasswordStudentNumber(t *testing.T) {
+	database.GetConnection()
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	asserts := assert.New(t)
+
+	pattern, err := studentnumber.Parse("{yy}-{major:2}-{seq:3}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	authService := NewService(factory.NewFactory()).(*service)
+	authService.StudentNumbers = pattern
+	yy := time.Now().Year() % 100
+
+	for i, want := range []struct {
+		majorID uint
+		number  string
+	}{
+		{1, fmt.Sprintf("%02d-01-001", yy)},
+		{1, fmt.Sprintf("%02d-01-002", yy)},
+		{2, fmt.Sprintf("%02d-02-001", yy)},
+	} {
+		majorID := want.majorID
+		payload := dto.RegisterStudentRequestBody{
+			Fullname: "Azka Fadhli Ramadhan",
+			Email:    fmt.Sprintf("azkaframadhan%d@edu.ac.id", i),
+			Password: "123abcABC!",
+			MajorID:  &majorID,
+		}
+		result, err := authService.RegisterByEmailAndPassword(context.Background(), &payload)
+		if asserts.NoError(err) && asserts.NotNil(result.StudentNumber) {
+			asserts.Equal(want.number, *result.StudentNumber)
+		}
+	}
+}
+
+func TestAuthServiceRegisterByEmailAndPasswordConcurrentStudentNumbers(t *testing.T) {
+	// the sequence relies on the row lock taken on the sequence
+	var versionComment string
+	database.GetConnection().Raw("SELECT @@version_comment").Scan(&versionComment)
+	if strings.Contains(versionComment, "Dolt") {
+		t.Skip("the database server does not lock rows in transactions")
+	}
+
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	authService := NewService(factory.NewFactory())
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		numbers = map[string]bool{}
+		errs    []error
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			majorID := uint(1)
+			payload := dto.RegisterStudentRequestBody{
+				Fullname: "Azka Fadhli Ramadhan",
+				Email:    fmt.Sprintf("azkaframadhan%d@edu.ac.id", i),
+				Password: "123abcABC!",
+				MajorID:  &majorID,
+			}
+			result, err := authService.RegisterByEmailAndPassword(context.Background(), &payload)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			numbers[*result.StudentNumber] = true
+		}(i)
+	}
+	wg.Wait()
+
+	asserts := assert.New(t)
+	asserts.Empty(errs)
+	asserts.Len(numbers, 8)
+}
+
+func TestAuthServiceRegisterByEmailAndPasswordBirthDateInFuture(t *testing.T) {
+	database.GetConnection()
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	var (
+		authService = NewService(factory.NewFactory())
+		majorID     = uint(1)
+		birthDate   = time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+		payload     = dto.RegisterStudentRequestBody{
+			Fullname:  "Azka Fadhli Ramadhan",
+			Email:     "azkaframadhan@edu.ac.id",
+			Password:  "123abcABC!",
+			MajorID:   &majorID,
+			BirthDate: &birthDate,
+		}
+	)
+	_, err := authService.RegisterByEmailAndPassword(context.Background(), &payload)
+	assert.True(t, errors.Is(err, res.ErrorConstant.InvalidBirthDate))
+}
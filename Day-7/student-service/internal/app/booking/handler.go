@@ -0,0 +1,140 @@
+package booking
+
+import (
+	"errors"
+	"net/http"
+
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/middleware"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	pkgdto "student-service/pkg/dto"
+	"student-service/pkg/i18n"
+	res "student-service/pkg/util/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	service     Service
+	idempotency echo.MiddlewareFunc
+}
+
+func NewHandler(f *factory.Factory) *handler {
+	return &handler{
+		service:     NewService(f),
+		idempotency: middleware.IdempotencyMiddleware(f.IdempotencyKeyRepository),
+	}
+}
+
+func (h *handler) Get(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	_, err := util.ParseJWTToken(authHeader)
+	if err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(dto.SearchBookingRequest)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	result, err := h.service.Find(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	return res.CustomSuccessBuilder(http.StatusOK, result.Data, i18n.GetBookingsSuccess, &result.PaginationInfo).Send(c)
+}
+
+func (h *handler) GetById(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	_, err := util.ParseJWTToken(authHeader)
+	if err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(pkgdto.ByIDRequest)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	result, err := h.service.FindByID(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	c.Response().Header().Set(res.HeaderETag, res.ETag(result.Version))
+	return res.SuccessResponse(result).Send(c)
+}
+
+// Create books a room for the authenticated student when no owner is set. Only class A can
+// book for other students or classes.
+func (h *handler) Create(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(dto.CreateBookingRequestBody)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	if payload.StudentID == nil && payload.ClassID == nil {
+		payload.StudentID = &jwtClaims.BID
+	}
+	if jwtClaims.ClassID != uint(enum.A) &&
+		((payload.StudentID != nil && *payload.StudentID != jwtClaims.BID) ||
+			(payload.ClassID != nil && *payload.ClassID != jwtClaims.ClassID)) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, errors.New("booking for someone else")).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	result, err := h.service.Store(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	return res.SuccessResponse(result).Send(c)
+}
+
+// Cancel cancels a booking. Students other than class A can only cancel their own bookings
+// and those of their class.
+func (h *handler) Cancel(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(dto.CancelBookingRequest)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	payload.IfMatch = c.Request().Header.Get(res.HeaderIfMatch)
+	if jwtClaims.ClassID != uint(enum.A) {
+		payload.OwnerStudentID = &jwtClaims.BID
+		payload.OwnerClassID = &jwtClaims.ClassID
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	result, err := h.service.Cancel(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	return res.SuccessResponse(result).Send(c)
+}
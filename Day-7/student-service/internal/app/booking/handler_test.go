@@ -0,0 +1,174 @@
+package booking
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"student-service/database"
+	"student-service/database/seeder"
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/mocks"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	adminClaims = util.CreateJWTClaims("vincentlhubbard@edu.ac.id", uint(1), uint(enum.A), uint(enum.Finance))
+	userClaims  = util.CreateJWTClaims("devoncthomas@edu.ac.id", uint(2), uint(enum.B), uint(enum.Finance))
+	otherClaims = util.CreateJWTClaims("bettinameaster@edu.ac.id", uint(3), uint(enum.B), uint(enum.Finance))
+	db          = database.GetConnection()
+	echoMock    = mocks.EchoMock{E: echo.New()}
+	f           = factory.Factory{
+		RoomRepository:    repository.NewRoomRepository(db),
+		BookingRepository: repository.NewBookingRepository(db),
+	}
+	bookingHandler = NewHandler(&f)
+)
+
+func bookingRequest(t *testing.T, claims dto.JWTClaims, method, payload string, id string) (echo.Context, func() (int, string)) {
+	c, rec := echoMock.RequestMock(method, "/", bytes.NewBufferString(payload))
+	if id != "" {
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+	}
+	token, err := util.CreateJWTToken(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	c.Request().Header.Set("Content-Type", "application/json")
+	return c, func() (int, string) { return rec.Code, rec.Body.String() }
+}
+
+// tomorrowAt returns the given hour of tomorrow, the seeded booking of room 1 is from 09:00 to 11:00.
+func tomorrowAt(hour int) string {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day()+1, hour, 0, 0, 0, time.Local).Format(time.RFC3339)
+}
+
+func bookingPayload(roomID uint, from, to int, extra string) string {
+	return fmt.Sprintf(`{"room_id":%d,"starts_at":%q,"ends_at":%q%s}`, roomID, tomorrowAt(from), tomorrowAt(to), extra)
+}
+
+func TestBookingHandlerCreate(t *testing.T) {
+	cases := []struct {
+		name     string
+		claims   dto.JWTClaims
+		payload  string
+		code     int
+		contains string
+		bookings int64
+	}{
+		{"own booking", userClaims, bookingPayload(1, 11, 12, ""), 200, `"student_id":2,"class_id":null`, 2},
+		{"class booking", userClaims, bookingPayload(1, 11, 12, `,"class_id":2`), 200, `"student_id":null,"class_id":2`, 2},
+		{"overlapping booking", userClaims, bookingPayload(1, 10, 12, ""), 409, "Room is already booked at this time", 1},
+		{"another room", userClaims, bookingPayload(2, 10, 12, ""), 200, `"room_id":2`, 2},
+		{"ends before it starts", userClaims, bookingPayload(1, 12, 11, ""), 422, "Booking must end after it starts", 1},
+		{"student and class", adminClaims, bookingPayload(1, 11, 12, `,"student_id":2,"class_id":2`), 422, "Booking is either for a student or for a class", 1},
+		{"for another student", userClaims, bookingPayload(1, 11, 12, `,"student_id":3`), 401, "unauthorized", 1},
+		{"for another student as class A", adminClaims, bookingPayload(1, 11, 12, `,"student_id":3`), 200, `"student_id":3`, 2},
+		{"room not found", userClaims, bookingPayload(99, 11, 12, ""), 400, `"field":"room_id"`, 1},
+		{"weekly", userClaims, bookingPayload(1, 11, 12, `,"recurrence":{"frequency":"weekly","occurrences":4}`), 200, `"starts_at":"` + tomorrowAt(11) + `"`, 5},
+		{"daily overlapping on the first day", userClaims, bookingPayload(1, 8, 10, `,"recurrence":{"frequency":"daily","occurrences":3}`), 409, "Room is already booked at this time", 1},
+		{"longer than a day", userClaims, fmt.Sprintf(`{"room_id":1,"starts_at":%q,"ends_at":%q,"recurrence":{"frequency":"daily","occurrences":2}}`,
+			tomorrowAt(12), time.Now().AddDate(0, 0, 3).Format(time.RFC3339)), 422, "Booking must end after it starts and before it repeats", 1},
+		{"unknown frequency", userClaims, bookingPayload(1, 11, 12, `,"recurrence":{"frequency":"monthly","occurrences":2}`), 400, `"field":"recurrence.frequency"`, 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			seeder.NewSeeder().SeedAll()
+
+			c, rec := bookingRequest(t, tc.claims, http.MethodPost, tc.payload, "")
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(bookingHandler.Create(c)) {
+				code, body := rec()
+				asserts.Equal(tc.code, code)
+				asserts.Contains(body, tc.contains)
+			}
+			var count int64
+			db.Table("bookings").Count(&count)
+			asserts.Equal(tc.bookings, count)
+		})
+	}
+}
+
+func TestBookingHandlerCancel(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	asserts := assert.New(t)
+
+	c, rec := bookingRequest(t, userClaims, http.MethodPost, bookingPayload(1, 13, 14, `,"recurrence":{"frequency":"weekly","occurrences":3}`), "")
+	if asserts.NoError(bookingHandler.Create(c)) {
+		code, _ := rec()
+		asserts.Equal(200, code)
+	}
+	var series []uint
+	db.Table("bookings").Where("series_id IS NOT NULL").Order("starts_at").Pluck("id", &series)
+	if len(series) != 3 {
+		t.Fatalf("booked %d occurrences", len(series))
+	}
+	first, second := fmt.Sprint(series[0]), fmt.Sprint(series[1])
+
+	// only the owner or class A can cancel
+	c, rec = bookingRequest(t, otherClaims, http.MethodPost, "", first)
+	if asserts.NoError(bookingHandler.Cancel(c)) {
+		code, body := rec()
+		asserts.Equal(401, code)
+		asserts.Contains(body, "unauthorized")
+	}
+
+	c, rec = bookingRequest(t, userClaims, http.MethodPost, "", first)
+	c.Request().Header.Set("If-Match", `"9"`)
+	if asserts.NoError(bookingHandler.Cancel(c)) {
+		code, body := rec()
+		asserts.Equal(412, code)
+		asserts.Contains(body, "Data has been modified")
+	}
+
+	// cancelling the series from the second occurrence leaves the first one
+	c, rec = bookingRequest(t, userClaims, http.MethodPost, `{"series":true}`, second)
+	if asserts.NoError(bookingHandler.Cancel(c)) {
+		code, _ := rec()
+		asserts.Equal(200, code)
+	}
+	var cancelled []uint
+	db.Table("bookings").Where("cancelled_at IS NOT NULL").Order("starts_at").Pluck("id", &cancelled)
+	asserts.Equal(series[1:], cancelled)
+
+	// the room is free again once the booking is cancelled
+	c, rec = bookingRequest(t, adminClaims, http.MethodPost, "", "1")
+	if asserts.NoError(bookingHandler.Cancel(c)) {
+		code, body := rec()
+		asserts.Equal(200, code)
+		asserts.Contains(body, `"version":2`)
+	}
+	c, rec = bookingRequest(t, userClaims, http.MethodPost, bookingPayload(1, 9, 11, ""), "")
+	if asserts.NoError(bookingHandler.Create(c)) {
+		code, _ := rec()
+		asserts.Equal(200, code)
+	}
+
+	// cancelled bookings are left out unless asked for
+	c, rec = bookingRequest(t, userClaims, http.MethodGet, "", "")
+	if asserts.NoError(bookingHandler.Get(c)) {
+		_, body := rec()
+		asserts.Contains(body, `"count":2`)
+	}
+	c, rec = bookingRequest(t, userClaims, http.MethodGet, "", "")
+	c.Request().URL.RawQuery = "cancelled=true"
+	if asserts.NoError(bookingHandler.Get(c)) {
+		_, body := rec()
+		asserts.Contains(body, `"count":5`)
+	}
+}
@@ -0,0 +1,17 @@
+package booking
+
+import (
+	"student-service/internal/dto"
+	"student-service/internal/middleware"
+	"student-service/internal/pkg/util"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) Route(g *echo.Group) {
+	g.Use(middleware.JWTMiddleware(dto.JWTClaims{}, util.JWT_SECRET))
+	g.GET("", h.Get)
+	g.GET("/:id", h.GetById)
+	g.POST("/:id/cancel", h.Cancel)
+	g.POST("", h.Create, h.idempotency)
+}
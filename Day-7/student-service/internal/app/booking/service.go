@@ -0,0 +1,171 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	pkgdto "student-service/pkg/dto"
+	res "student-service/pkg/util/response"
+)
+
+// frequencies maps the frequency of a recurring booking to the days between its occurrences.
+var frequencies = map[string]int{
+	"daily":  1,
+	"weekly": 7,
+}
+
+type service struct {
+	BookingRepository repository.Booking
+}
+
+type Service interface {
+	Find(ctx context.Context, payload *dto.SearchBookingRequest) (*pkgdto.SearchGetResponse[dto.BookingResponse], error)
+	FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.BookingResponse, error)
+	Store(ctx context.Context, payload *dto.CreateBookingRequestBody) ([]dto.BookingResponse, error)
+	Cancel(ctx context.Context, payload *dto.CancelBookingRequest) ([]dto.BookingResponse, error)
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		BookingRepository: f.BookingRepository,
+	}
+}
+
+func (s *service) Find(ctx context.Context, payload *dto.SearchBookingRequest) (*pkgdto.SearchGetResponse[dto.BookingResponse], error) {
+	bookings, info, err := s.BookingRepository.FindAll(ctx, payload, &payload.Pagination)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	var data []dto.BookingResponse
+	for i := range bookings {
+		data = append(data, newBookingResponse(&bookings[i]))
+	}
+
+	result := new(pkgdto.SearchGetResponse[dto.BookingResponse])
+	result.Data = data
+	result.PaginationInfo = *info
+
+	return result, nil
+}
+
+func (s *service) FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.BookingResponse, error) {
+	booking, err := s.BookingRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return &dto.BookingResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := newBookingResponse(&booking)
+	return &result, nil
+}
+
+// Store books the room, every occurrence of a recurring booking at once. Nothing is booked
+// when one of the occurrences overlaps another booking of the room.
+func (s *service) Store(ctx context.Context, payload *dto.CreateBookingRequestBody) ([]dto.BookingResponse, error) {
+	if (payload.StudentID == nil) == (payload.ClassID == nil) {
+		return nil, res.ErrorBuilder(res.ErrorConstant.InvalidBookingOwner, errors.New("set either student_id or class_id"))
+	}
+	if !payload.EndsAt.After(*payload.StartsAt) {
+		return nil, res.ErrorBuilder(res.ErrorConstant.InvalidBookingTime, errors.New("booking must end after it starts"))
+	}
+
+	booking := model.Booking{
+		RoomID:    *payload.RoomID,
+		StudentID: payload.StudentID,
+		ClassID:   payload.ClassID,
+		StartsAt:  *payload.StartsAt,
+		EndsAt:    *payload.EndsAt,
+		Purpose:   payload.Purpose,
+	}
+	bookings := []model.Booking{booking}
+
+	var series *model.BookingSeries
+	if payload.Recurrence != nil {
+		days := frequencies[*payload.Recurrence.Frequency]
+		if booking.EndsAt.After(booking.StartsAt.AddDate(0, 0, days)) {
+			return nil, res.ErrorBuilder(res.ErrorConstant.InvalidBookingTime, fmt.Errorf("a %s booking cannot last longer than %d days", *payload.Recurrence.Frequency, days))
+		}
+		series = &model.BookingSeries{
+			Frequency:   *payload.Recurrence.Frequency,
+			Occurrences: *payload.Recurrence.Occurrences,
+		}
+		for i := 1; i < int(series.Occurrences); i++ {
+			occurrence := booking
+			occurrence.StartsAt = booking.StartsAt.AddDate(0, 0, i*days)
+			occurrence.EndsAt = booking.EndsAt.AddDate(0, 0, i*days)
+			bookings = append(bookings, occurrence)
+		}
+	}
+
+	if err := s.BookingRepository.Book(ctx, bookings, series); err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	result := []dto.BookingResponse{}
+	for i := range bookings {
+		result = append(result, newBookingResponse(&bookings[i]))
+	}
+	return result, nil
+}
+
+// Cancel cancels the booking, and the following occurrences of its series when payload.Series
+// is set. Cancelling a cancelled booking changes nothing.
+func (s *service) Cancel(ctx context.Context, payload *dto.CancelBookingRequest) ([]dto.BookingResponse, error) {
+	booking, err := s.BookingRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+	if !isOwner(&booking, payload) {
+		return nil, res.ErrorBuilder(res.ErrorConstant.Unauthorized, fmt.Errorf("booking %d belongs to someone else", booking.ID))
+	}
+	if !res.IfMatch(payload.IfMatch, booking.Version) {
+		return nil, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("booking version does not match If-Match"))
+	}
+
+	cancelled := []model.Booking{booking}
+	if booking.CancelledAt == nil {
+		if cancelled, err = s.BookingRepository.Cancel(ctx, &booking, payload.Series, time.Now()); err != nil {
+			return nil, util.RepositoryErrorBuilder(err)
+		}
+	}
+
+	result := []dto.BookingResponse{}
+	for i := range cancelled {
+		result = append(result, newBookingResponse(&cancelled[i]))
+	}
+	return result, nil
+}
+
+// isOwner reports whether the booking belongs to the owner of the request, any booking does
+// when the request has no owner.
+func isOwner(booking *model.Booking, payload *dto.CancelBookingRequest) bool {
+	if payload.OwnerStudentID == nil && payload.OwnerClassID == nil {
+		return true
+	}
+	if booking.StudentID != nil && payload.OwnerStudentID != nil && *booking.StudentID == *payload.OwnerStudentID {
+		return true
+	}
+	return booking.ClassID != nil && payload.OwnerClassID != nil && *booking.ClassID == *payload.OwnerClassID
+}
+
+func newBookingResponse(booking *model.Booking) dto.BookingResponse {
+	return dto.BookingResponse{
+		ID:          booking.ID,
+		RoomID:      booking.RoomID,
+		StudentID:   booking.StudentID,
+		ClassID:     booking.ClassID,
+		StartsAt:    booking.StartsAt,
+		EndsAt:      booking.EndsAt,
+		Purpose:     booking.Purpose,
+		SeriesID:    booking.SeriesID,
+		CancelledAt: booking.CancelledAt,
+		Version:     booking.Version,
+	}
+}
@@ -0,0 +1,82 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"student-service/database"
+	"student-service/database/seeder"
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	res "student-service/pkg/util/response"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ctx            = context.Background()
+	bookingService = NewService(factory.NewFactory())
+)
+
+func TestBookingServiceStoreConcurrentOverlap(t *testing.T) {
+	// the overlap check relies on the row lock taken on the room
+	var versionComment string
+	database.GetConnection().Raw("SELECT @@version_comment").Scan(&versionComment)
+	if strings.Contains(versionComment, "Dolt") {
+		t.Skip("the database server does not lock rows in transactions")
+	}
+
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	now := time.Now()
+	startsAt := time.Date(now.Year(), now.Month(), now.Day()+2, 9, 0, 0, 0, time.Local)
+	endsAt := startsAt.Add(time.Hour)
+	roomID := uint(2)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		booked    int
+		conflicts int
+		others    []error
+	)
+	for _, studentID := range []uint{1, 2, 3} {
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func(studentID uint) {
+				defer wg.Done()
+				_, err := bookingService.Store(ctx, &dto.CreateBookingRequestBody{
+					RoomID:    &roomID,
+					StudentID: &studentID,
+					StartsAt:  &startsAt,
+					EndsAt:    &endsAt,
+				})
+				mu.Lock()
+				defer mu.Unlock()
+				switch {
+				case err == nil:
+					booked++
+				case errors.Is(err, res.ErrorConstant.BookingConflict):
+					conflicts++
+				default:
+					others = append(others, err)
+				}
+			}(studentID)
+		}
+	}
+	wg.Wait()
+
+	asserts := assert.New(t)
+	asserts.Empty(others)
+	asserts.Equal(1, booked)
+	asserts.Equal(8, conflicts)
+
+	var count int64
+	database.GetConnection().Table("bookings").Where("room_id = ?", roomID).Count(&count)
+	asserts.Equal(int64(1), count)
+}
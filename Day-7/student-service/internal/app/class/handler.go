@@ -6,21 +6,25 @@ import (
 
 	"student-service/internal/dto"
 	"student-service/internal/factory"
+	"student-service/internal/middleware"
 	"student-service/internal/pkg/enum"
 	"student-service/internal/pkg/util"
 	pkgdto "student-service/pkg/dto"
+	"student-service/pkg/i18n"
 	res "student-service/pkg/util/response"
 
 	"github.com/labstack/echo/v4"
 )
 
 type handler struct {
-	service Service
+	service     Service
+	idempotency echo.MiddlewareFunc
 }
 
 func NewHandler(f *factory.Factory) *handler {
 	return &handler{
-		service: NewService(f),
+		service:     NewService(f),
+		idempotency: middleware.IdempotencyMiddleware(f.IdempotencyKeyRepository),
 	}
 }
 
@@ -28,18 +32,18 @@ func (h *handler) Get(c echo.Context) error {
 	authHeader := c.Request().Header.Get("Authorization")
 	jwtClaims, err := util.ParseJWTToken(authHeader)
 	if (err != nil) || (jwtClaims.ClassID != uint(enum.A)) {
-		return res.ErrorBuilder(&res.ErrorConstant.Unauthorized, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
 	}
 
 	log.Println(jwtClaims)
 
 	payload := new(pkgdto.SearchGetRequest)
 	if err := c.Bind(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.BadRequest, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
 	}
 
-	if err := c.Validate(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.Validation, err).Send(c)
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
 	}
 
 	result, err := h.service.Find(c.Request().Context(), payload)
@@ -47,24 +51,24 @@ func (h *handler) Get(c echo.Context) error {
 		return res.ErrorResponse(err).Send(c)
 	}
 
-	return res.CustomSuccessBuilder(http.StatusOK, result.Data, "Get classes success", &result.PaginationInfo).Send(c)
+	return res.CustomSuccessBuilder(http.StatusOK, result.Data, i18n.GetClassesSuccess, &result.PaginationInfo).Send(c)
 }
 
 func (h *handler) GetById(c echo.Context) error {
 	authHeader := c.Request().Header.Get("Authorization")
 	jwtClaims, err := util.ParseJWTToken(authHeader)
 	if (err != nil) || (jwtClaims.ClassID != uint(enum.A)) {
-		return res.ErrorBuilder(&res.ErrorConstant.Unauthorized, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
 	}
 
 	log.Println(jwtClaims)
 
 	payload := new(pkgdto.ByIDRequest)
 	if err := c.Bind(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.BadRequest, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
 	}
-	if err := c.Validate(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.Validation, err).Send(c)
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
 	}
 
 	result, err := h.service.FindByID(c.Request().Context(), payload)
@@ -72,6 +76,7 @@ func (h *handler) GetById(c echo.Context) error {
 		return res.ErrorResponse(err).Send(c)
 	}
 
+	c.Response().Header().Set(res.HeaderETag, res.ETag(result.Version))
 	return res.SuccessResponse(result).Send(c)
 }
 
@@ -79,23 +84,50 @@ func (h *handler) UpdateById(c echo.Context) error {
 	authHeader := c.Request().Header.Get("Authorization")
 	jwtClaims, err := util.ParseJWTToken(authHeader)
 	if (err != nil) || (jwtClaims.ClassID != uint(enum.A)) {
-		return res.ErrorBuilder(&res.ErrorConstant.Unauthorized, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
 	}
 
 	log.Println(jwtClaims)
 
 	payload := new(dto.UpdateClassRequestBody)
 	if err := c.Bind(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.BadRequest, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
 	}
-	if err := c.Validate(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.Validation, err).Send(c)
+	payload.IfMatch = c.Request().Header.Get(res.HeaderIfMatch)
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
 	}
 	result, err := h.service.UpdateById(c.Request().Context(), payload)
 	if err != nil {
 		return res.ErrorResponse(err).Send(c)
 	}
 
+	c.Response().Header().Set(res.HeaderETag, res.ETag(result.Version))
+	return res.SuccessResponse(result).Send(c)
+}
+
+func (h *handler) PatchById(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if (err != nil) || (jwtClaims.ClassID != uint(enum.A)) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	log.Println(jwtClaims)
+
+	payload := new(pkgdto.PatchByIDRequest)
+	if err := util.BindPatch(c, payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+	result, err := h.service.PatchById(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	c.Response().Header().Set(res.HeaderETag, res.ETag(result.Version))
 	return res.SuccessResponse(result).Send(c)
 }
 
@@ -103,17 +135,18 @@ func (h *handler) DeleteById(c echo.Context) error {
 	authHeader := c.Request().Header.Get("Authorization")
 	jwtClaims, err := util.ParseJWTToken(authHeader)
 	if (err != nil) || (jwtClaims.ClassID != uint(enum.A)) {
-		return res.ErrorBuilder(&res.ErrorConstant.Unauthorized, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
 	}
 
 	log.Println(jwtClaims)
 
 	payload := new(pkgdto.ByIDRequest)
 	if err := c.Bind(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.BadRequest, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
 	}
-	if err := c.Validate(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.Validation, err).Send(c)
+	payload.IfMatch = c.Request().Header.Get(res.HeaderIfMatch)
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
 	}
 	result, err := h.service.DeleteById(c.Request().Context(), payload)
 	if err != nil {
@@ -127,17 +160,17 @@ func (h *handler) Create(c echo.Context) error {
 	authHeader := c.Request().Header.Get("Authorization")
 	jwtClaims, err := util.ParseJWTToken(authHeader)
 	if (err != nil) || (jwtClaims.ClassID != uint(enum.A)) {
-		return res.ErrorBuilder(&res.ErrorConstant.Unauthorized, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
 	}
 
 	log.Println(jwtClaims)
 
 	payload := new(dto.CreateClassRequestBody)
 	if err := c.Bind(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.BadRequest, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
 	}
-	if err := c.Validate(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.Validation, err).Send(c)
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
 	}
 
 	class, err := h.service.Store(c.Request().Context(), payload)
@@ -145,5 +178,109 @@ func (h *handler) Create(c echo.Context) error {
 		return res.ErrorResponse(err).Send(c)
 	}
 
+	c.Response().Header().Set(res.HeaderETag, res.ETag(class.Version))
 	return res.SuccessResponse(class).Send(c)
 }
+
+// GetStudents returns the students of the class, in a term when term_id is set.
+func (h *handler) GetStudents(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if (err != nil) || (jwtClaims.ClassID != uint(enum.A)) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(dto.ByIDInTermRequest)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	result, err := h.service.FindStudents(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	return res.SuccessResponse(result).Send(c)
+}
+
+// GetAttendance returns the attendance summary of the class in a term, the active term when
+// term_id is not set.
+func (h *handler) GetAttendance(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if (err != nil) || (jwtClaims.ClassID != uint(enum.A)) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(dto.ByIDInTermRequest)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	result, err := h.service.Attendance(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	return res.SuccessResponse(result).Send(c)
+}
+
+func (h *handler) BatchCreate(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if (err != nil) || (jwtClaims.ClassID != uint(enum.A)) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(pkgdto.BatchRequest[dto.CreateClassRequestBody])
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	return h.service.BatchStore(c.Request().Context(), payload).Send(c)
+}
+
+func (h *handler) BatchUpdate(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if (err != nil) || (jwtClaims.ClassID != uint(enum.A)) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(pkgdto.BatchRequest[dto.UpdateClassRequestBody])
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	return h.service.BatchUpdate(c.Request().Context(), payload).Send(c)
+}
+
+func (h *handler) BatchDelete(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if (err != nil) || (jwtClaims.ClassID != uint(enum.A)) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(pkgdto.BatchRequest[pkgdto.ByIDRequest])
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	return h.service.BatchDelete(c.Request().Context(), payload).Send(c)
+}
@@ -2,6 +2,7 @@ package class
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -13,6 +14,7 @@ import (
 	"student-service/internal/dto"
 	"student-service/internal/factory"
 	"student-service/internal/mocks"
+	"student-service/internal/model"
 	"student-service/internal/pkg/enum"
 	"student-service/internal/pkg/util"
 	"student-service/internal/repository"
@@ -22,10 +24,17 @@ import (
 )
 
 var (
-	adminClaims       = util.CreateJWTClaims(testEmail, testStudentID, testAClassID, testMajorID)
-	db                = database.GetConnection()
-	echoMock          = mocks.EchoMock{E: echo.New()}
-	f                 = factory.Factory{ClassRepository: repository.NewClassRepository(db)}
+	adminClaims = util.CreateJWTClaims(testEmail, testStudentID, testAClassID, testMajorID)
+	db          = database.GetConnection()
+	echoMock    = mocks.EchoMock{E: echo.New()}
+	f           = factory.Factory{
+		ClassRepository:   repository.NewClassRepository(db),
+		StudentRepository: repository.NewStudentRepository(db),
+		TermRepository:    repository.NewTermRepository(db),
+		Transactor:        repository.NewTransactor(db),
+
+		ClassSessionRepository: repository.NewClassSessionRepository(db),
+	}
 	classHandler      = NewHandler(&f)
 	testAClassID      = uint(enum.A)
 	testCreatePayload = dto.CreateClassRequestBody{Name: &testClassName}
@@ -512,3 +521,188 @@ func TestClassHandlerCreateSuccess(t *testing.T) {
 		asserts.Contains(body, "name")
 	}
 }
+
+func TestClassHandlerBatch(t *testing.T) {
+	cases := []struct {
+		name     string
+		method   string
+		payload  string
+		code     int
+		classes  int64
+		contains []string
+	}{
+		{"atomic create", http.MethodPost, `{"items":[{"name":"C"},{"name":"D"}]}`, 200, 4, []string{`"succeeded":2`, `"mode":"atomic"`}},
+		{"atomic create rolled back", http.MethodPost, `{"items":[{"name":"C"},{"name":"C"}]}`, 409, 2, []string{`"failed":2`, `"failed_dependency"`, `"error":"duplicate"`}},
+		{"atomic create invalid item", http.MethodPost, `{"mode":"atomic","items":[{"name":"C"},{}]}`, 400, 2, []string{`"index":1,"status":400`, `"field":"name"`}},
+		{"best effort create", http.MethodPost, `{"mode":"best_effort","items":[{"name":"C"},{"name":"A"},{}]}`, 207, 3, []string{`"succeeded":1`, `"failed":2`, `"error":"duplicate"`}},
+		{"atomic update", http.MethodPut, `{"items":[{"id":1,"name":"C"},{"id":2,"name":"D"}]}`, 200, 2, []string{`"version":2`}},
+		{"atomic update not found", http.MethodPut, `{"items":[{"id":1,"name":"C"},{"id":99,"name":"D"}]}`, 404, 2, []string{`"error":"not_found"`}},
+		{"atomic update at versions", http.MethodPut, `{"items":[{"id":1,"name":"C","version":1},{"id":2,"name":"D","version":1}]}`, 200, 2, []string{`"succeeded":2`}},
+		{"atomic update stale version", http.MethodPut, `{"items":[{"id":1,"name":"C","version":1},{"id":2,"name":"D","version":5}]}`, 412, 2, []string{`"failed_dependency"`, `"error":"precondition_failed"`}},
+		{"best effort delete stale version", http.MethodDelete, `{"mode":"best_effort","items":[{"id":1,"version":5},{"id":2,"version":1}]}`, 207, 1, []string{`"succeeded":1`, `"error":"precondition_failed"`}},
+		{"best effort delete", http.MethodDelete, `{"mode":"best_effort","items":[{"id":1},{"id":99}]}`, 207, 1, []string{`"succeeded":1`}},
+		{"invalid mode", http.MethodDelete, `{"mode":"all","items":[{"id":1}]}`, 400, 2, []string{`"field":"mode"`}},
+		{"empty batch", http.MethodPost, `{"items":[]}`, 400, 2, []string{`"field":"items"`}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			seeder.NewSeeder().SeedAll()
+
+			token, err := util.CreateJWTToken(adminClaims)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c, rec := echoMock.RequestMock(tc.method, "/", bytes.NewBufferString(tc.payload))
+			c.SetPath("/api/v1/classes/batch")
+			c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+			c.Request().Header.Set("Content-Type", "application/json")
+
+			var handle echo.HandlerFunc
+			switch tc.method {
+			case http.MethodPost:
+				handle = classHandler.BatchCreate
+			case http.MethodPut:
+				handle = classHandler.BatchUpdate
+			default:
+				handle = classHandler.BatchDelete
+			}
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(handle(c)) {
+				asserts.Equal(tc.code, rec.Code)
+
+				body := rec.Body.String()
+				for _, s := range tc.contains {
+					asserts.Contains(body, s)
+				}
+
+				var count int64
+				db.Model(&model.Class{}).Count(&count)
+				asserts.Equal(tc.classes, count)
+			}
+		})
+	}
+}
+
+func TestClassHandlerBatchProblemDetails(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	token, err := util.CreateJWTToken(adminClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, rec := echoMock.RequestMock(http.MethodPost, "/", bytes.NewBufferString(`{"mode":"best_effort","items":[{"name":"A"}]}`))
+	c.SetPath("/api/v1/classes/batch")
+	c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	c.Request().Header.Set("Content-Type", "application/json")
+	c.Request().Header.Set("Accept", "application/problem+json")
+
+	// testing
+	asserts := assert.New(t)
+	if asserts.NoError(classHandler.BatchCreate(c)) {
+		asserts.Equal(207, rec.Code)
+		asserts.Contains(rec.Body.String(), `"type":"/problems/duplicate"`)
+	}
+}
+
+func TestClassHandlerGetStudentsByTerm(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	// close the current term, then move Bettina from class B to class A
+	ctx := context.Background()
+	term, err := f.TermRepository.FindByID(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.TermRepository.Close(ctx, &term); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&model.Student{}).Where("id = ?", 3).Update("class_id", testAClassID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := util.CreateJWTToken(adminClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		termID   string
+		contains []string
+		excludes []string
+	}{
+		{"current class", "", []string{"vincentlhubbard@edu.ac.id", "bettinameaster@edu.ac.id"}, nil},
+		{"closed term", "2", []string{"vincentlhubbard@edu.ac.id"}, []string{"bettinameaster@edu.ac.id"}},
+		{"open term", "3", []string{"vincentlhubbard@edu.ac.id", "bettinameaster@edu.ac.id"}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, rec := echoMock.RequestMock(http.MethodGet, "/", nil)
+			c.SetPath("/api/v1/classes/:id/students")
+			c.SetParamNames("id")
+			c.SetParamValues(strconv.Itoa(int(testAClassID)))
+			if tc.termID != "" {
+				c.QueryParams().Set("term_id", tc.termID)
+			}
+			c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(classHandler.GetStudents(c)) {
+				asserts.Equal(200, rec.Code)
+
+				body := rec.Body.String()
+				for _, s := range tc.contains {
+					asserts.Contains(body, s)
+				}
+				for _, s := range tc.excludes {
+					asserts.NotContains(body, s)
+				}
+			}
+		})
+	}
+}
+
+func TestClassHandlerGetAttendance(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	token, err := util.CreateJWTToken(adminClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, rec := echoMock.RequestMock(http.MethodGet, "/", nil)
+	c.SetPath("/api/v1/classes/:id/attendances")
+	c.SetParamNames("id")
+	c.SetParamValues(strconv.Itoa(int(testBClassID)))
+	c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	// testing
+	asserts := assert.New(t)
+	if asserts.NoError(classHandler.GetAttendance(c)) {
+		asserts.Equal(200, rec.Code)
+
+		body := rec.Body.String()
+		asserts.Contains(body, `"class_id":2,"term_id":2,"sessions":2,"present":1,"late":1,"absent":1,"excused":1,"rate":66.67`)
+		asserts.Contains(body, `"email":"devoncthomas@edu.ac.id"`)
+		asserts.Contains(body, `"term_id":2,"present":1,"late":0,"absent":1,"excused":0,"rate":50}`)
+		asserts.Contains(body, `"term_id":2,"present":0,"late":1,"absent":0,"excused":1,"rate":100}`)
+	}
+
+	c, rec = echoMock.RequestMock(http.MethodGet, "/", nil)
+	c.SetParamNames("id")
+	c.SetParamValues(strconv.Itoa(int(testAClassID)))
+	c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	if asserts.NoError(classHandler.GetAttendance(c)) {
+		asserts.Equal(200, rec.Code)
+		asserts.Contains(rec.Body.String(), `"sessions":0,"present":0,"late":0,"absent":0,"excused":0,"rate":null`)
+	}
+}
@@ -3,45 +3,81 @@ package class
 import (
 	"context"
 	"errors"
+	"net/http"
 
+	"student-service/internal/app/outbox"
 	"student-service/internal/dto"
 	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
 	"student-service/internal/repository"
-	"student-service/pkg/constant"
+	"student-service/pkg/cache"
 	pkgdto "student-service/pkg/dto"
+	pkgutil "student-service/pkg/util"
 	res "student-service/pkg/util/response"
 )
 
 type service struct {
-	ClassRepository repository.Class
+	ClassRepository   repository.Class
+	StudentRepository repository.Student
+	TermRepository    repository.Term
+	Validator         *pkgutil.CustomValidator
+	Transactor        repository.Transactor
+	Cache             cache.Cache
+	Events            outbox.Recorder
+
+	ClassSessionRepository repository.ClassSession
 }
 
 type Service interface {
 	Find(ctx context.Context, payload *pkgdto.SearchGetRequest) (*pkgdto.SearchGetResponse[dto.ClassResponse], error)
 	FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.ClassResponse, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]dto.ClassResponse, error)
 	Store(ctx context.Context, payload *dto.CreateClassRequestBody) (*dto.ClassResponse, error)
 	UpdateById(ctx context.Context, payload *dto.UpdateClassRequestBody) (*dto.ClassResponse, error)
+	PatchById(ctx context.Context, payload *pkgdto.PatchByIDRequest) (*dto.ClassResponse, error)
 	DeleteById(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.ClassWithCUDResponse, error)
+	FindStudents(ctx context.Context, payload *dto.ByIDInTermRequest) ([]dto.StudentResponse, error)
+	Attendance(ctx context.Context, payload *dto.ByIDInTermRequest) (*dto.ClassAttendanceResponse, error)
+	BatchStore(ctx context.Context, payload *pkgdto.BatchRequest[dto.CreateClassRequestBody]) *res.Batch
+	BatchUpdate(ctx context.Context, payload *pkgdto.BatchRequest[dto.UpdateClassRequestBody]) *res.Batch
+	BatchDelete(ctx context.Context, payload *pkgdto.BatchRequest[pkgdto.ByIDRequest]) *res.Batch
 }
 
 func NewService(f *factory.Factory) Service {
 	return &service{
-		ClassRepository: f.ClassRepository,
+		ClassRepository:   f.ClassRepository,
+		StudentRepository: f.StudentRepository,
+		TermRepository:    f.TermRepository,
+		Validator:         f.NewValidator(),
+		Transactor:        f.Transactor,
+		Cache:             f.Cache,
+		Events:            outbox.NewRecorder(f),
+
+		ClassSessionRepository: f.ClassSessionRepository,
 	}
 }
 
 func (s *service) Find(ctx context.Context, payload *pkgdto.SearchGetRequest) (*pkgdto.SearchGetResponse[dto.ClassResponse], error) {
+	return cache.Fetch(ctx, s.Cache, cache.Key(util.ClassCachePrefix+"list:", payload), func() (*pkgdto.SearchGetResponse[dto.ClassResponse], error) {
+		return s.find(ctx, payload)
+	})
+}
+
+func (s *service) find(ctx context.Context, payload *pkgdto.SearchGetRequest) (*pkgdto.SearchGetResponse[dto.ClassResponse], error) {
 	classes, info, err := s.ClassRepository.FindAll(ctx, payload, &payload.Pagination)
 	if err != nil {
-		return nil, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return nil, util.RepositoryErrorBuilder(err)
 	}
 
 	var data []dto.ClassResponse
 
 	for _, class := range classes {
 		data = append(data, dto.ClassResponse{
-			ID:   class.ID,
-			Name: class.Name,
+			ID:      class.ID,
+			Name:    class.Name,
+			Version: class.Version,
 		})
 
 	}
@@ -53,83 +89,308 @@ func (s *service) Find(ctx context.Context, payload *pkgdto.SearchGetRequest) (*
 	return result, nil
 }
 func (s *service) FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.ClassResponse, error) {
+	return cache.Fetch(ctx, s.Cache, cache.Key(util.ClassCachePrefix+"id:", payload), func() (*dto.ClassResponse, error) {
+		return s.findByID(ctx, payload)
+	})
+}
+
+func (s *service) findByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.ClassResponse, error) {
 	var result dto.ClassResponse
 	data, err := s.ClassRepository.FindByID(ctx, payload.ID)
 	if err != nil {
-		if err == constant.RECORD_NOT_FOUND {
-			return &dto.ClassResponse{}, res.ErrorBuilder(&res.ErrorConstant.NotFound, err)
-		}
-		return &dto.ClassResponse{}, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return &dto.ClassResponse{}, util.RepositoryErrorBuilder(err)
 	}
 
 	result.ID = data.ID
 	result.Name = data.Name
+	result.Version = data.Version
 
 	return &result, nil
 }
 
+// FindByIDs returns the classes with the ids in one query, ordered by id. Missing ones are left
+// out.
+func (s *service) FindByIDs(ctx context.Context, ids []uint) ([]dto.ClassResponse, error) {
+	classes, err := s.ClassRepository.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	result := make([]dto.ClassResponse, 0, len(classes))
+	for _, class := range classes {
+		result = append(result, dto.ClassResponse{
+			ID:      class.ID,
+			Name:    class.Name,
+			Version: class.Version,
+		})
+	}
+	return result, nil
+}
+
 func (s *service) Store(ctx context.Context, payload *dto.CreateClassRequestBody) (*dto.ClassResponse, error) {
+	defer s.invalidate(ctx)
+
 	var result dto.ClassResponse
 	isExist, err := s.ClassRepository.ExistByName(ctx, *payload.Name)
 	if err != nil {
-		return &result, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return &result, util.RepositoryErrorBuilder(err)
 	}
 	if isExist {
-		return &result, res.ErrorBuilder(&res.ErrorConstant.Duplicate, errors.New("class already exists"))
+		return &result, res.ErrorBuilder(res.ErrorConstant.Duplicate, errors.New("class already exists"))
 	}
 
-	data, err := s.ClassRepository.Save(ctx, payload)
+	err = s.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		data, err := s.ClassRepository.Save(ctx, payload)
+		if err != nil {
+			return err
+		}
+
+		result.ID = data.ID
+		result.Name = data.Name
+		result.Version = data.Version
+
+		return s.Events.Record(ctx, enum.ClassCreated, data.ID, &result)
+	})
 	if err != nil {
-		return &result, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return &dto.ClassResponse{}, util.RepositoryErrorBuilder(err)
 	}
 
-	result.ID = data.ID
-	result.Name = data.Name
-
 	return &result, nil
 }
 
 func (s *service) UpdateById(ctx context.Context, payload *dto.UpdateClassRequestBody) (*dto.ClassResponse, error) {
+	defer s.invalidate(ctx)
+
 	class, err := s.ClassRepository.FindByID(ctx, *payload.ID)
 	if err != nil {
-		if err == constant.RECORD_NOT_FOUND {
-			return &dto.ClassResponse{}, res.ErrorBuilder(&res.ErrorConstant.NotFound, err)
+		return &dto.ClassResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	if !res.IfMatch(payload.IfMatch, class.Version) {
+		return &dto.ClassResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("class version does not match If-Match"))
+	}
+
+	var result dto.ClassResponse
+	err = s.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.ClassRepository.Edit(ctx, &class, payload); err != nil {
+			return err
 		}
-		return &dto.ClassResponse{}, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		result.ID = class.ID
+		result.Name = class.Name
+		result.Version = class.Version
+
+		return s.Events.Record(ctx, enum.ClassUpdated, class.ID, &result)
+	})
+	if err != nil {
+		return &dto.ClassResponse{}, util.RepositoryErrorBuilder(err)
 	}
 
-	_, err = s.ClassRepository.Edit(ctx, &class, payload)
+	return &result, nil
+}
+
+func (s *service) PatchById(ctx context.Context, payload *pkgdto.PatchByIDRequest) (*dto.ClassResponse, error) {
+	defer s.invalidate(ctx)
+
+	class, err := s.ClassRepository.FindByID(ctx, payload.ID)
 	if err != nil {
-		return &dto.ClassResponse{}, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return &dto.ClassResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	if !res.IfMatch(payload.IfMatch, class.Version) {
+		return &dto.ClassResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("class version does not match If-Match"))
+	}
+
+	document := dto.ClassPatchDocument{Name: class.Name}
+	var patched dto.ClassPatchDocument
+	if err := pkgutil.ApplyPatch(payload.ContentType, payload.Patch, &document, &patched); err != nil {
+		return &dto.ClassResponse{}, util.PatchErrorBuilder(err)
+	}
+	patched.ID = class.ID
+	if err := s.Validator.ValidateCtx(ctx, &patched); err != nil {
+		return &dto.ClassResponse{}, res.ValidationErrorBuilder(err)
 	}
+
 	var result dto.ClassResponse
-	result.ID = class.ID
-	result.Name = class.Name
+	err = s.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.ClassRepository.Patch(ctx, &class, &patched); err != nil {
+			return err
+		}
+		result.ID = class.ID
+		result.Name = class.Name
+		result.Version = class.Version
+
+		return s.Events.Record(ctx, enum.ClassUpdated, class.ID, &result)
+	})
+	if err != nil {
+		return &dto.ClassResponse{}, util.RepositoryErrorBuilder(err)
+	}
 
 	return &result, nil
 }
+
 func (s *service) DeleteById(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.ClassWithCUDResponse, error) {
+	defer s.invalidate(ctx)
+
 	class, err := s.ClassRepository.FindByID(ctx, payload.ID)
 	if err != nil {
-		if err == constant.RECORD_NOT_FOUND {
-			return &dto.ClassWithCUDResponse{}, res.ErrorBuilder(&res.ErrorConstant.NotFound, err)
-		}
-		return &dto.ClassWithCUDResponse{}, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return &dto.ClassWithCUDResponse{}, util.RepositoryErrorBuilder(err)
 	}
-	_, err = s.ClassRepository.Destroy(ctx, &class)
+	if !res.IfMatch(payload.IfMatch, class.Version) {
+		return &dto.ClassWithCUDResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("class version does not match If-Match"))
+	}
+	var result *dto.ClassWithCUDResponse
+	err = s.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.ClassRepository.Destroy(ctx, &class); err != nil {
+			return err
+		}
+		result = toClassWithCUDResponse(&class)
+
+		return s.Events.Record(ctx, enum.ClassDeleted, class.ID, result)
+	})
 	if err != nil {
-		return &dto.ClassWithCUDResponse{}, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return &dto.ClassWithCUDResponse{}, util.RepositoryErrorBuilder(err)
 	}
 
-	result := &dto.ClassWithCUDResponse{
+	return result, nil
+}
+
+func toClassWithCUDResponse(class *model.Class) *dto.ClassWithCUDResponse {
+	return &dto.ClassWithCUDResponse{
 		ClassResponse: dto.ClassResponse{
-			ID:   class.ID,
-			Name: class.Name,
+			ID:      class.ID,
+			Name:    class.Name,
+			Version: class.Version,
 		},
 		CreatedAt: class.CreatedAt,
 		UpdatedAt: class.UpdatedAt,
 		DeletedAt: class.DeletedAt,
 	}
+}
+
+// FindStudents returns the students of the class. In a closed term they are the students who
+// were in the class when it was closed, otherwise the students currently in the class.
+func (s *service) FindStudents(ctx context.Context, payload *dto.ByIDInTermRequest) ([]dto.StudentResponse, error) {
+	if _, err := s.ClassRepository.FindByID(ctx, payload.ID); err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	var closedTermID *uint
+	if payload.TermID != nil {
+		term, err := util.FindTerm(ctx, s.TermRepository, payload.TermID)
+		if err != nil {
+			return nil, err
+		}
+		if term.Closed {
+			closedTermID = &term.ID
+		}
+	}
+
+	students, err := s.StudentRepository.FindByClassID(ctx, payload.ID, closedTermID)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	result := []dto.StudentResponse{}
+	for _, student := range students {
+		result = append(result, dto.StudentResponse{
+			ID:            student.ID,
+			StudentNumber: student.StudentNumber,
+			Fullname:      student.Fullname,
+			Email:         student.Email,
+			Version:       student.Version,
+		})
+	}
 
 	return result, nil
 }
+
+// Attendance summarizes the attendance in the sessions of the class in the term, the active term
+// when it is not set. The students are those in the class during the term, the totals count
+// every attendance in the sessions of the class, also of students who left it.
+func (s *service) Attendance(ctx context.Context, payload *dto.ByIDInTermRequest) (*dto.ClassAttendanceResponse, error) {
+	if _, err := s.ClassRepository.FindByID(ctx, payload.ID); err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+	term, err := util.FindTerm(ctx, s.TermRepository, payload.TermID)
+	if err != nil {
+		return nil, err
+	}
+
+	var closedTermID *uint
+	if term.Closed {
+		closedTermID = &term.ID
+	}
+	students, err := s.StudentRepository.FindByClassID(ctx, payload.ID, closedTermID)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+	sessions, err := s.ClassSessionRepository.CountSessions(ctx, payload.ID, term.ID)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+	counts, err := s.ClassSessionRepository.CountAttendance(ctx, term.ID, &payload.ID, nil)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	byStudent := map[uint][]repository.AttendanceCount{}
+	for _, count := range counts {
+		byStudent[count.StudentID] = append(byStudent[count.StudentID], count)
+	}
+
+	result := &dto.ClassAttendanceResponse{
+		ClassID:                   payload.ID,
+		TermID:                    term.ID,
+		Sessions:                  sessions,
+		AttendanceSummaryResponse: util.AttendanceSummary(counts...),
+		Students:                  []dto.StudentAttendanceResponse{},
+	}
+	for _, student := range students {
+		result.Students = append(result.Students, dto.StudentAttendanceResponse{
+			Student: dto.StudentResponse{
+				ID:            student.ID,
+				StudentNumber: student.StudentNumber,
+				Fullname:      student.Fullname,
+				Email:         student.Email,
+				Version:       student.Version,
+			},
+			TermID:                    term.ID,
+			AttendanceSummaryResponse: util.AttendanceSummary(byStudent[student.ID]...),
+		})
+	}
+
+	return result, nil
+}
+
+func (s *service) BatchStore(ctx context.Context, payload *pkgdto.BatchRequest[dto.CreateClassRequestBody]) *res.Batch {
+	defer s.invalidate(ctx)
+
+	return util.RunBatch(ctx, s.Transactor, payload, http.StatusOK, s.Validator.ValidateCtx,
+		func(ctx context.Context, item *dto.CreateClassRequestBody) (interface{}, error) {
+			return s.Store(ctx, item)
+		})
+}
+
+func (s *service) BatchUpdate(ctx context.Context, payload *pkgdto.BatchRequest[dto.UpdateClassRequestBody]) *res.Batch {
+	defer s.invalidate(ctx)
+
+	return util.RunBatch(ctx, s.Transactor, payload, http.StatusOK, s.Validator.ValidateCtx,
+		func(ctx context.Context, item *dto.UpdateClassRequestBody) (interface{}, error) {
+			return s.UpdateById(ctx, item)
+		})
+}
+
+func (s *service) BatchDelete(ctx context.Context, payload *pkgdto.BatchRequest[pkgdto.ByIDRequest]) *res.Batch {
+	defer s.invalidate(ctx)
+
+	return util.RunBatch(ctx, s.Transactor, payload, http.StatusOK, s.Validator.ValidateCtx,
+		func(ctx context.Context, item *pkgdto.ByIDRequest) (interface{}, error) {
+			return s.DeleteById(ctx, item)
+		})
+}
+
+// invalidate drops the cached classes, and the cached students, which include their class. Batches
+// invalidate again once they are done, reads during an atomic batch may cache uncommitted data.
+func (s *service) invalidate(ctx context.Context) {
+	cache.Invalidate(ctx, s.Cache, util.ClassCachePrefix, util.StudentCachePrefix)
+}
@@ -3,12 +3,16 @@ package class
 import (
 	"context"
 	"testing"
+	"time"
 
 	"student-service/database"
 	"student-service/database/seeder"
 	"student-service/internal/dto"
 	"student-service/internal/factory"
+	"student-service/internal/model"
 	"student-service/internal/pkg/enum"
+	"student-service/internal/repository"
+	"student-service/pkg/cache"
 	pkgdto "student-service/pkg/dto"
 
 	"github.com/stretchr/testify/assert"
@@ -186,3 +190,177 @@ func TestClassServiceCreateClassAlreadyExist(t *testing.T) {
 		asserts.Equal(err.Error(), "error code 409")
 	}
 }
+
+func TestClassServiceUpdateByIdDuplicateName(t *testing.T) {
+	database.GetConnection()
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	var (
+		asserts = assert.New(t)
+		id      = uint(enum.B)
+		name    = enum.Class(testAClassID).String()
+		payload = dto.UpdateClassRequestBody{
+			ID:   &id,
+			Name: &name,
+		}
+	)
+
+	_, err := classService.UpdateById(ctx, &payload)
+	if asserts.Error(err) {
+		asserts.Equal(err.Error(), "error code 409")
+	}
+}
+
+func TestClassServiceUpdateByIdIfMatch(t *testing.T) {
+	database.GetConnection()
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	var (
+		asserts = assert.New(t)
+		id      = uint(1)
+		name    = "Finance Dept."
+		payload = dto.UpdateClassRequestBody{
+			ID:      &id,
+			Name:    &name,
+			IfMatch: `"1"`,
+		}
+	)
+
+	res, err := classService.UpdateById(ctx, &payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	asserts.Equal(uint(2), res.Version)
+
+	_, err = classService.UpdateById(ctx, &payload)
+	if asserts.Error(err) {
+		asserts.Equal(err.Error(), "error code 412")
+	}
+}
+
+func TestClassServiceUpdateByIdStaleVersion(t *testing.T) {
+	db := database.GetConnection()
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	var (
+		asserts         = assert.New(t)
+		classRepository = repository.NewClassRepository(db)
+		first           = "Finance Dept."
+		second          = "Marketing Dept."
+	)
+
+	class, err := classRepository.FindByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale := class
+
+	_, err = classRepository.Edit(ctx, &class, &dto.UpdateClassRequestBody{Name: &first})
+	if err != nil {
+		t.Fatal(err)
+	}
+	asserts.Equal(uint(2), class.Version)
+
+	_, err = classRepository.Edit(ctx, &stale, &dto.UpdateClassRequestBody{Name: &second})
+	asserts.ErrorIs(err, repository.ErrStaleVersion)
+
+	_, err = classRepository.Destroy(ctx, &stale)
+	asserts.ErrorIs(err, repository.ErrStaleVersion)
+
+	class, err = classRepository.FindByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	asserts.Equal(first, class.Name)
+}
+
+func TestClassServiceDeleteByIdIfMatch(t *testing.T) {
+	database.GetConnection()
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	var (
+		asserts = assert.New(t)
+		payload = pkgdto.ByIDRequest{ID: 1, IfMatch: `"2", W/"1"`}
+	)
+
+	_, err := classService.DeleteById(ctx, &payload)
+	if asserts.Error(err) {
+		asserts.Equal(err.Error(), "error code 412")
+	}
+
+	payload.IfMatch = `"2", "1"`
+	_, err = classService.DeleteById(ctx, &payload)
+	asserts.NoError(err)
+}
+
+func TestClassServicePatchById(t *testing.T) {
+	database.GetConnection()
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	var (
+		asserts = assert.New(t)
+		payload = pkgdto.PatchByIDRequest{
+			ByIDRequest: pkgdto.ByIDRequest{ID: 1, IfMatch: `"1"`},
+			ContentType: "application/merge-patch+json",
+			Patch:       []byte(`{"name":"Finance Dept."}`),
+		}
+	)
+
+	res, err := classService.PatchById(ctx, &payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	asserts.Equal("Finance Dept.", res.Name)
+	asserts.Equal(uint(2), res.Version)
+
+	payload.IfMatch = ""
+	payload.Patch = []byte(`{"name":null}`)
+	_, err = classService.PatchById(ctx, &payload)
+	if asserts.Error(err) {
+		asserts.Equal(err.Error(), "error code 400")
+	}
+}
+
+func TestClassServiceCacheInvalidation(t *testing.T) {
+	db := database.GetConnection()
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	f := factory.NewFactory()
+	f.Cache = cache.NewLRU(10, time.Minute)
+	service := NewService(f)
+	asserts := assert.New(t)
+
+	before, err := service.Find(ctx, &pkgdto.SearchGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&model.Class{}).Where("id = ?", before.Data[0].ID).Update("name", "Z").Error; err != nil {
+		t.Fatal(err)
+	}
+	cached, err := service.Find(ctx, &pkgdto.SearchGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	asserts.Equal(before.Data[0].Name, cached.Data[0].Name)
+
+	payload := pkgdto.PatchByIDRequest{
+		ByIDRequest: pkgdto.ByIDRequest{ID: before.Data[1].ID},
+		ContentType: "application/merge-patch+json",
+		Patch:       []byte(`{"name":"Y"}`),
+	}
+	if _, err := service.PatchById(ctx, &payload); err != nil {
+		t.Fatal(err)
+	}
+	after, err := service.Find(ctx, &pkgdto.SearchGetRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	asserts.Equal("Z", after.Data[0].Name)
+	asserts.Equal("Y", after.Data[1].Name)
+}
@@ -0,0 +1,224 @@
+package course
+
+import (
+	"net/http"
+
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/middleware"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	pkgdto "student-service/pkg/dto"
+	"student-service/pkg/i18n"
+	res "student-service/pkg/util/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	service     Service
+	idempotency echo.MiddlewareFunc
+}
+
+func NewHandler(f *factory.Factory) *handler {
+	return &handler{
+		service:     NewService(f),
+		idempotency: middleware.IdempotencyMiddleware(f.IdempotencyKeyRepository),
+	}
+}
+
+func (h *handler) Get(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	_, err := util.ParseJWTToken(authHeader)
+	if err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(dto.SearchCourseRequest)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	result, err := h.service.Find(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	return res.CustomSuccessBuilder(http.StatusOK, result.Data, i18n.GetCoursesSuccess, &result.PaginationInfo).Send(c)
+}
+
+func (h *handler) GetById(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	_, err := util.ParseJWTToken(authHeader)
+	if err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(dto.ByIDInTermRequest)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	result, err := h.service.FindByID(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	c.Response().Header().Set(res.HeaderETag, res.ETag(result.Version))
+	return res.SuccessResponse(result).Send(c)
+}
+
+func (h *handler) Create(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if (err != nil) || (jwtClaims.ClassID != uint(enum.A)) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(dto.CreateCourseRequestBody)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	result, err := h.service.Store(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	c.Response().Header().Set(res.HeaderETag, res.ETag(result.Version))
+	return res.SuccessResponse(result).Send(c)
+}
+
+func (h *handler) UpdateById(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if (err != nil) || (jwtClaims.ClassID != uint(enum.A)) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(dto.UpdateCourseRequestBody)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	payload.IfMatch = c.Request().Header.Get(res.HeaderIfMatch)
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+	result, err := h.service.UpdateById(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	c.Response().Header().Set(res.HeaderETag, res.ETag(result.Version))
+	return res.SuccessResponse(result).Send(c)
+}
+
+func (h *handler) DeleteById(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if (err != nil) || (jwtClaims.ClassID != uint(enum.A)) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(pkgdto.ByIDRequest)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	payload.IfMatch = c.Request().Header.Get(res.HeaderIfMatch)
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+	result, err := h.service.DeleteById(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	return res.SuccessResponse(result).Send(c)
+}
+
+// Enroll enrolls the authenticated student in the course. Class A students may enroll
+// another student by sending its id.
+func (h *handler) Enroll(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(dto.EnrollRequestBody)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	if payload.StudentID == nil {
+		payload.StudentID = &jwtClaims.BID
+	}
+	if (jwtClaims.BID != *payload.StudentID) && (jwtClaims.ClassID != uint(enum.A)) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, nil).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	result, err := h.service.Enroll(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	return res.SuccessResponse(result).Send(c)
+}
+
+func (h *handler) Withdraw(c echo.Context) error {
+	payload := new(dto.WithdrawRequest)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if (err != nil) || !((jwtClaims.BID == payload.StudentID) || (jwtClaims.ClassID == uint(enum.A))) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	result, err := h.service.Withdraw(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	return res.SuccessResponse(result).Send(c)
+}
+
+// GetStudents returns the roster of the course in a term.
+func (h *handler) GetStudents(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if (err != nil) || (jwtClaims.ClassID != uint(enum.A)) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(dto.ByIDInTermRequest)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	result, err := h.service.FindStudents(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	return res.SuccessResponse(result).Send(c)
+}
@@ -0,0 +1,186 @@
+package course
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"student-service/database"
+	"student-service/database/seeder"
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/mocks"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	adminClaims   = util.CreateJWTClaims(testEmail, testStudentID, uint(enum.A), testMajorID)
+	userClaims    = util.CreateJWTClaims("devoncthomas@edu.ac.id", uint(2), uint(enum.B), testMajorID)
+	db            = database.GetConnection()
+	echoMock      = mocks.EchoMock{E: echo.New()}
+	f             = factory.Factory{CourseRepository: repository.NewCourseRepository(db), TermRepository: repository.NewTermRepository(db), Transactor: repository.NewTransactor(db)}
+	courseHandler = NewHandler(&f)
+	testMajorID   = uint(enum.Finance)
+	testEmail     = "vincentlhubbard@edu.ac.id"
+	testStudentID = uint(1)
+)
+
+func courseRequest(t *testing.T, claims *dto.JWTClaims, method, payload string, names, values []string) (echo.Context, *bytes.Buffer, func() int) {
+	c, rec := echoMock.RequestMock(method, "/", bytes.NewBufferString(payload))
+	c.SetParamNames(names...)
+	c.SetParamValues(values...)
+	c.Request().Header.Set("Content-Type", "application/json")
+	if claims != nil {
+		token, err := util.CreateJWTToken(*claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+	return c, rec.Body, func() int { return rec.Code }
+}
+
+func TestCourseHandlerCreate(t *testing.T) {
+	cases := []struct {
+		name     string
+		claims   *dto.JWTClaims
+		payload  string
+		code     int
+		contains string
+	}{
+		{"success", &adminClaims, `{"code":"IT201","name":"Databases","major_id":2,"credits":3,"capacity":25}`, 200, `"enrolled":0`},
+		{"duplicate code", &adminClaims, `{"code":"IT101","name":"Databases","major_id":2,"credits":3,"capacity":25}`, 400, `"field":"code"`},
+		{"unknown major", &adminClaims, `{"code":"IT201","name":"Databases","major_id":99,"credits":3,"capacity":25}`, 400, `"field":"major_id"`},
+		{"no credits", &adminClaims, `{"code":"IT201","name":"Databases","major_id":2,"credits":0,"capacity":25}`, 400, `"field":"credits"`},
+		{"not class A", &userClaims, `{"code":"IT201","name":"Databases","major_id":2,"credits":3,"capacity":25}`, 401, "unauthorized"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			seeder.NewSeeder().SeedAll()
+
+			c, body, code := courseRequest(t, tc.claims, http.MethodPost, tc.payload, nil, nil)
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(courseHandler.Create(c)) {
+				asserts.Equal(tc.code, code())
+				asserts.Contains(body.String(), tc.contains)
+			}
+		})
+	}
+}
+
+func TestCourseHandlerEnroll(t *testing.T) {
+	cases := []struct {
+		name     string
+		claims   *dto.JWTClaims
+		courseID string
+		payload  string
+		code     int
+		contains string
+	}{
+		{"self", &userClaims, "1", `{}`, 200, `"student_id":2`},
+		{"class A enrolls another student", &adminClaims, "1", `{"student_id":3}`, 200, `"student_id":3`},
+		{"class B enrolls another student", &userClaims, "1", `{"student_id":3}`, 401, "unauthorized"},
+		{"unknown student", &adminClaims, "1", `{"student_id":99}`, 400, `"field":"student_id"`},
+		{"unknown course", &userClaims, "99", `{}`, 404, "not_found"},
+		{"course full", &userClaims, "2", `{}`, 409, "Course has no seat left"},
+		{"seat left in another term", &userClaims, "2", `{"term_id":3}`, 200, `"term_id":3`},
+		{"closed term", &userClaims, "1", `{"term_id":1}`, 409, "Term is closed"},
+		{"unknown term", &userClaims, "1", `{"term_id":99}`, 400, `"field":"term_id"`},
+		{"already enrolled", &adminClaims, "2", `{}`, 409, "already enrolled"},
+		{"unauthenticated", nil, "1", `{}`, 401, "unauthorized"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			seeder.NewSeeder().SeedAll()
+			// the second course has two seats, taken by the first and third students
+			for _, studentID := range []uint{1, 3} {
+				if _, err := f.CourseRepository.Enroll(ctx, 2, studentID, activeTermID); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			c, body, code := courseRequest(t, tc.claims, http.MethodPost, tc.payload, []string{"id"}, []string{tc.courseID})
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(courseHandler.Enroll(c)) {
+				asserts.Equal(tc.code, code())
+				asserts.Contains(body.String(), tc.contains)
+			}
+		})
+	}
+}
+
+func TestCourseHandlerWithdraw(t *testing.T) {
+	cases := []struct {
+		name      string
+		claims    *dto.JWTClaims
+		studentID string
+		code      int
+	}{
+		{"self", &userClaims, "2", 200},
+		{"class A withdraws another student", &adminClaims, "2", 200},
+		{"class B withdraws another student", &userClaims, "1", 401},
+		{"not enrolled", &adminClaims, "3", 404},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			seeder.NewSeeder().SeedAll()
+			for _, studentID := range []uint{1, 2} {
+				if _, err := f.CourseRepository.Enroll(ctx, 1, studentID, activeTermID); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			c, _, code := courseRequest(t, tc.claims, http.MethodDelete, "", []string{"id", "student_id"}, []string{"1", tc.studentID})
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(courseHandler.Withdraw(c)) {
+				asserts.Equal(tc.code, code())
+			}
+		})
+	}
+}
+
+func TestCourseHandlerGetStudents(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	for _, studentID := range []uint{2, 3} {
+		if _, err := f.CourseRepository.Enroll(ctx, 1, studentID, activeTermID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	asserts := assert.New(t)
+
+	c, body, code := courseRequest(t, &adminClaims, http.MethodGet, "", []string{"id"}, []string{"1"})
+	if asserts.NoError(courseHandler.GetStudents(c)) {
+		asserts.Equal(200, code())
+		asserts.Contains(body.String(), "devoncthomas@edu.ac.id")
+		asserts.Contains(body.String(), "bettinameaster@edu.ac.id")
+		asserts.NotContains(body.String(), testEmail)
+	}
+
+	c, body, code = courseRequest(t, &adminClaims, http.MethodGet, "", []string{"id"}, []string{"1"})
+	c.QueryParams().Set("term_id", "3")
+	if asserts.NoError(courseHandler.GetStudents(c)) {
+		asserts.Equal(200, code())
+		asserts.Contains(body.String(), `"data":[]`)
+	}
+
+	c, _, code = courseRequest(t, &userClaims, http.MethodGet, "", []string{"id"}, []string{"1"})
+	if asserts.NoError(courseHandler.GetStudents(c)) {
+		asserts.Equal(401, code())
+	}
+}
@@ -0,0 +1,287 @@
+package course
+
+import (
+	"context"
+	"errors"
+
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	"student-service/pkg/cache"
+	pkgdto "student-service/pkg/dto"
+	res "student-service/pkg/util/response"
+)
+
+type service struct {
+	CourseRepository repository.Course
+	TermRepository   repository.Term
+	Transactor       repository.Transactor
+	Cache            cache.Cache
+}
+
+type Service interface {
+	Find(ctx context.Context, payload *dto.SearchCourseRequest) (*pkgdto.SearchGetResponse[dto.CourseResponse], error)
+	FindByID(ctx context.Context, payload *dto.ByIDInTermRequest) (*dto.CourseResponse, error)
+	Store(ctx context.Context, payload *dto.CreateCourseRequestBody) (*dto.CourseResponse, error)
+	UpdateById(ctx context.Context, payload *dto.UpdateCourseRequestBody) (*dto.CourseResponse, error)
+	DeleteById(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.CourseWithCUDResponse, error)
+	Enroll(ctx context.Context, payload *dto.EnrollRequestBody) (*dto.EnrollmentResponse, error)
+	Withdraw(ctx context.Context, payload *dto.WithdrawRequest) (*dto.EnrollmentResponse, error)
+	FindStudents(ctx context.Context, payload *dto.ByIDInTermRequest) ([]dto.EnrolledStudentResponse, error)
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		CourseRepository: f.CourseRepository,
+		TermRepository:   f.TermRepository,
+		Transactor:       f.Transactor,
+		Cache:            f.Cache,
+	}
+}
+
+func (s *service) Find(ctx context.Context, payload *dto.SearchCourseRequest) (*pkgdto.SearchGetResponse[dto.CourseResponse], error) {
+	courses, info, err := s.CourseRepository.FindAll(ctx, payload, &payload.Pagination)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	term, err := s.countTerm(ctx, payload.TermID)
+	if err != nil {
+		return nil, err
+	}
+	var counts map[uint]uint
+	if term != nil {
+		courseIDs := make([]uint, 0, len(courses))
+		for _, course := range courses {
+			courseIDs = append(courseIDs, course.ID)
+		}
+		if counts, err = s.CourseRepository.CountEnrollments(ctx, term.ID, courseIDs...); err != nil {
+			return nil, util.RepositoryErrorBuilder(err)
+		}
+	}
+
+	var data []dto.CourseResponse
+	for i := range courses {
+		data = append(data, newCourseResponse(&courses[i], term, counts[courses[i].ID]))
+	}
+
+	result := new(pkgdto.SearchGetResponse[dto.CourseResponse])
+	result.Data = data
+	result.PaginationInfo = *info
+
+	return result, nil
+}
+
+func (s *service) FindByID(ctx context.Context, payload *dto.ByIDInTermRequest) (*dto.CourseResponse, error) {
+	course, err := s.CourseRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return &dto.CourseResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	return s.courseResponse(ctx, &course, payload.TermID)
+}
+
+func (s *service) Store(ctx context.Context, payload *dto.CreateCourseRequestBody) (*dto.CourseResponse, error) {
+	course, err := s.CourseRepository.Save(ctx, payload)
+	if err != nil {
+		return &dto.CourseResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	return s.courseResponse(ctx, &course, nil)
+}
+
+func (s *service) UpdateById(ctx context.Context, payload *dto.UpdateCourseRequestBody) (*dto.CourseResponse, error) {
+	course, err := s.CourseRepository.FindByID(ctx, *payload.ID)
+	if err != nil {
+		return &dto.CourseResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	if !res.IfMatch(payload.IfMatch, course.Version) {
+		return &dto.CourseResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("course version does not match If-Match"))
+	}
+	if payload.Capacity != nil {
+		enrolled, err := s.CourseRepository.MaxEnrolled(ctx, course.ID)
+		if err != nil {
+			return &dto.CourseResponse{}, util.RepositoryErrorBuilder(err)
+		}
+		if *payload.Capacity < enrolled {
+			return &dto.CourseResponse{}, res.ErrorBuilder(res.ErrorConstant.CapacityBelowEnrolled, nil)
+		}
+	}
+
+	_, err = s.CourseRepository.Edit(ctx, &course, payload)
+	if err != nil {
+		return &dto.CourseResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	return s.courseResponse(ctx, &course, nil)
+}
+
+func (s *service) DeleteById(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.CourseWithCUDResponse, error) {
+	course, err := s.CourseRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return &dto.CourseWithCUDResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	if !res.IfMatch(payload.IfMatch, course.Version) {
+		return &dto.CourseWithCUDResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("course version does not match If-Match"))
+	}
+	enrolled, err := s.CourseRepository.MaxEnrolled(ctx, course.ID)
+	if err != nil {
+		return &dto.CourseWithCUDResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	if enrolled > 0 {
+		return &dto.CourseWithCUDResponse{}, res.ErrorBuilder(res.ErrorConstant.InvalidReference, errors.New("course still has enrolled students in an open term"))
+	}
+
+	_, err = s.CourseRepository.Destroy(ctx, &course)
+	if err != nil {
+		return &dto.CourseWithCUDResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := &dto.CourseWithCUDResponse{
+		CourseResponse: newCourseResponse(&course, nil, 0),
+		CreatedAt:      course.CreatedAt,
+		UpdatedAt:      course.UpdatedAt,
+		DeletedAt:      course.DeletedAt,
+	}
+
+	return result, nil
+}
+
+func (s *service) Enroll(ctx context.Context, payload *dto.EnrollRequestBody) (*dto.EnrollmentResponse, error) {
+	term, err := util.FindTerm(ctx, s.TermRepository, payload.TermID)
+	if err != nil {
+		return &dto.EnrollmentResponse{}, err
+	}
+	if err := util.CheckTermOpen(term); err != nil {
+		return &dto.EnrollmentResponse{}, err
+	}
+
+	var enrollment model.Enrollment
+	err = util.WithinOpenTerm(ctx, s.Transactor, s.TermRepository, term.ID, func(ctx context.Context) error {
+		enrollment, err = s.CourseRepository.Enroll(ctx, payload.CourseID, *payload.StudentID, term.ID)
+		return err
+	})
+	if errors.Is(err, repository.ErrDuplicate) {
+		return &dto.EnrollmentResponse{}, res.ErrorBuilder(res.ErrorConstant.AlreadyEnrolled, err)
+	}
+	if err != nil {
+		return &dto.EnrollmentResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	// students are listed by the terms they are enrolled in
+	cache.Invalidate(ctx, s.Cache, util.StudentCachePrefix)
+
+	return &dto.EnrollmentResponse{
+		CourseID:   enrollment.CourseID,
+		StudentID:  enrollment.StudentID,
+		TermID:     enrollment.TermID,
+		EnrolledAt: enrollment.CreatedAt,
+	}, nil
+}
+
+func (s *service) Withdraw(ctx context.Context, payload *dto.WithdrawRequest) (*dto.EnrollmentResponse, error) {
+	term, err := util.FindTerm(ctx, s.TermRepository, payload.TermID)
+	if err != nil {
+		return &dto.EnrollmentResponse{}, err
+	}
+	if err := util.CheckTermOpen(term); err != nil {
+		return &dto.EnrollmentResponse{}, err
+	}
+
+	err = util.WithinOpenTerm(ctx, s.Transactor, s.TermRepository, term.ID, func(ctx context.Context) error {
+		return s.CourseRepository.Withdraw(ctx, payload.CourseID, payload.StudentID, term.ID)
+	})
+	if err != nil {
+		return &dto.EnrollmentResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	cache.Invalidate(ctx, s.Cache, util.StudentCachePrefix)
+
+	return &dto.EnrollmentResponse{
+		CourseID:  payload.CourseID,
+		StudentID: payload.StudentID,
+		TermID:    term.ID,
+	}, nil
+}
+
+// FindStudents returns the roster of the course in the term, the active term when it is not set.
+func (s *service) FindStudents(ctx context.Context, payload *dto.ByIDInTermRequest) ([]dto.EnrolledStudentResponse, error) {
+	if _, err := s.CourseRepository.FindByID(ctx, payload.ID); err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+	term, err := util.FindTerm(ctx, s.TermRepository, payload.TermID)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollments, err := s.CourseRepository.FindEnrollmentsByCourseID(ctx, payload.ID, term.ID)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	result := []dto.EnrolledStudentResponse{}
+	for _, enrollment := range enrollments {
+		result = append(result, dto.EnrolledStudentResponse{
+			StudentResponse: dto.StudentResponse{
+				ID:            enrollment.Student.ID,
+				StudentNumber: enrollment.Student.StudentNumber,
+				Fullname:      enrollment.Student.Fullname,
+				Email:         enrollment.Student.Email,
+				Version:       enrollment.Student.Version,
+			},
+			EnrolledAt: enrollment.CreatedAt,
+		})
+	}
+
+	return result, nil
+}
+
+// countTerm returns the term whose enrollments are counted: the term with the given id, or the
+// active term. It is nil when id is nil and no term is active.
+func (s *service) countTerm(ctx context.Context, id *uint) (*model.Term, error) {
+	term, err := util.FindTerm(ctx, s.TermRepository, id)
+	if id == nil && errors.Is(err, res.ErrorConstant.NoActiveTerm) {
+		return nil, nil
+	}
+	return term, err
+}
+
+// courseResponse returns the course with its enrollments in the term with the given id, or in
+// the active term.
+func (s *service) courseResponse(ctx context.Context, course *model.Course, termID *uint) (*dto.CourseResponse, error) {
+	term, err := s.countTerm(ctx, termID)
+	if err != nil {
+		return &dto.CourseResponse{}, err
+	}
+	var enrolled uint
+	if term != nil {
+		counts, err := s.CourseRepository.CountEnrollments(ctx, term.ID, course.ID)
+		if err != nil {
+			return &dto.CourseResponse{}, util.RepositoryErrorBuilder(err)
+		}
+		enrolled = counts[course.ID]
+	}
+
+	result := newCourseResponse(course, term, enrolled)
+	return &result, nil
+}
+
+// newCourseResponse returns the response of a course with enrolled students in the term,
+// term is nil when the enrollments were not counted.
+func newCourseResponse(course *model.Course, term *model.Term, enrolled uint) dto.CourseResponse {
+	result := dto.CourseResponse{
+		ID:       course.ID,
+		Code:     course.Code,
+		Name:     course.Name,
+		MajorID:  course.MajorID,
+		Credits:  course.Credits,
+		Capacity: course.Capacity,
+		Enrolled: enrolled,
+		Version:  course.Version,
+	}
+	if term != nil {
+		result.TermID = &term.ID
+	}
+	return result
+}
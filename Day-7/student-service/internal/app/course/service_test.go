@@ -0,0 +1,273 @@
+package course
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"student-service/database"
+	"student-service/database/seeder"
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/repository"
+	pkgdto "student-service/pkg/dto"
+	res "student-service/pkg/util/response"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ctx           = context.Background()
+	courseService = NewService(factory.NewFactory())
+	// fullCourseID is the seeded course with two seats.
+	fullCourseID = uint(2)
+	// closedTermID, activeTermID and nextTermID are the seeded terms.
+	closedTermID = uint(1)
+	activeTermID = uint(2)
+	nextTermID   = uint(3)
+)
+
+// enroll enrolls the student in the course in the active term.
+func enroll(courseID, studentID uint) (*dto.EnrollmentResponse, error) {
+	return courseService.Enroll(ctx, &dto.EnrollRequestBody{CourseID: courseID, StudentID: &studentID})
+}
+
+func TestCourseServiceFindAllByMajor(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	majorID := uint(1)
+	result, err := courseService.Find(ctx, &dto.SearchCourseRequest{MajorID: &majorID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	asserts := assert.New(t)
+	asserts.Len(result.Data, 2)
+	for _, course := range result.Data {
+		asserts.Equal(majorID, course.MajorID)
+	}
+}
+
+func TestCourseServiceEnrollAndWithdraw(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	asserts := assert.New(t)
+
+	enrollment, err := enroll(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	asserts.Equal(uint(1), enrollment.CourseID)
+	asserts.Equal(uint(2), enrollment.StudentID)
+	asserts.Equal(activeTermID, enrollment.TermID)
+	asserts.False(enrollment.EnrolledAt.IsZero())
+
+	_, err = enroll(1, 2)
+	asserts.True(errors.Is(err, res.ErrorConstant.AlreadyEnrolled))
+
+	course, err := courseService.FindByID(ctx, &dto.ByIDInTermRequest{ID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	asserts.Equal(uint(1), course.Enrolled)
+	asserts.Equal(&activeTermID, course.TermID)
+	asserts.Equal(uint(2), course.Version)
+
+	course, err = courseService.FindByID(ctx, &dto.ByIDInTermRequest{ID: 1, TermID: &nextTermID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	asserts.Equal(uint(0), course.Enrolled)
+
+	roster, err := courseService.FindStudents(ctx, &dto.ByIDInTermRequest{ID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asserts.Len(roster, 1) {
+		asserts.Equal(uint(2), roster[0].ID)
+	}
+
+	_, err = courseService.Withdraw(ctx, &dto.WithdrawRequest{CourseID: 1, StudentID: 2})
+	asserts.NoError(err)
+	_, err = courseService.Withdraw(ctx, &dto.WithdrawRequest{CourseID: 1, StudentID: 2})
+	asserts.True(errors.Is(err, res.ErrorConstant.NotFound))
+
+	course, err = courseService.FindByID(ctx, &dto.ByIDInTermRequest{ID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	asserts.Equal(uint(0), course.Enrolled)
+}
+
+func TestCourseServiceEnrollByTerm(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	asserts := assert.New(t)
+
+	// the capacity counts the enrollments of one term
+	for _, studentID := range []uint{1, 2} {
+		if _, err := enroll(fullCourseID, studentID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	studentID := uint(3)
+	_, err := enroll(fullCourseID, studentID)
+	asserts.True(errors.Is(err, res.ErrorConstant.CourseFull))
+	_, err = courseService.Enroll(ctx, &dto.EnrollRequestBody{CourseID: fullCourseID, StudentID: &studentID, TermID: &nextTermID})
+	asserts.NoError(err)
+
+	// closed terms are read-only
+	_, err = courseService.Enroll(ctx, &dto.EnrollRequestBody{CourseID: 1, StudentID: &studentID, TermID: &closedTermID})
+	asserts.True(errors.Is(err, res.ErrorConstant.TermClosed))
+	_, err = courseService.Withdraw(ctx, &dto.WithdrawRequest{CourseID: 1, StudentID: studentID, TermID: &closedTermID})
+	asserts.True(errors.Is(err, res.ErrorConstant.TermClosed))
+
+	// without an active term, the term must be chosen
+	if err := database.GetConnection().Table("terms").Where("id = ?", activeTermID).Update("closed", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	_, err = enroll(1, studentID)
+	asserts.True(errors.Is(err, res.ErrorConstant.NoActiveTerm))
+	result, err := courseService.Find(ctx, &dto.SearchCourseRequest{})
+	if asserts.NoError(err) {
+		for _, course := range result.Data {
+			asserts.Nil(course.TermID)
+			asserts.Zero(course.Enrolled)
+		}
+	}
+}
+
+// staleTerms reads the terms as open, like a term read just before another request closed it.
+type staleTerms struct {
+	repository.Term
+}
+
+func (r staleTerms) FindByID(ctx context.Context, id uint) (model.Term, error) {
+	term, err := r.Term.FindByID(ctx, id)
+	term.Closed = false
+	return term, err
+}
+
+func TestCourseServiceEnrollInTermClosedAfterItWasRead(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	asserts := assert.New(t)
+
+	f := factory.NewFactory()
+	stale := &service{
+		CourseRepository: f.CourseRepository,
+		TermRepository:   staleTerms{f.TermRepository},
+		Transactor:       f.Transactor,
+		Cache:            f.Cache,
+	}
+	studentID := uint(3)
+
+	// testing
+	_, err := stale.Enroll(ctx, &dto.EnrollRequestBody{CourseID: 1, StudentID: &studentID, TermID: &closedTermID})
+	asserts.True(errors.Is(err, res.ErrorConstant.TermClosed))
+	_, err = stale.Withdraw(ctx, &dto.WithdrawRequest{CourseID: 1, StudentID: studentID, TermID: &closedTermID})
+	asserts.True(errors.Is(err, res.ErrorConstant.TermClosed))
+	enrollments, err := f.CourseRepository.FindEnrollmentsByCourseID(ctx, 1, closedTermID)
+	if asserts.NoError(err) {
+		for _, enrollment := range enrollments {
+			asserts.NotEqual(studentID, enrollment.StudentID)
+		}
+	}
+}
+
+func TestCourseServiceEnrollNotFound(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	_, err := enroll(99, 1)
+	assert.True(t, errors.Is(err, res.ErrorConstant.NotFound))
+}
+
+func TestCourseServiceEnrollConcurrentCapacity(t *testing.T) {
+	// the capacity check relies on the row lock taken on the course
+	var versionComment string
+	database.GetConnection().Raw("SELECT @@version_comment").Scan(&versionComment)
+	if strings.Contains(versionComment, "Dolt") {
+		t.Skip("the database server does not lock rows in transactions")
+	}
+
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		full   int
+		others []error
+	)
+	for _, studentID := range []uint{1, 2, 3} {
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func(studentID uint) {
+				defer wg.Done()
+				_, err := enroll(fullCourseID, studentID)
+				mu.Lock()
+				defer mu.Unlock()
+				if errors.Is(err, res.ErrorConstant.CourseFull) {
+					full++
+				} else if err != nil && !errors.Is(err, res.ErrorConstant.AlreadyEnrolled) {
+					others = append(others, err)
+				}
+			}(studentID)
+		}
+	}
+	wg.Wait()
+
+	asserts := assert.New(t)
+	asserts.Empty(others)
+	asserts.NotZero(full)
+
+	var count int64
+	database.GetConnection().Table("enrollments").Where("course_id = ?", fullCourseID).Count(&count)
+	asserts.Equal(int64(2), count)
+
+	course, err := courseService.FindByID(ctx, &dto.ByIDInTermRequest{ID: fullCourseID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	asserts.Equal(uint(2), course.Enrolled)
+}
+
+func TestCourseServiceUpdateByIdCapacityBelowEnrolled(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	for _, studentID := range []uint{1, 2} {
+		if _, err := enroll(fullCourseID, studentID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	capacity := uint(1)
+	_, err := courseService.UpdateById(ctx, &dto.UpdateCourseRequestBody{ID: &fullCourseID, Capacity: &capacity})
+	assert.True(t, errors.Is(err, res.ErrorConstant.CapacityBelowEnrolled))
+}
+
+func TestCourseServiceDeleteByIdWithEnrollments(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	asserts := assert.New(t)
+
+	if _, err := enroll(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	_, err := courseService.DeleteById(ctx, &pkgdto.ByIDRequest{ID: 1})
+	asserts.True(errors.Is(err, res.ErrorConstant.InvalidReference))
+
+	if _, err := courseService.Withdraw(ctx, &dto.WithdrawRequest{CourseID: 1, StudentID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := courseService.DeleteById(ctx, &pkgdto.ByIDRequest{ID: 1})
+	if asserts.NoError(err) {
+		asserts.NotNil(result.DeletedAt)
+	}
+}
@@ -0,0 +1,124 @@
+package grade
+
+import (
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/middleware"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	pkgdto "student-service/pkg/dto"
+	res "student-service/pkg/util/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	service     Service
+	idempotency echo.MiddlewareFunc
+}
+
+func NewHandler(f *factory.Factory) *handler {
+	return &handler{
+		service:     NewService(f),
+		idempotency: middleware.IdempotencyMiddleware(f.IdempotencyKeyRepository),
+	}
+}
+
+// GetById returns a grade to class A students and to the graded student.
+func (h *handler) GetById(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(pkgdto.ByIDRequest)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	result, err := h.service.FindByID(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+	if (jwtClaims.BID != result.StudentID) && (jwtClaims.ClassID != uint(enum.A)) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, nil).Send(c)
+	}
+
+	c.Response().Header().Set(res.HeaderETag, res.ETag(result.Version))
+	return res.SuccessResponse(result).Send(c)
+}
+
+func (h *handler) Create(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if (err != nil) || (jwtClaims.ClassID != uint(enum.A)) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(dto.CreateGradeRequestBody)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+
+	result, err := h.service.Store(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	c.Response().Header().Set(res.HeaderETag, res.ETag(result.Version))
+	return res.SuccessResponse(result).Send(c)
+}
+
+func (h *handler) UpdateById(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if (err != nil) || (jwtClaims.ClassID != uint(enum.A)) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(dto.UpdateGradeRequestBody)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	payload.IfMatch = c.Request().Header.Get(res.HeaderIfMatch)
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+	result, err := h.service.UpdateById(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	c.Response().Header().Set(res.HeaderETag, res.ETag(result.Version))
+	return res.SuccessResponse(result).Send(c)
+}
+
+func (h *handler) DeleteById(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if (err != nil) || (jwtClaims.ClassID != uint(enum.A)) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	payload := new(pkgdto.ByIDRequest)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	payload.IfMatch = c.Request().Header.Get(res.HeaderIfMatch)
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+	result, err := h.service.DeleteById(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	return res.SuccessResponse(result).Send(c)
+}
@@ -0,0 +1,173 @@
+package grade
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"student-service/database"
+	"student-service/database/seeder"
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/mocks"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	adminClaims  = util.CreateJWTClaims("vincentlhubbard@edu.ac.id", uint(1), uint(enum.A), uint(enum.Finance))
+	userClaims   = util.CreateJWTClaims("devoncthomas@edu.ac.id", uint(2), uint(enum.B), uint(enum.Finance))
+	db           = database.GetConnection()
+	echoMock     = mocks.EchoMock{E: echo.New()}
+	f            = factory.Factory{GradeRepository: repository.NewGradeRepository(db), TermRepository: repository.NewTermRepository(db), Transactor: repository.NewTransactor(db)}
+	gradeHandler = NewHandler(&f)
+)
+
+func gradeRequest(t *testing.T, claims dto.JWTClaims, method, payload string, id string) (echo.Context, func() (int, string)) {
+	c, rec := echoMock.RequestMock(method, "/", bytes.NewBufferString(payload))
+	if id != "" {
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+	}
+	token, err := util.CreateJWTToken(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	c.Request().Header.Set("Content-Type", "application/json")
+	return c, func() (int, string) { return rec.Code, rec.Body.String() }
+}
+
+// seedGrade records a grade of the second student in the first course in the active term.
+func seedGrade(t *testing.T) *dto.GradeResponse {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	studentID, courseID, termID := uint(2), uint(1), uint(2)
+	grade, err := NewService(&f).Store(context.Background(), &dto.CreateGradeRequestBody{StudentID: &studentID, CourseID: &courseID, TermID: &termID, Letter: "B"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return grade
+}
+
+func TestGradeHandlerCreate(t *testing.T) {
+	cases := []struct {
+		name     string
+		claims   dto.JWTClaims
+		payload  string
+		code     int
+		contains []string
+	}{
+		{"letter from assessments", adminClaims, `{"student_id":2,"course_id":3,"term_id":2,"assessments":[{"name":"midterm","weight":40,"score":80},{"name":"final","weight":60,"score":95}]}`, 200, []string{`"score":89`, `"letter":"B+"`, `"name":"final"`}},
+		{"explicit letter", adminClaims, `{"student_id":2,"course_id":3,"term_id":2,"letter":"A-"}`, 200, []string{`"score":null`, `"letter":"A-"`, `"assessments":[]`}},
+		{"letter overrides assessments", adminClaims, `{"student_id":2,"course_id":3,"term_id":2,"letter":"A","assessments":[{"name":"final","weight":100,"score":50}]}`, 200, []string{`"score":50`, `"letter":"A"`}},
+		{"unknown letter", adminClaims, `{"student_id":2,"course_id":3,"term_id":2,"letter":"AB"}`, 422, []string{"Letter grade is not in the grade scale"}},
+		{"weights do not add up", adminClaims, `{"student_id":2,"course_id":3,"term_id":2,"assessments":[{"name":"final","weight":60,"score":95}]}`, 422, []string{"Assessment weights must add up to 100"}},
+		{"invalid assessment", adminClaims, `{"student_id":2,"course_id":3,"term_id":2,"assessments":[{"name":"final","weight":100,"score":101}]}`, 400, []string{`"field":"assessments[0].score"`}},
+		{"no letter nor assessments", adminClaims, `{"student_id":2,"course_id":3,"term_id":2}`, 400, []string{`"field":"letter"`}},
+		{"unknown course", adminClaims, `{"student_id":2,"course_id":99,"term_id":2,"letter":"A"}`, 400, []string{`"field":"course_id"`}},
+		{"duplicate", adminClaims, `{"student_id":2,"course_id":1,"term_id":2,"letter":"A"}`, 409, []string{"duplicate"}},
+		{"retake in another term", adminClaims, `{"student_id":2,"course_id":1,"term_id":3,"letter":"A"}`, 200, []string{`"term_id":3`}},
+		{"closed term", adminClaims, `{"student_id":2,"course_id":3,"term_id":1,"letter":"A"}`, 409, []string{"Term is closed"}},
+		{"unknown term", adminClaims, `{"student_id":2,"course_id":3,"term_id":99,"letter":"A"}`, 400, []string{`"field":"term_id"`}},
+		{"not class A", userClaims, `{"student_id":2,"course_id":3,"term_id":2,"letter":"A"}`, 401, []string{"unauthorized"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seedGrade(t)
+
+			c, rec := gradeRequest(t, tc.claims, http.MethodPost, tc.payload, "")
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(gradeHandler.Create(c)) {
+				code, body := rec()
+				asserts.Equal(tc.code, code)
+				for _, s := range tc.contains {
+					asserts.Contains(body, s)
+				}
+			}
+		})
+	}
+}
+
+func TestGradeHandlerGetById(t *testing.T) {
+	cases := []struct {
+		name   string
+		claims dto.JWTClaims
+		code   int
+	}{
+		{"graded student", userClaims, 200},
+		{"class A", adminClaims, 200},
+		{"other student", util.CreateJWTClaims("bettinameaster@edu.ac.id", uint(3), uint(enum.B), uint(enum.IT)), 401},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			grade := seedGrade(t)
+
+			c, rec := gradeRequest(t, tc.claims, http.MethodGet, "", fmt.Sprint(grade.ID))
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(gradeHandler.GetById(c)) {
+				code, _ := rec()
+				asserts.Equal(tc.code, code)
+			}
+		})
+	}
+}
+
+func TestGradeHandlerUpdateById(t *testing.T) {
+	grade := seedGrade(t)
+	asserts := assert.New(t)
+
+	c, rec := gradeRequest(t, userClaims, http.MethodPut, `{"letter":"A"}`, fmt.Sprint(grade.ID))
+	if asserts.NoError(gradeHandler.UpdateById(c)) {
+		code, _ := rec()
+		asserts.Equal(401, code)
+	}
+
+	c, rec = gradeRequest(t, adminClaims, http.MethodPut, `{"assessments":[{"name":"final","weight":100,"score":91}]}`, fmt.Sprint(grade.ID))
+	c.Request().Header.Set("If-Match", `"1"`)
+	if asserts.NoError(gradeHandler.UpdateById(c)) {
+		code, body := rec()
+		asserts.Equal(200, code)
+		asserts.Contains(body, `"letter":"A-"`)
+		asserts.Contains(body, `"score":91`)
+		asserts.Contains(body, `"version":2`)
+	}
+
+	c, rec = gradeRequest(t, adminClaims, http.MethodPut, `{"letter":"A"}`, fmt.Sprint(grade.ID))
+	c.Request().Header.Set("If-Match", `"1"`)
+	if asserts.NoError(gradeHandler.UpdateById(c)) {
+		code, _ := rec()
+		asserts.Equal(412, code)
+	}
+
+	c, rec = gradeRequest(t, adminClaims, http.MethodPut, `{"letter":"A"}`, fmt.Sprint(grade.ID))
+	if asserts.NoError(gradeHandler.UpdateById(c)) {
+		code, body := rec()
+		asserts.Equal(200, code)
+		asserts.Contains(body, `"letter":"A"`)
+		asserts.Contains(body, `"score":91`)
+		asserts.Contains(body, `"name":"final"`)
+	}
+
+	// grades of closed terms are read-only
+	if err := db.Table("terms").Where("id = ?", grade.TermID).Update("closed", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	c, rec = gradeRequest(t, adminClaims, http.MethodPut, `{"letter":"B"}`, fmt.Sprint(grade.ID))
+	if asserts.NoError(gradeHandler.UpdateById(c)) {
+		code, body := rec()
+		asserts.Equal(409, code)
+		asserts.Contains(body, "Term is closed")
+	}
+}
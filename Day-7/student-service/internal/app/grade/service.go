@@ -0,0 +1,210 @@
+package grade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	pkgdto "student-service/pkg/dto"
+	"student-service/pkg/grading"
+	res "student-service/pkg/util/response"
+)
+
+type service struct {
+	GradeRepository repository.Grade
+	TermRepository  repository.Term
+	Transactor      repository.Transactor
+	Scale           *grading.Scale
+}
+
+type Service interface {
+	FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.GradeResponse, error)
+	Store(ctx context.Context, payload *dto.CreateGradeRequestBody) (*dto.GradeResponse, error)
+	UpdateById(ctx context.Context, payload *dto.UpdateGradeRequestBody) (*dto.GradeResponse, error)
+	DeleteById(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.GradeWithCUDResponse, error)
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		GradeRepository: f.GradeRepository,
+		TermRepository:  f.TermRepository,
+		Transactor:      f.Transactor,
+		Scale:           grading.DefaultScale(),
+	}
+}
+
+func (s *service) FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.GradeResponse, error) {
+	grade, err := s.GradeRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return &dto.GradeResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	return newGradeResponse(&grade), nil
+}
+
+func (s *service) Store(ctx context.Context, payload *dto.CreateGradeRequestBody) (*dto.GradeResponse, error) {
+	term, err := util.FindTerm(ctx, s.TermRepository, payload.TermID)
+	if err != nil {
+		return &dto.GradeResponse{}, err
+	}
+	if err := util.CheckTermOpen(term); err != nil {
+		return &dto.GradeResponse{}, err
+	}
+
+	grade := model.Grade{
+		StudentID: *payload.StudentID,
+		CourseID:  *payload.CourseID,
+		TermID:    term.ID,
+	}
+	var letter *string
+	if payload.Letter != "" {
+		letter = &payload.Letter
+	}
+	if err := s.grade(&grade, letter, payload.Assessments); err != nil {
+		return &dto.GradeResponse{}, err
+	}
+
+	err = util.WithinOpenTerm(ctx, s.Transactor, s.TermRepository, term.ID, func(ctx context.Context) error {
+		return s.GradeRepository.Save(ctx, &grade)
+	})
+	if err != nil {
+		return &dto.GradeResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	return newGradeResponse(&grade), nil
+}
+
+func (s *service) UpdateById(ctx context.Context, payload *dto.UpdateGradeRequestBody) (*dto.GradeResponse, error) {
+	grade, err := s.GradeRepository.FindByID(ctx, *payload.ID)
+	if err != nil {
+		return &dto.GradeResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	if !res.IfMatch(payload.IfMatch, grade.Version) {
+		return &dto.GradeResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("grade version does not match If-Match"))
+	}
+	if err := util.CheckTermOpen(&grade.Term); err != nil {
+		return &dto.GradeResponse{}, err
+	}
+
+	updateData := model.Grade{Score: grade.Score, Letter: grade.Letter}
+	switch {
+	case payload.Assessments != nil:
+		if err := s.grade(&updateData, payload.Letter, payload.Assessments); err != nil {
+			return &dto.GradeResponse{}, err
+		}
+		if updateData.Assessments == nil {
+			updateData.Assessments = []model.Assessment{}
+		}
+	case payload.Letter != nil:
+		if _, ok := s.Scale.Points(*payload.Letter); !ok {
+			return &dto.GradeResponse{}, unknownLetterError(*payload.Letter)
+		}
+		updateData.Letter = *payload.Letter
+	}
+
+	err = util.WithinOpenTerm(ctx, s.Transactor, s.TermRepository, grade.TermID, func(ctx context.Context) error {
+		_, err := s.GradeRepository.Edit(ctx, &grade, &updateData)
+		return err
+	})
+	if err != nil {
+		return &dto.GradeResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	return newGradeResponse(&grade), nil
+}
+
+func (s *service) DeleteById(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.GradeWithCUDResponse, error) {
+	grade, err := s.GradeRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return &dto.GradeWithCUDResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	if !res.IfMatch(payload.IfMatch, grade.Version) {
+		return &dto.GradeWithCUDResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("grade version does not match If-Match"))
+	}
+	if err := util.CheckTermOpen(&grade.Term); err != nil {
+		return &dto.GradeWithCUDResponse{}, err
+	}
+
+	err = util.WithinOpenTerm(ctx, s.Transactor, s.TermRepository, grade.TermID, func(ctx context.Context) error {
+		_, err := s.GradeRepository.Destroy(ctx, &grade)
+		return err
+	})
+	if err != nil {
+		return &dto.GradeWithCUDResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := &dto.GradeWithCUDResponse{
+		GradeResponse: *newGradeResponse(&grade),
+		CreatedAt:     grade.CreatedAt,
+		UpdatedAt:     grade.UpdatedAt,
+		DeletedAt:     grade.DeletedAt,
+	}
+
+	return result, nil
+}
+
+// grade sets the assessments, score and letter of grade. The score is the weighted average
+// of the assessments, whose weights must add up to 100. The letter is derived from the score
+// unless it is set, and must belong to the grade scale.
+func (s *service) grade(grade *model.Grade, letter *string, assessments []dto.AssessmentRequest) error {
+	grade.Score = nil
+	grade.Assessments = nil
+	if len(assessments) > 0 {
+		var weights, score float64
+		for _, assessment := range assessments {
+			weights += *assessment.Weight
+			score += *assessment.Score * *assessment.Weight / 100
+			grade.Assessments = append(grade.Assessments, model.Assessment{
+				Name:   *assessment.Name,
+				Weight: *assessment.Weight,
+				Score:  *assessment.Score,
+			})
+		}
+		if math.Abs(weights-100) > 0.001 {
+			return res.ErrorBuilder(res.ErrorConstant.InvalidAssessmentWeights, fmt.Errorf("assessment weights add up to %v", weights))
+		}
+		score = math.Round(score*100) / 100
+		grade.Score = &score
+		grade.Letter = s.Scale.Letter(score)
+	}
+
+	if letter != nil {
+		if _, ok := s.Scale.Points(*letter); !ok {
+			return unknownLetterError(*letter)
+		}
+		grade.Letter = *letter
+	}
+	return nil
+}
+
+func unknownLetterError(letter string) error {
+	return res.ErrorBuilder(res.ErrorConstant.UnknownGradeLetter, fmt.Errorf("letter %q is not in the grade scale", letter))
+}
+
+func newGradeResponse(grade *model.Grade) *dto.GradeResponse {
+	result := &dto.GradeResponse{
+		ID:          grade.ID,
+		StudentID:   grade.StudentID,
+		CourseID:    grade.CourseID,
+		TermID:      grade.TermID,
+		Score:       grade.Score,
+		Letter:      grade.Letter,
+		Assessments: []dto.AssessmentResponse{},
+		Version:     grade.Version,
+	}
+	for _, assessment := range grade.Assessments {
+		result.Assessments = append(result.Assessments, dto.AssessmentResponse{
+			Name:   assessment.Name,
+			Weight: assessment.Weight,
+			Score:  assessment.Score,
+		})
+	}
+	return result
+}
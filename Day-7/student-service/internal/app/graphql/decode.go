@@ -0,0 +1,183 @@
+package graphql
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// resolveValue resolves the fields without a resolver to the JSON field of the response they
+// are named after, e.g. studentNumber to student_number.
+func resolveValue(p graphql.ResolveParams) (interface{}, error) {
+	value, _ := fieldValue(p.Source, p.Info.FieldName)
+	return value, nil
+}
+
+// fieldValue returns the value of the key of a map, or of the struct field whose JSON name is
+// the snake case of name, ignoring case like encoding/json. Pointers and embedded structs are
+// followed.
+func fieldValue(source interface{}, name string) (interface{}, bool) {
+	v := reflect.ValueOf(source)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		value := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		if !value.IsValid() {
+			return nil, false
+		}
+		return value.Interface(), true
+	case reflect.Struct:
+		field, ok := structField(v, snakeCase(name))
+		if !ok {
+			return nil, false
+		}
+		return field.Interface(), true
+	}
+	return nil, false
+}
+
+// structField returns the field of v with the JSON name, looking into embedded structs.
+func structField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if strings.EqualFold(jsonName(field), name) {
+			return v.Field(i), true
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.Anonymous || field.Tag.Get("json") != "" {
+			continue
+		}
+		embedded := v.Field(i)
+		if embedded.Kind() == reflect.Ptr {
+			if embedded.IsNil() {
+				continue
+			}
+			embedded = embedded.Elem()
+		}
+		if embedded.Kind() == reflect.Struct {
+			if value, ok := structField(embedded, name); ok {
+				return value, true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func jsonName(field reflect.StructField) string {
+	tag := strings.Split(field.Tag.Get("json"), ",")[0]
+	switch {
+	case tag == "-":
+		return ""
+	case tag != "":
+		return tag
+	case field.Anonymous:
+		return ""
+	}
+	return field.Name
+}
+
+// snakeCase returns the snake case of a camel case name, e.g. class_id for classId.
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// decodeArgs copies the arguments, or the fields of an input object, to the struct target
+// points to. Each struct field gets the argument whose snake case is its JSON name, converted to
+// the type of the field: numbers and ID strings to integers, values to pointers.
+func decodeArgs(args map[string]interface{}, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decode into %T, not a pointer to a struct", target)
+	}
+	for name, value := range args {
+		field, ok := structField(v.Elem(), snakeCase(name))
+		if !ok {
+			continue
+		}
+		if err := assign(field, value); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func assign(field reflect.Value, value interface{}) error {
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	if field.Kind() == reflect.Ptr {
+		elem := reflect.New(field.Type().Elem())
+		if err := assign(elem.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := toInt(value)
+		if err != nil || field.OverflowInt(n) {
+			return fmt.Errorf("invalid integer %v", value)
+		}
+		field.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := toInt(value)
+		if err != nil || n < 0 || field.OverflowUint(uint64(n)) {
+			return fmt.Errorf("invalid unsigned integer %v", value)
+		}
+		field.SetUint(uint64(n))
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	if !v.Type().ConvertibleTo(field.Type()) || v.Kind() == reflect.String && field.Kind() != reflect.String {
+		return fmt.Errorf("cannot use %T as %s", value, field.Type())
+	}
+	field.Set(v.Convert(field.Type()))
+	return nil
+}
+
+func toInt(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), nil
+	case float64:
+		if v == math.Trunc(v) {
+			return int64(v), nil
+		}
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	}
+	return 0, fmt.Errorf("%v is not an integer", value)
+}
@@ -0,0 +1,46 @@
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/pkg/util"
+	res "student-service/pkg/util/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	schema *Schema
+}
+
+func NewHandler(f *factory.Factory) *handler {
+	return &handler{
+		schema: NewSchema(f),
+	}
+}
+
+// Post executes a GraphQL request, {"query": ..., "operationName": ..., "variables": {...}}.
+// Errors of the operation are reported in the response with status 200, next to the data that
+// could be resolved.
+func (h *handler) Post(c echo.Context) error {
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+
+	request := new(dto.GraphQLRequest)
+	if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	if request.Query == "" {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, errors.New("query is required")).Send(c)
+	}
+
+	ctx := util.WithJWTClaims(c.Request().Context(), jwtClaims)
+	return c.JSON(http.StatusOK, h.schema.Execute(ctx, *request))
+}
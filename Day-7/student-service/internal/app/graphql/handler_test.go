@@ -0,0 +1,190 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"student-service/database"
+	"student-service/database/seeder"
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/mocks"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	adminClaims    = util.CreateJWTClaims(testEmail, testStudentID, testAClassID, testMajorID)
+	userClaims     = util.CreateJWTClaims(testEmail, uint(2), uint(enum.B), testMajorID)
+	db             = database.GetConnection()
+	echoMock       = mocks.EchoMock{E: echo.New()}
+	graphqlHandler = NewHandler(&f)
+	f              = factory.Factory{
+		StudentRepository: repository.NewStudentRepository(db),
+		MajorRepository:   repository.NewMajorRepository(db),
+		ClassRepository:   repository.NewClassRepository(db),
+		CourseRepository:  repository.NewCourseRepository(db),
+		GradeRepository:   repository.NewGradeRepository(db),
+		TermRepository:    repository.NewTermRepository(db),
+		Transactor:        repository.NewTransactor(db),
+	}
+	testAClassID  = uint(enum.A)
+	testMajorID   = uint(enum.Finance)
+	testEmail     = "vincentlhubbard@edu.ac.id"
+	testStudentID = uint(1)
+)
+
+type testResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func post(t *testing.T, claims *dto.JWTClaims, query string) (int, *testResponse) {
+	return postRequest(t, claims, dto.GraphQLRequest{Query: query})
+}
+
+func postRequest(t *testing.T, claims *dto.JWTClaims, request dto.GraphQLRequest) (int, *testResponse) {
+	b, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, rec := echoMock.RequestMock(http.MethodPost, "/", strings.NewReader(string(b)))
+	c.SetPath("/api/v1/graphql")
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if claims != nil {
+		token, err := util.CreateJWTToken(*claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	if err := graphqlHandler.Post(c); err != nil {
+		t.Fatal(err)
+	}
+	response := new(testResponse)
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), response); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rec.Code, response
+}
+
+func TestGraphQLHandlerPostUnauthorized(t *testing.T) {
+	code, _ := post(t, nil, `{ me { id } }`)
+	assert.Equal(t, 401, code)
+}
+
+func TestGraphQLHandlerPostEmptyQuery(t *testing.T) {
+	code, _ := post(t, &adminClaims, "")
+	assert.Equal(t, 400, code)
+}
+
+func TestGraphQLHandlerPostQuery(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	code, response := post(t, &adminClaims, `{
+		me { email class { name } major { name } }
+		students(pageSize: 3) { data { id fullname class { id name } major { name } } pagination { page count } }
+	}`)
+
+	asserts := assert.New(t)
+	if asserts.Equal(200, code) && asserts.Empty(response.Errors) {
+		me := response.Data["me"].(map[string]interface{})
+		asserts.Equal(testEmail, me["email"])
+		asserts.NotNil(me["class"])
+		asserts.NotNil(me["major"])
+
+		students := response.Data["students"].(map[string]interface{})
+		data := students["data"].([]interface{})
+		asserts.Len(data, 3)
+		for _, item := range data {
+			student := item.(map[string]interface{})
+			asserts.NotEmpty(student["fullname"])
+			asserts.NotEmpty(student["major"].(map[string]interface{})["name"])
+		}
+	}
+}
+
+func TestGraphQLHandlerPostNestedQueryWithVariables(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	code, response := postRequest(t, &adminClaims, dto.GraphQLRequest{
+		Query: `query Class($id: ID!) {
+			class(id: $id) { name students { fullname studentNumber class { name } major { name } } }
+		}`,
+		OperationName: "Class",
+		Variables:     map[string]interface{}{"id": "2"},
+	})
+
+	asserts := assert.New(t)
+	if asserts.Equal(200, code) && asserts.Empty(response.Errors) {
+		class := response.Data["class"].(map[string]interface{})
+		asserts.Equal("B", class["name"])
+		students := class["students"].([]interface{})
+		if asserts.Len(students, 2) {
+			for _, item := range students {
+				student := item.(map[string]interface{})
+				asserts.NotEmpty(student["studentNumber"])
+				asserts.Equal("B", student["class"].(map[string]interface{})["name"])
+				asserts.NotEmpty(student["major"].(map[string]interface{})["name"])
+			}
+		}
+	}
+}
+
+func TestGraphQLHandlerPostInvalidQuery(t *testing.T) {
+	code, response := post(t, &adminClaims, `{ me { unknown } }`)
+
+	asserts := assert.New(t)
+	if asserts.Equal(200, code) && asserts.Len(response.Errors, 1) {
+		asserts.Contains(response.Errors[0].Message, `Cannot query field "unknown" on type "Student"`)
+		asserts.Equal("bad_request", response.Errors[0].Extensions["code"])
+		asserts.Nil(response.Data)
+	}
+}
+
+func TestGraphQLHandlerPostClassAOnly(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	code, response := post(t, &userClaims, `{ majors { data { name } } classes { data { name } } }`)
+
+	asserts := assert.New(t)
+	if asserts.Equal(200, code) && asserts.Len(response.Errors, 1) {
+		asserts.Equal("unauthorized", response.Errors[0].Extensions["code"])
+		asserts.Nil(response.Data["classes"])
+		asserts.NotNil(response.Data["majors"])
+	}
+}
+
+func TestGraphQLHandlerPostMutation(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	code, response := post(t, &adminClaims, `mutation { createMajor(name: "Astronomy") { id name } }`)
+
+	asserts := assert.New(t)
+	if asserts.Equal(200, code) && asserts.Empty(response.Errors) {
+		major := response.Data["createMajor"].(map[string]interface{})
+		asserts.Equal("Astronomy", major["name"])
+
+		_, response = post(t, &adminClaims, fmt.Sprintf(`mutation { updateMajor(id: %q, name: "Cosmology", ifMatch: "\"stale\"") { id } }`, major["id"]))
+		if asserts.Len(response.Errors, 1) {
+			asserts.Equal("precondition_failed", response.Errors[0].Extensions["code"])
+		}
+	}
+}
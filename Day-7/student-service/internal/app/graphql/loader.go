@@ -0,0 +1,70 @@
+package graphql
+
+import (
+	"context"
+)
+
+// loader loads the records of the keys requested while a level of a query is resolved with one
+// call, e.g. the classes of a page of students. Load returns a thunk, which the executor calls
+// once every field of the level is resolved, the first call loads all the keys requested so far.
+type loader struct {
+	load    func(ctx context.Context, keys []uint) (map[uint]interface{}, error)
+	pending map[uint]bool
+	values  map[uint]interface{}
+	errs    map[uint]error
+}
+
+func newLoader(load func(ctx context.Context, keys []uint) (map[uint]interface{}, error)) *loader {
+	return &loader{load: load, pending: map[uint]bool{}, values: map[uint]interface{}{}, errs: map[uint]error{}}
+}
+
+// Load returns a thunk resolving to the record of key, nil when there is none. The executor
+// resolves the fields of a query one at a time, so a loader is not safe for concurrent use.
+func (l *loader) Load(ctx context.Context, key uint) func() (interface{}, error) {
+	if _, ok := l.values[key]; !ok {
+		l.pending[key] = true
+	}
+	return func() (interface{}, error) {
+		if len(l.pending) > 0 {
+			keys := make([]uint, 0, len(l.pending))
+			for key := range l.pending {
+				keys = append(keys, key)
+			}
+			l.pending = map[uint]bool{}
+			values, err := l.load(ctx, keys)
+			for _, key := range keys {
+				if err != nil {
+					l.errs[key] = err
+					continue
+				}
+				l.values[key] = values[key]
+			}
+		}
+		if err := l.errs[key]; err != nil {
+			return nil, err
+		}
+		return l.values[key], nil
+	}
+}
+
+// loaders are the loaders of a request, they are not shared by requests so that the records
+// loaded are never stale nor seen by another user.
+type loaders struct {
+	classes       *loader
+	majors        *loader
+	classStudents *loader
+}
+
+type loadersKey struct{}
+
+func (r *resolver) withLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{
+		classes:       newLoader(r.loadClasses),
+		majors:        newLoader(r.loadMajors),
+		classStudents: newLoader(r.loadClassStudents),
+	})
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,52 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoaderLoadsALevelAtOnce(t *testing.T) {
+	var calls [][]uint
+	l := newLoader(func(ctx context.Context, keys []uint) (map[uint]interface{}, error) {
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+		calls = append(calls, keys)
+		return map[uint]interface{}{1: "A", 2: "B", 3: "C"}, nil
+	})
+	ctx := context.Background()
+
+	asserts := assert.New(t)
+	thunks := []func() (interface{}, error){l.Load(ctx, 1), l.Load(ctx, 2), l.Load(ctx, 1), l.Load(ctx, 4)}
+	var values []interface{}
+	for _, thunk := range thunks {
+		value, err := thunk()
+		asserts.NoError(err)
+		values = append(values, value)
+	}
+	asserts.Equal([]interface{}{"A", "B", "A", nil}, values)
+	asserts.Equal([][]uint{{1, 2, 4}}, calls)
+
+	// the next level loads the keys it did not load yet
+	first, second := l.Load(ctx, 2), l.Load(ctx, 3)
+	value, _ := first()
+	asserts.Equal("B", value)
+	value, _ = second()
+	asserts.Equal("C", value)
+	asserts.Equal([][]uint{{1, 2, 4}, {3}}, calls)
+}
+
+func TestLoaderError(t *testing.T) {
+	l := newLoader(func(ctx context.Context, keys []uint) (map[uint]interface{}, error) {
+		return nil, errors.New("database is gone")
+	})
+	ctx := context.Background()
+
+	first, second := l.Load(ctx, 1), l.Load(ctx, 2)
+	_, err := first()
+	assert.EqualError(t, err, "database is gone")
+	_, err = second()
+	assert.EqualError(t, err, "database is gone")
+}
@@ -0,0 +1,14 @@
+package graphql
+
+import (
+	"student-service/internal/dto"
+	"student-service/internal/middleware"
+	"student-service/internal/pkg/util"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) Route(g *echo.Group) {
+	g.Use(middleware.JWTMiddleware(dto.JWTClaims{}, util.JWT_SECRET))
+	g.POST("", h.Post)
+}
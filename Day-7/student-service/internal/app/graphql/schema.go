@@ -0,0 +1,566 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"student-service/internal/app/class"
+	"student-service/internal/app/major"
+	"student-service/internal/app/student"
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	pkgdto "student-service/pkg/dto"
+	"student-service/pkg/i18n"
+	pkgutil "student-service/pkg/util"
+	res "student-service/pkg/util/response"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/sirupsen/logrus"
+)
+
+// resolver resolves the fields of the schema with the services of the REST API, and applies the
+// same permissions as its handlers.
+type resolver struct {
+	student   student.Service
+	class     class.Service
+	major     major.Service
+	validator *pkgutil.CustomValidator
+}
+
+// Schema executes GraphQL requests on students, classes and majors. Related records are loaded
+// for all the objects of a list at once, e.g. the classes of a page of students with one query.
+type Schema struct {
+	schema   graphql.Schema
+	resolver *resolver
+}
+
+func NewSchema(f *factory.Factory) *Schema {
+	r := &resolver{
+		student:   student.NewService(f),
+		class:     class.NewService(f),
+		major:     major.NewService(f),
+		validator: f.NewValidator(),
+	}
+
+	paginationType := object("PaginationInfo", graphql.Fields{
+		"page":        {Type: graphql.Int},
+		"pageSize":    {Type: graphql.Int},
+		"count":       {Type: graphql.NewNonNull(graphql.Int)},
+		"moreRecords": {Type: graphql.NewNonNull(graphql.Boolean)},
+		"totalPage":   {Type: graphql.NewNonNull(graphql.Int)},
+	})
+	majorType := object("Major", graphql.Fields{
+		"id":      {Type: graphql.NewNonNull(graphql.ID)},
+		"name":    {Type: graphql.NewNonNull(graphql.String)},
+		"version": {Type: graphql.NewNonNull(graphql.Int)},
+	})
+	classType := object("Class", graphql.Fields{
+		"id":      {Type: graphql.NewNonNull(graphql.ID)},
+		"name":    {Type: graphql.NewNonNull(graphql.String)},
+		"version": {Type: graphql.NewNonNull(graphql.Int)},
+	})
+	studentType := object("Student", graphql.Fields{
+		"id":            {Type: graphql.NewNonNull(graphql.ID)},
+		"studentNumber": {Type: graphql.String},
+		"fullname":      {Type: graphql.NewNonNull(graphql.String)},
+		"email":         {Type: graphql.NewNonNull(graphql.String)},
+		"version":       {Type: graphql.NewNonNull(graphql.Int)},
+		"intakeYear":    {Type: graphql.Int},
+		"phone":         {Type: graphql.String},
+		"birthDate":     {Type: graphql.String},
+		"gender":        {Type: graphql.String},
+		"address":       {Type: graphql.String},
+		"class":         {Type: classType, Resolve: r.studentClass},
+		"major":         {Type: majorType, Resolve: r.studentMajor},
+	})
+	classType.AddFieldConfig("students", &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(studentType)), Resolve: r.classStudents})
+	pageOf := func(t *graphql.Object) *graphql.Object {
+		return object(t.Name()+"Page", graphql.Fields{
+			"data":       {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t)))},
+			"pagination": {Type: graphql.NewNonNull(paginationType)},
+		})
+	}
+	searchArgs := func() graphql.FieldConfigArgument {
+		return graphql.FieldConfigArgument{
+			"search":   {Type: graphql.String},
+			"page":     {Type: graphql.Int},
+			"pageSize": {Type: graphql.Int},
+			"ascField": {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+			"dscField": {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		}
+	}
+	idArgs := func(names ...string) graphql.FieldConfigArgument {
+		args := graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}}
+		for _, name := range names {
+			args[name] = &graphql.ArgumentConfig{Type: graphql.String}
+		}
+		return args
+	}
+
+	studentSearchArgs := searchArgs()
+	studentSearchArgs["majorId"] = &graphql.ArgumentConfig{Type: graphql.ID}
+	studentSearchArgs["intakeYear"] = &graphql.ArgumentConfig{Type: graphql.Int}
+	studentSearchArgs["gender"] = &graphql.ArgumentConfig{Type: graphql.String}
+	studentSearchArgs["termId"] = &graphql.ArgumentConfig{Type: graphql.ID}
+	// the fields are nullable, so that the error of one leaves the others in the response
+	query := object("Query", graphql.Fields{
+		"me":       {Type: studentType, Resolve: r.me},
+		"student":  {Type: studentType, Args: idArgs(), Resolve: r.studentByID},
+		"students": {Type: pageOf(studentType), Args: studentSearchArgs, Resolve: r.students},
+		"class":    {Type: classType, Args: idArgs(), Resolve: r.classByID},
+		"classes":  {Type: pageOf(classType), Args: searchArgs(), Resolve: r.classes},
+		"major":    {Type: majorType, Args: idArgs(), Resolve: r.majorByID},
+		"majors":   {Type: pageOf(majorType), Args: searchArgs(), Resolve: r.majors},
+	})
+
+	nameArgs := graphql.FieldConfigArgument{"name": {Type: graphql.NewNonNull(graphql.String)}}
+	updateNameArgs := idArgs("ifMatch")
+	updateNameArgs["name"] = nameArgs["name"]
+	updateStudentArgs := idArgs("ifMatch")
+	updateStudentArgs["input"] = &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "UpdateStudentInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"fullname":  {Type: graphql.String},
+			"email":     {Type: graphql.String},
+			"password":  {Type: graphql.String},
+			"classId":   {Type: graphql.ID},
+			"majorId":   {Type: graphql.ID},
+			"phone":     {Type: graphql.String},
+			"birthDate": {Type: graphql.String},
+			"gender":    {Type: graphql.String},
+			"address":   {Type: graphql.String},
+		},
+	}))}
+	mutation := object("Mutation", graphql.Fields{
+		"createClass":   {Type: classType, Args: nameArgs, Resolve: r.createClass},
+		"updateClass":   {Type: classType, Args: updateNameArgs, Resolve: r.updateClass},
+		"deleteClass":   {Type: classType, Args: idArgs("ifMatch"), Resolve: r.deleteClass},
+		"createMajor":   {Type: majorType, Args: nameArgs, Resolve: r.createMajor},
+		"updateMajor":   {Type: majorType, Args: updateNameArgs, Resolve: r.updateMajor},
+		"deleteMajor":   {Type: majorType, Args: idArgs("ifMatch"), Resolve: r.deleteMajor},
+		"updateStudent": {Type: studentType, Args: updateStudentArgs, Resolve: r.updateStudent},
+		"deleteStudent": {Type: studentType, Args: idArgs("ifMatch"), Resolve: r.deleteStudent},
+	})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+	if err != nil {
+		// the schema is the same on every start, an invalid one is a bug
+		panic(err)
+	}
+	return &Schema{schema: schema, resolver: r}
+}
+
+// Execute executes the request with the loaders of a new request. Errors are reported with
+// their localized message and error code, like in the REST API.
+func (s *Schema) Execute(ctx context.Context, request dto.GraphQLRequest) *graphql.Result {
+	result := graphql.Do(graphql.Params{
+		Schema:         s.schema,
+		RequestString:  request.Query,
+		VariableValues: request.Variables,
+		OperationName:  request.OperationName,
+		Context:        s.resolver.withLoaders(ctx),
+	})
+	for i := range result.Errors {
+		result.Errors[i] = formatError(ctx, result.Errors[i])
+	}
+	return result
+}
+
+// object returns an object type whose fields without a resolver resolve to the JSON fields of
+// the responses.
+func object(name string, fields graphql.Fields) *graphql.Object {
+	for _, field := range fields {
+		if field.Resolve == nil {
+			field.Resolve = resolveValue
+		}
+	}
+	return graphql.NewObject(graphql.ObjectConfig{Name: name, Fields: fields})
+}
+
+func (r *resolver) me(p graphql.ResolveParams) (interface{}, error) {
+	return r.student.FindByID(p.Context, &pkgdto.ByIDRequest{ID: util.JWTClaimsFromContext(p.Context).BID})
+}
+
+func (r *resolver) studentByID(p graphql.ResolveParams) (interface{}, error) {
+	payload, err := r.byIDRequest(p)
+	if err != nil {
+		return nil, err
+	}
+	return r.student.FindByID(p.Context, payload)
+}
+
+// students lists the students like GET /students, with their details loaded in one query.
+func (r *resolver) students(p graphql.ResolveParams) (interface{}, error) {
+	payload := &dto.SearchStudentRequest{SearchGetRequest: searchRequest(p.Args)}
+	payload.Gender, _ = p.Args["gender"].(string)
+	if intakeYear, ok := p.Args["intakeYear"].(int); ok {
+		payload.IntakeYear = &intakeYear
+	}
+	for name, target := range map[string]**uint{"majorId": &payload.MajorID, "termId": &payload.TermID} {
+		if value, ok := p.Args[name]; ok && value != nil {
+			id, err := parseID(value)
+			if err != nil {
+				return nil, err
+			}
+			*target = &id
+		}
+	}
+	if err := r.validator.ValidateCtx(p.Context, payload); err != nil {
+		return nil, res.ValidationErrorBuilder(err)
+	}
+
+	result, err := r.student.Find(p.Context, payload)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(result.Data))
+	for _, student := range result.Data {
+		ids = append(ids, student.ID)
+	}
+	students, err := r.student.FindByIDs(p.Context, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]dto.StudentDetailResponse, len(students))
+	for _, student := range students {
+		byID[student.ID] = student
+	}
+	data := make([]dto.StudentDetailResponse, 0, len(ids))
+	for _, id := range ids {
+		if student, ok := byID[id]; ok {
+			data = append(data, student)
+		}
+	}
+	return page(data, result.PaginationInfo), nil
+}
+
+func (r *resolver) classByID(p graphql.ResolveParams) (interface{}, error) {
+	if err := requireClassA(p.Context); err != nil {
+		return nil, err
+	}
+	payload, err := r.byIDRequest(p)
+	if err != nil {
+		return nil, err
+	}
+	return r.class.FindByID(p.Context, payload)
+}
+
+func (r *resolver) classes(p graphql.ResolveParams) (interface{}, error) {
+	if err := requireClassA(p.Context); err != nil {
+		return nil, err
+	}
+	payload := searchRequest(p.Args)
+	result, err := r.class.Find(p.Context, &payload)
+	if err != nil {
+		return nil, err
+	}
+	return page(result.Data, result.PaginationInfo), nil
+}
+
+func (r *resolver) majorByID(p graphql.ResolveParams) (interface{}, error) {
+	payload, err := r.byIDRequest(p)
+	if err != nil {
+		return nil, err
+	}
+	return r.major.FindByID(p.Context, payload)
+}
+
+func (r *resolver) majors(p graphql.ResolveParams) (interface{}, error) {
+	payload := searchRequest(p.Args)
+	result, err := r.major.Find(p.Context, &payload)
+	if err != nil {
+		return nil, err
+	}
+	return page(result.Data, result.PaginationInfo), nil
+}
+
+// studentClass loads the classes of all the students of a level of the query with one call.
+// Anyone can see the class of a student, like in GET /students/:id.
+func (r *resolver) studentClass(p graphql.ResolveParams) (interface{}, error) {
+	student, ok := studentDetail(p.Source)
+	if !ok || student.Class == nil {
+		return nil, nil
+	}
+	return loadersFrom(p.Context).classes.Load(p.Context, student.Class.ID), nil
+}
+
+func (r *resolver) loadClasses(ctx context.Context, ids []uint) (map[uint]interface{}, error) {
+	classes, err := r.class.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]interface{}, len(classes))
+	for _, class := range classes {
+		byID[class.ID] = class
+	}
+	return byID, nil
+}
+
+// studentMajor loads the majors of all the students of a level of the query with one call.
+func (r *resolver) studentMajor(p graphql.ResolveParams) (interface{}, error) {
+	student, ok := studentDetail(p.Source)
+	if !ok {
+		return nil, nil
+	}
+	return loadersFrom(p.Context).majors.Load(p.Context, student.Major.ID), nil
+}
+
+func (r *resolver) loadMajors(ctx context.Context, ids []uint) (map[uint]interface{}, error) {
+	majors, err := r.major.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]interface{}, len(majors))
+	for _, major := range majors {
+		byID[major.ID] = major
+	}
+	return byID, nil
+}
+
+// classStudents loads the students of all the classes of a level of the query with one call.
+// Only class A can list them, like in GET /classes/:id/students.
+func (r *resolver) classStudents(p graphql.ResolveParams) (interface{}, error) {
+	if err := requireClassA(p.Context); err != nil {
+		return nil, err
+	}
+	return loadersFrom(p.Context).classStudents.Load(p.Context, sourceID(p.Source)), nil
+}
+
+func (r *resolver) loadClassStudents(ctx context.Context, ids []uint) (map[uint]interface{}, error) {
+	students, err := r.student.FindByClassIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byClass := make(map[uint][]dto.StudentDetailResponse, len(ids))
+	for _, student := range students {
+		byClass[student.Class.ID] = append(byClass[student.Class.ID], student)
+	}
+	result := make(map[uint]interface{}, len(ids))
+	for _, id := range ids {
+		result[id] = append([]dto.StudentDetailResponse{}, byClass[id]...)
+	}
+	return result, nil
+}
+
+func (r *resolver) createClass(p graphql.ResolveParams) (interface{}, error) {
+	if err := requireClassA(p.Context); err != nil {
+		return nil, err
+	}
+	payload := new(dto.CreateClassRequestBody)
+	if err := r.decode(p, payload); err != nil {
+		return nil, err
+	}
+	return r.class.Store(p.Context, payload)
+}
+
+func (r *resolver) updateClass(p graphql.ResolveParams) (interface{}, error) {
+	if err := requireClassA(p.Context); err != nil {
+		return nil, err
+	}
+	payload := &dto.UpdateClassRequestBody{IfMatch: ifMatch(p.Args)}
+	if err := r.decode(p, payload); err != nil {
+		return nil, err
+	}
+	return r.class.UpdateById(p.Context, payload)
+}
+
+func (r *resolver) deleteClass(p graphql.ResolveParams) (interface{}, error) {
+	if err := requireClassA(p.Context); err != nil {
+		return nil, err
+	}
+	payload, err := r.byIDRequest(p)
+	if err != nil {
+		return nil, err
+	}
+	return r.class.DeleteById(p.Context, payload)
+}
+
+func (r *resolver) createMajor(p graphql.ResolveParams) (interface{}, error) {
+	if err := requireClassA(p.Context); err != nil {
+		return nil, err
+	}
+	payload := new(dto.CreateMajorRequestBody)
+	if err := r.decode(p, payload); err != nil {
+		return nil, err
+	}
+	return r.major.Store(p.Context, payload)
+}
+
+func (r *resolver) updateMajor(p graphql.ResolveParams) (interface{}, error) {
+	if err := requireClassA(p.Context); err != nil {
+		return nil, err
+	}
+	payload := &dto.UpdateMajorRequestBody{IfMatch: ifMatch(p.Args)}
+	if err := r.decode(p, payload); err != nil {
+		return nil, err
+	}
+	return r.major.UpdateById(p.Context, payload)
+}
+
+func (r *resolver) deleteMajor(p graphql.ResolveParams) (interface{}, error) {
+	if err := requireClassA(p.Context); err != nil {
+		return nil, err
+	}
+	payload, err := r.byIDRequest(p)
+	if err != nil {
+		return nil, err
+	}
+	return r.major.DeleteById(p.Context, payload)
+}
+
+// updateStudent updates the authenticated student, only class A can update other students.
+func (r *resolver) updateStudent(p graphql.ResolveParams) (interface{}, error) {
+	input, _ := p.Args["input"].(map[string]interface{})
+	payload := &dto.UpdateStudentRequestBody{IfMatch: ifMatch(p.Args)}
+	if err := decodeArgs(input, payload); err != nil {
+		return nil, res.ErrorBuilder(res.ErrorConstant.BadRequest, err)
+	}
+	if err := decodeArgs(p.Args, payload); err != nil {
+		return nil, res.ErrorBuilder(res.ErrorConstant.BadRequest, err)
+	}
+	// before validating, the lookups of the validation tell whether an email is taken
+	claims := util.JWTClaimsFromContext(p.Context)
+	if (payload.ID == nil || claims.BID != *payload.ID) && claims.ClassID != uint(enum.A) {
+		return nil, res.ErrorBuilder(res.ErrorConstant.Unauthorized, errors.New("updating someone else"))
+	}
+	if err := r.validate(p, payload); err != nil {
+		return nil, err
+	}
+	return r.student.UpdateById(p.Context, payload)
+}
+
+// deleteStudent deletes the authenticated student if they are in class A, like DELETE
+// /students/:id.
+func (r *resolver) deleteStudent(p graphql.ResolveParams) (interface{}, error) {
+	payload, err := r.byIDRequest(p)
+	if err != nil {
+		return nil, err
+	}
+	claims := util.JWTClaimsFromContext(p.Context)
+	if claims.BID != payload.ID || claims.ClassID != uint(enum.A) {
+		return nil, res.ErrorBuilder(res.ErrorConstant.Unauthorized, errors.New("deleting a student"))
+	}
+	return r.student.DeleteById(p.Context, payload)
+}
+
+func (r *resolver) byIDRequest(p graphql.ResolveParams) (*pkgdto.ByIDRequest, error) {
+	payload := &pkgdto.ByIDRequest{IfMatch: ifMatch(p.Args)}
+	if err := r.decode(p, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// decode copies the arguments to the payload and validates it, like binding a request.
+func (r *resolver) decode(p graphql.ResolveParams, payload interface{}) error {
+	if err := decodeArgs(p.Args, payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err)
+	}
+	return r.validate(p, payload)
+}
+
+func (r *resolver) validate(p graphql.ResolveParams, payload interface{}) error {
+	if err := r.validator.ValidateCtx(p.Context, payload); err != nil {
+		return res.ValidationErrorBuilder(err)
+	}
+	return nil
+}
+
+func searchRequest(args map[string]interface{}) pkgdto.SearchGetRequest {
+	var payload pkgdto.SearchGetRequest
+	payload.Search, _ = args["search"].(string)
+	payload.AscField = stringList(args["ascField"])
+	payload.DscField = stringList(args["dscField"])
+	if page, ok := args["page"].(int); ok {
+		payload.Page = &page
+	}
+	if pageSize, ok := args["pageSize"].(int); ok {
+		payload.PageSize = &pageSize
+	}
+	return payload
+}
+
+func stringList(value interface{}) []string {
+	list, _ := value.([]interface{})
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func page(data interface{}, info pkgdto.PaginationInfo) map[string]interface{} {
+	return map[string]interface{}{"data": data, "pagination": info}
+}
+
+func ifMatch(args map[string]interface{}) string {
+	value, _ := args["ifMatch"].(string)
+	return value
+}
+
+func parseID(value interface{}) (uint, error) {
+	s, _ := value.(string)
+	id, err := strconv.ParseUint(s, 10, 0)
+	if err != nil {
+		return 0, res.ErrorBuilder(res.ErrorConstant.BadRequest, err)
+	}
+	return uint(id), nil
+}
+
+// studentDetail returns the student of a source with details, the results of deleting students
+// have none.
+func studentDetail(source interface{}) (*dto.StudentDetailResponse, bool) {
+	switch student := source.(type) {
+	case dto.StudentDetailResponse:
+		return &student, true
+	case *dto.StudentDetailResponse:
+		return student, true
+	}
+	return nil, false
+}
+
+// sourceID returns the id of a class or major response.
+func sourceID(source interface{}) uint {
+	value, _ := fieldValue(source, "id")
+	id, _ := value.(uint)
+	return id
+}
+
+func requireClassA(ctx context.Context) error {
+	if util.JWTClaimsFromContext(ctx).ClassID != uint(enum.A) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, errors.New("not in class A"))
+	}
+	return nil
+}
+
+// formatError reports the errors of the services with their localized message and error code,
+// like the REST API.
+func formatError(ctx context.Context, formatted gqlerrors.FormattedError) gqlerrors.FormattedError {
+	err := formatted.OriginalError()
+	if located, ok := err.(*gqlerrors.Error); ok && located.OriginalError != nil {
+		err = located.OriginalError
+	}
+	var re *res.Error
+	if !errors.As(err, &re) {
+		formatted.Extensions = map[string]interface{}{"code": res.ErrorConstant.BadRequest.Code()}
+		return formatted
+	}
+	if re.Err != nil {
+		logrus.Error(re.Err)
+	}
+
+	lang := i18n.FromContext(ctx)
+	formatted.Message = re.Kind.LocalizedMessage(lang)
+	formatted.Extensions = map[string]interface{}{"code": re.Kind.Code(), "status": re.Kind.Status()}
+	var validationErrors pkgutil.ValidationErrors
+	if errors.As(re.Err, &validationErrors) {
+		formatted.Extensions["errors"] = validationErrors.Localize(lang)
+	}
+	return formatted
+}
@@ -0,0 +1,144 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"student-service/database"
+	"student-service/database/seeder"
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/mocks"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	pkgdto "student-service/pkg/dto"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	adminClaims = util.CreateJWTClaims("vincentlhubbard@edu.ac.id", uint(1), uint(enum.A), uint(enum.Finance))
+	userClaims  = util.CreateJWTClaims("devoncthomas@edu.ac.id", uint(2), uint(enum.B), uint(enum.Finance))
+	db          = database.GetConnection()
+	echoMock    = mocks.EchoMock{E: echo.New()}
+	f           = factory.Factory{
+		JobRepository: repository.NewJobRepository(db),
+	}
+	jobHandler = NewHandler(&f)
+)
+
+func newSearchJobRequest() *dto.SearchJobRequest {
+	page, pageSize := 1, 10
+	return &dto.SearchJobRequest{Pagination: pkgdto.Pagination{Page: &page, PageSize: &pageSize}}
+}
+
+func jobRequest(t *testing.T, claims dto.JWTClaims, method, target string, params ...string) (echo.Context, func() (int, string)) {
+	c, rec := echoMock.RequestMock(method, target, bytes.NewBufferString(""))
+	// params are names followed by their values
+	if len(params) > 0 {
+		c.SetParamNames(params[:len(params)/2]...)
+		c.SetParamValues(params[len(params)/2:]...)
+	}
+	token, err := util.CreateJWTToken(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	return c, func() (int, string) { return rec.Code, rec.Body.String() }
+}
+
+func TestJobHandlerGet(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	enqueue(t, testJob, testPayload{Name: "Devon"})
+	enqueue(t, enum.JobWelcomeEmail, dto.WelcomeEmailJob{Email: "devoncthomas@edu.ac.id"})
+
+	cases := []struct {
+		name     string
+		claims   dto.JWTClaims
+		target   string
+		code     int
+		contains []string
+		excludes []string
+	}{
+		{"all", adminClaims, "/", 200, []string{`"type":"email.welcome"`, `"type":"test.job","payload":{"name":"Devon"},"status":"pending","attempts":0,"max_attempts":3`}, nil},
+		{"by type", adminClaims, "/?type=test.job", 200, []string{`"type":"test.job"`}, []string{"email.welcome"}},
+		{"by status", adminClaims, "/?status=dead", 200, []string{`"data":[]`}, nil},
+		{"unknown status", adminClaims, "/?status=lost", 400, []string{`"field":"status"`}, nil},
+		{"not class A", userClaims, "/", 401, []string{"unauthorized"}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, rec := jobRequest(t, tc.claims, http.MethodGet, tc.target)
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(jobHandler.Get(c)) {
+				code, body := rec()
+				asserts.Equal(tc.code, code)
+				for _, s := range tc.contains {
+					asserts.Contains(body, s)
+				}
+				for _, s := range tc.excludes {
+					asserts.NotContains(body, s)
+				}
+			}
+		})
+	}
+}
+
+func TestJobHandlerGetStats(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	enqueue(t, testJob, testPayload{})
+	enqueue(t, testJob, testPayload{})
+	w, _ := newTestWorker("worker-1")
+	w.Handle(testJob, Typed(func(ctx context.Context, payload testPayload) error { return nil }))
+	w.RunDue(ctx)
+	enqueue(t, testJob, testPayload{})
+
+	asserts := assert.New(t)
+	c, rec := jobRequest(t, adminClaims, http.MethodGet, "/stats")
+	if asserts.NoError(jobHandler.GetStats(c)) {
+		code, body := rec()
+		asserts.Equal(200, code)
+		asserts.Contains(body, `{"pending":1,"running":0,"succeeded":2,"dead":0}`)
+	}
+}
+
+func TestJobHandlerRetry(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	dead := enqueue(t, testJob, testPayload{})
+	w, _ := newTestWorker("worker-1")
+	w.RunDue(ctx)
+	pending := enqueue(t, testJob, testPayload{})
+
+	cases := []struct {
+		name     string
+		claims   dto.JWTClaims
+		id       uint
+		code     int
+		contains string
+	}{
+		{"not class A", userClaims, dead.ID, 401, "unauthorized"},
+		{"dead", adminClaims, dead.ID, 202, `"status":"pending","attempts":0`},
+		{"not dead", adminClaims, pending.ID, 409, "Only dead jobs can be retried"},
+		{"not found", adminClaims, pending.ID + 1, 404, "not found"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, rec := jobRequest(t, tc.claims, http.MethodPost, "/", "id", fmt.Sprint(tc.id))
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(jobHandler.Retry(c)) {
+				code, body := rec()
+				asserts.Equal(tc.code, code)
+				asserts.Contains(body, tc.contains)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/repository"
+	pkgutil "student-service/pkg/util"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Queue enqueues jobs for the workers.
+type Queue interface {
+	Enqueue(ctx context.Context, jobType enum.JobType, payload interface{}) error
+}
+
+type queue struct {
+	JobRepository repository.Job
+	MaxAttempts   int
+}
+
+func NewQueue(f *factory.Factory) Queue {
+	return &queue{
+		JobRepository: f.JobRepository,
+		MaxAttempts:   maxAttempts(),
+	}
+}
+
+// Enqueue adds a job of jobType with payload, run as soon as a worker is free. Called with a
+// transaction, the job only runs once it is committed.
+func (q *queue) Enqueue(ctx context.Context, jobType enum.JobType, payload interface{}) error {
+	if q.JobRepository == nil {
+		return nil
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return q.JobRepository.Save(ctx, &model.Job{
+		Type:        string(jobType),
+		Payload:     string(body),
+		Status:      string(enum.JobPending),
+		MaxAttempts: q.MaxAttempts,
+		RunAt:       time.Now(),
+	})
+}
+
+// maxAttempts returns how many times a job is attempted before it is dead, JOB_MAX_ATTEMPTS, 5
+// by default.
+func maxAttempts() int {
+	attempts, err := strconv.Atoi(pkgutil.Getenv("JOB_MAX_ATTEMPTS", "5"))
+	if err != nil || attempts <= 0 {
+		logrus.Warnf("invalid JOB_MAX_ATTEMPTS, using 5: %v", err)
+		return 5
+	}
+	return attempts
+}
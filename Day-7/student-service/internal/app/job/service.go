@@ -0,0 +1,111 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	pkgdto "student-service/pkg/dto"
+	res "student-service/pkg/util/response"
+)
+
+type service struct {
+	JobRepository repository.Job
+}
+
+type Service interface {
+	Find(ctx context.Context, payload *dto.SearchJobRequest) (*pkgdto.SearchGetResponse[dto.JobResponse], error)
+	FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.JobResponse, error)
+	Stats(ctx context.Context) (*dto.JobStatsResponse, error)
+	Retry(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.JobResponse, error)
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		JobRepository: f.JobRepository,
+	}
+}
+
+func (s *service) Find(ctx context.Context, payload *dto.SearchJobRequest) (*pkgdto.SearchGetResponse[dto.JobResponse], error) {
+	jobs, info, err := s.JobRepository.FindAll(ctx, payload)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	data := []dto.JobResponse{}
+	for i := range jobs {
+		data = append(data, newJobResponse(&jobs[i]))
+	}
+
+	result := new(pkgdto.SearchGetResponse[dto.JobResponse])
+	result.Data = data
+	result.PaginationInfo = *info
+
+	return result, nil
+}
+
+func (s *service) FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.JobResponse, error) {
+	job, err := s.JobRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return &dto.JobResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := newJobResponse(&job)
+	return &result, nil
+}
+
+func (s *service) Stats(ctx context.Context) (*dto.JobStatsResponse, error) {
+	counts, err := s.JobRepository.CountByStatus(ctx)
+	if err != nil {
+		return &dto.JobStatsResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	return &dto.JobStatsResponse{
+		Pending:   counts[string(enum.JobPending)],
+		Running:   counts[string(enum.JobRunning)],
+		Succeeded: counts[string(enum.JobSucceeded)],
+		Dead:      counts[string(enum.JobDead)],
+	}, nil
+}
+
+// Retry runs a dead job again, with all its attempts.
+func (s *service) Retry(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.JobResponse, error) {
+	job, err := s.JobRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return &dto.JobResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	if job.Status != string(enum.JobDead) {
+		return &dto.JobResponse{}, res.ErrorBuilder(res.ErrorConstant.JobNotDead, fmt.Errorf("job %d is %s", job.ID, job.Status))
+	}
+
+	if err := s.JobRepository.Retry(ctx, &job, time.Now()); err != nil {
+		return &dto.JobResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := newJobResponse(&job)
+	return &result, nil
+}
+
+func newJobResponse(job *model.Job) dto.JobResponse {
+	return dto.JobResponse{
+		ID:          job.ID,
+		Type:        job.Type,
+		Payload:     json.RawMessage(job.Payload),
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt,
+		LockedUntil: job.LockedUntil,
+		LastError:   job.LastError,
+		FinishedAt:  job.FinishedAt,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+}
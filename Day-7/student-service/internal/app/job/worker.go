@@ -0,0 +1,203 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	pkgutil "student-service/pkg/util"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxRetryDelay is the longest wait between two attempts of a job.
+	maxRetryDelay = time.Hour
+	// maxErrorLength is how much of the error of a failed attempt is kept.
+	maxErrorLength = 1024
+)
+
+// Handler runs a job with its payload. A job may run more than once, when an attempt fails or its
+// worker stops before recording the outcome, so handlers must be safe to repeat.
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// Typed returns the handler that decodes the payload into a T for fn. A job whose payload cannot
+// be decoded is dead at once.
+func Typed[T any](fn func(ctx context.Context, payload T) error) Handler {
+	return func(ctx context.Context, payload json.RawMessage) error {
+		var decoded T
+		if err := json.Unmarshal(payload, &decoded); err != nil {
+			return Permanent(fmt.Errorf("decode payload: %w", err))
+		}
+		return fn(ctx, decoded)
+	}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as a failure that retrying does not fix, the job is dead at once.
+func Permanent(err error) error {
+	return &permanentError{err}
+}
+
+// Worker runs the jobs of the queue with their handler. A failed job is retried with an
+// exponential backoff until it has run MaxAttempts times, it is then dead. A job whose worker did
+// not finish it within Visibility is run again by any worker.
+type Worker struct {
+	JobRepository repository.Job
+	Concurrency   int
+	PollInterval  time.Duration
+	Visibility    time.Duration
+	RetryBase     time.Duration
+	handlers      map[enum.JobType]Handler
+	owner         string
+	now           func() time.Time
+}
+
+func NewWorker(f *factory.Factory) *Worker {
+	return &Worker{
+		JobRepository: f.JobRepository,
+		Concurrency:   concurrency(),
+		PollInterval:  duration("JOB_POLL_INTERVAL", time.Second),
+		Visibility:    duration("JOB_VISIBILITY_TIMEOUT", 5*time.Minute),
+		RetryBase:     duration("JOB_RETRY_BASE", 10*time.Second),
+		handlers:      map[enum.JobType]Handler{},
+		owner:         util.InstanceID,
+		now:           time.Now,
+	}
+}
+
+// Handle runs the jobs of jobType with handler. Handlers are added before Run.
+func (w *Worker) Handle(jobType enum.JobType, handler Handler) {
+	w.handlers[jobType] = handler
+}
+
+// Run runs the due jobs on Concurrency goroutines, every PollInterval, until ctx is done. It then
+// returns once the running jobs are done, ctx does not cancel them.
+func (w *Worker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < w.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticker := time.NewTicker(w.PollInterval)
+			defer ticker.Stop()
+			for {
+				w.RunDue(ctx)
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// RunDue runs the due jobs one after another until none is left or ctx is done, and returns how
+// many it ran.
+func (w *Worker) RunDue(ctx context.Context) int {
+	ran := 0
+	for ctx.Err() == nil {
+		job, err := w.JobRepository.Claim(ctx, w.owner, w.now(), w.Visibility)
+		if err != nil {
+			logrus.Warnf("claim job: %v", err)
+			return ran
+		}
+		if job == nil {
+			return ran
+		}
+		w.run(job)
+		ran++
+	}
+	return ran
+}
+
+func (w *Worker) run(job *model.Job) {
+	var err error
+	if job.Attempts > job.MaxAttempts {
+		// the worker of the last attempt stopped before recording its outcome
+		err = Permanent(errors.New("last attempt did not finish within the visibility timeout"))
+	} else {
+		err = w.handle(job)
+	}
+
+	now := w.now()
+	switch {
+	case err == nil:
+		job.Status = string(enum.JobSucceeded)
+		job.FinishedAt = &now
+		job.LastError = ""
+	case errors.As(err, new(*permanentError)) || job.Attempts >= job.MaxAttempts:
+		job.Status = string(enum.JobDead)
+		job.FinishedAt = &now
+		job.LastError = err.Error()
+	default:
+		job.Status = string(enum.JobPending)
+		job.RunAt = now.Add(pkgutil.Backoff(w.RetryBase, maxRetryDelay, job.Attempts))
+		job.LastError = err.Error()
+	}
+	if len(job.LastError) > maxErrorLength {
+		job.LastError = job.LastError[:maxErrorLength]
+	}
+	if err != nil {
+		logrus.Warnf("job %d %s attempt %d: %v", job.ID, job.Type, job.Attempts, err)
+	}
+
+	if err := w.JobRepository.Edit(context.Background(), job); err != nil {
+		logrus.Warnf("record job %d: %v", job.ID, err)
+	}
+}
+
+// handle runs the handler of job. It is cancelled once the visibility timeout passed, as the job
+// can then be claimed again. A panic fails the attempt.
+func (w *Worker) handle(job *model.Job) (err error) {
+	handler, ok := w.handlers[enum.JobType(job.Type)]
+	if !ok {
+		return Permanent(fmt.Errorf("no handler for job type %s", job.Type))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.Visibility)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, json.RawMessage(job.Payload))
+}
+
+// concurrency returns how many jobs run at once, JOB_CONCURRENCY, 4 by default.
+func concurrency() int {
+	n, err := strconv.Atoi(pkgutil.Getenv("JOB_CONCURRENCY", "4"))
+	if err != nil || n <= 0 {
+		logrus.Warnf("invalid JOB_CONCURRENCY, using 4: %v", err)
+		return 4
+	}
+	return n
+}
+
+// duration returns the duration of the environment variable name, fallback when it is not set.
+func duration(name string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(pkgutil.Getenv(name, fallback.String()))
+	if err != nil || d <= 0 {
+		logrus.Warnf("invalid %s, using %s: %v", name, fallback, err)
+		return fallback
+	}
+	return d
+}
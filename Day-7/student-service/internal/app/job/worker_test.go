@@ -0,0 +1,221 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"student-service/database/seeder"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ctx = context.Background()
+
+const testJob enum.JobType = "test.job"
+
+type testPayload struct {
+	Name string `json:"name"`
+}
+
+func enqueue(t *testing.T, jobType enum.JobType, payload interface{}) model.Job {
+	q := &queue{JobRepository: f.JobRepository, MaxAttempts: 3}
+	if err := q.Enqueue(ctx, jobType, payload); err != nil {
+		t.Fatal(err)
+	}
+	jobs, _, err := f.JobRepository.FindAll(ctx, newSearchJobRequest())
+	if err != nil || len(jobs) == 0 {
+		t.Fatal("job was not enqueued", err)
+	}
+	return jobs[0]
+}
+
+func findJob(t *testing.T, id uint) model.Job {
+	job, err := f.JobRepository.FindByID(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+// newTestWorker returns a worker of owner whose clock is moved with advance. It starts at the
+// next whole second, after the jobs enqueued so far and without fractions the database rounds.
+func newTestWorker(owner string) (*Worker, func(time.Duration)) {
+	now := time.Now().Add(time.Second).Truncate(time.Second)
+	w := &Worker{
+		JobRepository: f.JobRepository,
+		Concurrency:   1,
+		PollInterval:  time.Second,
+		Visibility:    time.Minute,
+		RetryBase:     10 * time.Second,
+		handlers:      map[enum.JobType]Handler{},
+		owner:         owner,
+		now:           func() time.Time { return now },
+	}
+	return w, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestWorkerRunDue(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	job := enqueue(t, testJob, testPayload{Name: "Devon"})
+
+	var names []string
+	w, _ := newTestWorker("worker-1")
+	w.Handle(testJob, Typed(func(ctx context.Context, payload testPayload) error {
+		names = append(names, payload.Name)
+		return nil
+	}))
+
+	asserts := assert.New(t)
+	asserts.Equal(1, w.RunDue(ctx))
+	asserts.Equal(0, w.RunDue(ctx))
+	asserts.Equal([]string{"Devon"}, names)
+
+	job = findJob(t, job.ID)
+	asserts.Equal(string(enum.JobSucceeded), job.Status)
+	asserts.Equal(1, job.Attempts)
+	asserts.NotNil(job.FinishedAt)
+	asserts.Nil(job.LockedUntil)
+	asserts.Empty(job.LockedBy)
+}
+
+func TestWorkerRetriesUntilDead(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	job := enqueue(t, testJob, testPayload{})
+
+	w, advance := newTestWorker("worker-1")
+	w.Handle(testJob, func(ctx context.Context, payload json.RawMessage) error {
+		return errors.New("mail server unavailable")
+	})
+
+	asserts := assert.New(t)
+	asserts.Equal(1, w.RunDue(ctx))
+	job = findJob(t, job.ID)
+	asserts.Equal(string(enum.JobPending), job.Status)
+	asserts.Equal(1, job.Attempts)
+	asserts.Equal("mail server unavailable", job.LastError)
+
+	// not due before the backoff
+	advance(9 * time.Second)
+	asserts.Equal(0, w.RunDue(ctx))
+	advance(time.Second)
+	asserts.Equal(1, w.RunDue(ctx))
+	advance(20 * time.Second)
+	asserts.Equal(1, w.RunDue(ctx))
+
+	job = findJob(t, job.ID)
+	asserts.Equal(string(enum.JobDead), job.Status)
+	asserts.Equal(3, job.Attempts)
+	asserts.NotNil(job.FinishedAt)
+
+	advance(time.Hour)
+	asserts.Equal(0, w.RunDue(ctx))
+}
+
+func TestWorkerKillsJobsThatCannotSucceed(t *testing.T) {
+	cases := []struct {
+		name    string
+		handler Handler
+		payload interface{}
+		err     string
+	}{
+		{"no handler", nil, testPayload{}, "no handler for job type test.job"},
+		{"invalid payload", Typed(func(ctx context.Context, payload testPayload) error { return nil }), []int{1}, "decode payload: json: cannot unmarshal array"},
+		{"permanent error", func(ctx context.Context, payload json.RawMessage) error {
+			return Permanent(errors.New("no such student"))
+		}, testPayload{}, "no such student"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			job := enqueue(t, testJob, tc.payload)
+			w, _ := newTestWorker("worker-1")
+			if tc.handler != nil {
+				w.Handle(testJob, tc.handler)
+			}
+
+			asserts := assert.New(t)
+			asserts.Equal(1, w.RunDue(ctx))
+			job = findJob(t, job.ID)
+			asserts.Equal(string(enum.JobDead), job.Status)
+			asserts.Equal(1, job.Attempts)
+			asserts.Contains(job.LastError, tc.err)
+		})
+	}
+}
+
+func TestWorkerRecoversFromPanics(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	job := enqueue(t, testJob, testPayload{})
+
+	w, _ := newTestWorker("worker-1")
+	w.Handle(testJob, func(ctx context.Context, payload json.RawMessage) error {
+		panic("nil map")
+	})
+
+	asserts := assert.New(t)
+	asserts.Equal(1, w.RunDue(ctx))
+	job = findJob(t, job.ID)
+	asserts.Equal(string(enum.JobPending), job.Status)
+	asserts.Equal("panic: nil map", job.LastError)
+}
+
+func TestWorkerRunsJobsAgainAfterTheVisibilityTimeout(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	job := enqueue(t, testJob, testPayload{})
+
+	// the first worker claims the job and stops before recording its outcome
+	crashed, _ := newTestWorker("worker-1")
+	claimed, err := f.JobRepository.Claim(ctx, "worker-1", crashed.now(), crashed.Visibility)
+	asserts := assert.New(t)
+	if !asserts.NoError(err) || !asserts.NotNil(claimed) {
+		return
+	}
+
+	w, advance := newTestWorker("worker-2")
+	ran := 0
+	w.Handle(testJob, func(ctx context.Context, payload json.RawMessage) error {
+		ran++
+		return nil
+	})
+	asserts.Equal(0, w.RunDue(ctx))
+
+	advance(time.Minute + time.Second)
+	asserts.Equal(1, w.RunDue(ctx))
+	asserts.Equal(1, ran)
+	job = findJob(t, job.ID)
+	asserts.Equal(string(enum.JobSucceeded), job.Status)
+	asserts.Equal(2, job.Attempts)
+
+	// the outcome of the first worker is ignored
+	claimed.Status = string(enum.JobPending)
+	asserts.ErrorIs(f.JobRepository.Edit(ctx, claimed), repository.ErrStaleVersion)
+	asserts.Equal(string(enum.JobSucceeded), findJob(t, job.ID).Status)
+}
+
+func TestWorkerKillsJobsThatNeverFinish(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	job := enqueue(t, testJob, testPayload{})
+
+	w, advance := newTestWorker("worker-1")
+	w.Handle(testJob, func(ctx context.Context, payload json.RawMessage) error { return nil })
+	asserts := assert.New(t)
+	for i := 0; i < 3; i++ {
+		claimed, err := f.JobRepository.Claim(ctx, "worker-1", w.now(), w.Visibility)
+		if !asserts.NoError(err) || !asserts.NotNil(claimed) {
+			return
+		}
+		advance(time.Minute + time.Second)
+	}
+
+	asserts.Equal(1, w.RunDue(ctx))
+	job = findJob(t, job.ID)
+	asserts.Equal(string(enum.JobDead), job.Status)
+	asserts.Equal(4, job.Attempts)
+	asserts.Contains(job.LastError, "visibility timeout")
+}
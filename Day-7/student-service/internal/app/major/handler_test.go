@@ -26,7 +26,7 @@ var (
 	db                = database.GetConnection()
 	majorHandler      = NewHandler(&f)
 	echoMock          = mocks.EchoMock{E: echo.New()}
-	f                 = factory.Factory{MajorRepository: repository.NewMajorRepository(db)}
+	f                 = factory.Factory{MajorRepository: repository.NewMajorRepository(db), Transactor: repository.NewTransactor(db)}
 	testAClassID      = uint(enum.A)
 	testCreatePayload = dto.CreateMajorRequestBody{Name: &testMajorName}
 	testMajorID       = uint(enum.Finance)
@@ -510,3 +510,65 @@ func TestMajorHandlerCreateSuccess(t *testing.T) {
 		asserts.Contains(body, "name")
 	}
 }
+
+func TestMajorHandlerParallelErrors(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	token, err := util.CreateJWTToken(adminClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	emptyPayload, err := json.Marshal(dto.CreateMajorRequestBody{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// each request runs in parallel with the others on the same echo.Echo, and must get only
+	// its own error
+	cases := []struct {
+		name    string
+		method  string
+		handler echo.HandlerFunc
+		id      string
+		payload []byte
+		token   string
+		code    int
+		body    string
+	}{
+		{"invalid id", http.MethodGet, majorHandler.GetById, "a", nil, token, 400, "Bad Request"},
+		{"not found", http.MethodGet, majorHandler.GetById, "99", nil, token, 404, "Data not found"},
+		{"unauthorized", http.MethodGet, majorHandler.GetById, strconv.Itoa(int(testMajorID)), nil, "", 401, "unauthorized"},
+		{"validation", http.MethodPost, majorHandler.Create, "", emptyPayload, token, 400, `"field":"name"`},
+		{"success", http.MethodGet, majorHandler.GetById, strconv.Itoa(int(testMajorID)), nil, token, 200, testMajorName},
+	}
+	for i := 0; i < 20; i++ {
+		for _, tc := range cases {
+			tc := tc
+			t.Run(fmt.Sprintf("%s-%d", tc.name, i), func(t *testing.T) {
+				t.Parallel()
+
+				c, rec := echoMock.RequestMock(tc.method, "/", bytes.NewBuffer(tc.payload))
+				c.SetPath("/api/v1/majors")
+				c.Request().Header.Set("Content-Type", "application/json")
+				if tc.id != "" {
+					c.SetParamNames("id")
+					c.SetParamValues(tc.id)
+				}
+				if tc.token != "" {
+					c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", tc.token))
+				}
+
+				// testing
+				asserts := assert.New(t)
+				if asserts.NoError(tc.handler(c)) {
+					asserts.Equal(tc.code, rec.Code)
+					asserts.Contains(rec.Body.String(), tc.body)
+					if tc.name != "validation" {
+						asserts.NotContains(rec.Body.String(), `"errors"`)
+					}
+				}
+			})
+		}
+	}
+}
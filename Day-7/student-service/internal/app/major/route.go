@@ -10,9 +10,14 @@ import (
 
 func (h *handler) Route(g *echo.Group) {
 	g.Use(middleware.JWTMiddleware(dto.JWTClaims{}, util.JWT_SECRET))
-	g.GET("", h.Get)
-	g.GET("/:id", h.GetById)
+	httpCache := middleware.HTTPCacheMiddleware(middleware.ReferenceDataMaxAge())
+	g.GET("", h.Get, httpCache)
+	g.GET("/:id", h.GetById, httpCache)
 	g.PUT("/:id", h.UpdateById)
+	g.PATCH("/:id", h.PatchById)
 	g.DELETE("/:id", h.DeleteById)
-	g.POST("", h.Create)
+	g.POST("", h.Create, h.idempotency)
+	g.POST("/batch", h.BatchCreate, h.idempotency)
+	g.PUT("/batch", h.BatchUpdate)
+	g.DELETE("/batch", h.BatchDelete)
 }
@@ -3,45 +3,71 @@ package major
 import (
 	"context"
 	"errors"
+	"net/http"
 
+	"student-service/internal/app/outbox"
 	"student-service/internal/dto"
 	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
 	"student-service/internal/repository"
-	"student-service/pkg/constant"
+	"student-service/pkg/cache"
 	pkgdto "student-service/pkg/dto"
+	pkgutil "student-service/pkg/util"
 	res "student-service/pkg/util/response"
 )
 
 type service struct {
 	MajorRepository repository.Major
+	Validator       *pkgutil.CustomValidator
+	Transactor      repository.Transactor
+	Cache           cache.Cache
+	Events          outbox.Recorder
 }
 
 type Service interface {
 	Find(ctx context.Context, payload *pkgdto.SearchGetRequest) (*pkgdto.SearchGetResponse[dto.MajorResponse], error)
 	FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.MajorResponse, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]dto.MajorResponse, error)
 	Store(ctx context.Context, payload *dto.CreateMajorRequestBody) (*dto.MajorResponse, error)
 	UpdateById(ctx context.Context, payload *dto.UpdateMajorRequestBody) (*dto.MajorResponse, error)
+	PatchById(ctx context.Context, payload *pkgdto.PatchByIDRequest) (*dto.MajorResponse, error)
 	DeleteById(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.MajorWithCUDResponse, error)
+	BatchStore(ctx context.Context, payload *pkgdto.BatchRequest[dto.CreateMajorRequestBody]) *res.Batch
+	BatchUpdate(ctx context.Context, payload *pkgdto.BatchRequest[dto.UpdateMajorRequestBody]) *res.Batch
+	BatchDelete(ctx context.Context, payload *pkgdto.BatchRequest[pkgdto.ByIDRequest]) *res.Batch
 }
 
 func NewService(f *factory.Factory) Service {
 	return &service{
 		MajorRepository: f.MajorRepository,
+		Validator:       f.NewValidator(),
+		Transactor:      f.Transactor,
+		Cache:           f.Cache,
+		Events:          outbox.NewRecorder(f),
 	}
 }
 
 func (s *service) Find(ctx context.Context, payload *pkgdto.SearchGetRequest) (*pkgdto.SearchGetResponse[dto.MajorResponse], error) {
+	return cache.Fetch(ctx, s.Cache, cache.Key(util.MajorCachePrefix+"list:", payload), func() (*pkgdto.SearchGetResponse[dto.MajorResponse], error) {
+		return s.find(ctx, payload)
+	})
+}
+
+func (s *service) find(ctx context.Context, payload *pkgdto.SearchGetRequest) (*pkgdto.SearchGetResponse[dto.MajorResponse], error) {
 	majors, info, err := s.MajorRepository.FindAll(ctx, payload, &payload.Pagination)
 	if err != nil {
-		return nil, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return nil, util.RepositoryErrorBuilder(err)
 	}
 
 	var data []dto.MajorResponse
 
 	for _, major := range majors {
 		data = append(data, dto.MajorResponse{
-			ID:   major.ID,
-			Name: major.Name,
+			ID:      major.ID,
+			Name:    major.Name,
+			Version: major.Version,
 		})
 
 	}
@@ -53,83 +79,213 @@ func (s *service) Find(ctx context.Context, payload *pkgdto.SearchGetRequest) (*
 	return result, nil
 }
 func (s *service) FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.MajorResponse, error) {
+	return cache.Fetch(ctx, s.Cache, cache.Key(util.MajorCachePrefix+"id:", payload), func() (*dto.MajorResponse, error) {
+		return s.findByID(ctx, payload)
+	})
+}
+
+func (s *service) findByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.MajorResponse, error) {
 	var result dto.MajorResponse
 	data, err := s.MajorRepository.FindByID(ctx, payload.ID)
 	if err != nil {
-		if err == constant.RECORD_NOT_FOUND {
-			return &dto.MajorResponse{}, res.ErrorBuilder(&res.ErrorConstant.NotFound, err)
-		}
-		return &dto.MajorResponse{}, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return &dto.MajorResponse{}, util.RepositoryErrorBuilder(err)
 	}
 
 	result.ID = data.ID
 	result.Name = data.Name
+	result.Version = data.Version
 
 	return &result, nil
 }
 
+// FindByIDs returns the majors with the ids in one query, ordered by id. Missing ones are left
+// out.
+func (s *service) FindByIDs(ctx context.Context, ids []uint) ([]dto.MajorResponse, error) {
+	majors, err := s.MajorRepository.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	result := make([]dto.MajorResponse, 0, len(majors))
+	for _, major := range majors {
+		result = append(result, dto.MajorResponse{
+			ID:      major.ID,
+			Name:    major.Name,
+			Version: major.Version,
+		})
+	}
+	return result, nil
+}
+
 func (s *service) Store(ctx context.Context, payload *dto.CreateMajorRequestBody) (*dto.MajorResponse, error) {
+	defer s.invalidate(ctx)
+
 	var result dto.MajorResponse
 	isExist, err := s.MajorRepository.ExistByName(ctx, *payload.Name)
 	if err != nil {
-		return &result, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return &result, util.RepositoryErrorBuilder(err)
 	}
 	if isExist {
-		return &result, res.ErrorBuilder(&res.ErrorConstant.Duplicate, errors.New("major already exists"))
+		return &result, res.ErrorBuilder(res.ErrorConstant.Duplicate, errors.New("major already exists"))
 	}
 
-	data, err := s.MajorRepository.Save(ctx, payload)
+	err = s.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		data, err := s.MajorRepository.Save(ctx, payload)
+		if err != nil {
+			return err
+		}
+
+		result.ID = data.ID
+		result.Name = data.Name
+		result.Version = data.Version
+
+		return s.Events.Record(ctx, enum.MajorCreated, data.ID, &result)
+	})
 	if err != nil {
-		return &result, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return &dto.MajorResponse{}, util.RepositoryErrorBuilder(err)
 	}
 
-	result.ID = data.ID
-	result.Name = data.Name
-
 	return &result, nil
 }
 
 func (s *service) UpdateById(ctx context.Context, payload *dto.UpdateMajorRequestBody) (*dto.MajorResponse, error) {
+	defer s.invalidate(ctx)
+
 	major, err := s.MajorRepository.FindByID(ctx, *payload.ID)
 	if err != nil {
-		if err == constant.RECORD_NOT_FOUND {
-			return &dto.MajorResponse{}, res.ErrorBuilder(&res.ErrorConstant.NotFound, err)
+		return &dto.MajorResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	if !res.IfMatch(payload.IfMatch, major.Version) {
+		return &dto.MajorResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("major version does not match If-Match"))
+	}
+
+	var result dto.MajorResponse
+	err = s.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.MajorRepository.Edit(ctx, &major, payload); err != nil {
+			return err
 		}
-		return &dto.MajorResponse{}, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		result.ID = major.ID
+		result.Name = major.Name
+		result.Version = major.Version
+
+		return s.Events.Record(ctx, enum.MajorUpdated, major.ID, &result)
+	})
+	if err != nil {
+		return &dto.MajorResponse{}, util.RepositoryErrorBuilder(err)
 	}
 
-	_, err = s.MajorRepository.Edit(ctx, &major, payload)
+	return &result, nil
+}
+
+func (s *service) PatchById(ctx context.Context, payload *pkgdto.PatchByIDRequest) (*dto.MajorResponse, error) {
+	defer s.invalidate(ctx)
+
+	major, err := s.MajorRepository.FindByID(ctx, payload.ID)
 	if err != nil {
-		return &dto.MajorResponse{}, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return &dto.MajorResponse{}, util.RepositoryErrorBuilder(err)
 	}
+
+	if !res.IfMatch(payload.IfMatch, major.Version) {
+		return &dto.MajorResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("major version does not match If-Match"))
+	}
+
+	document := dto.MajorPatchDocument{Name: major.Name}
+	var patched dto.MajorPatchDocument
+	if err := pkgutil.ApplyPatch(payload.ContentType, payload.Patch, &document, &patched); err != nil {
+		return &dto.MajorResponse{}, util.PatchErrorBuilder(err)
+	}
+	patched.ID = major.ID
+	if err := s.Validator.ValidateCtx(ctx, &patched); err != nil {
+		return &dto.MajorResponse{}, res.ValidationErrorBuilder(err)
+	}
+
 	var result dto.MajorResponse
-	result.ID = major.ID
-	result.Name = major.Name
+	err = s.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.MajorRepository.Patch(ctx, &major, &patched); err != nil {
+			return err
+		}
+		result.ID = major.ID
+		result.Name = major.Name
+		result.Version = major.Version
+
+		return s.Events.Record(ctx, enum.MajorUpdated, major.ID, &result)
+	})
+	if err != nil {
+		return &dto.MajorResponse{}, util.RepositoryErrorBuilder(err)
+	}
 
 	return &result, nil
 }
+
 func (s *service) DeleteById(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.MajorWithCUDResponse, error) {
+	defer s.invalidate(ctx)
+
 	major, err := s.MajorRepository.FindByID(ctx, payload.ID)
 	if err != nil {
-		if err == constant.RECORD_NOT_FOUND {
-			return &dto.MajorWithCUDResponse{}, res.ErrorBuilder(&res.ErrorConstant.NotFound, err)
-		}
-		return &dto.MajorWithCUDResponse{}, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return &dto.MajorWithCUDResponse{}, util.RepositoryErrorBuilder(err)
 	}
-	_, err = s.MajorRepository.Destroy(ctx, &major)
+	if !res.IfMatch(payload.IfMatch, major.Version) {
+		return &dto.MajorWithCUDResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("major version does not match If-Match"))
+	}
+	var result *dto.MajorWithCUDResponse
+	err = s.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.MajorRepository.Destroy(ctx, &major); err != nil {
+			return err
+		}
+		result = toMajorWithCUDResponse(&major)
+
+		return s.Events.Record(ctx, enum.MajorDeleted, major.ID, result)
+	})
 	if err != nil {
-		return &dto.MajorWithCUDResponse{}, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return &dto.MajorWithCUDResponse{}, util.RepositoryErrorBuilder(err)
 	}
 
-	result := &dto.MajorWithCUDResponse{
+	return result, nil
+}
+
+func toMajorWithCUDResponse(major *model.Major) *dto.MajorWithCUDResponse {
+	return &dto.MajorWithCUDResponse{
 		MajorResponse: dto.MajorResponse{
-			ID:   major.ID,
-			Name: major.Name,
+			ID:      major.ID,
+			Name:    major.Name,
+			Version: major.Version,
 		},
 		CreatedAt: major.CreatedAt,
 		UpdatedAt: major.UpdatedAt,
 		DeletedAt: major.DeletedAt,
 	}
+}
 
-	return result, nil
+func (s *service) BatchStore(ctx context.Context, payload *pkgdto.BatchRequest[dto.CreateMajorRequestBody]) *res.Batch {
+	defer s.invalidate(ctx)
+
+	return util.RunBatch(ctx, s.Transactor, payload, http.StatusOK, s.Validator.ValidateCtx,
+		func(ctx context.Context, item *dto.CreateMajorRequestBody) (interface{}, error) {
+			return s.Store(ctx, item)
+		})
+}
+
+func (s *service) BatchUpdate(ctx context.Context, payload *pkgdto.BatchRequest[dto.UpdateMajorRequestBody]) *res.Batch {
+	defer s.invalidate(ctx)
+
+	return util.RunBatch(ctx, s.Transactor, payload, http.StatusOK, s.Validator.ValidateCtx,
+		func(ctx context.Context, item *dto.UpdateMajorRequestBody) (interface{}, error) {
+			return s.UpdateById(ctx, item)
+		})
+}
+
+func (s *service) BatchDelete(ctx context.Context, payload *pkgdto.BatchRequest[pkgdto.ByIDRequest]) *res.Batch {
+	defer s.invalidate(ctx)
+
+	return util.RunBatch(ctx, s.Transactor, payload, http.StatusOK, s.Validator.ValidateCtx,
+		func(ctx context.Context, item *pkgdto.ByIDRequest) (interface{}, error) {
+			return s.DeleteById(ctx, item)
+		})
+}
+
+// invalidate drops the cached majors, and the cached students, which include their major. Batches
+// invalidate again once they are done, reads during an atomic batch may cache uncommitted data.
+func (s *service) invalidate(ctx context.Context) {
+	cache.Invalidate(ctx, s.Cache, util.MajorCachePrefix, util.StudentCachePrefix)
 }
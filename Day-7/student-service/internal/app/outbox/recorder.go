@@ -0,0 +1,67 @@
+package outbox
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/repository"
+)
+
+// Recorder records domain events in the outbox.
+type Recorder interface {
+	Record(ctx context.Context, event enum.Event, aggregateID uint, data interface{}) error
+}
+
+type recorder struct {
+	OutboxRepository repository.Outbox
+}
+
+func NewRecorder(f *factory.Factory) Recorder {
+	return &recorder{
+		OutboxRepository: f.OutboxRepository,
+	}
+}
+
+// Record adds the event about the aggregate with aggregateID, the student, class or major named
+// by the event, to the outbox. It must be called with the transaction of the change, so that
+// the event is recorded if and only if the change is committed.
+func (r *recorder) Record(ctx context.Context, event enum.Event, aggregateID uint, data interface{}) error {
+	if r.OutboxRepository == nil {
+		return nil
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	id, err := newEventID()
+	if err != nil {
+		return err
+	}
+	aggregateType, _, _ := strings.Cut(string(event), ".")
+
+	return r.OutboxRepository.Save(ctx, &model.OutboxEvent{
+		EventID:       id,
+		Type:          string(event),
+		AggregateType: aggregateType,
+		AggregateID:   strconv.FormatUint(uint64(aggregateID), 10),
+		Data:          string(body),
+		OccurredAt:    time.Now(),
+	})
+}
+
+func newEventID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "evt_" + hex.EncodeToString(b), nil
+}
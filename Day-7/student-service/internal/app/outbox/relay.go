@@ -0,0 +1,182 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	"student-service/pkg/events"
+	pkgutil "student-service/pkg/util"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// leaseName is the lease the relaying replica holds, one replica relays at a time so that the
+	// events of an aggregate stay in order.
+	leaseName = "outbox-relay"
+	leaseTTL  = 30 * time.Second
+	// publishTimeout bounds publishing an event to the brokers. It is shorter than half of
+	// leaseTTL, the lease is renewed once half of it has passed, so it outlasts each publish.
+	publishTimeout = 10 * time.Second
+	// batchSize is how many events are read at once.
+	batchSize = 100
+	// retryBase and maxRetryDelay bound the wait before publishing a failed event again.
+	retryBase     = time.Second
+	maxRetryDelay = 10 * time.Minute
+	// maxErrorLength is how much of the error of a failed attempt is kept.
+	maxErrorLength = 1024
+)
+
+// Relay publishes the events recorded in the outbox to the in-process subscribers of the event
+// bus, then to the broker. An event is published at least once: it is published again, to all
+// of them, until they all accept it. The events of an aggregate are published in the order they
+// were recorded.
+type Relay struct {
+	OutboxRepository repository.Outbox
+	LeaseRepository  repository.Lease
+	Brokers          []events.Broker
+	PollInterval     time.Duration
+	owner            string
+	now              func() time.Time
+}
+
+func NewRelay(f *factory.Factory) *Relay {
+	brokers := []events.Broker{f.EventBus}
+	if f.EventBroker != nil {
+		brokers = append(brokers, f.EventBroker)
+	}
+	return &Relay{
+		OutboxRepository: f.OutboxRepository,
+		LeaseRepository:  f.LeaseRepository,
+		Brokers:          brokers,
+		PollInterval:     pollInterval(),
+		owner:            util.InstanceID,
+		now:              time.Now,
+	}
+}
+
+// Run publishes the recorded events every PollInterval until ctx is done, as long as this
+// replica holds the relay lease.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.PollInterval)
+	defer ticker.Stop()
+	for {
+		// a full batch is followed by the next one right away
+		if r.PublishPending(ctx) == batchSize && ctx.Err() == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			if err := r.LeaseRepository.Release(context.Background(), leaseName, r.owner); err != nil {
+				logrus.Warnf("release %s lease: %v", leaseName, err)
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishPending publishes the oldest unpublished events if this replica holds the relay lease,
+// and returns how many it attempted. A failed event holds back the next events of its aggregate
+// until it is published, the events of other aggregates are published meanwhile. The lease is
+// renewed while the events are published, they are left for later once it is lost.
+func (r *Relay) PublishPending(ctx context.Context) int {
+	now := r.now()
+	if !r.acquireLease(ctx, now) {
+		return 0
+	}
+	leaseUntil := now.Add(leaseTTL)
+
+	pending, err := r.OutboxRepository.FindUnpublished(ctx, now, batchSize)
+	if err != nil {
+		logrus.Warnf("find unpublished events: %v", err)
+		return 0
+	}
+
+	heldBack := map[string]bool{}
+	attempted := 0
+	for i := range pending {
+		event := &pending[i]
+		aggregate := event.AggregateType + ":" + event.AggregateID
+		if heldBack[aggregate] {
+			continue
+		}
+
+		now = r.now()
+		if now.Add(leaseTTL / 2).After(leaseUntil) {
+			if !r.acquireLease(ctx, now) {
+				break
+			}
+			leaseUntil = now.Add(leaseTTL)
+		}
+
+		attempted++
+		if err := r.publish(ctx, event); err != nil {
+			logrus.Warnf("publish event %s: %v", event.EventID, err)
+			heldBack[aggregate] = true
+			event.Attempts++
+			next := now.Add(pkgutil.Backoff(retryBase, maxRetryDelay, event.Attempts))
+			event.NextAttemptAt = &next
+			event.LastError = err.Error()
+			if len(event.LastError) > maxErrorLength {
+				event.LastError = event.LastError[:maxErrorLength]
+			}
+		} else {
+			event.PublishedAt = &now
+			event.NextAttemptAt = nil
+			event.LastError = ""
+		}
+
+		// the outcome is recorded even when ctx is done, the attempt was made
+		if err := r.OutboxRepository.Edit(context.Background(), event); err != nil {
+			logrus.Warnf("record event %s: %v", event.EventID, err)
+			heldBack[aggregate] = true
+		}
+	}
+	return attempted
+}
+
+// acquireLease acquires or renews the relay lease and reports whether this replica holds it.
+func (r *Relay) acquireLease(ctx context.Context, now time.Time) bool {
+	held, err := r.LeaseRepository.Acquire(ctx, leaseName, r.owner, now, leaseTTL)
+	if err != nil {
+		logrus.Warnf("acquire %s lease: %v", leaseName, err)
+		return false
+	}
+	return held
+}
+
+func (r *Relay) publish(ctx context.Context, event *model.OutboxEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+
+	message := events.Event{
+		ID:            event.EventID,
+		Type:          event.Type,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		OccurredAt:    event.OccurredAt,
+		Data:          json.RawMessage(event.Data),
+	}
+	for _, broker := range r.Brokers {
+		if err := broker.Publish(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pollInterval returns how often the outbox is read, OUTBOX_POLL_INTERVAL, 1 second by default.
+func pollInterval() time.Duration {
+	interval, err := time.ParseDuration(pkgutil.Getenv("OUTBOX_POLL_INTERVAL", "1s"))
+	if err != nil || interval <= 0 {
+		logrus.Warnf("invalid OUTBOX_POLL_INTERVAL, using 1s: %v", err)
+		return time.Second
+	}
+	return interval
+}
@@ -0,0 +1,237 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"student-service/database"
+	"student-service/database/seeder"
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/repository"
+	"student-service/pkg/events"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ctx = context.Background()
+	db  = database.GetConnection()
+	f   = factory.Factory{
+		Transactor:       repository.NewTransactor(db),
+		OutboxRepository: repository.NewOutboxRepository(db),
+		LeaseRepository:  repository.NewLeaseRepository(db),
+	}
+)
+
+func record(t *testing.T, event enum.Event, aggregateID uint) {
+	if err := NewRecorder(&f).Record(ctx, event, aggregateID, dto.MajorResponse{ID: aggregateID}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// broker records the ids of the events it accepts, failing those in fail. It calls before, if
+// set, before each event.
+type broker struct {
+	published []string
+	fail      map[string]bool
+	before    func()
+}
+
+func (b *broker) Publish(ctx context.Context, event events.Event) error {
+	if b.before != nil {
+		b.before()
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		return errors.New("published without a timeout")
+	}
+	if b.fail[event.Type+":"+event.AggregateID] {
+		return errors.New("broker unavailable")
+	}
+	b.published = append(b.published, event.Type+":"+event.AggregateID)
+	return nil
+}
+
+// newTestRelay returns a relay of owner whose clock is moved with advance. It starts at the
+// next whole second, without fractions the database rounds.
+func newTestRelay(owner string, brokers ...events.Broker) (*Relay, func(time.Duration)) {
+	now := time.Now().Add(time.Second).Truncate(time.Second)
+	r := &Relay{
+		OutboxRepository: f.OutboxRepository,
+		LeaseRepository:  f.LeaseRepository,
+		Brokers:          brokers,
+		PollInterval:     time.Second,
+		owner:            owner,
+		now:              func() time.Time { return now },
+	}
+	return r, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestRecorderRecordsWithTheTransaction(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+
+	asserts := assert.New(t)
+	err := f.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := NewRecorder(&f).Record(ctx, enum.MajorDeleted, 1, dto.MajorResponse{ID: 1}); err != nil {
+			return err
+		}
+		return errors.New("rolled back")
+	})
+	asserts.EqualError(err, "rolled back")
+	pending, err := f.OutboxRepository.FindUnpublished(ctx, time.Now(), batchSize)
+	asserts.NoError(err)
+	asserts.Empty(pending)
+
+	asserts.NoError(f.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return NewRecorder(&f).Record(ctx, enum.MajorDeleted, 1, dto.MajorResponse{ID: 1, Name: "Law"})
+	}))
+	pending, err = f.OutboxRepository.FindUnpublished(ctx, time.Now(), batchSize)
+	asserts.NoError(err)
+	if asserts.Len(pending, 1) {
+		asserts.Equal("major.deleted", pending[0].Type)
+		asserts.Equal("major", pending[0].AggregateType)
+		asserts.Equal("1", pending[0].AggregateID)
+		asserts.Equal(`{"id":1,"name":"Law","version":0}`, pending[0].Data)
+		asserts.Contains(pending[0].EventID, "evt_")
+	}
+}
+
+func TestRelayPublishPending(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	record(t, enum.MajorCreated, 1)
+	record(t, enum.ClassCreated, 1)
+	record(t, enum.MajorUpdated, 1)
+
+	var handled []events.Event
+	bus := events.NewBus()
+	bus.Subscribe(events.All, func(ctx context.Context, event events.Event) error {
+		handled = append(handled, event)
+		return nil
+	})
+	b := &broker{}
+	r, _ := newTestRelay("replica-1", bus, b)
+
+	asserts := assert.New(t)
+	asserts.Equal(3, r.PublishPending(ctx))
+	asserts.Equal(0, r.PublishPending(ctx))
+	asserts.Equal([]string{"major.created:1", "class.created:1", "major.updated:1"}, b.published)
+	if asserts.Len(handled, 3) {
+		asserts.Equal("major", handled[0].AggregateType)
+		asserts.JSONEq(`{"id":1,"name":"","version":0}`, string(handled[0].Data))
+	}
+}
+
+func TestRelayHoldsBackTheAggregateOfAFailedEvent(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	record(t, enum.MajorCreated, 1)
+	record(t, enum.MajorCreated, 2)
+	record(t, enum.MajorUpdated, 1)
+
+	b := &broker{fail: map[string]bool{"major.created:1": true}}
+	r, advance := newTestRelay("replica-1", b)
+
+	asserts := assert.New(t)
+	asserts.Equal(2, r.PublishPending(ctx))
+	asserts.Equal([]string{"major.created:2"}, b.published)
+	pending, err := f.OutboxRepository.FindUnpublished(ctx, r.now().Add(retryBase), batchSize)
+	asserts.NoError(err)
+	if asserts.Len(pending, 2) {
+		asserts.Equal(1, pending[0].Attempts)
+		asserts.Equal("broker unavailable", pending[0].LastError)
+		asserts.Equal(0, pending[1].Attempts)
+	}
+
+	// not due before the backoff
+	b.fail = nil
+	asserts.Equal(0, r.PublishPending(ctx))
+	advance(retryBase)
+	asserts.Equal(2, r.PublishPending(ctx))
+	asserts.Equal([]string{"major.created:2", "major.created:1", "major.updated:1"}, b.published)
+}
+
+func TestRelayPublishesOtherAggregatesWhileOneIsHeldBack(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	record(t, enum.MajorCreated, 1)
+	for i := 0; i < batchSize; i++ {
+		record(t, enum.MajorUpdated, 1)
+	}
+	record(t, enum.MajorCreated, 2)
+
+	b := &broker{fail: map[string]bool{"major.created:1": true}}
+	r, _ := newTestRelay("replica-1", b)
+
+	// the held back events filled the first batch, they are left out of the next ones
+	asserts := assert.New(t)
+	asserts.Equal(1, r.PublishPending(ctx))
+	asserts.Equal(1, r.PublishPending(ctx))
+	asserts.Equal([]string{"major.created:2"}, b.published)
+}
+
+func TestRelayPublishesOnlyWithTheLease(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	record(t, enum.StudentDeleted, 3)
+
+	b := &broker{}
+	first, _ := newTestRelay("replica-1", b)
+	second, _ := newTestRelay("replica-2", b)
+
+	asserts := assert.New(t)
+	asserts.Equal(1, first.PublishPending(ctx))
+	record(t, enum.StudentRegistered, 4)
+	asserts.Equal(0, second.PublishPending(ctx))
+
+	asserts.NoError(f.LeaseRepository.Release(ctx, leaseName, "replica-1"))
+	asserts.Equal(1, second.PublishPending(ctx))
+	asserts.Equal([]string{"student.deleted:3", "student.registered:4"}, b.published)
+}
+
+func TestRelayRenewsTheLeaseWhilePublishing(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	for i := uint(1); i <= 5; i++ {
+		record(t, enum.StudentDeleted, i)
+	}
+
+	b := &broker{}
+	first, advance := newTestRelay("replica-1", b)
+	second, _ := newTestRelay("replica-2", b)
+	// each event takes 10 seconds, the batch outlasts the lease it started with
+	b.before = func() { advance(10 * time.Second) }
+
+	asserts := assert.New(t)
+	asserts.Equal(5, first.PublishPending(ctx))
+	held, err := f.LeaseRepository.Acquire(ctx, leaseName, "replica-2", first.now(), leaseTTL)
+	asserts.NoError(err)
+	asserts.False(held)
+	asserts.Equal(0, second.PublishPending(ctx))
+	asserts.Len(b.published, 5)
+}
+
+func TestRelayStopsWhenTheLeaseIsLost(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	for i := uint(1); i <= 5; i++ {
+		record(t, enum.StudentDeleted, i)
+	}
+
+	b := &broker{}
+	r, advance := newTestRelay("replica-1", b)
+	// the second event outlasts the lease, another replica takes it over meanwhile
+	b.before = func() {
+		if len(b.published) == 1 {
+			advance(leaseTTL + time.Second)
+			held, err := f.LeaseRepository.Acquire(ctx, leaseName, "replica-2", r.now(), leaseTTL)
+			if err != nil || !held {
+				t.Errorf("replica-2 did not take the lease over: %v", err)
+			}
+		}
+	}
+
+	asserts := assert.New(t)
+	asserts.Equal(2, r.PublishPending(ctx))
+	asserts.Equal([]string{"student.deleted:1", "student.deleted:2"}, b.published)
+	pending, err := f.OutboxRepository.FindUnpublished(ctx, r.now(), batchSize)
+	asserts.NoError(err)
+	asserts.Len(pending, 3)
+}
@@ -0,0 +1,150 @@
+package room
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"student-service/database"
+	"student-service/database/seeder"
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/mocks"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	adminClaims = util.CreateJWTClaims("vincentlhubbard@edu.ac.id", uint(1), uint(enum.A), uint(enum.Finance))
+	userClaims  = util.CreateJWTClaims("devoncthomas@edu.ac.id", uint(2), uint(enum.B), uint(enum.Finance))
+	db          = database.GetConnection()
+	echoMock    = mocks.EchoMock{E: echo.New()}
+	f           = factory.Factory{
+		RoomRepository: repository.NewRoomRepository(db),
+	}
+	roomHandler = NewHandler(&f)
+)
+
+func roomRequest(t *testing.T, claims dto.JWTClaims, method, payload string, id string) (echo.Context, func() (int, string)) {
+	c, rec := echoMock.RequestMock(method, "/", bytes.NewBufferString(payload))
+	if id != "" {
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+	}
+	token, err := util.CreateJWTToken(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	c.Request().Header.Set("Content-Type", "application/json")
+	return c, func() (int, string) { return rec.Code, rec.Body.String() }
+}
+
+// tomorrowAt returns the given hour of tomorrow, the seeded booking of room 1 is from 09:00 to 11:00.
+func tomorrowAt(hour int) string {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day()+1, hour, 0, 0, 0, time.Local).Format(time.RFC3339)
+}
+
+func TestRoomHandlerCreate(t *testing.T) {
+	cases := []struct {
+		name     string
+		claims   dto.JWTClaims
+		payload  string
+		code     int
+		contains string
+	}{
+		{"success", adminClaims, `{"name":"Meeting Room 2","capacity":8,"facilities":[" TV ","tv","Projector"]}`, 200, `"capacity":8,"facilities":["tv","projector"]`},
+		{"duplicate name", adminClaims, `{"name":"Auditorium","capacity":8}`, 400, `"field":"name"`},
+		{"no capacity", adminClaims, `{"name":"Meeting Room 2"}`, 400, `"field":"capacity"`},
+		{"not class A", userClaims, `{"name":"Meeting Room 2","capacity":8}`, 401, "unauthorized"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			seeder.NewSeeder().SeedAll()
+
+			c, rec := roomRequest(t, tc.claims, http.MethodPost, tc.payload, "")
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(roomHandler.Create(c)) {
+				code, body := rec()
+				asserts.Equal(tc.code, code)
+				asserts.Contains(body, tc.contains)
+			}
+		})
+	}
+}
+
+func TestRoomHandlerGetAvailable(t *testing.T) {
+	cases := []struct {
+		name     string
+		query    url.Values
+		code     int
+		contains []string
+		excludes []string
+	}{
+		{"overlapping booking", url.Values{"starts_at": {tomorrowAt(10)}, "ends_at": {tomorrowAt(12)}}, 200, []string{`"name":"Auditorium"`}, []string{`"name":"Meeting Room 1"`}},
+		{"back to back", url.Values{"starts_at": {tomorrowAt(11)}, "ends_at": {tomorrowAt(12)}}, 200, []string{`"name":"Auditorium"`, `"name":"Meeting Room 1"`}, nil},
+		{"facility", url.Values{"starts_at": {tomorrowAt(11)}, "ends_at": {tomorrowAt(12)}, "facility": {"TV", "whiteboard"}}, 200, []string{`"name":"Meeting Room 1"`}, []string{`"name":"Auditorium"`}},
+		{"capacity", url.Values{"starts_at": {tomorrowAt(11)}, "ends_at": {tomorrowAt(12)}, "capacity": {"10"}}, 200, []string{`"name":"Auditorium"`}, []string{`"name":"Meeting Room 1"`}},
+		{"ends before it starts", url.Values{"starts_at": {tomorrowAt(12)}, "ends_at": {tomorrowAt(11)}}, 422, []string{"Booking must end after it starts"}, nil},
+		{"no window", url.Values{}, 400, []string{`"field":"starts_at"`}, nil},
+	}
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, rec := roomRequest(t, userClaims, http.MethodGet, "", "")
+			c.Request().URL.RawQuery = tc.query.Encode()
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(roomHandler.GetAvailable(c)) {
+				code, body := rec()
+				asserts.Equal(tc.code, code)
+				for _, contains := range tc.contains {
+					asserts.Contains(body, contains)
+				}
+				for _, excludes := range tc.excludes {
+					asserts.NotContains(body, excludes)
+				}
+			}
+		})
+	}
+}
+
+func TestRoomHandlerDeleteById(t *testing.T) {
+	cases := []struct {
+		name   string
+		roomID string
+		code   int
+	}{
+		{"room without bookings", "2", 200},
+		{"room with upcoming bookings", "1", 422},
+		{"not found", "99", 404},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			seeder.NewSeeder().SeedAll()
+
+			c, rec := roomRequest(t, adminClaims, http.MethodDelete, "", tc.roomID)
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(roomHandler.DeleteById(c)) {
+				code, _ := rec()
+				asserts.Equal(tc.code, code)
+			}
+		})
+	}
+}
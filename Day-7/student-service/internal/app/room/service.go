@@ -0,0 +1,186 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	pkgdto "student-service/pkg/dto"
+	res "student-service/pkg/util/response"
+)
+
+type service struct {
+	RoomRepository repository.Room
+}
+
+type Service interface {
+	Find(ctx context.Context, payload *dto.SearchRoomRequest) (*pkgdto.SearchGetResponse[dto.RoomResponse], error)
+	FindAvailable(ctx context.Context, payload *dto.AvailableRoomRequest) ([]dto.RoomResponse, error)
+	FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.RoomResponse, error)
+	Store(ctx context.Context, payload *dto.CreateRoomRequestBody) (*dto.RoomResponse, error)
+	UpdateById(ctx context.Context, payload *dto.UpdateRoomRequestBody) (*dto.RoomResponse, error)
+	DeleteById(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.RoomWithCUDResponse, error)
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		RoomRepository: f.RoomRepository,
+	}
+}
+
+func (s *service) Find(ctx context.Context, payload *dto.SearchRoomRequest) (*pkgdto.SearchGetResponse[dto.RoomResponse], error) {
+	rooms, info, err := s.RoomRepository.FindAll(ctx, payload, &payload.Pagination)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	var data []dto.RoomResponse
+	for i := range rooms {
+		data = append(data, newRoomResponse(&rooms[i]))
+	}
+
+	result := new(pkgdto.SearchGetResponse[dto.RoomResponse])
+	result.Data = data
+	result.PaginationInfo = *info
+
+	return result, nil
+}
+
+// FindAvailable returns the rooms that are free during the whole time window, smallest first.
+func (s *service) FindAvailable(ctx context.Context, payload *dto.AvailableRoomRequest) ([]dto.RoomResponse, error) {
+	if !payload.EndsAt.After(*payload.StartsAt) {
+		return nil, res.ErrorBuilder(res.ErrorConstant.InvalidBookingTime, errors.New("time window must end after it starts"))
+	}
+
+	rooms, err := s.RoomRepository.FindAvailable(ctx, payload)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	result := []dto.RoomResponse{}
+	for i := range rooms {
+		result = append(result, newRoomResponse(&rooms[i]))
+	}
+	return result, nil
+}
+
+func (s *service) FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.RoomResponse, error) {
+	room, err := s.RoomRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return &dto.RoomResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := newRoomResponse(&room)
+	return &result, nil
+}
+
+func (s *service) Store(ctx context.Context, payload *dto.CreateRoomRequestBody) (*dto.RoomResponse, error) {
+	room := model.Room{
+		Name:       *payload.Name,
+		Capacity:   *payload.Capacity,
+		Facilities: newFacilities(payload.Facilities),
+	}
+
+	if err := s.RoomRepository.Save(ctx, &room); err != nil {
+		return &dto.RoomResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := newRoomResponse(&room)
+	return &result, nil
+}
+
+func (s *service) UpdateById(ctx context.Context, payload *dto.UpdateRoomRequestBody) (*dto.RoomResponse, error) {
+	room, err := s.RoomRepository.FindByID(ctx, *payload.ID)
+	if err != nil {
+		return &dto.RoomResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	if !res.IfMatch(payload.IfMatch, room.Version) {
+		return &dto.RoomResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("room version does not match If-Match"))
+	}
+
+	updateData := model.Room{Name: room.Name, Capacity: room.Capacity}
+	if payload.Name != nil {
+		updateData.Name = *payload.Name
+	}
+	if payload.Capacity != nil {
+		updateData.Capacity = *payload.Capacity
+	}
+	if payload.Facilities != nil {
+		updateData.Facilities = newFacilities(payload.Facilities)
+	}
+
+	_, err = s.RoomRepository.Edit(ctx, &room, &updateData)
+	if err != nil {
+		return &dto.RoomResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := newRoomResponse(&room)
+	return &result, nil
+}
+
+func (s *service) DeleteById(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.RoomWithCUDResponse, error) {
+	room, err := s.RoomRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return &dto.RoomWithCUDResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	if !res.IfMatch(payload.IfMatch, room.Version) {
+		return &dto.RoomWithCUDResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("room version does not match If-Match"))
+	}
+	booked, err := s.RoomRepository.HasBookingsAfter(ctx, room.ID, time.Now())
+	if err != nil {
+		return &dto.RoomWithCUDResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	if booked {
+		return &dto.RoomWithCUDResponse{}, res.ErrorBuilder(res.ErrorConstant.InvalidReference, errors.New("room still has upcoming bookings"))
+	}
+
+	_, err = s.RoomRepository.Destroy(ctx, &room)
+	if err != nil {
+		return &dto.RoomWithCUDResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := &dto.RoomWithCUDResponse{
+		RoomResponse: newRoomResponse(&room),
+		CreatedAt:    room.CreatedAt,
+		UpdatedAt:    room.UpdatedAt,
+		DeletedAt:    room.DeletedAt,
+	}
+
+	return result, nil
+}
+
+// newFacilities returns the facilities with the names in lower case, without duplicates.
+func newFacilities(names []string) []model.RoomFacility {
+	facilities := []model.RoomFacility{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		facilities = append(facilities, model.RoomFacility{Name: name})
+	}
+	return facilities
+}
+
+func newRoomResponse(room *model.Room) dto.RoomResponse {
+	result := dto.RoomResponse{
+		ID:         room.ID,
+		Name:       room.Name,
+		Capacity:   room.Capacity,
+		Facilities: []string{},
+		Version:    room.Version,
+	}
+	for _, facility := range room.Facilities {
+		result.Facilities = append(result.Facilities, facility.Name)
+	}
+	return result
+}
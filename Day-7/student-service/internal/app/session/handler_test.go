@@ -0,0 +1,184 @@
+package session
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"student-service/database"
+	"student-service/database/seeder"
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/mocks"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	pkgutil "student-service/pkg/util"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	adminClaims = util.CreateJWTClaims("vincentlhubbard@edu.ac.id", uint(1), uint(enum.A), uint(enum.Finance))
+	userClaims  = util.CreateJWTClaims("devoncthomas@edu.ac.id", uint(2), uint(enum.B), uint(enum.Finance))
+	db          = database.GetConnection()
+	echoMock    = mocks.EchoMock{E: echo.New()}
+	f           = factory.Factory{
+		ClassRepository:        repository.NewClassRepository(db),
+		StudentRepository:      repository.NewStudentRepository(db),
+		TermRepository:         repository.NewTermRepository(db),
+		ClassSessionRepository: repository.NewClassSessionRepository(db),
+		Transactor:             repository.NewTransactor(db),
+	}
+	sessionHandler = NewHandler(&f)
+)
+
+func sessionRequest(t *testing.T, claims dto.JWTClaims, method, payload string, id string) (echo.Context, func() (int, string)) {
+	c, rec := echoMock.RequestMock(method, "/", bytes.NewBufferString(payload))
+	if id != "" {
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+	}
+	token, err := util.CreateJWTToken(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	c.Request().Header.Set("Content-Type", "application/json")
+	return c, func() (int, string) { return rec.Code, rec.Body.String() }
+}
+
+func TestSessionHandlerCreate(t *testing.T) {
+	today := time.Now().Format(pkgutil.DateLayout)
+	cases := []struct {
+		name     string
+		claims   dto.JWTClaims
+		payload  string
+		code     int
+		contains string
+	}{
+		{"success in the active term", adminClaims, fmt.Sprintf(`{"class_id":1,"date":"%s","topic":"Ledgers"}`, today), 200, fmt.Sprintf(`"class_id":1,"term_id":2,"date":"%s","topic":"Ledgers","attendances":[]`, today)},
+		{"date outside of the term", adminClaims, `{"class_id":1,"date":"2000-01-01"}`, 422, "Session date is outside of its term"},
+		{"closed term", adminClaims, fmt.Sprintf(`{"class_id":1,"term_id":1,"date":"%s"}`, today), 409, "Term is closed"},
+		{"unknown class", adminClaims, fmt.Sprintf(`{"class_id":99,"date":"%s"}`, today), 400, `"field":"class_id"`},
+		{"invalid date", adminClaims, `{"class_id":1,"date":"tomorrow"}`, 400, `"field":"date"`},
+		{"not class A", userClaims, fmt.Sprintf(`{"class_id":1,"date":"%s"}`, today), 401, "unauthorized"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			seeder.NewSeeder().SeedAll()
+
+			c, rec := sessionRequest(t, tc.claims, http.MethodPost, tc.payload, "")
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(sessionHandler.Create(c)) {
+				code, body := rec()
+				asserts.Equal(tc.code, code)
+				asserts.Contains(body, tc.contains)
+			}
+		})
+	}
+}
+
+func TestSessionHandlerGet(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	cases := []struct {
+		name    string
+		classID string
+		termID  string
+		total   string
+	}{
+		{"class in the active term", "2", "", `"count":2`},
+		{"class without sessions", "1", "", `"count":0`},
+		{"other term", "2", "3", `"count":0`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, rec := sessionRequest(t, userClaims, http.MethodGet, "", "")
+			c.QueryParams().Set("class_id", tc.classID)
+			if tc.termID != "" {
+				c.QueryParams().Set("term_id", tc.termID)
+			}
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(sessionHandler.Get(c)) {
+				code, body := rec()
+				asserts.Equal(200, code)
+				asserts.Contains(body, tc.total)
+			}
+		})
+	}
+}
+
+func TestSessionHandlerMarkAttendance(t *testing.T) {
+	cases := []struct {
+		name     string
+		claims   dto.JWTClaims
+		ifMatch  string
+		payload  string
+		code     int
+		contains string
+	}{
+		{"success", adminClaims, `"1"`, `{"records":[{"student_id":2,"status":"late","note":"Bus"}]}`, 200, `{"student_id":2,"status":"late","note":"Bus"`},
+		{"keeps students not in the records", adminClaims, "", `{"records":[{"student_id":2,"status":"absent"}]}`, 200, `{"student_id":3,"status":"late","note":""`},
+		{"student not in the class", adminClaims, "", `{"records":[{"student_id":1,"status":"present"}]}`, 422, "Student is not in the class"},
+		{"unknown status", adminClaims, "", `{"records":[{"student_id":2,"status":"sleeping"}]}`, 400, `"field":"records[0].status"`},
+		{"no records", adminClaims, "", `{"records":[]}`, 400, `"field":"records"`},
+		{"stale version", adminClaims, `"2"`, `{"records":[{"student_id":2,"status":"late"}]}`, 412, "Data has been modified"},
+		{"not class A", userClaims, "", `{"records":[{"student_id":2,"status":"present"}]}`, 401, "unauthorized"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			seeder.NewSeeder().SeedAll()
+
+			c, rec := sessionRequest(t, tc.claims, http.MethodPut, tc.payload, "1")
+			if tc.ifMatch != "" {
+				c.Request().Header.Set("If-Match", tc.ifMatch)
+			}
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(sessionHandler.MarkAttendance(c)) {
+				code, body := rec()
+				asserts.Equal(tc.code, code)
+				asserts.Contains(body, tc.contains)
+			}
+		})
+	}
+}
+
+func TestSessionHandlerClosedTerm(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	if err := db.Table("terms").Where("id = ?", 2).Update("closed", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	asserts := assert.New(t)
+
+	c, rec := sessionRequest(t, adminClaims, http.MethodPut, `{"records":[{"student_id":2,"status":"present"}]}`, "1")
+	if asserts.NoError(sessionHandler.MarkAttendance(c)) {
+		code, _ := rec()
+		asserts.Equal(409, code)
+	}
+
+	c, rec = sessionRequest(t, adminClaims, http.MethodPut, `{"topic":"Renamed"}`, "1")
+	if asserts.NoError(sessionHandler.UpdateById(c)) {
+		code, _ := rec()
+		asserts.Equal(409, code)
+	}
+
+	c, rec = sessionRequest(t, adminClaims, http.MethodDelete, "", "1")
+	if asserts.NoError(sessionHandler.DeleteById(c)) {
+		code, _ := rec()
+		asserts.Equal(409, code)
+	}
+}
@@ -0,0 +1,260 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	pkgdto "student-service/pkg/dto"
+	pkgutil "student-service/pkg/util"
+	res "student-service/pkg/util/response"
+)
+
+type service struct {
+	ClassSessionRepository repository.ClassSession
+	StudentRepository      repository.Student
+	TermRepository         repository.Term
+	Transactor             repository.Transactor
+}
+
+type Service interface {
+	Find(ctx context.Context, payload *dto.SearchClassSessionRequest) (*pkgdto.SearchGetResponse[dto.ClassSessionResponse], error)
+	FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.ClassSessionResponse, error)
+	Store(ctx context.Context, payload *dto.CreateClassSessionRequestBody) (*dto.ClassSessionResponse, error)
+	UpdateById(ctx context.Context, payload *dto.UpdateClassSessionRequestBody) (*dto.ClassSessionResponse, error)
+	DeleteById(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.ClassSessionWithCUDResponse, error)
+	MarkAttendance(ctx context.Context, payload *dto.MarkAttendanceRequestBody) (*dto.ClassSessionResponse, error)
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		ClassSessionRepository: f.ClassSessionRepository,
+		StudentRepository:      f.StudentRepository,
+		TermRepository:         f.TermRepository,
+		Transactor:             f.Transactor,
+	}
+}
+
+// Find lists the sessions in the term, the active term when it is not set.
+func (s *service) Find(ctx context.Context, payload *dto.SearchClassSessionRequest) (*pkgdto.SearchGetResponse[dto.ClassSessionResponse], error) {
+	term, err := util.FindTerm(ctx, s.TermRepository, payload.TermID)
+	if err != nil {
+		return nil, err
+	}
+	payload.TermID = &term.ID
+
+	sessions, info, err := s.ClassSessionRepository.FindAll(ctx, payload, &payload.Pagination)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	var data []dto.ClassSessionResponse
+	for i := range sessions {
+		data = append(data, newClassSessionResponse(&sessions[i]))
+	}
+
+	result := new(pkgdto.SearchGetResponse[dto.ClassSessionResponse])
+	result.Data = data
+	result.PaginationInfo = *info
+
+	return result, nil
+}
+
+func (s *service) FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.ClassSessionResponse, error) {
+	session, err := s.ClassSessionRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return &dto.ClassSessionResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := newClassSessionResponse(&session)
+	return &result, nil
+}
+
+func (s *service) Store(ctx context.Context, payload *dto.CreateClassSessionRequestBody) (*dto.ClassSessionResponse, error) {
+	term, err := util.FindTerm(ctx, s.TermRepository, payload.TermID)
+	if err != nil {
+		return &dto.ClassSessionResponse{}, err
+	}
+	if err := util.CheckTermOpen(term); err != nil {
+		return &dto.ClassSessionResponse{}, err
+	}
+
+	session := model.ClassSession{
+		ClassID: *payload.ClassID,
+		TermID:  term.ID,
+		Topic:   payload.Topic,
+	}
+	if session.Date, err = sessionDate(term, *payload.Date); err != nil {
+		return &dto.ClassSessionResponse{}, err
+	}
+
+	err = util.WithinOpenTerm(ctx, s.Transactor, s.TermRepository, term.ID, func(ctx context.Context) error {
+		return s.ClassSessionRepository.Save(ctx, &session)
+	})
+	if err != nil {
+		return &dto.ClassSessionResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := newClassSessionResponse(&session)
+	return &result, nil
+}
+
+func (s *service) UpdateById(ctx context.Context, payload *dto.UpdateClassSessionRequestBody) (*dto.ClassSessionResponse, error) {
+	session, err := s.ClassSessionRepository.FindByID(ctx, *payload.ID)
+	if err != nil {
+		return &dto.ClassSessionResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	if !res.IfMatch(payload.IfMatch, session.Version) {
+		return &dto.ClassSessionResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("class session version does not match If-Match"))
+	}
+	if err := util.CheckTermOpen(&session.Term); err != nil {
+		return &dto.ClassSessionResponse{}, err
+	}
+
+	updateData := model.ClassSession{Date: session.Date, Topic: session.Topic}
+	if payload.Date != nil {
+		if updateData.Date, err = sessionDate(&session.Term, *payload.Date); err != nil {
+			return &dto.ClassSessionResponse{}, err
+		}
+	}
+	if payload.Topic != nil {
+		updateData.Topic = *payload.Topic
+	}
+
+	err = util.WithinOpenTerm(ctx, s.Transactor, s.TermRepository, session.TermID, func(ctx context.Context) error {
+		_, err := s.ClassSessionRepository.Edit(ctx, &session, &updateData)
+		return err
+	})
+	if err != nil {
+		return &dto.ClassSessionResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := newClassSessionResponse(&session)
+	return &result, nil
+}
+
+func (s *service) DeleteById(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.ClassSessionWithCUDResponse, error) {
+	session, err := s.ClassSessionRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return &dto.ClassSessionWithCUDResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	if !res.IfMatch(payload.IfMatch, session.Version) {
+		return &dto.ClassSessionWithCUDResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("class session version does not match If-Match"))
+	}
+	if err := util.CheckTermOpen(&session.Term); err != nil {
+		return &dto.ClassSessionWithCUDResponse{}, err
+	}
+
+	err = util.WithinOpenTerm(ctx, s.Transactor, s.TermRepository, session.TermID, func(ctx context.Context) error {
+		_, err := s.ClassSessionRepository.Destroy(ctx, &session)
+		return err
+	})
+	if err != nil {
+		return &dto.ClassSessionWithCUDResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := &dto.ClassSessionWithCUDResponse{
+		ClassSessionResponse: newClassSessionResponse(&session),
+		CreatedAt:            session.CreatedAt,
+		UpdatedAt:            session.UpdatedAt,
+		DeletedAt:            session.DeletedAt,
+	}
+
+	return result, nil
+}
+
+// MarkAttendance marks the attendance of students of the class in the session. Every student
+// must currently be in the class of the session.
+func (s *service) MarkAttendance(ctx context.Context, payload *dto.MarkAttendanceRequestBody) (*dto.ClassSessionResponse, error) {
+	session, err := s.ClassSessionRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return &dto.ClassSessionResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	if !res.IfMatch(payload.IfMatch, session.Version) {
+		return &dto.ClassSessionResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("class session version does not match If-Match"))
+	}
+	if err := util.CheckTermOpen(&session.Term); err != nil {
+		return &dto.ClassSessionResponse{}, err
+	}
+
+	students, err := s.StudentRepository.FindByClassID(ctx, session.ClassID, nil)
+	if err != nil {
+		return &dto.ClassSessionResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	inClass := map[uint]bool{}
+	for _, student := range students {
+		inClass[student.ID] = true
+	}
+
+	var (
+		attendances []model.Attendance
+		notInClass  []uint
+	)
+	for _, record := range payload.Records {
+		if !inClass[*record.StudentID] {
+			notInClass = append(notInClass, *record.StudentID)
+			continue
+		}
+		attendances = append(attendances, model.Attendance{
+			StudentID: *record.StudentID,
+			Status:    *record.Status,
+			Note:      record.Note,
+		})
+	}
+	if len(notInClass) > 0 {
+		return &dto.ClassSessionResponse{}, res.ErrorBuilder(res.ErrorConstant.StudentNotInClass, fmt.Errorf("students %v are not in class %d", notInClass, session.ClassID))
+	}
+
+	err = util.WithinOpenTerm(ctx, s.Transactor, s.TermRepository, session.TermID, func(ctx context.Context) error {
+		_, err := s.ClassSessionRepository.MarkAttendance(ctx, &session, attendances)
+		return err
+	})
+	if err != nil {
+		return &dto.ClassSessionResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := newClassSessionResponse(&session)
+	return &result, nil
+}
+
+// sessionDate parses the date of a session, which must be within the dates of its term.
+func sessionDate(term *model.Term, value string) (time.Time, error) {
+	date, err := pkgutil.ParseDate(value)
+	if err != nil {
+		return date, res.ErrorBuilder(res.ErrorConstant.BadRequest, err)
+	}
+	if date.Before(term.StartDate) || date.After(term.EndDate) {
+		return date, res.ErrorBuilder(res.ErrorConstant.InvalidSessionDate, fmt.Errorf("%s is not between %s and %s", value,
+			term.StartDate.Format(pkgutil.DateLayout), term.EndDate.Format(pkgutil.DateLayout)))
+	}
+	return date, nil
+}
+
+func newClassSessionResponse(session *model.ClassSession) dto.ClassSessionResponse {
+	result := dto.ClassSessionResponse{
+		ID:          session.ID,
+		ClassID:     session.ClassID,
+		TermID:      session.TermID,
+		Date:        session.Date.Format(pkgutil.DateLayout),
+		Topic:       session.Topic,
+		Attendances: []dto.AttendanceResponse{},
+		Version:     session.Version,
+	}
+	for _, attendance := range session.Attendances {
+		result.Attendances = append(result.Attendances, dto.AttendanceResponse{
+			StudentID: attendance.StudentID,
+			Status:    attendance.Status,
+			Note:      attendance.Note,
+			MarkedAt:  attendance.UpdatedAt,
+		})
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package statistics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"student-service/database"
+	"student-service/database/seeder"
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/mocks"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	adminClaims = util.CreateJWTClaims("vincentlhubbard@edu.ac.id", uint(1), uint(enum.A), uint(enum.Finance))
+	userClaims  = util.CreateJWTClaims("devoncthomas@edu.ac.id", uint(2), uint(enum.B), uint(enum.Finance))
+	db          = database.GetConnection()
+	echoMock    = mocks.EchoMock{E: echo.New()}
+	f           = factory.Factory{
+		StatisticRepository: repository.NewStatisticRepository(db),
+	}
+	statisticsHandler = NewHandler(&f)
+)
+
+func get(t *testing.T, claims dto.JWTClaims) (int, string) {
+	c, rec := echoMock.RequestMock(http.MethodGet, "/", bytes.NewBufferString(""))
+	token, err := util.CreateJWTToken(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	if err := statisticsHandler.Get(c); err != nil {
+		t.Fatal(err)
+	}
+	return rec.Code, rec.Body.String()
+}
+
+func TestStatisticsHandlerGet(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	if err := db.Delete(&model.Student{}, 3).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	asserts := assert.New(t)
+	code, body := get(t, userClaims)
+	asserts.Equal(401, code)
+	asserts.Contains(body, "unauthorized")
+
+	// computed on the first request, before the task ran
+	code, body = get(t, adminClaims)
+	asserts.Equal(200, code)
+	asserts.Contains(body, `"students":2,"classes":2,"majors":3,"courses":3`)
+	asserts.Contains(body, `"students_per_major":[{"id":1,"name":"Finance","students":2},{"id":2,"name":"Information Technology","students":0}`)
+	asserts.Contains(body, `"students_per_class":[{"id":1,"name":"A","students":1},{"id":2,"name":"B","students":1}]`)
+
+	// served as computed until the task computes them again
+	if err := db.Delete(&model.Student{}, 2).Error; err != nil {
+		t.Fatal(err)
+	}
+	_, body = get(t, adminClaims)
+	asserts.Contains(body, `"students":2,`)
+
+	_, err := NewService(&f).Compute(context.Background())
+	asserts.NoError(err)
+	_, body = get(t, adminClaims)
+	asserts.Contains(body, `"students":1,`)
+}
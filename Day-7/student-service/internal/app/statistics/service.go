@@ -0,0 +1,68 @@
+package statistics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+)
+
+// overview is the name the statistics are saved under.
+const overview = "overview"
+
+type service struct {
+	StatisticRepository repository.Statistic
+}
+
+type Service interface {
+	Find(ctx context.Context) (*dto.StatisticsResponse, error)
+	Compute(ctx context.Context) (*dto.StatisticsResponse, error)
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		StatisticRepository: f.StatisticRepository,
+	}
+}
+
+// Find returns the statistics as last computed by the scheduled task, they are computed now
+// when the task has not run yet.
+func (s *service) Find(ctx context.Context) (*dto.StatisticsResponse, error) {
+	statistic, err := s.StatisticRepository.Find(ctx, overview)
+	if errors.Is(err, repository.ErrNotFound) {
+		return s.Compute(ctx)
+	}
+	if err != nil {
+		return &dto.StatisticsResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := new(dto.StatisticsResponse)
+	if err := json.Unmarshal([]byte(statistic.Data), result); err != nil {
+		return &dto.StatisticsResponse{}, err
+	}
+	return result, nil
+}
+
+// Compute counts the records again and saves the statistics.
+func (s *service) Compute(ctx context.Context) (*dto.StatisticsResponse, error) {
+	result, err := s.StatisticRepository.Compute(ctx)
+	if err != nil {
+		return &dto.StatisticsResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	result.ComputedAt = time.Now()
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return &dto.StatisticsResponse{}, err
+	}
+	if err := s.StatisticRepository.Save(ctx, &model.Statistic{Name: overview, Data: string(data), ComputedAt: result.ComputedAt}); err != nil {
+		return &dto.StatisticsResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	return &result, nil
+}
@@ -9,6 +9,7 @@ import (
 	"student-service/internal/pkg/enum"
 	"student-service/internal/pkg/util"
 	pkgdto "student-service/pkg/dto"
+	"student-service/pkg/i18n"
 	res "student-service/pkg/util/response"
 
 	"github.com/labstack/echo/v4"
@@ -28,16 +29,16 @@ func (h *handler) Get(c echo.Context) error {
 	authHeader := c.Request().Header.Get("Authorization")
 	_, err := util.ParseJWTToken(authHeader)
 	if err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.Unauthorized, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
 	}
 
-	payload := new(pkgdto.SearchGetRequest)
+	payload := new(dto.SearchStudentRequest)
 	if err := c.Bind(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.BadRequest, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
 	}
 
-	if err := c.Validate(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.Validation, err).Send(c)
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
 	}
 
 	result, err := h.service.Find(c.Request().Context(), payload)
@@ -45,22 +46,22 @@ func (h *handler) Get(c echo.Context) error {
 		return res.ErrorResponse(err).Send(c)
 	}
 
-	return res.CustomSuccessBuilder(http.StatusOK, result.Data, "Get students success", &result.PaginationInfo).Send(c)
+	return res.CustomSuccessBuilder(http.StatusOK, result.Data, i18n.GetStudentsSuccess, &result.PaginationInfo).Send(c)
 }
 
 func (h *handler) GetById(c echo.Context) error {
 	authHeader := c.Request().Header.Get("Authorization")
 	_, err := util.ParseJWTToken(authHeader)
 	if err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.Unauthorized, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
 	}
 
 	payload := new(pkgdto.ByIDRequest)
 	if err := c.Bind(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.BadRequest, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
 	}
-	if err := c.Validate(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.Validation, err).Send(c)
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
 	}
 
 	result, err := h.service.FindByID(c.Request().Context(), payload)
@@ -68,44 +69,70 @@ func (h *handler) GetById(c echo.Context) error {
 		return res.ErrorResponse(err).Send(c)
 	}
 
+	c.Response().Header().Set(res.HeaderETag, detailETag(result))
 	return res.SuccessResponse(result).Send(c)
 }
 
 func (h *handler) UpdateById(c echo.Context) error {
 	payload := new(dto.UpdateStudentRequestBody)
 	if err := c.Bind(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.BadRequest, err).Send(c)
-	}
-	if err := c.Validate(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.Validation, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
 	}
+	payload.IfMatch = c.Request().Header.Get(res.HeaderIfMatch)
 	authHeader := c.Request().Header.Get("Authorization")
 	jwtClaims, err := util.ParseJWTToken(authHeader)
-	isAOrSameB := (jwtClaims.BID == *payload.ID) || (jwtClaims.ClassID == uint(enum.A))
+	isAOrSameB := (payload.ID != nil && jwtClaims.BID == *payload.ID) || (jwtClaims.ClassID == uint(enum.A))
 	log.Println(isAOrSameB)
 	if (err != nil) || !isAOrSameB {
-		return res.ErrorBuilder(&res.ErrorConstant.Unauthorized, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
 	}
 	result, err := h.service.UpdateById(c.Request().Context(), payload)
 	if err != nil {
 		return res.ErrorResponse(err).Send(c)
 	}
 
+	c.Response().Header().Set(res.HeaderETag, detailETag(result))
+	return res.SuccessResponse(result).Send(c)
+}
+
+func (h *handler) PatchById(c echo.Context) error {
+	payload := new(pkgdto.PatchByIDRequest)
+	if err := util.BindPatch(c, payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if (err != nil) || !((jwtClaims.BID == payload.ID) || (jwtClaims.ClassID == uint(enum.A))) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+	result, err := h.service.PatchById(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	c.Response().Header().Set(res.HeaderETag, detailETag(result))
 	return res.SuccessResponse(result).Send(c)
 }
 
 func (h *handler) DeleteById(c echo.Context) error {
 	payload := new(pkgdto.ByIDRequest)
 	if err := c.Bind(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.BadRequest, err).Send(c)
-	}
-	if err := c.Validate(payload); err != nil {
-		return res.ErrorBuilder(&res.ErrorConstant.Validation, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
 	}
+	payload.IfMatch = c.Request().Header.Get(res.HeaderIfMatch)
 	authHeader := c.Request().Header.Get("Authorization")
 	jwtClaims, err := util.ParseJWTToken(authHeader)
 	if (err != nil) || (jwtClaims.BID != payload.ID) || (jwtClaims.ClassID != uint(enum.A)) {
-		return res.ErrorBuilder(&res.ErrorConstant.Unauthorized, err).Send(c)
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
 	}
 	result, err := h.service.DeleteById(c.Request().Context(), payload)
 	if err != nil {
@@ -114,3 +141,81 @@ func (h *handler) DeleteById(c echo.Context) error {
 
 	return res.SuccessResponse(result).Send(c)
 }
+
+// GetCourses returns the courses the student is enrolled in, only those in a term when
+// term_id is set.
+func (h *handler) GetCourses(c echo.Context) error {
+	payload := new(dto.ByIDInTermRequest)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if (err != nil) || !((jwtClaims.BID == payload.ID) || (jwtClaims.ClassID == uint(enum.A))) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+	result, err := h.service.FindCourses(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	return res.SuccessResponse(result).Send(c)
+}
+
+// GetTranscript returns the transcript of the student, only to the student and to class A.
+func (h *handler) GetTranscript(c echo.Context) error {
+	payload := new(pkgdto.ByIDRequest)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if (err != nil) || !((jwtClaims.BID == payload.ID) || (jwtClaims.ClassID == uint(enum.A))) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+	result, err := h.service.Transcript(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	return res.SuccessResponse(result).Send(c)
+}
+
+// GetAttendance returns the attendance summary of the student in a term, the active term when
+// term_id is not set.
+func (h *handler) GetAttendance(c echo.Context) error {
+	payload := new(dto.ByIDInTermRequest)
+	if err := c.Bind(payload); err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+	}
+	authHeader := c.Request().Header.Get("Authorization")
+	jwtClaims, err := util.ParseJWTToken(authHeader)
+	if (err != nil) || !((jwtClaims.BID == payload.ID) || (jwtClaims.ClassID == uint(enum.A))) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, err).Send(c)
+	}
+	if err := util.Validate(c, payload); err != nil {
+		return res.ValidationErrorBuilder(err).Send(c)
+	}
+	result, err := h.service.Attendance(c.Request().Context(), payload)
+	if err != nil {
+		return res.ErrorResponse(err).Send(c)
+	}
+
+	return res.SuccessResponse(result).Send(c)
+}
+
+// detailETag returns the entity tag of a student with the versions of the class and major it
+// embeds, renaming them changes it too.
+func detailETag(student *dto.StudentDetailResponse) string {
+	var classVersion uint
+	if student.Class != nil {
+		classVersion = student.Class.Version
+	}
+	return res.ETag(student.Version, classVersion, student.Major.Version)
+}
@@ -1,21 +1,28 @@
 package student
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"testing"
 
 	"student-service/database"
 	"student-service/database/seeder"
+	"student-service/internal/dto"
 	"student-service/internal/factory"
+	"student-service/internal/middleware"
 	"student-service/internal/mocks"
+	"student-service/internal/model"
 	"student-service/internal/pkg/enum"
 	"student-service/internal/pkg/util"
 	"student-service/internal/repository"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
 var (
@@ -24,11 +31,21 @@ var (
 	db             = database.GetConnection()
 	echoMock       = mocks.EchoMock{E: echo.New()}
 	studentHandler = NewHandler(&f)
-	f              = factory.Factory{StudentRepository: repository.NewStudentRepository(db)}
-	testAClassID   = uint(enum.A)
-	testMajorID    = uint(enum.Finance)
-	testEmail      = "vincentlhubbard@edu.ac.id"
-	testStudentID  = uint(1)
+	f              = factory.Factory{
+		StudentRepository: repository.NewStudentRepository(db),
+		MajorRepository:   repository.NewMajorRepository(db),
+		ClassRepository:   repository.NewClassRepository(db),
+		CourseRepository:  repository.NewCourseRepository(db),
+		GradeRepository:   repository.NewGradeRepository(db),
+		TermRepository:    repository.NewTermRepository(db),
+		Transactor:        repository.NewTransactor(db),
+
+		ClassSessionRepository: repository.NewClassSessionRepository(db),
+	}
+	testAClassID  = uint(enum.A)
+	testMajorID   = uint(enum.Finance)
+	testEmail     = "vincentlhubbard@edu.ac.id"
+	testStudentID = uint(1)
 )
 
 func TestStudentHandlerGetInvalidPayload(t *testing.T) {
@@ -255,6 +272,44 @@ func TestStudentHandlerUpdateByIdUnauthorized(t *testing.T) {
 	}
 }
 
+// Someone else's student answers 401 whatever the payload, the validation of the email must not
+// tell whether it is taken.
+func TestStudentHandlerUpdateByIdUnauthorizedBeforeValidation(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	token, err := util.CreateJWTToken(userClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		payload string
+	}{
+		{"taken email", fmt.Sprintf(`{"email":%q}`, testEmail)},
+		{"free email", `{"email":"free@edu.ac.id"}`},
+		{"missing class", `{"class_id":99}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, rec := echoMock.RequestMock(http.MethodPut, "/", bytes.NewBufferString(tc.payload))
+			c.SetPath("/api/v1/students")
+			c.SetParamNames("id")
+			c.SetParamValues(strconv.Itoa(int(3)))
+			c.Request().Header.Set("Content-Type", "application/json")
+			c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(studentHandler.UpdateById(c)) {
+				asserts.Equal(401, rec.Code)
+				asserts.NotContains(rec.Body.String(), `"errors"`)
+			}
+		})
+	}
+}
+
 func TestStudentHandlerUpdateByIdSuccess(t *testing.T) {
 	seeder.NewSeeder().DeleteAll()
 	seeder.NewSeeder().SeedAll()
@@ -376,3 +431,482 @@ func TestStudentHandlerDeleteByIdSuccess(t *testing.T) {
 		asserts.Contains(body, "deleted_at")
 	}
 }
+
+func TestStudentHandlerGetByIdETag(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	c, rec := echoMock.RequestMock(http.MethodGet, "/", nil)
+	token, err := util.CreateJWTToken(adminClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.SetPath("/api/v1/students")
+	c.SetParamNames("id")
+	c.SetParamValues(strconv.Itoa(int(testStudentID)))
+	c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	// testing
+	asserts := assert.New(t)
+	if asserts.NoError(studentHandler.GetById(c)) {
+		asserts.Equal(200, rec.Code)
+		asserts.Equal(`"1.1.1"`, rec.Header().Get("ETag"))
+		asserts.Contains(rec.Body.String(), `"version":1`)
+	}
+}
+
+func TestStudentHandlerGetByIdRevalidatesEmbeddedRecords(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	token, err := util.CreateJWTToken(adminClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	getById := middleware.HTTPCacheMiddleware(0)(studentHandler.GetById)
+	revalidate := func(etag string) (int, string) {
+		c, rec := echoMock.RequestMock(http.MethodGet, "/", nil)
+		c.SetPath("/api/v1/students")
+		c.SetParamNames("id")
+		c.SetParamValues(strconv.Itoa(int(testStudentID)))
+		c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+		c.Request().Header.Add("If-None-Match", etag)
+		if err := getById(c); err != nil {
+			t.Fatal(err)
+		}
+		return rec.Code, rec.Body.String()
+	}
+
+	asserts := assert.New(t)
+	code, _ := revalidate(`"1.1.1"`)
+	asserts.Equal(304, code)
+
+	// renaming the class of the student does not change the student
+	err = db.Model(&model.Class{}).Where("id = ?", testAClassID).
+		Updates(map[string]interface{}{"name": "A1", "version": gorm.Expr("version + 1")}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, body := revalidate(`"1.1.1"`)
+	asserts.Equal(200, code)
+	asserts.Contains(body, `"name":"A1"`)
+}
+
+func TestStudentHandlerUpdateByIdPreconditionFailed(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	c, rec := echoMock.RequestMock(http.MethodPut, "/", strings.NewReader(`{"fullname":"Vincent"}`))
+	token, err := util.CreateJWTToken(adminClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.SetPath("/api/v1/students")
+	c.SetParamNames("id")
+	c.SetParamValues(strconv.Itoa(int(testStudentID)))
+	c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	c.Request().Header.Add("Content-Type", "application/json")
+	c.Request().Header.Add("If-Match", `"2"`)
+
+	// testing
+	asserts := assert.New(t)
+	if asserts.NoError(studentHandler.UpdateById(c)) {
+		asserts.Equal(412, rec.Code)
+		asserts.Contains(rec.Body.String(), "precondition_failed")
+	}
+}
+
+func TestStudentHandlerUpdateByIdIfMatch(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	c, rec := echoMock.RequestMock(http.MethodPut, "/", strings.NewReader(`{"fullname":"Vincent"}`))
+	token, err := util.CreateJWTToken(adminClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.SetPath("/api/v1/students")
+	c.SetParamNames("id")
+	c.SetParamValues(strconv.Itoa(int(testStudentID)))
+	c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	c.Request().Header.Add("Content-Type", "application/json")
+	c.Request().Header.Add("If-Match", `"1"`)
+
+	// testing
+	asserts := assert.New(t)
+	if asserts.NoError(studentHandler.UpdateById(c)) {
+		asserts.Equal(200, rec.Code)
+		asserts.Equal(`"2.1.1"`, rec.Header().Get("ETag"))
+		asserts.Contains(rec.Body.String(), "Vincent")
+	}
+}
+
+func TestStudentHandlerPatchById(t *testing.T) {
+	cases := []struct {
+		name        string
+		contentType string
+		patch       string
+		code        int
+		contains    []string
+	}{
+		{"merge patch unassigns class", "application/merge-patch+json", `{"class_id":null,"fullname":"Vincent"}`, 200, []string{`"class":null`, `"fullname":"Vincent"`, `"version":2`}},
+		{"json patch", "application/json-patch+json", `[{"op":"replace","path":"/major_id","value":2}]`, 200, []string{`"major":{"id":2`}},
+		{"invalid document", "application/merge-patch+json", `{"email":"not-an-email"}`, 400, []string{`"field":"email"`}},
+		{"unknown class", "application/merge-patch+json", `{"class_id":99}`, 400, []string{`"tag":"exists"`}},
+		{"unknown field", "application/merge-patch+json", `{"id":2}`, 422, []string{"unprocessable_entity"}},
+		{"student number is immutable", "application/merge-patch+json", `{"student_number":"2025010099"}`, 422, []string{"unprocessable_entity"}},
+		{"profile", "application/merge-patch+json", `{"phone":"+628111222333","birth_date":"2004-12-01","gender":"male"}`, 200, []string{`"phone":"+628111222333","birth_date":"2004-12-01","gender":"male"`}},
+		{"birth date in the future", "application/merge-patch+json", `{"birth_date":"2999-01-01"}`, 422, []string{"Birth date must be in the past"}},
+		{"unsupported media type", "application/json", `{"fullname":"Vincent"}`, 415, []string{"unsupported_media_type"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			seeder.NewSeeder().SeedAll()
+
+			c, rec := echoMock.RequestMock(http.MethodPatch, "/", strings.NewReader(tc.patch))
+			token, err := util.CreateJWTToken(adminClaims)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c.SetPath("/api/v1/students")
+			c.SetParamNames("id")
+			c.SetParamValues(strconv.Itoa(int(testStudentID)))
+			c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+			c.Request().Header.Add("Content-Type", tc.contentType)
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(studentHandler.PatchById(c)) {
+				asserts.Equal(tc.code, rec.Code)
+
+				body := rec.Body.String()
+				for _, s := range tc.contains {
+					asserts.Contains(body, s)
+				}
+			}
+		})
+	}
+}
+
+func TestStudentHandlerPatchByIdUnauthorized(t *testing.T) {
+	c, rec := echoMock.RequestMock(http.MethodPatch, "/", strings.NewReader(`{"fullname":"Vincent"}`))
+	token, err := util.CreateJWTToken(userClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.SetPath("/api/v1/students")
+	c.SetParamNames("id")
+	c.SetParamValues(strconv.Itoa(int(testStudentID)))
+	c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	c.Request().Header.Add("Content-Type", "application/merge-patch+json")
+
+	// testing
+	asserts := assert.New(t)
+	if asserts.NoError(studentHandler.PatchById(c)) {
+		asserts.Equal(401, rec.Code)
+	}
+}
+
+func TestStudentHandlerGetCourses(t *testing.T) {
+	cases := []struct {
+		name      string
+		claims    dto.JWTClaims
+		studentID string
+		termID    string
+		code      int
+		contains  []string
+	}{
+		{"own courses", userClaims, "2", "", 200, []string{`"code":"FIN101","name":"Introduction to Finance","major_id":1,"credits":3,"capacity":30,"term_id":2,"enrolled":1`, `"code":"IT101"`, `"enrolled_at"`}},
+		{"class A", adminClaims, "2", "", 200, []string{`"code":"FIN101"`}},
+		{"in a term", adminClaims, "2", "3", 200, []string{`"code":"IT101"`, `"term_id":3`}},
+		{"no courses", adminClaims, "3", "", 200, []string{`"data":[]`}},
+		{"other student", userClaims, "3", "", 401, []string{"unauthorized"}},
+		{"not found", adminClaims, "99", "", 404, []string{"not_found"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			seeder.NewSeeder().SeedAll()
+			// the first course in the active term, the third one in the next term
+			for courseID, termID := range map[uint]uint{1: 2, 3: 3} {
+				if _, err := f.CourseRepository.Enroll(context.Background(), courseID, 2, termID); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			c, rec := echoMock.RequestMock(http.MethodGet, "/", nil)
+			token, err := util.CreateJWTToken(tc.claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c.SetPath("/api/v1/students/:id/courses")
+			c.SetParamNames("id")
+			c.SetParamValues(tc.studentID)
+			if tc.termID != "" {
+				c.QueryParams().Add("term_id", tc.termID)
+			}
+			c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(studentHandler.GetCourses(c)) {
+				asserts.Equal(tc.code, rec.Code)
+
+				body := rec.Body.String()
+				for _, s := range tc.contains {
+					asserts.Contains(body, s)
+				}
+			}
+		})
+	}
+}
+
+func TestStudentHandlerGetTranscript(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	// the first course is retaken in the active term, grades are saved out of term order
+	grades := []model.Grade{
+		{StudentID: 2, CourseID: 2, TermID: 2, Letter: "B+"},
+		{StudentID: 2, CourseID: 1, TermID: 1, Letter: "C"},
+		{StudentID: 2, CourseID: 3, TermID: 1, Letter: "A"},
+		{StudentID: 2, CourseID: 1, TermID: 2, Letter: "A-"},
+	}
+	for i := range grades {
+		if err := f.GradeRepository.Save(context.Background(), &grades[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		name      string
+		claims    dto.JWTClaims
+		studentID string
+		code      int
+		contains  []string
+	}{
+		{"own transcript", userClaims, "2", 200, []string{
+			`"scale":"4.0"`,
+			`"terms":[{"term_id":1,"term":"Previous Semester","courses":[{"course_id":1,"code":"FIN101","name":"Introduction to Finance","credits":3,"score":null,"letter":"C","points":2}`,
+			`{"term_id":2,"term":"Current Semester","courses":[{"course_id":2`,
+			`"credits":6,"gpa":3}`,
+			`"credits":7,"gpa":3.47}`,
+			`],"credits":10,"gpa":3.63}`,
+		}},
+		{"class A", adminClaims, "2", 200, []string{`"gpa":3.63`}},
+		{"empty transcript", adminClaims, "3", 200, []string{`"terms":[],"credits":0,"gpa":0`}},
+		{"other student", userClaims, "3", 401, []string{"unauthorized"}},
+		{"not found", adminClaims, "99", 404, []string{"not_found"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, rec := echoMock.RequestMock(http.MethodGet, "/", nil)
+			token, err := util.CreateJWTToken(tc.claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c.SetPath("/api/v1/students/:id/transcript")
+			c.SetParamNames("id")
+			c.SetParamValues(tc.studentID)
+			c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(studentHandler.GetTranscript(c)) {
+				asserts.Equal(tc.code, rec.Code)
+
+				body := rec.Body.String()
+				for _, s := range tc.contains {
+					asserts.Contains(body, s)
+				}
+			}
+		})
+	}
+}
+
+func TestStudentHandlerGetAttendance(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	cases := []struct {
+		name      string
+		claims    dto.JWTClaims
+		studentID string
+		termID    string
+		code      int
+		contains  []string
+	}{
+		{"own attendance", userClaims, "2", "", 200, []string{`"id":2`, `"term_id":2,"present":1,"late":0,"absent":1,"excused":0,"rate":50}`}},
+		{"late counts, excused is left out", adminClaims, "3", "", 200, []string{`"present":0,"late":1,"absent":0,"excused":1,"rate":100}`}},
+		{"no sessions in the term", adminClaims, "2", "3", 200, []string{`"term_id":3,"present":0,"late":0,"absent":0,"excused":0,"rate":null}`}},
+		{"other student", userClaims, "3", "", 401, []string{"unauthorized"}},
+		{"not found", adminClaims, "99", "", 404, []string{"not_found"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, rec := echoMock.RequestMock(http.MethodGet, "/", nil)
+			token, err := util.CreateJWTToken(tc.claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c.SetPath("/api/v1/students/:id/attendances")
+			c.SetParamNames("id")
+			c.SetParamValues(tc.studentID)
+			if tc.termID != "" {
+				c.QueryParams().Set("term_id", tc.termID)
+			}
+			c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(studentHandler.GetAttendance(c)) {
+				asserts.Equal(tc.code, rec.Code)
+
+				body := rec.Body.String()
+				for _, s := range tc.contains {
+					asserts.Contains(body, s)
+				}
+			}
+		})
+	}
+}
+
+func TestStudentHandlerGetSearch(t *testing.T) {
+	cases := []struct {
+		name     string
+		query    string
+		contains []string
+		excludes []string
+	}{
+		{"student number", "search=20250200", []string{"Bettina M. Easter"}, []string{"Vincent L. Hubbard", "Devon C. Thomas"}},
+		{"email", "search=devoncthomas", []string{"Devon C. Thomas"}, []string{"Vincent L. Hubbard", "Bettina M. Easter"}},
+		{"phone is not searched", "search=%2B62812", nil, []string{"Vincent L. Hubbard", "Devon C. Thomas", "Bettina M. Easter"}},
+		{"address is not searched", "search=bandung", nil, []string{"Vincent L. Hubbard", "Devon C. Thomas", "Bettina M. Easter"}},
+		{"gender", "gender=female", []string{"Bettina M. Easter"}, []string{"Vincent L. Hubbard", "Devon C. Thomas"}},
+		{"major", "major_id=1", []string{"Vincent L. Hubbard", "Devon C. Thomas"}, []string{"Bettina M. Easter"}},
+		{"intake year", "intake_year=2024", nil, []string{"Vincent L. Hubbard", "Devon C. Thomas", "Bettina M. Easter"}},
+	}
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, rec := echoMock.RequestMock(http.MethodGet, "/?"+tc.query, nil)
+			token, err := util.CreateJWTToken(adminClaims)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(studentHandler.Get(c)) {
+				asserts.Equal(200, rec.Code)
+
+				body := rec.Body.String()
+				for _, s := range tc.contains {
+					asserts.Contains(body, s)
+				}
+				for _, s := range tc.excludes {
+					asserts.NotContains(body, s)
+				}
+			}
+		})
+	}
+}
+
+func TestStudentHandlerGetRankedSearch(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	asserts := assert.New(t)
+
+	search := func(query string) string {
+		c, rec := echoMock.RequestMock(http.MethodGet, "/?"+query, nil)
+		token, err := util.CreateJWTToken(adminClaims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+		if err := studentHandler.Get(c); err != nil {
+			t.Fatal(err)
+		}
+		asserts.Equal(200, rec.Code)
+		return rec.Body.String()
+	}
+
+	// words match in any order, as prefixes or with a typo, and are highlighted
+	for _, query := range []string{"thomas+devon", "dev+th", "devn+thomas"} {
+		body := search("search=" + query)
+		asserts.Contains(body, "Devon C. Thomas", query)
+		asserts.NotContains(body, "Vincent L. Hubbard", query)
+	}
+	asserts.Contains(search("search=devon+thomas"), `"fullname":"\u003cem\u003eDevon\u003c/em\u003e C. \u003cem\u003eThomas\u003c/em\u003e"`)
+
+	// changes are searchable, and matches in the name rank above those in the email
+	var vincentID uint
+	db.Table("students").Where("email = ?", "vincentlhubbard@edu.ac.id").Pluck("id", &vincentID)
+	if err := db.Model(&model.Student{}).Where("id = ?", vincentID).Update("email", "thomas@edu.ac.id").Error; err != nil {
+		t.Fatal(err)
+	}
+	body := search("search=thomas")
+	if asserts.Contains(body, "Vincent L. Hubbard") {
+		asserts.Less(strings.Index(body, "Devon C. Thomas"), strings.Index(body, "Vincent L. Hubbard"))
+	}
+	asserts.Contains(body, `"email":"\u003cem\u003ethomas\u003c/em\u003e@edu.ac.id"`)
+
+	body = search("search=thomas&page_size=1&page=2")
+	asserts.Contains(body, "Vincent L. Hubbard")
+	asserts.NotContains(body, "Devon C. Thomas")
+	asserts.Contains(body, `"count":2`)
+
+	if err := db.Delete(&model.Student{}, vincentID).Error; err != nil {
+		t.Fatal(err)
+	}
+	asserts.NotContains(search("search=thomas"), "Vincent L. Hubbard")
+}
+
+func TestStudentHandlerUpdateByIdProfile(t *testing.T) {
+	cases := []struct {
+		name     string
+		payload  string
+		code     int
+		contains string
+	}{
+		{"profile", `{"phone":"+628999888777","address":"Jl. Asia Afrika No. 8"}`, 200, `"student_number":"2025010002"`},
+		{"birth date", `{"birth_date":"2005-03-15"}`, 200, `"birth_date":"2005-03-15"`},
+		{"invalid phone", `{"phone":"0812-3456"}`, 400, `"field":"phone"`},
+		{"invalid gender", `{"gender":"unknown"}`, 400, `"field":"gender"`},
+		{"invalid birth date", `{"birth_date":"14/03/2005"}`, 400, `"field":"birth_date"`},
+		{"birth date in the future", `{"birth_date":"2999-01-01"}`, 422, "Birth date must be in the past"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			seeder.NewSeeder().SeedAll()
+
+			c, rec := echoMock.RequestMock(http.MethodPut, "/", strings.NewReader(tc.payload))
+			token, err := util.CreateJWTToken(userClaims)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.SetParamNames("id")
+			c.SetParamValues(strconv.Itoa(int(userClaims.BID)))
+			c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+			c.Request().Header.Set("Content-Type", "application/json")
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(studentHandler.UpdateById(c)) {
+				asserts.Equal(tc.code, rec.Code)
+				asserts.Contains(rec.Body.String(), tc.contains)
+			}
+		})
+	}
+}
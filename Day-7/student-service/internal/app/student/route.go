@@ -10,8 +10,13 @@ import (
 
 func (h *handler) Route(g *echo.Group) {
 	g.Use(middleware.JWTMiddleware(dto.JWTClaims{}, util.JWT_SECRET))
+	g.Use(middleware.HTTPCacheMiddleware(0))
 	g.GET("", h.Get)
 	g.GET("/:id", h.GetById)
 	g.PUT("/:id", h.UpdateById)
+	g.PATCH("/:id", h.PatchById)
 	g.DELETE("/:id", h.DeleteById)
+	g.GET("/:id/courses", h.GetCourses)
+	g.GET("/:id/transcript", h.GetTranscript)
+	g.GET("/:id/attendances", h.GetAttendance)
 }
@@ -2,45 +2,89 @@ package student
 
 import (
 	"context"
+	"errors"
+	"strings"
 
+	"student-service/internal/app/outbox"
 	"student-service/internal/dto"
 	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
 	"student-service/internal/repository"
-	"student-service/pkg/constant"
+	"student-service/pkg/cache"
 	pkgdto "student-service/pkg/dto"
+	"student-service/pkg/grading"
+	"student-service/pkg/search"
+	pkgutil "student-service/pkg/util"
 	res "student-service/pkg/util/response"
 )
 
 type service struct {
 	StudentRepository repository.Student
+	CourseRepository  repository.Course
+	GradeRepository   repository.Grade
+	TermRepository    repository.Term
+	Scale             *grading.Scale
+	Validator         *pkgutil.CustomValidator
+	Transactor        repository.Transactor
+	Cache             cache.Cache
+	Events            outbox.Recorder
+
+	ClassSessionRepository repository.ClassSession
 }
 
 type Service interface {
-	Find(ctx context.Context, payload *pkgdto.SearchGetRequest) (*pkgdto.SearchGetResponse[dto.StudentResponse], error)
+	Find(ctx context.Context, payload *dto.SearchStudentRequest) (*pkgdto.SearchGetResponse[dto.StudentResponse], error)
 	FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.StudentDetailResponse, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]dto.StudentDetailResponse, error)
+	FindByClassIDs(ctx context.Context, classIDs []uint) ([]dto.StudentDetailResponse, error)
 	UpdateById(ctx context.Context, payload *dto.UpdateStudentRequestBody) (*dto.StudentDetailResponse, error)
+	PatchById(ctx context.Context, payload *pkgdto.PatchByIDRequest) (*dto.StudentDetailResponse, error)
 	DeleteById(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.StudentWithCUDResponse, error)
+	FindCourses(ctx context.Context, payload *dto.ByIDInTermRequest) ([]dto.EnrolledCourseResponse, error)
+	Transcript(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.TranscriptResponse, error)
+	Attendance(ctx context.Context, payload *dto.ByIDInTermRequest) (*dto.StudentAttendanceResponse, error)
 }
 
 func NewService(f *factory.Factory) Service {
 	return &service{
 		StudentRepository: f.StudentRepository,
+		CourseRepository:  f.CourseRepository,
+		GradeRepository:   f.GradeRepository,
+		TermRepository:    f.TermRepository,
+		Scale:             grading.DefaultScale(),
+		Validator:         f.NewValidator(),
+		Transactor:        f.Transactor,
+		Cache:             f.Cache,
+		Events:            outbox.NewRecorder(f),
+
+		ClassSessionRepository: f.ClassSessionRepository,
 	}
 }
 
-func (s *service) Find(ctx context.Context, payload *pkgdto.SearchGetRequest) (*pkgdto.SearchGetResponse[dto.StudentResponse], error) {
+func (s *service) Find(ctx context.Context, payload *dto.SearchStudentRequest) (*pkgdto.SearchGetResponse[dto.StudentResponse], error) {
+	return cache.Fetch(ctx, s.Cache, cache.Key(util.StudentCachePrefix+"list:", payload), func() (*pkgdto.SearchGetResponse[dto.StudentResponse], error) {
+		return s.find(ctx, payload)
+	})
+}
+
+func (s *service) find(ctx context.Context, payload *dto.SearchStudentRequest) (*pkgdto.SearchGetResponse[dto.StudentResponse], error) {
 	students, info, err := s.StudentRepository.FindAll(ctx, payload, &payload.Pagination)
 	if err != nil {
-		return nil, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return nil, util.RepositoryErrorBuilder(err)
 	}
 
 	var data []dto.StudentResponse
 
 	for _, student := range students {
 		data = append(data, dto.StudentResponse{
-			ID:       student.ID,
-			Fullname: student.Fullname,
-			Email:    student.Email,
+			ID:            student.ID,
+			StudentNumber: student.StudentNumber,
+			Fullname:      student.Fullname,
+			Email:         student.Email,
+			Version:       student.Version,
+			Highlights:    highlights(payload.Search, &student),
 		})
 
 	}
@@ -53,89 +97,404 @@ func (s *service) Find(ctx context.Context, payload *pkgdto.SearchGetRequest) (*
 }
 
 func (s *service) FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.StudentDetailResponse, error) {
+	return cache.Fetch(ctx, s.Cache, cache.Key(util.StudentCachePrefix+"id:", payload), func() (*dto.StudentDetailResponse, error) {
+		return s.findByID(ctx, payload)
+	})
+}
+
+func (s *service) findByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.StudentDetailResponse, error) {
 	data, err := s.StudentRepository.FindByID(ctx, payload.ID, true)
 	if err != nil {
-		if err == constant.RECORD_NOT_FOUND {
-			return &dto.StudentDetailResponse{}, res.ErrorBuilder(&res.ErrorConstant.NotFound, err)
-		}
-		return &dto.StudentDetailResponse{}, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return &dto.StudentDetailResponse{}, util.RepositoryErrorBuilder(err)
 	}
 
-	result := &dto.StudentDetailResponse{
-		StudentResponse: dto.StudentResponse{
-			ID:       data.ID,
-			Fullname: data.Fullname,
-			Email:    data.Email,
-		},
-		Class: dto.ClassResponse{
-			ID:   data.Class.ID,
-			Name: data.Class.Name,
-		},
-		Major: dto.MajorResponse{
-			ID:   data.Major.ID,
-			Name: data.Major.Name,
-		},
+	return newStudentDetailResponse(&data), nil
+}
+
+// FindByIDs returns the students with the ids in one query, ordered by id. Missing ones are left
+// out. Only the ids of their class and major are set.
+func (s *service) FindByIDs(ctx context.Context, ids []uint) ([]dto.StudentDetailResponse, error) {
+	students, err := s.StudentRepository.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
 	}
+	return newStudentDetailResponses(students), nil
+}
 
-	return result, nil
+// FindByClassIDs returns the students currently in the classes in one query, ordered by id. Only
+// the ids of their class and major are set.
+func (s *service) FindByClassIDs(ctx context.Context, classIDs []uint) ([]dto.StudentDetailResponse, error) {
+	students, err := s.StudentRepository.FindByClassIDs(ctx, classIDs)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+	return newStudentDetailResponses(students), nil
 }
 
 func (s *service) UpdateById(ctx context.Context, payload *dto.UpdateStudentRequestBody) (*dto.StudentDetailResponse, error) {
+	defer cache.Invalidate(ctx, s.Cache, util.StudentCachePrefix)
+
 	student, err := s.StudentRepository.FindByID(ctx, *payload.ID, false)
 	if err != nil {
-		if err == constant.RECORD_NOT_FOUND {
-			return &dto.StudentDetailResponse{}, res.ErrorBuilder(&res.ErrorConstant.NotFound, err)
+		return &dto.StudentDetailResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	if !res.IfMatch(payload.IfMatch, student.Version) {
+		return &dto.StudentDetailResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("student version does not match If-Match"))
+	}
+	if err := util.CheckBirthDate(payload.BirthDate); err != nil {
+		return &dto.StudentDetailResponse{}, err
+	}
+
+	oldClassID := student.ClassID
+	var result *dto.StudentDetailResponse
+	err = s.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.StudentRepository.Edit(ctx, &student, payload); err != nil {
+			return err
 		}
-		return &dto.StudentDetailResponse{}, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		result = newStudentDetailResponse(&student)
+
+		return s.recordUpdate(ctx, oldClassID, result)
+	})
+	if err != nil {
+		return &dto.StudentDetailResponse{}, util.RepositoryErrorBuilder(err)
 	}
 
-	_, err = s.StudentRepository.Edit(ctx, &student, payload)
+	return result, nil
+}
+
+func (s *service) PatchById(ctx context.Context, payload *pkgdto.PatchByIDRequest) (*dto.StudentDetailResponse, error) {
+	defer cache.Invalidate(ctx, s.Cache, util.StudentCachePrefix)
+
+	student, err := s.StudentRepository.FindByID(ctx, payload.ID, false)
 	if err != nil {
-		return &dto.StudentDetailResponse{}, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return &dto.StudentDetailResponse{}, util.RepositoryErrorBuilder(err)
 	}
 
-	result := &dto.StudentDetailResponse{
-		StudentResponse: dto.StudentResponse{
-			ID:       student.ID,
-			Fullname: student.Fullname,
-			Email:    student.Email,
-		},
-		Class: dto.ClassResponse{
-			ID:   student.Class.ID,
-			Name: student.Class.Name,
-		},
-		Major: dto.MajorResponse{
-			ID:   student.Major.ID,
-			Name: student.Major.Name,
-		},
+	if !res.IfMatch(payload.IfMatch, student.Version) {
+		return &dto.StudentDetailResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("student version does not match If-Match"))
+	}
+
+	document := dto.StudentPatchDocument{
+		Fullname: student.Fullname,
+		Email:    student.Email,
+		ClassID:  student.ClassID,
+		MajorID:  student.MajorID,
+		Phone:    student.Phone,
+		Gender:   student.Gender,
+		Address:  student.Address,
+	}
+	if student.BirthDate != nil {
+		birthDate := student.BirthDate.Format(pkgutil.DateLayout)
+		document.BirthDate = &birthDate
+	}
+	var patched dto.StudentPatchDocument
+	if err := pkgutil.ApplyPatch(payload.ContentType, payload.Patch, &document, &patched); err != nil {
+		return &dto.StudentDetailResponse{}, util.PatchErrorBuilder(err)
+	}
+	patched.ID = student.ID
+	if err := s.Validator.ValidateCtx(ctx, &patched); err != nil {
+		return &dto.StudentDetailResponse{}, res.ValidationErrorBuilder(err)
+	}
+	if err := util.CheckBirthDate(patched.BirthDate); err != nil {
+		return &dto.StudentDetailResponse{}, err
+	}
+
+	oldClassID := student.ClassID
+	var result *dto.StudentDetailResponse
+	err = s.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.StudentRepository.Patch(ctx, &student, &patched); err != nil {
+			return err
+		}
+		result = newStudentDetailResponse(&student)
+
+		return s.recordUpdate(ctx, oldClassID, result)
+	})
+	if err != nil {
+		return &dto.StudentDetailResponse{}, util.RepositoryErrorBuilder(err)
 	}
 
 	return result, nil
 }
 
 func (s *service) DeleteById(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.StudentWithCUDResponse, error) {
+	defer cache.Invalidate(ctx, s.Cache, util.StudentCachePrefix)
+
 	student, err := s.StudentRepository.FindByID(ctx, payload.ID, false)
 	if err != nil {
-		if err == constant.RECORD_NOT_FOUND {
-			return &dto.StudentWithCUDResponse{}, res.ErrorBuilder(&res.ErrorConstant.NotFound, err)
-		}
-		return &dto.StudentWithCUDResponse{}, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return &dto.StudentWithCUDResponse{}, util.RepositoryErrorBuilder(err)
 	}
-	_, err = s.StudentRepository.Destroy(ctx, &student)
+	if !res.IfMatch(payload.IfMatch, student.Version) {
+		return &dto.StudentWithCUDResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("student version does not match If-Match"))
+	}
+	var result *dto.StudentWithCUDResponse
+	err = s.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.StudentRepository.Destroy(ctx, &student); err != nil {
+			return err
+		}
+		result = newStudentWithCUDResponse(&student)
+
+		return s.Events.Record(ctx, enum.StudentDeleted, student.ID, result)
+	})
 	if err != nil {
-		return &dto.StudentWithCUDResponse{}, res.ErrorBuilder(&res.ErrorConstant.InternalServerError, err)
+		return &dto.StudentWithCUDResponse{}, util.RepositoryErrorBuilder(err)
 	}
 
-	result := &dto.StudentWithCUDResponse{
+	return result, nil
+}
+
+func newStudentWithCUDResponse(student *model.Student) *dto.StudentWithCUDResponse {
+	return &dto.StudentWithCUDResponse{
 		StudentResponse: dto.StudentResponse{
-			ID:       student.ID,
-			Fullname: student.Fullname,
-			Email:    student.Email,
+			ID:            student.ID,
+			StudentNumber: student.StudentNumber,
+			Fullname:      student.Fullname,
+			Email:         student.Email,
+			Version:       student.Version,
 		},
 		CreatedAt: student.CreatedAt,
 		UpdatedAt: student.UpdatedAt,
 		DeletedAt: student.DeletedAt,
 	}
+}
+
+// recordUpdate records the update of a student, and the change of its class when it was in
+// oldClassID before.
+func (s *service) recordUpdate(ctx context.Context, oldClassID *uint, student *dto.StudentDetailResponse) error {
+	if err := s.Events.Record(ctx, enum.StudentUpdated, student.ID, student); err != nil {
+		return err
+	}
+
+	var newClassID *uint
+	if student.Class != nil {
+		newClassID = &student.Class.ID
+	}
+	if (oldClassID == nil) != (newClassID == nil) || (oldClassID != nil && *oldClassID != *newClassID) {
+		return s.Events.Record(ctx, enum.StudentClassChanged, student.ID, dto.StudentClassChangedData{
+			StudentID:  student.ID,
+			OldClassID: oldClassID,
+			NewClassID: newClassID,
+		})
+	}
+	return nil
+}
+
+// FindCourses returns the courses the student is enrolled in, in every term unless the term
+// is set. Every course tells how many students are enrolled in its term.
+func (s *service) FindCourses(ctx context.Context, payload *dto.ByIDInTermRequest) ([]dto.EnrolledCourseResponse, error) {
+	if _, err := s.StudentRepository.FindByID(ctx, payload.ID, false); err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	enrollments, err := s.CourseRepository.FindEnrollmentsByStudentID(ctx, payload.ID, payload.TermID)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	courseIDs := map[uint][]uint{}
+	for _, enrollment := range enrollments {
+		courseIDs[enrollment.TermID] = append(courseIDs[enrollment.TermID], enrollment.CourseID)
+	}
+	counts := map[uint]map[uint]uint{}
+	for termID, ids := range courseIDs {
+		if counts[termID], err = s.CourseRepository.CountEnrollments(ctx, termID, ids...); err != nil {
+			return nil, util.RepositoryErrorBuilder(err)
+		}
+	}
+
+	result := []dto.EnrolledCourseResponse{}
+	for i := range enrollments {
+		enrollment := &enrollments[i]
+		result = append(result, dto.EnrolledCourseResponse{
+			CourseResponse: dto.CourseResponse{
+				ID:       enrollment.Course.ID,
+				Code:     enrollment.Course.Code,
+				Name:     enrollment.Course.Name,
+				MajorID:  enrollment.Course.MajorID,
+				Credits:  enrollment.Course.Credits,
+				Capacity: enrollment.Course.Capacity,
+				TermID:   &enrollment.TermID,
+				Enrolled: counts[enrollment.TermID][enrollment.CourseID],
+				Version:  enrollment.Course.Version,
+			},
+			EnrolledAt: enrollment.CreatedAt,
+		})
+	}
+
+	return result, nil
+}
+
+// Transcript groups the grades of the student by term, in the order the terms started. The GPA
+// of a term counts its grades, the cumulative GPA counts the latest grade of every course so
+// retaken courses count once.
+func (s *service) Transcript(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.TranscriptResponse, error) {
+	student, err := s.StudentRepository.FindByID(ctx, payload.ID, false)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	grades, err := s.GradeRepository.FindByStudentID(ctx, payload.ID)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	result := &dto.TranscriptResponse{
+		Student: dto.StudentResponse{
+			ID:            student.ID,
+			StudentNumber: student.StudentNumber,
+			Fullname:      student.Fullname,
+			Email:         student.Email,
+			Version:       student.Version,
+		},
+		Scale: s.Scale.Name,
+		Terms: []dto.TranscriptTermResponse{},
+	}
+
+	var (
+		termResults []grading.Result
+		courseIDs   []uint
+		latest      = map[uint]grading.Result{}
+	)
+	closeTerm := func() {
+		last := &result.Terms[len(result.Terms)-1]
+		last.GPA, last.Credits = grading.GPA(termResults)
+		termResults = nil
+	}
+	// grades are ordered by term
+	for _, grade := range grades {
+		if len(result.Terms) == 0 || result.Terms[len(result.Terms)-1].TermID != grade.TermID {
+			if len(result.Terms) > 0 {
+				closeTerm()
+			}
+			result.Terms = append(result.Terms, dto.TranscriptTermResponse{TermID: grade.TermID, Term: grade.Term.Name})
+		}
+
+		points, ok := s.Scale.Points(grade.Letter)
+		if !ok && grade.Score != nil {
+			// the grade was given with another scale
+			points, _ = s.Scale.Points(s.Scale.Letter(*grade.Score))
+		}
+		term := &result.Terms[len(result.Terms)-1]
+		term.Courses = append(term.Courses, dto.TranscriptCourseResponse{
+			CourseID: grade.CourseID,
+			Code:     grade.Course.Code,
+			Name:     grade.Course.Name,
+			Credits:  grade.Course.Credits,
+			Score:    grade.Score,
+			Letter:   grade.Letter,
+			Points:   points,
+		})
+
+		courseResult := grading.Result{Credits: grade.Course.Credits, Points: points}
+		termResults = append(termResults, courseResult)
+		if _, ok := latest[grade.CourseID]; !ok {
+			courseIDs = append(courseIDs, grade.CourseID)
+		}
+		latest[grade.CourseID] = courseResult
+	}
+	if len(result.Terms) > 0 {
+		closeTerm()
+	}
+
+	cumulative := make([]grading.Result, 0, len(courseIDs))
+	for _, courseID := range courseIDs {
+		cumulative = append(cumulative, latest[courseID])
+	}
+	result.GPA, result.Credits = grading.GPA(cumulative)
 
 	return result, nil
 }
+
+// newStudentDetailResponse returns the response of a student loaded with its class and major.
+func newStudentDetailResponse(student *model.Student) *dto.StudentDetailResponse {
+	result := &dto.StudentDetailResponse{
+		StudentResponse: dto.StudentResponse{
+			ID:            student.ID,
+			StudentNumber: student.StudentNumber,
+			Fullname:      student.Fullname,
+			Email:         student.Email,
+			Version:       student.Version,
+		},
+		IntakeYear: student.IntakeYear,
+		Phone:      student.Phone,
+		Gender:     student.Gender,
+		Address:    student.Address,
+		Major: dto.MajorResponse{
+			ID:      student.MajorID,
+			Name:    student.Major.Name,
+			Version: student.Major.Version,
+		},
+	}
+	if student.BirthDate != nil {
+		birthDate := student.BirthDate.Format(pkgutil.DateLayout)
+		result.BirthDate = &birthDate
+	}
+	if student.ClassID != nil {
+		result.Class = &dto.ClassResponse{
+			ID:      *student.ClassID,
+			Name:    student.Class.Name,
+			Version: student.Class.Version,
+		}
+	}
+	return result
+}
+
+func newStudentDetailResponses(students []model.Student) []dto.StudentDetailResponse {
+	result := make([]dto.StudentDetailResponse, 0, len(students))
+	for i := range students {
+		result = append(result, *newStudentDetailResponse(&students[i]))
+	}
+	return result
+}
+
+// Attendance summarizes the attendance of the student in the sessions of a term, the active term
+// when it is not set.
+func (s *service) Attendance(ctx context.Context, payload *dto.ByIDInTermRequest) (*dto.StudentAttendanceResponse, error) {
+	student, err := s.StudentRepository.FindByID(ctx, payload.ID, false)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+	term, err := util.FindTerm(ctx, s.TermRepository, payload.TermID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.ClassSessionRepository.CountAttendance(ctx, term.ID, nil, &student.ID)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	return &dto.StudentAttendanceResponse{
+		Student: dto.StudentResponse{
+			ID:            student.ID,
+			StudentNumber: student.StudentNumber,
+			Fullname:      student.Fullname,
+			Email:         student.Email,
+			Version:       student.Version,
+		},
+		TermID:                    term.ID,
+		AttendanceSummaryResponse: util.AttendanceSummary(counts...),
+	}, nil
+}
+
+// highlights returns the fields of the student matching the search, nil without a search.
+func highlights(query string, student *model.Student) map[string]string {
+	if strings.TrimSpace(query) == "" {
+		return nil
+	}
+	fields := map[string]string{
+		"fullname": student.Fullname,
+		"email":    student.Email,
+	}
+	if student.StudentNumber != nil {
+		fields["student_number"] = *student.StudentNumber
+	}
+
+	result := map[string]string{}
+	for name, value := range fields {
+		if highlighted := search.Highlight(query, value); highlighted != "" {
+			result[name] = highlighted
+		}
+	}
+	return result
+}
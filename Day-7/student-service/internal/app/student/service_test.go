@@ -26,7 +26,7 @@ var (
 		MajorID:  &testMajorID,
 		ClassID:  &testAClassID,
 	}
-	testFindAllPayload  = pkgdto.SearchGetRequest{}
+	testFindAllPayload  = dto.SearchStudentRequest{}
 	testFindByIdPayload = pkgdto.ByIDRequest{ID: 1}
 )
 
@@ -48,6 +48,30 @@ func TestStudentServiceFindAllSuccess(t *testing.T) {
 	}
 }
 
+func TestStudentServiceFindAllByTerm(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	for _, studentID := range []uint{2, 3} {
+		if _, err := factory.NewFactory().CourseRepository.Enroll(ctx, 1, studentID, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	asserts := assert.New(t)
+	termID := uint(2)
+	res, err := testStudentService.Find(ctx, &dto.SearchStudentRequest{TermID: &termID})
+	if asserts.NoError(err) && asserts.Len(res.Data, 2) {
+		asserts.Equal(uint(2), res.Data[0].ID)
+		asserts.Equal(uint(3), res.Data[1].ID)
+	}
+
+	termID = 3
+	res, err = testStudentService.Find(ctx, &dto.SearchStudentRequest{TermID: &termID})
+	if asserts.NoError(err) {
+		asserts.Empty(res.Data)
+	}
+}
+
 func TestStudentServiceFindByIdSuccess(t *testing.T) {
 	database.GetConnection()
 	seeder.NewSeeder().DeleteAll()
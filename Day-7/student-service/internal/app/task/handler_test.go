@@ -0,0 +1,122 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"student-service/database"
+	"student-service/database/seeder"
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/mocks"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	adminClaims = util.CreateJWTClaims("vincentlhubbard@edu.ac.id", uint(1), uint(enum.A), uint(enum.Finance))
+	userClaims  = util.CreateJWTClaims("devoncthomas@edu.ac.id", uint(2), uint(enum.B), uint(enum.Finance))
+	db          = database.GetConnection()
+	echoMock    = mocks.EchoMock{E: echo.New()}
+	f           = factory.Factory{
+		OutboxRepository:      repository.NewOutboxRepository(db),
+		LeaseRepository:       repository.NewLeaseRepository(db),
+		JobRepository:         repository.NewJobRepository(db),
+		TaskRunRepository:     repository.NewTaskRunRepository(db),
+		MaintenanceRepository: repository.NewMaintenanceRepository(db),
+		StatisticRepository:   repository.NewStatisticRepository(db),
+	}
+	taskHandler = NewHandler(&f)
+)
+
+func taskRequest(t *testing.T, claims dto.JWTClaims, method, target string) (echo.Context, func() (int, string)) {
+	c, rec := echoMock.RequestMock(method, target, bytes.NewBufferString(""))
+	token, err := util.CreateJWTToken(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	return c, func() (int, string) { return rec.Code, rec.Body.String() }
+}
+
+func TestTaskHandlerGet(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	s, advance := newTestScheduler("replica-1", Tasks(&f)...)
+	advance(24 * time.Hour)
+	asserts := assert.New(t)
+	asserts.Equal(3, s.RunDue(ctx))
+
+	cases := []struct {
+		name     string
+		claims   dto.JWTClaims
+		code     int
+		contains []string
+	}{
+		{"all", adminClaims, 200, []string{
+			`"name":"purge.deleted","schedule":"0 3 * * *"`,
+			`"name":"purge.history","schedule":"30 3 * * *"`,
+			`"name":"statistics.compute","schedule":"*/15 * * * *"`,
+			`"status":"succeeded","result":"0 students, 0 classes, 0 majors, 0 courses"`,
+		}},
+		{"not class A", userClaims, 401, []string{"unauthorized"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, rec := taskRequest(t, tc.claims, http.MethodGet, "/")
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(taskHandler.Get(c)) {
+				code, body := rec()
+				asserts.Equal(tc.code, code)
+				for _, s := range tc.contains {
+					asserts.Contains(body, s)
+				}
+			}
+		})
+	}
+}
+
+func TestTaskHandlerGetRuns(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	s, advance := newTestScheduler("replica-1", everyMinute(func(ctx context.Context) (string, error) {
+		return "cleaned up", nil
+	}))
+	advance(time.Minute)
+	s.RunDue(ctx)
+
+	cases := []struct {
+		name     string
+		claims   dto.JWTClaims
+		target   string
+		code     int
+		contains string
+	}{
+		{"all", adminClaims, "/", 200, `"task":"test.task","scheduled_at"`},
+		{"by task", adminClaims, "/?task=purge.deleted", 200, `"data":[]`},
+		{"by status", adminClaims, "/?status=succeeded", 200, `"owner":"replica-1","status":"succeeded","result":"cleaned up","error":""`},
+		{"unknown status", adminClaims, "/?status=lost", 400, `"field":"status"`},
+		{"not class A", userClaims, "/", 401, "unauthorized"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, rec := taskRequest(t, tc.claims, http.MethodGet, tc.target)
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(taskHandler.GetRuns(c)) {
+				code, body := rec()
+				asserts.Equal(tc.code, code)
+				asserts.Contains(body, tc.contains)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	"student-service/pkg/cron"
+	pkgutil "student-service/pkg/util"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// leaseMargin is how much longer than the timeout of its run a task holds its lease.
+	leaseMargin = time.Minute
+	// maxErrorLength is how much of the error of a failed run is kept.
+	maxErrorLength = 1024
+)
+
+// Task is housekeeping work run on a cron schedule.
+type Task struct {
+	Name     enum.TaskName
+	Schedule *cron.Schedule
+	// Run does the work and returns a summary of it for the run history. It must stop once ctx is
+	// done.
+	Run func(ctx context.Context) (string, error)
+}
+
+// Scheduler runs the tasks at their scheduled times. Every scheduled time of a task runs on one
+// replica, the first one to record the run, and not while the previous run of the task is still
+// running on another replica. A scheduled time missed while no replica ran is not made up for.
+type Scheduler struct {
+	TaskRunRepository repository.TaskRun
+	LeaseRepository   repository.Lease
+	Timeout           time.Duration
+	tasks             []Task
+	next              map[enum.TaskName]time.Time
+	owner             string
+	now               func() time.Time
+}
+
+func NewScheduler(f *factory.Factory) *Scheduler {
+	s := &Scheduler{
+		TaskRunRepository: f.TaskRunRepository,
+		LeaseRepository:   f.LeaseRepository,
+		Timeout:           timeout(),
+		next:              map[enum.TaskName]time.Time{},
+		owner:             util.InstanceID,
+		now:               time.Now,
+	}
+	for _, task := range Tasks(f) {
+		s.Add(task)
+	}
+	return s
+}
+
+// Add schedules the task from now.
+func (s *Scheduler) Add(task Task) {
+	next := task.Schedule.Next(s.now())
+	if next.IsZero() {
+		logrus.Warnf("task %s never runs on %q", task.Name, task.Schedule)
+		return
+	}
+	s.tasks = append(s.tasks, task)
+	s.next[task.Name] = next
+}
+
+// Run runs the tasks at their scheduled times until ctx is done. A running task is finished
+// first.
+func (s *Scheduler) Run(ctx context.Context) {
+	if len(s.tasks) == 0 {
+		return
+	}
+	for {
+		s.RunDue(ctx)
+		timer := time.NewTimer(s.nextRunAt().Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// RunDue runs the tasks whose scheduled time has come, one after the other, and returns how many
+// of them this replica ran.
+func (s *Scheduler) RunDue(ctx context.Context) int {
+	ran := 0
+	for _, task := range s.tasks {
+		if ctx.Err() != nil {
+			break
+		}
+		now := s.now()
+		scheduledAt := s.next[task.Name]
+		if scheduledAt.After(now) {
+			continue
+		}
+		s.next[task.Name] = task.Schedule.Next(now)
+		if s.run(task, scheduledAt) {
+			ran++
+		}
+	}
+	return ran
+}
+
+func (s *Scheduler) nextRunAt() time.Time {
+	var next time.Time
+	for _, at := range s.next {
+		if next.IsZero() || at.Before(next) {
+			next = at
+		}
+	}
+	return next
+}
+
+// run runs the task for its scheduled time unless another replica did, and reports whether it
+// ran. The outcome is recorded even when the scheduler is stopping, the run was made.
+func (s *Scheduler) run(task Task, scheduledAt time.Time) bool {
+	ctx := context.Background()
+	run := &model.TaskRun{
+		Task:        string(task.Name),
+		ScheduledAt: scheduledAt,
+		Owner:       s.owner,
+		Status:      string(enum.TaskRunRunning),
+		StartedAt:   s.now(),
+	}
+	started, err := s.TaskRunRepository.Start(ctx, run)
+	if err != nil {
+		logrus.Warnf("start task %s: %v", task.Name, err)
+		return false
+	}
+	if !started {
+		return false
+	}
+
+	lease := "task:" + string(task.Name)
+	held, err := s.LeaseRepository.Acquire(ctx, lease, s.owner, s.now(), s.Timeout+leaseMargin)
+	switch {
+	case err != nil:
+		run.Status = string(enum.TaskRunFailed)
+		run.Error = fmt.Sprintf("acquire %s lease: %v", lease, err)
+	case !held:
+		run.Status = string(enum.TaskRunSkipped)
+		run.Error = "the previous run is still running"
+	default:
+		run.Result, err = s.call(task)
+		if err := s.LeaseRepository.Release(ctx, lease, s.owner); err != nil {
+			logrus.Warnf("release %s lease: %v", lease, err)
+		}
+		if err != nil {
+			logrus.Warnf("run task %s: %v", task.Name, err)
+			run.Status = string(enum.TaskRunFailed)
+			run.Error = err.Error()
+			if len(run.Error) > maxErrorLength {
+				run.Error = run.Error[:maxErrorLength]
+			}
+		} else {
+			run.Status = string(enum.TaskRunSucceeded)
+		}
+	}
+
+	finishedAt := s.now()
+	run.FinishedAt = &finishedAt
+	if err := s.TaskRunRepository.Edit(ctx, run); err != nil {
+		logrus.Warnf("record task %s: %v", task.Name, err)
+	}
+	return run.Status != string(enum.TaskRunSkipped)
+}
+
+func (s *Scheduler) call(task Task) (result string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.Timeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return task.Run(ctx)
+}
+
+// timeout returns how long a task may run, TASK_TIMEOUT, 10 minutes by default.
+func timeout() time.Duration {
+	d, err := time.ParseDuration(pkgutil.Getenv("TASK_TIMEOUT", "10m"))
+	if err != nil || d <= 0 {
+		logrus.Warnf("invalid TASK_TIMEOUT, using 10m: %v", err)
+		return 10 * time.Minute
+	}
+	return d
+}
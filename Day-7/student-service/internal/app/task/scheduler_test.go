@@ -0,0 +1,183 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"student-service/database/seeder"
+	"student-service/internal/dto"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	"student-service/pkg/cron"
+	pkgdto "student-service/pkg/dto"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ctx = context.Background()
+
+const testTask enum.TaskName = "test.task"
+
+func newSearchTaskRunRequest() *dto.SearchTaskRunRequest {
+	page, pageSize := 1, 10
+	return &dto.SearchTaskRunRequest{Pagination: pkgdto.Pagination{Page: &page, PageSize: &pageSize}}
+}
+
+func findRuns(t *testing.T) []model.TaskRun {
+	runs, _, err := f.TaskRunRepository.FindAll(ctx, newSearchTaskRunRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return runs
+}
+
+// everyMinute returns a task running fn every minute.
+func everyMinute(fn func(ctx context.Context) (string, error)) Task {
+	schedule, _ := cron.Parse("* * * * *")
+	return Task{Name: testTask, Schedule: schedule, Run: fn}
+}
+
+// newTestScheduler returns a scheduler of owner whose clock is moved with advance. It starts at
+// the current minute, so that schedulers created in a test share their scheduled times.
+func newTestScheduler(owner string, tasks ...Task) (*Scheduler, func(time.Duration)) {
+	now := time.Now().Truncate(time.Minute)
+	s := &Scheduler{
+		TaskRunRepository: f.TaskRunRepository,
+		LeaseRepository:   f.LeaseRepository,
+		Timeout:           time.Minute,
+		next:              map[enum.TaskName]time.Time{},
+		owner:             owner,
+		now:               func() time.Time { return now },
+	}
+	for _, task := range tasks {
+		s.Add(task)
+	}
+	return s, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestSchedulerRunDue(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	ran := 0
+	s, advance := newTestScheduler("replica-1", everyMinute(func(ctx context.Context) (string, error) {
+		ran++
+		return "cleaned up", nil
+	}))
+
+	asserts := assert.New(t)
+	asserts.Equal(0, s.RunDue(ctx))
+	advance(time.Minute)
+	asserts.Equal(1, s.RunDue(ctx))
+	asserts.Equal(0, s.RunDue(ctx))
+	// a scheduled time missed is not made up for
+	advance(3 * time.Minute)
+	asserts.Equal(1, s.RunDue(ctx))
+	asserts.Equal(2, ran)
+
+	runs := findRuns(t)
+	if asserts.Len(runs, 2) {
+		asserts.Equal(string(testTask), runs[0].Task)
+		asserts.Equal(string(enum.TaskRunSucceeded), runs[0].Status)
+		asserts.Equal("cleaned up", runs[0].Result)
+		asserts.Equal("replica-1", runs[0].Owner)
+		asserts.NotNil(runs[0].FinishedAt)
+		asserts.Equal(time.Minute, runs[0].ScheduledAt.Sub(runs[1].ScheduledAt))
+	}
+}
+
+func TestSchedulerRunsAScheduledTimeOnce(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	ran := 0
+	task := everyMinute(func(ctx context.Context) (string, error) {
+		ran++
+		return "", nil
+	})
+	first, advanceFirst := newTestScheduler("replica-1", task)
+	second, advanceSecond := newTestScheduler("replica-2", task)
+
+	asserts := assert.New(t)
+	advanceFirst(time.Minute)
+	advanceSecond(time.Minute + 10*time.Second)
+	asserts.Equal(1, first.RunDue(ctx))
+	asserts.Equal(0, second.RunDue(ctx))
+	asserts.Equal(1, ran)
+
+	// the next scheduled time goes to whichever replica comes first
+	advanceFirst(time.Minute)
+	advanceSecond(time.Minute)
+	asserts.Equal(1, second.RunDue(ctx))
+	asserts.Equal(0, first.RunDue(ctx))
+	asserts.Equal(2, ran)
+
+	runs := findRuns(t)
+	if asserts.Len(runs, 2) {
+		asserts.Equal("replica-2", runs[0].Owner)
+		asserts.Equal("replica-1", runs[1].Owner)
+	}
+}
+
+func TestSchedulerSkipsWhileThePreviousRunIsRunning(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	ran := 0
+	s, advance := newTestScheduler("replica-2", everyMinute(func(ctx context.Context) (string, error) {
+		ran++
+		return "", nil
+	}))
+
+	// another replica is still running the task
+	asserts := assert.New(t)
+	held, err := f.LeaseRepository.Acquire(ctx, "task:"+string(testTask), "replica-1", s.now(), time.Hour)
+	if !asserts.NoError(err) || !asserts.True(held) {
+		return
+	}
+
+	advance(time.Minute)
+	asserts.Equal(0, s.RunDue(ctx))
+	asserts.Equal(0, ran)
+	runs := findRuns(t)
+	if asserts.Len(runs, 1) {
+		asserts.Equal(string(enum.TaskRunSkipped), runs[0].Status)
+		asserts.Equal("the previous run is still running", runs[0].Error)
+	}
+
+	asserts.NoError(f.LeaseRepository.Release(ctx, "task:"+string(testTask), "replica-1"))
+	advance(time.Minute)
+	asserts.Equal(1, s.RunDue(ctx))
+	asserts.Equal(1, ran)
+}
+
+func TestSchedulerRecordsFailures(t *testing.T) {
+	cases := []struct {
+		name string
+		run  func(ctx context.Context) (string, error)
+		err  string
+	}{
+		{"error", func(ctx context.Context) (string, error) { return "", errors.New("database is gone") }, "database is gone"},
+		{"panic", func(ctx context.Context) (string, error) { panic("nil map") }, "panic: nil map"},
+		{"timeout", func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}, "context deadline exceeded"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			s, advance := newTestScheduler("replica-1", everyMinute(tc.run))
+			s.Timeout = 10 * time.Millisecond
+
+			asserts := assert.New(t)
+			advance(time.Minute)
+			asserts.Equal(1, s.RunDue(ctx))
+			runs := findRuns(t)
+			if asserts.Len(runs, 1) {
+				asserts.Equal(string(enum.TaskRunFailed), runs[0].Status)
+				asserts.Equal(tc.err, runs[0].Error)
+			}
+
+			// the lease is released, the next scheduled time runs
+			advance(time.Minute)
+			asserts.Equal(1, s.RunDue(ctx))
+		})
+	}
+}
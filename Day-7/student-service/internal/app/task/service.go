@@ -0,0 +1,86 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	pkgdto "student-service/pkg/dto"
+)
+
+type service struct {
+	TaskRunRepository repository.TaskRun
+	tasks             []Task
+}
+
+type Service interface {
+	Find(ctx context.Context) ([]dto.TaskResponse, error)
+	FindRuns(ctx context.Context, payload *dto.SearchTaskRunRequest) (*pkgdto.SearchGetResponse[dto.TaskRunResponse], error)
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		TaskRunRepository: f.TaskRunRepository,
+		tasks:             Tasks(f),
+	}
+}
+
+// Find returns the scheduled tasks with when they run next and their last run.
+func (s *service) Find(ctx context.Context) ([]dto.TaskResponse, error) {
+	now := time.Now()
+	result := []dto.TaskResponse{}
+	for _, task := range s.tasks {
+		last, err := s.TaskRunRepository.FindLatest(ctx, string(task.Name))
+		if err != nil {
+			return nil, util.RepositoryErrorBuilder(err)
+		}
+
+		response := dto.TaskResponse{
+			Name:      string(task.Name),
+			Schedule:  task.Schedule.String(),
+			NextRunAt: task.Schedule.Next(now),
+		}
+		if last != nil {
+			run := newTaskRunResponse(last)
+			response.LastRun = &run
+		}
+		result = append(result, response)
+	}
+	return result, nil
+}
+
+func (s *service) FindRuns(ctx context.Context, payload *dto.SearchTaskRunRequest) (*pkgdto.SearchGetResponse[dto.TaskRunResponse], error) {
+	runs, info, err := s.TaskRunRepository.FindAll(ctx, payload)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	data := []dto.TaskRunResponse{}
+	for i := range runs {
+		data = append(data, newTaskRunResponse(&runs[i]))
+	}
+
+	result := new(pkgdto.SearchGetResponse[dto.TaskRunResponse])
+	result.Data = data
+	result.PaginationInfo = *info
+
+	return result, nil
+}
+
+func newTaskRunResponse(run *model.TaskRun) dto.TaskRunResponse {
+	return dto.TaskRunResponse{
+		ID:          run.ID,
+		Task:        run.Task,
+		ScheduledAt: run.ScheduledAt,
+		Owner:       run.Owner,
+		Status:      run.Status,
+		Result:      run.Result,
+		Error:       run.Error,
+		StartedAt:   run.StartedAt,
+		FinishedAt:  run.FinishedAt,
+	}
+}
@@ -0,0 +1,119 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"student-service/internal/app/statistics"
+	"student-service/internal/factory"
+	"student-service/internal/pkg/enum"
+	"student-service/pkg/cron"
+	pkgutil "student-service/pkg/util"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Tasks returns the housekeeping tasks with their schedules from the environment, a task whose
+// schedule is off is left out:
+//   - purge.deleted deletes for good the records soft deleted more than PURGE_DELETED_AFTER_DAYS
+//     days ago, 30 by default, on TASK_PURGE_DELETED_SCHEDULE, every day at 03:00 by default.
+//   - purge.history deletes the published outbox events, succeeded jobs and task runs older than
+//     PURGE_HISTORY_AFTER_DAYS days, 14 by default, on TASK_PURGE_HISTORY_SCHEDULE, every day at
+//     03:30 by default.
+//   - statistics.compute computes the statistics again on TASK_COMPUTE_STATISTICS_SCHEDULE, every
+//     15 minutes by default.
+func Tasks(f *factory.Factory) []Task {
+	var tasks []Task
+	add := func(name enum.TaskName, scheduleEnv, fallback string, run func(ctx context.Context) (string, error)) {
+		if schedule := schedule(scheduleEnv, fallback); schedule != nil {
+			tasks = append(tasks, Task{Name: name, Schedule: schedule, Run: run})
+		}
+	}
+	add(enum.TaskPurgeDeleted, "TASK_PURGE_DELETED_SCHEDULE", "0 3 * * *", PurgeDeleted(f, days("PURGE_DELETED_AFTER_DAYS", 30)))
+	add(enum.TaskPurgeHistory, "TASK_PURGE_HISTORY_SCHEDULE", "30 3 * * *", PurgeHistory(f, days("PURGE_HISTORY_AFTER_DAYS", 14)))
+	add(enum.TaskComputeStatistics, "TASK_COMPUTE_STATISTICS_SCHEDULE", "*/15 * * * *", ComputeStatistics(f))
+	return tasks
+}
+
+// PurgeDeleted deletes for good the records soft deleted more than after days ago. Records that
+// others still refer to are kept.
+func PurgeDeleted(f *factory.Factory, after int) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		purged, kept, err := f.MaintenanceRepository.PurgeDeleted(ctx, time.Now().AddDate(0, 0, -after))
+		tables := make([]string, 0, len(purged))
+		for table := range purged {
+			tables = append(tables, table)
+		}
+		sort.Strings(tables)
+		counts := []string{}
+		for _, table := range tables {
+			counts = append(counts, fmt.Sprintf("%d %s", purged[table], table))
+		}
+		if len(counts) == 0 {
+			counts = append(counts, "nothing")
+		}
+		return fmt.Sprintf("purged %s, kept %d still referenced", strings.Join(counts, ", "), kept), err
+	}
+}
+
+// PurgeHistory deletes the published outbox events, succeeded jobs and finished task runs older
+// than after days.
+func PurgeHistory(f *factory.Factory, after int) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		before := time.Now().AddDate(0, 0, -after)
+		events, err := f.OutboxRepository.PurgePublished(ctx, before)
+		if err != nil {
+			return "", err
+		}
+		jobs, err := f.JobRepository.PurgeSucceeded(ctx, before)
+		if err != nil {
+			return "", err
+		}
+		runs, err := f.TaskRunRepository.Purge(ctx, before)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("purged %d outbox events, %d jobs, %d task runs", events, jobs, runs), nil
+	}
+}
+
+// ComputeStatistics computes the statistics served by the statistics endpoint again.
+func ComputeStatistics(f *factory.Factory) func(ctx context.Context) (string, error) {
+	service := statistics.NewService(f)
+	return func(ctx context.Context) (string, error) {
+		result, err := service.Compute(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d students, %d classes, %d majors, %d courses", result.Students, result.Classes, result.Majors, result.Courses), nil
+	}
+}
+
+// schedule returns the cron schedule of the environment variable name, fallback when it is not
+// set, and nil when it is off.
+func schedule(name, fallback string) *cron.Schedule {
+	spec := pkgutil.Getenv(name, fallback)
+	if strings.EqualFold(spec, "off") {
+		return nil
+	}
+	s, err := cron.Parse(spec)
+	if err != nil {
+		logrus.Warnf("invalid %s, using %s: %v", name, fallback, err)
+		s, _ = cron.Parse(fallback)
+	}
+	return s
+}
+
+// days returns the number of days of the environment variable name, fallback when it is not set.
+func days(name string, fallback int) int {
+	n, err := strconv.Atoi(pkgutil.Getenv(name, strconv.Itoa(fallback)))
+	if err != nil || n <= 0 {
+		logrus.Warnf("invalid %s, using %d: %v", name, fallback, err)
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,85 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"student-service/database/seeder"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// softDelete marks the rows of table with ids as deleted days ago.
+func softDelete(t *testing.T, table string, days int, ids ...uint) {
+	if err := db.Exec("UPDATE "+table+" SET deleted_at = ? WHERE id IN ?", time.Now().AddDate(0, 0, -days), ids).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func count(t *testing.T, table, where string, args ...interface{}) int64 {
+	var n int64
+	if err := db.Table(table).Where(where, args...).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	softDelete(t, "class_sessions", 40, 1)
+	softDelete(t, "rooms", 40, 2)
+	// major 1 has students and courses, student 3 has attendances in session 2
+	softDelete(t, "majors", 40, 1, 3)
+	softDelete(t, "students", 40, 3)
+	// deleted too recently
+	softDelete(t, "classes", 10, 1)
+
+	asserts := assert.New(t)
+	result, err := PurgeDeleted(&f, 30)(ctx)
+	asserts.NoError(err)
+	asserts.Equal("purged 1 class_sessions, 1 majors, 1 rooms, kept 2 still referenced", result)
+
+	asserts.Zero(count(t, "class_sessions", "id = ?", 1))
+	asserts.Zero(count(t, "attendances", "session_id = ?", 1))
+	asserts.Equal(int64(2), count(t, "attendances", "session_id = ?", 2))
+	asserts.Zero(count(t, "rooms", "id = ?", 2))
+	asserts.Zero(count(t, "room_facilities", "room_id = ?", 2))
+	asserts.Zero(count(t, "majors", "id = ?", 3))
+	asserts.Equal(int64(1), count(t, "majors", "id = ?", 1))
+	asserts.Equal(int64(1), count(t, "students", "id = ?", 3))
+	asserts.Equal(int64(1), count(t, "classes", "id = ?", 1))
+
+	result, err = PurgeDeleted(&f, 30)(ctx)
+	asserts.NoError(err)
+	asserts.Equal("purged nothing, kept 2 still referenced", result)
+}
+
+func TestPurgeHistory(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	old, recent := time.Now().AddDate(0, 0, -20), time.Now().AddDate(0, 0, -1)
+	rows := []interface{}{
+		&model.OutboxEvent{EventID: "evt_1", Type: "major.created", AggregateType: "major", AggregateID: "1", Data: "{}", OccurredAt: old, PublishedAt: &old},
+		&model.OutboxEvent{EventID: "evt_2", Type: "major.created", AggregateType: "major", AggregateID: "2", Data: "{}", OccurredAt: old},
+		&model.OutboxEvent{EventID: "evt_3", Type: "major.created", AggregateType: "major", AggregateID: "3", Data: "{}", OccurredAt: recent, PublishedAt: &recent},
+		&model.Job{Type: "test.job", Payload: "{}", Status: string(enum.JobSucceeded), MaxAttempts: 1, RunAt: old, FinishedAt: &old},
+		&model.Job{Type: "test.job", Payload: "{}", Status: string(enum.JobDead), MaxAttempts: 1, RunAt: old, FinishedAt: &old},
+		&model.TaskRun{Task: string(testTask), ScheduledAt: old, Owner: "replica-1", Status: string(enum.TaskRunSucceeded), StartedAt: old, FinishedAt: &old},
+		&model.TaskRun{Task: string(testTask), ScheduledAt: recent, Owner: "replica-1", Status: string(enum.TaskRunSucceeded), StartedAt: recent, FinishedAt: &recent},
+	}
+	for _, row := range rows {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	asserts := assert.New(t)
+	result, err := PurgeHistory(&f, 14)(ctx)
+	asserts.NoError(err)
+	asserts.Equal("purged 1 outbox events, 1 jobs, 1 task runs", result)
+	asserts.Equal(int64(2), count(t, "outbox_events", "1 = 1"))
+	asserts.Equal(int64(1), count(t, "jobs", "status = ?", enum.JobDead))
+	asserts.Equal(int64(1), count(t, "task_runs", "1 = 1"))
+}
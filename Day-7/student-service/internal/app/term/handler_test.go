@@ -0,0 +1,193 @@
+package term
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"student-service/database"
+	"student-service/database/seeder"
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/mocks"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	pkgutil "student-service/pkg/util"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	adminClaims = util.CreateJWTClaims("vincentlhubbard@edu.ac.id", uint(1), uint(enum.A), uint(enum.Finance))
+	userClaims  = util.CreateJWTClaims("devoncthomas@edu.ac.id", uint(2), uint(enum.B), uint(enum.Finance))
+	db          = database.GetConnection()
+	echoMock    = mocks.EchoMock{E: echo.New()}
+	f           = factory.Factory{
+		TermRepository:    repository.NewTermRepository(db),
+		StudentRepository: repository.NewStudentRepository(db),
+		CourseRepository:  repository.NewCourseRepository(db),
+	}
+	termHandler = NewHandler(&f)
+)
+
+func termRequest(t *testing.T, claims dto.JWTClaims, method, payload string, id string) (echo.Context, func() (int, string)) {
+	c, rec := echoMock.RequestMock(method, "/", bytes.NewBufferString(payload))
+	if id != "" {
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+	}
+	token, err := util.CreateJWTToken(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	c.Request().Header.Set("Content-Type", "application/json")
+	return c, func() (int, string) { return rec.Code, rec.Body.String() }
+}
+
+func TestTermHandlerCreate(t *testing.T) {
+	cases := []struct {
+		name     string
+		claims   dto.JWTClaims
+		payload  string
+		code     int
+		contains string
+	}{
+		{"success", adminClaims, `{"name":"2027 Odd Semester","start_date":"2027-08-01","end_date":"2028-01-31"}`, 200, `"start_date":"2027-08-01","end_date":"2028-01-31","closed":false`},
+		{"duplicate name", adminClaims, `{"name":"Current Semester","start_date":"2027-08-01","end_date":"2028-01-31"}`, 400, `"field":"name"`},
+		{"invalid date", adminClaims, `{"name":"2027 Odd Semester","start_date":"2027-08-01","end_date":"31/01/2028"}`, 400, `"field":"end_date"`},
+		{"ends before it starts", adminClaims, `{"name":"2027 Odd Semester","start_date":"2027-08-01","end_date":"2027-07-31"}`, 422, "Term cannot end before it starts"},
+		{"not class A", userClaims, `{"name":"2027 Odd Semester","start_date":"2027-08-01","end_date":"2028-01-31"}`, 401, "unauthorized"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			seeder.NewSeeder().SeedAll()
+
+			c, rec := termRequest(t, tc.claims, http.MethodPost, tc.payload, "")
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(termHandler.Create(c)) {
+				code, body := rec()
+				asserts.Equal(tc.code, code)
+				asserts.Contains(body, tc.contains)
+			}
+		})
+	}
+}
+
+func TestTermHandlerGetActive(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	asserts := assert.New(t)
+
+	c, rec := termRequest(t, userClaims, http.MethodGet, "", "")
+	if asserts.NoError(termHandler.GetActive(c)) {
+		code, body := rec()
+		asserts.Equal(200, code)
+		asserts.Contains(body, `"name":"Current Semester"`)
+		asserts.Contains(body, `"closed":false`)
+	}
+
+	// a term that started later is active over an overlapping one
+	today := time.Now().Format(pkgutil.DateLayout)
+	payload := fmt.Sprintf(`{"name":"Short Semester","start_date":"%s","end_date":"%s"}`, today, today)
+	c, rec = termRequest(t, adminClaims, http.MethodPost, payload, "")
+	if asserts.NoError(termHandler.Create(c)) {
+		code, _ := rec()
+		asserts.Equal(200, code)
+	}
+	c, rec = termRequest(t, userClaims, http.MethodGet, "", "")
+	if asserts.NoError(termHandler.GetActive(c)) {
+		_, body := rec()
+		asserts.Contains(body, `"name":"Short Semester"`)
+	}
+
+	if err := db.Table("terms").Where("closed = ?", false).Update("closed", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	c, rec = termRequest(t, userClaims, http.MethodGet, "", "")
+	if asserts.NoError(termHandler.GetActive(c)) {
+		code, body := rec()
+		asserts.Equal(422, code)
+		asserts.Contains(body, "No term is active")
+	}
+}
+
+func TestTermHandlerCloseAndReopen(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	asserts := assert.New(t)
+
+	c, rec := termRequest(t, userClaims, http.MethodPost, "", "2")
+	if asserts.NoError(termHandler.Close(c)) {
+		code, _ := rec()
+		asserts.Equal(401, code)
+	}
+
+	c, rec = termRequest(t, adminClaims, http.MethodPost, "", "2")
+	c.Request().Header.Set("If-Match", `"1"`)
+	if asserts.NoError(termHandler.Close(c)) {
+		code, body := rec()
+		asserts.Equal(200, code)
+		asserts.Contains(body, `"closed":true,"version":2`)
+	}
+
+	// the class of every student is recorded when the term is closed
+	var count int64
+	db.Table("class_memberships").Where("term_id = ?", 2).Count(&count)
+	asserts.Equal(int64(3), count)
+
+	c, rec = termRequest(t, adminClaims, http.MethodPut, `{"name":"Renamed Semester"}`, "2")
+	if asserts.NoError(termHandler.UpdateById(c)) {
+		code, body := rec()
+		asserts.Equal(409, code)
+		asserts.Contains(body, "Term is closed")
+	}
+
+	c, rec = termRequest(t, adminClaims, http.MethodPost, "", "2")
+	if asserts.NoError(termHandler.Reopen(c)) {
+		code, body := rec()
+		asserts.Equal(200, code)
+		asserts.Contains(body, `"closed":false,"version":3`)
+	}
+	db.Table("class_memberships").Where("term_id = ?", 2).Count(&count)
+	asserts.Zero(count)
+}
+
+func TestTermHandlerDeleteById(t *testing.T) {
+	cases := []struct {
+		name   string
+		termID string
+		code   int
+	}{
+		{"unused term", "3", 200},
+		{"term with enrollments", "2", 422},
+		{"closed term", "1", 409},
+		{"not found", "99", 404},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			seeder.NewSeeder().SeedAll()
+			if _, err := f.CourseRepository.Enroll(context.Background(), 1, 2, 2); err != nil {
+				t.Fatal(err)
+			}
+
+			c, rec := termRequest(t, adminClaims, http.MethodDelete, "", tc.termID)
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(termHandler.DeleteById(c)) {
+				code, _ := rec()
+				asserts.Equal(tc.code, code)
+			}
+		})
+	}
+}
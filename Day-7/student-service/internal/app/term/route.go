@@ -0,0 +1,21 @@
+package term
+
+import (
+	"student-service/internal/dto"
+	"student-service/internal/middleware"
+	"student-service/internal/pkg/util"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handler) Route(g *echo.Group) {
+	g.Use(middleware.JWTMiddleware(dto.JWTClaims{}, util.JWT_SECRET))
+	g.GET("", h.Get)
+	g.GET("/active", h.GetActive)
+	g.GET("/:id", h.GetById)
+	g.PUT("/:id", h.UpdateById)
+	g.DELETE("/:id", h.DeleteById)
+	g.POST("", h.Create, h.idempotency)
+	g.POST("/:id/close", h.Close)
+	g.POST("/:id/reopen", h.Reopen)
+}
@@ -0,0 +1,235 @@
+package term
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	pkgdto "student-service/pkg/dto"
+	pkgutil "student-service/pkg/util"
+	res "student-service/pkg/util/response"
+)
+
+type service struct {
+	TermRepository repository.Term
+}
+
+type Service interface {
+	Find(ctx context.Context, payload *pkgdto.SearchGetRequest) (*pkgdto.SearchGetResponse[dto.TermResponse], error)
+	FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.TermResponse, error)
+	FindActive(ctx context.Context) (*dto.TermResponse, error)
+	Store(ctx context.Context, payload *dto.CreateTermRequestBody) (*dto.TermResponse, error)
+	UpdateById(ctx context.Context, payload *dto.UpdateTermRequestBody) (*dto.TermResponse, error)
+	DeleteById(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.TermWithCUDResponse, error)
+	Close(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.TermResponse, error)
+	Reopen(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.TermResponse, error)
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		TermRepository: f.TermRepository,
+	}
+}
+
+func (s *service) Find(ctx context.Context, payload *pkgdto.SearchGetRequest) (*pkgdto.SearchGetResponse[dto.TermResponse], error) {
+	terms, info, err := s.TermRepository.FindAll(ctx, payload, &payload.Pagination)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	var data []dto.TermResponse
+	for i := range terms {
+		data = append(data, newTermResponse(&terms[i]))
+	}
+
+	result := new(pkgdto.SearchGetResponse[dto.TermResponse])
+	result.Data = data
+	result.PaginationInfo = *info
+
+	return result, nil
+}
+
+func (s *service) FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.TermResponse, error) {
+	term, err := s.TermRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return &dto.TermResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := newTermResponse(&term)
+	return &result, nil
+}
+
+func (s *service) FindActive(ctx context.Context) (*dto.TermResponse, error) {
+	term, err := util.FindTerm(ctx, s.TermRepository, nil)
+	if err != nil {
+		return &dto.TermResponse{}, err
+	}
+
+	result := newTermResponse(term)
+	return &result, nil
+}
+
+func (s *service) Store(ctx context.Context, payload *dto.CreateTermRequestBody) (*dto.TermResponse, error) {
+	term := model.Term{Name: *payload.Name}
+	if err := setDates(&term, payload.StartDate, payload.EndDate); err != nil {
+		return &dto.TermResponse{}, err
+	}
+
+	if err := s.TermRepository.Save(ctx, &term); err != nil {
+		return &dto.TermResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := newTermResponse(&term)
+	return &result, nil
+}
+
+func (s *service) UpdateById(ctx context.Context, payload *dto.UpdateTermRequestBody) (*dto.TermResponse, error) {
+	term, err := s.TermRepository.FindByID(ctx, *payload.ID)
+	if err != nil {
+		return &dto.TermResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	if !res.IfMatch(payload.IfMatch, term.Version) {
+		return &dto.TermResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("term version does not match If-Match"))
+	}
+	if err := util.CheckTermOpen(&term); err != nil {
+		return &dto.TermResponse{}, err
+	}
+
+	updateData := term
+	if payload.Name != nil {
+		updateData.Name = *payload.Name
+	}
+	if err := setDates(&updateData, payload.StartDate, payload.EndDate); err != nil {
+		return &dto.TermResponse{}, err
+	}
+
+	_, err = s.TermRepository.Edit(ctx, &term, &updateData)
+	if err != nil {
+		return &dto.TermResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := newTermResponse(&term)
+	return &result, nil
+}
+
+func (s *service) DeleteById(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.TermWithCUDResponse, error) {
+	term, err := s.TermRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return &dto.TermWithCUDResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	if !res.IfMatch(payload.IfMatch, term.Version) {
+		return &dto.TermWithCUDResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("term version does not match If-Match"))
+	}
+	if err := util.CheckTermOpen(&term); err != nil {
+		return &dto.TermWithCUDResponse{}, err
+	}
+	isUsed, err := s.TermRepository.IsUsed(ctx, term.ID)
+	if err != nil {
+		return &dto.TermWithCUDResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	if isUsed {
+		return &dto.TermWithCUDResponse{}, res.ErrorBuilder(res.ErrorConstant.InvalidReference, errors.New("term still has enrollments or grades"))
+	}
+
+	_, err = s.TermRepository.Destroy(ctx, &term)
+	if err != nil {
+		return &dto.TermWithCUDResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := &dto.TermWithCUDResponse{
+		TermResponse: newTermResponse(&term),
+		CreatedAt:    term.CreatedAt,
+		UpdatedAt:    term.UpdatedAt,
+		DeletedAt:    term.DeletedAt,
+	}
+
+	return result, nil
+}
+
+// Close makes the term read-only and records the class of every student in it. Closing a
+// closed term changes nothing.
+func (s *service) Close(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.TermResponse, error) {
+	term, err := s.TermRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return &dto.TermResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	if !res.IfMatch(payload.IfMatch, term.Version) {
+		return &dto.TermResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("term version does not match If-Match"))
+	}
+
+	if !term.Closed {
+		if _, err := s.TermRepository.Close(ctx, &term); err != nil {
+			return &dto.TermResponse{}, util.RepositoryErrorBuilder(err)
+		}
+	}
+
+	result := newTermResponse(&term)
+	return &result, nil
+}
+
+// Reopen makes a closed term writable again. Reopening an open term changes nothing.
+func (s *service) Reopen(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.TermResponse, error) {
+	term, err := s.TermRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return &dto.TermResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	if !res.IfMatch(payload.IfMatch, term.Version) {
+		return &dto.TermResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("term version does not match If-Match"))
+	}
+
+	if term.Closed {
+		if _, err := s.TermRepository.Reopen(ctx, &term); err != nil {
+			return &dto.TermResponse{}, util.RepositoryErrorBuilder(err)
+		}
+	}
+
+	result := newTermResponse(&term)
+	return &result, nil
+}
+
+// setDates sets the dates of the term that are not nil, the term must not end before it starts.
+func setDates(term *model.Term, startDate, endDate *string) error {
+	var err error
+	if startDate != nil {
+		if term.StartDate, err = parseDate(*startDate); err != nil {
+			return err
+		}
+	}
+	if endDate != nil {
+		if term.EndDate, err = parseDate(*endDate); err != nil {
+			return err
+		}
+	}
+
+	if term.EndDate.Before(term.StartDate) {
+		return res.ErrorBuilder(res.ErrorConstant.InvalidTermDates, fmt.Errorf("term ends on %s before it starts on %s",
+			term.EndDate.Format(pkgutil.DateLayout), term.StartDate.Format(pkgutil.DateLayout)))
+	}
+	return nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	date, err := pkgutil.ParseDate(value)
+	if err != nil {
+		return date, res.ErrorBuilder(res.ErrorConstant.BadRequest, err)
+	}
+	return date, nil
+}
+
+func newTermResponse(term *model.Term) dto.TermResponse {
+	return dto.TermResponse{
+		ID:        term.ID,
+		Name:      term.Name,
+		StartDate: term.StartDate.Format(pkgutil.DateLayout),
+		EndDate:   term.EndDate.Format(pkgutil.DateLayout),
+		Closed:    term.Closed,
+		Version:   term.Version,
+	}
+}
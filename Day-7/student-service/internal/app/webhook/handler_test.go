@@ -0,0 +1,182 @@
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"student-service/database"
+	"student-service/database/seeder"
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/mocks"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	adminClaims = util.CreateJWTClaims("vincentlhubbard@edu.ac.id", uint(1), uint(enum.A), uint(enum.Finance))
+	userClaims  = util.CreateJWTClaims("devoncthomas@edu.ac.id", uint(2), uint(enum.B), uint(enum.Finance))
+	db          = database.GetConnection()
+	echoMock    = mocks.EchoMock{E: echo.New()}
+	f           = factory.Factory{
+		WebhookRepository: repository.NewWebhookRepository(db),
+	}
+	webhookHandler = NewHandler(&f)
+)
+
+func webhookRequest(t *testing.T, claims dto.JWTClaims, method, payload string, params ...string) (echo.Context, func() (int, string)) {
+	c, rec := echoMock.RequestMock(method, "/", bytes.NewBufferString(payload))
+	// params are names followed by their values
+	if len(params) > 0 {
+		c.SetParamNames(params[:len(params)/2]...)
+		c.SetParamValues(params[len(params)/2:]...)
+	}
+	token, err := util.CreateJWTToken(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Request().Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	c.Request().Header.Set("Content-Type", "application/json")
+	return c, func() (int, string) { return rec.Code, rec.Body.String() }
+}
+
+func TestWebhookHandlerCreate(t *testing.T) {
+	cases := []struct {
+		name     string
+		claims   dto.JWTClaims
+		payload  string
+		code     int
+		contains string
+	}{
+		{"generated secret", adminClaims, `{"url":"https://example.com/hook","events":["student.registered","class.deleted"]}`, 200, `"secret":"whsec_`},
+		{"given secret", adminClaims, `{"url":"https://example.com/hook","events":["major.created"],"secret":"0123456789abcdef","active":false}`, 200, `"active":false,"created_at"`},
+		{"unknown event", adminClaims, `{"url":"https://example.com/hook","events":["student.graduated"]}`, 400, `"field":"events[0]"`},
+		{"no url", adminClaims, `{"events":["major.created"]}`, 400, `"field":"url"`},
+		{"short secret", adminClaims, `{"url":"https://example.com/hook","events":["major.created"],"secret":"short"}`, 400, `"field":"secret"`},
+		{"not class A", userClaims, `{"url":"https://example.com/hook","events":["major.created"]}`, 401, "unauthorized"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+
+			c, rec := webhookRequest(t, tc.claims, http.MethodPost, tc.payload)
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(webhookHandler.Create(c)) {
+				code, body := rec()
+				asserts.Equal(tc.code, code)
+				asserts.Contains(body, tc.contains)
+			}
+		})
+	}
+}
+
+func TestWebhookHandlerGetHidesSecret(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	subscription := createSubscription(t, "https://example.com/hook", "0123456789abcdef", enum.MajorCreated)
+
+	asserts := assert.New(t)
+	c, rec := webhookRequest(t, adminClaims, http.MethodGet, "")
+	if asserts.NoError(webhookHandler.Get(c)) {
+		code, body := rec()
+		asserts.Equal(200, code)
+		asserts.Contains(body, `"events":["major.created"]`)
+		asserts.NotContains(body, "0123456789abcdef")
+	}
+
+	c, rec = webhookRequest(t, adminClaims, http.MethodGet, "", "id", fmt.Sprint(subscription.ID))
+	if asserts.NoError(webhookHandler.GetById(c)) {
+		code, body := rec()
+		asserts.Equal(200, code)
+		asserts.NotContains(body, "0123456789abcdef")
+	}
+
+	c, rec = webhookRequest(t, userClaims, http.MethodGet, "")
+	if asserts.NoError(webhookHandler.Get(c)) {
+		code, _ := rec()
+		asserts.Equal(401, code)
+	}
+}
+
+func TestWebhookHandlerUpdateById(t *testing.T) {
+	cases := []struct {
+		name     string
+		payload  string
+		ifMatch  string
+		code     int
+		contains string
+	}{
+		{"events", `{"events":["student.deleted"]}`, `"1"`, 200, `"events":["student.deleted"],"active":true`},
+		{"deactivate", `{"active":false}`, "", 200, `"active":false`},
+		{"stale version", `{"active":false}`, `"2"`, 412, "precondition"},
+		{"invalid url", `{"url":"not a url"}`, "", 400, `"field":"url"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeder.NewSeeder().DeleteAll()
+			subscription := createSubscription(t, "https://example.com/hook", "0123456789abcdef", enum.MajorCreated)
+
+			c, rec := webhookRequest(t, adminClaims, http.MethodPut, tc.payload, "id", fmt.Sprint(subscription.ID))
+			if tc.ifMatch != "" {
+				c.Request().Header.Set("If-Match", tc.ifMatch)
+			}
+
+			// testing
+			asserts := assert.New(t)
+			if asserts.NoError(webhookHandler.UpdateById(c)) {
+				code, body := rec()
+				asserts.Equal(tc.code, code)
+				asserts.Contains(body, tc.contains)
+			}
+		})
+	}
+}
+
+func TestWebhookHandlerDeliveries(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	subscription := createSubscription(t, "https://example.com/hook", "0123456789abcdef", enum.MajorCreated)
+	other := createSubscription(t, "https://example.com/other", "0123456789abcdef", enum.MajorDeleted)
+	publish(t, enum.MajorCreated, dto.MajorResponse{ID: 7, Name: "Law"})
+
+	asserts := assert.New(t)
+	c, rec := webhookRequest(t, adminClaims, http.MethodGet, "", "id", fmt.Sprint(subscription.ID))
+	if !asserts.NoError(webhookHandler.GetDeliveries(c)) {
+		return
+	}
+	code, body := rec()
+	asserts.Equal(200, code)
+	asserts.Contains(body, `"event":"major.created","payload":{"id":"evt_`)
+	asserts.Contains(body, `"data":{"id":7,"name":"Law","version":0}`)
+	asserts.Contains(body, `"status":"pending"`)
+
+	c, rec = webhookRequest(t, adminClaims, http.MethodGet, "", "id", fmt.Sprint(other.ID))
+	if asserts.NoError(webhookHandler.GetDeliveries(c)) {
+		_, body := rec()
+		asserts.Contains(body, `"data":[]`)
+	}
+
+	deliveries, _, err := f.WebhookRepository.FindDeliveries(ctx, &dto.SearchWebhookDeliveryRequest{SubscriptionID: subscription.ID})
+	if !asserts.NoError(err) || !asserts.Len(deliveries, 1) {
+		return
+	}
+
+	c, rec = webhookRequest(t, adminClaims, http.MethodPost, "", "id", "delivery_id", fmt.Sprint(subscription.ID), fmt.Sprint(deliveries[0].ID))
+	if asserts.NoError(webhookHandler.Redeliver(c)) {
+		code, body := rec()
+		asserts.Equal(202, code)
+		asserts.Contains(body, fmt.Sprintf(`"redelivery_of_id":%d`, deliveries[0].ID))
+	}
+
+	c, rec = webhookRequest(t, adminClaims, http.MethodPost, "", "id", "delivery_id", fmt.Sprint(other.ID), fmt.Sprint(deliveries[0].ID))
+	if asserts.NoError(webhookHandler.Redeliver(c)) {
+		code, _ := rec()
+		asserts.Equal(404, code)
+	}
+}
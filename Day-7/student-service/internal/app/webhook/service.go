@@ -0,0 +1,227 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	pkgdto "student-service/pkg/dto"
+	res "student-service/pkg/util/response"
+)
+
+type service struct {
+	WebhookRepository repository.Webhook
+}
+
+type Service interface {
+	Find(ctx context.Context, payload *pkgdto.Pagination) (*pkgdto.SearchGetResponse[dto.WebhookResponse], error)
+	FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.WebhookResponse, error)
+	Store(ctx context.Context, payload *dto.CreateWebhookRequestBody) (*dto.WebhookWithSecretResponse, error)
+	UpdateById(ctx context.Context, payload *dto.UpdateWebhookRequestBody) (*dto.WebhookResponse, error)
+	DeleteById(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.WebhookResponse, error)
+	FindDeliveries(ctx context.Context, payload *dto.SearchWebhookDeliveryRequest) (*pkgdto.SearchGetResponse[dto.WebhookDeliveryResponse], error)
+	Redeliver(ctx context.Context, payload *dto.RedeliverWebhookRequest) (*dto.WebhookDeliveryResponse, error)
+}
+
+func NewService(f *factory.Factory) Service {
+	return &service{
+		WebhookRepository: f.WebhookRepository,
+	}
+}
+
+func (s *service) Find(ctx context.Context, payload *pkgdto.Pagination) (*pkgdto.SearchGetResponse[dto.WebhookResponse], error) {
+	subscriptions, info, err := s.WebhookRepository.FindAll(ctx, payload)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	data := []dto.WebhookResponse{}
+	for i := range subscriptions {
+		data = append(data, newWebhookResponse(&subscriptions[i]))
+	}
+
+	result := new(pkgdto.SearchGetResponse[dto.WebhookResponse])
+	result.Data = data
+	result.PaginationInfo = *info
+
+	return result, nil
+}
+
+func (s *service) FindByID(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.WebhookResponse, error) {
+	subscription, err := s.WebhookRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return &dto.WebhookResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := newWebhookResponse(&subscription)
+	return &result, nil
+}
+
+// Store subscribes the URL, with a generated secret when none is given. The secret is only
+// returned here.
+func (s *service) Store(ctx context.Context, payload *dto.CreateWebhookRequestBody) (*dto.WebhookWithSecretResponse, error) {
+	subscription := model.WebhookSubscription{
+		URL:    *payload.URL,
+		Events: strings.Join(payload.Events, ","),
+		Active: true,
+	}
+	if payload.Active != nil {
+		subscription.Active = *payload.Active
+	}
+	if payload.Secret != nil {
+		subscription.Secret = *payload.Secret
+	} else {
+		secret, err := newSecret()
+		if err != nil {
+			return &dto.WebhookWithSecretResponse{}, res.ErrorBuilder(res.ErrorConstant.InternalServerError, err)
+		}
+		subscription.Secret = secret
+	}
+
+	if err := s.WebhookRepository.Save(ctx, &subscription); err != nil {
+		return &dto.WebhookWithSecretResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	return &dto.WebhookWithSecretResponse{
+		WebhookResponse: newWebhookResponse(&subscription),
+		Secret:          subscription.Secret,
+	}, nil
+}
+
+func (s *service) UpdateById(ctx context.Context, payload *dto.UpdateWebhookRequestBody) (*dto.WebhookResponse, error) {
+	subscription, err := s.WebhookRepository.FindByID(ctx, *payload.ID)
+	if err != nil {
+		return &dto.WebhookResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	if !res.IfMatch(payload.IfMatch, subscription.Version) {
+		return &dto.WebhookResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("webhook version does not match If-Match"))
+	}
+
+	if _, err := s.WebhookRepository.Edit(ctx, &subscription, payload); err != nil {
+		return &dto.WebhookResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := newWebhookResponse(&subscription)
+	return &result, nil
+}
+
+// DeleteById deletes the subscription, its pending deliveries fail when they are attempted.
+func (s *service) DeleteById(ctx context.Context, payload *pkgdto.ByIDRequest) (*dto.WebhookResponse, error) {
+	subscription, err := s.WebhookRepository.FindByID(ctx, payload.ID)
+	if err != nil {
+		return &dto.WebhookResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	if !res.IfMatch(payload.IfMatch, subscription.Version) {
+		return &dto.WebhookResponse{}, res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, errors.New("webhook version does not match If-Match"))
+	}
+
+	if _, err := s.WebhookRepository.Destroy(ctx, &subscription); err != nil {
+		return &dto.WebhookResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := newWebhookResponse(&subscription)
+	return &result, nil
+}
+
+func (s *service) FindDeliveries(ctx context.Context, payload *dto.SearchWebhookDeliveryRequest) (*pkgdto.SearchGetResponse[dto.WebhookDeliveryResponse], error) {
+	if _, err := s.WebhookRepository.FindByID(ctx, payload.SubscriptionID); err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	deliveries, info, err := s.WebhookRepository.FindDeliveries(ctx, payload)
+	if err != nil {
+		return nil, util.RepositoryErrorBuilder(err)
+	}
+
+	data := []dto.WebhookDeliveryResponse{}
+	for i := range deliveries {
+		data = append(data, newWebhookDeliveryResponse(&deliveries[i]))
+	}
+
+	result := new(pkgdto.SearchGetResponse[dto.WebhookDeliveryResponse])
+	result.Data = data
+	result.PaginationInfo = *info
+
+	return result, nil
+}
+
+// Redeliver queues a new delivery of the payload of a delivery, whatever its outcome was. The
+// payload keeps its event id, so receivers can tell it is the same event.
+func (s *service) Redeliver(ctx context.Context, payload *dto.RedeliverWebhookRequest) (*dto.WebhookDeliveryResponse, error) {
+	subscription, err := s.WebhookRepository.FindByID(ctx, payload.SubscriptionID)
+	if err != nil {
+		return &dto.WebhookDeliveryResponse{}, util.RepositoryErrorBuilder(err)
+	}
+	if !subscription.Active {
+		return &dto.WebhookDeliveryResponse{}, res.ErrorBuilder(res.ErrorConstant.BadRequest, fmt.Errorf("webhook %d is not active", subscription.ID))
+	}
+
+	original, err := s.WebhookRepository.FindDeliveryByID(ctx, payload.SubscriptionID, payload.DeliveryID)
+	if err != nil {
+		return &dto.WebhookDeliveryResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	now := time.Now()
+	deliveries := []model.WebhookDelivery{{
+		SubscriptionID: subscription.ID,
+		Event:          original.Event,
+		Payload:        original.Payload,
+		Status:         string(enum.DeliveryPending),
+		NextAttemptAt:  &now,
+		RedeliveryOfID: &original.ID,
+	}}
+	if err := s.WebhookRepository.SaveDeliveries(ctx, deliveries); err != nil {
+		return &dto.WebhookDeliveryResponse{}, util.RepositoryErrorBuilder(err)
+	}
+
+	result := newWebhookDeliveryResponse(&deliveries[0])
+	return &result, nil
+}
+
+func newWebhookResponse(subscription *model.WebhookSubscription) dto.WebhookResponse {
+	return dto.WebhookResponse{
+		ID:        subscription.ID,
+		URL:       subscription.URL,
+		Events:    strings.Split(subscription.Events, ","),
+		Active:    subscription.Active,
+		CreatedAt: subscription.CreatedAt,
+		Version:   subscription.Version,
+	}
+}
+
+func newWebhookDeliveryResponse(delivery *model.WebhookDelivery) dto.WebhookDeliveryResponse {
+	return dto.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		Event:          delivery.Event,
+		Payload:        json.RawMessage(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastAttemptAt:  delivery.LastAttemptAt,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		RedeliveryOfID: delivery.RedeliveryOfID,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"student-service/internal/dto"
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/repository"
+	"student-service/pkg/events"
+)
+
+// Subscriber creates the webhook deliveries of the domain events.
+type Subscriber struct {
+	WebhookRepository repository.Webhook
+}
+
+func NewSubscriber(f *factory.Factory) *Subscriber {
+	return &Subscriber{
+		WebhookRepository: f.WebhookRepository,
+	}
+}
+
+// Subscribe makes the subscriber handle every event published to bus.
+func (s *Subscriber) Subscribe(bus *events.Bus) {
+	bus.Subscribe(events.All, s.Handle)
+}
+
+// Handle creates a pending delivery of the event for every active subscription to it, the worker
+// sends them. The relay publishes an event again when a broker fails, the deliveries already
+// created for it are kept. The payload has the id of the event, so receivers can drop the events
+// they already got.
+func (s *Subscriber) Handle(ctx context.Context, event events.Event) error {
+	subscriptions, err := s.WebhookRepository.FindActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []model.WebhookDelivery
+	for _, subscription := range subscriptions {
+		if !subscribes(&subscription, enum.Event(event.Type)) {
+			continue
+		}
+		deliveries = append(deliveries, model.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        &event.ID,
+			Event:          event.Type,
+			Status:         string(enum.DeliveryPending),
+			NextAttemptAt:  &event.OccurredAt,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(dto.WebhookEventPayload{
+		ID:        event.ID,
+		Event:     event.Type,
+		CreatedAt: event.OccurredAt,
+		Data:      event.Data,
+	})
+	if err != nil {
+		return err
+	}
+	for i := range deliveries {
+		deliveries[i].Payload = string(body)
+	}
+
+	return s.WebhookRepository.SaveDeliveries(ctx, deliveries)
+}
+
+func subscribes(subscription *model.WebhookSubscription, event enum.Event) bool {
+	for _, subscribed := range strings.Split(subscription.Events, ",") {
+		if subscribed == string(event) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"student-service/internal/factory"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/repository"
+	pkgutil "student-service/pkg/util"
+	pkgwebhook "student-service/pkg/webhook"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// batchSize is how many deliveries are claimed at once.
+	batchSize = 20
+	// maxRetryDelay is the longest wait between two attempts of a delivery.
+	maxRetryDelay = 6 * time.Hour
+	// maxErrorLength is how much of the error of a failed attempt is kept.
+	maxErrorLength = 1024
+)
+
+var errInactiveSubscription = errors.New("webhook is deleted or inactive")
+
+// Worker sends the pending webhook deliveries, retrying failed ones with an exponential backoff
+// until they succeed or MaxAttempts is reached.
+type Worker struct {
+	WebhookRepository repository.Webhook
+	Sender            *pkgwebhook.Sender
+	MaxAttempts       int
+	RetryBase         time.Duration
+	PollInterval      time.Duration
+	now               func() time.Time
+}
+
+func NewWorker(f *factory.Factory) *Worker {
+	return &Worker{
+		WebhookRepository: f.WebhookRepository,
+		Sender:            pkgwebhook.NewSender(duration("WEBHOOK_TIMEOUT", 10*time.Second)),
+		MaxAttempts:       maxAttempts(),
+		RetryBase:         duration("WEBHOOK_RETRY_BASE", 30*time.Second),
+		PollInterval:      duration("WEBHOOK_POLL_INTERVAL", 5*time.Second),
+		now:               time.Now,
+	}
+}
+
+// Run sends the due deliveries every PollInterval until ctx is done.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.PollInterval)
+	defer ticker.Stop()
+	for {
+		w.DeliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue sends the deliveries that are due and records their outcome, it returns how many
+// were attempted.
+func (w *Worker) DeliverDue(ctx context.Context) int {
+	// a claimed delivery is retried once the lease ends if the outcome was not recorded, the
+	// deliveries of a batch are sent one after another within the lease
+	lease := batchSize*w.Sender.Client.Timeout + time.Minute
+	attempted := 0
+	for ctx.Err() == nil {
+		deliveries, err := w.WebhookRepository.ClaimDueDeliveries(ctx, w.now(), lease, batchSize)
+		if err != nil {
+			logrus.Warnf("claim webhook deliveries: %v", err)
+			return attempted
+		}
+		for i := range deliveries {
+			// once the lease ended another replica may have claimed the delivery
+			if !w.now().Before(*deliveries[i].NextAttemptAt) {
+				continue
+			}
+			w.deliver(ctx, &deliveries[i])
+			attempted++
+		}
+		if len(deliveries) < batchSize {
+			return attempted
+		}
+	}
+	return attempted
+}
+
+func (w *Worker) deliver(ctx context.Context, delivery *model.WebhookDelivery) {
+	subscription := &delivery.Subscription
+	leaseUntil := *delivery.NextAttemptAt
+	now := w.now()
+	delivery.LastAttemptAt = &now
+
+	var err error
+	if !subscription.Active || (subscription.DeletedAt != nil && subscription.DeletedAt.Valid) {
+		err = errInactiveSubscription
+	} else {
+		delivery.Attempts++
+		delivery.ResponseStatus, err = w.Sender.Send(ctx, pkgwebhook.Request{
+			URL:        subscription.URL,
+			Secret:     subscription.Secret,
+			Event:      delivery.Event,
+			DeliveryID: delivery.ID,
+			Body:       []byte(delivery.Payload),
+		})
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = string(enum.DeliverySucceeded)
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	case errors.Is(err, errInactiveSubscription) || delivery.Attempts >= w.MaxAttempts:
+		delivery.Status = string(enum.DeliveryFailed)
+		delivery.NextAttemptAt = nil
+		delivery.LastError = truncate(err.Error(), maxErrorLength)
+	default:
+		next := now.Add(pkgutil.Backoff(w.RetryBase, maxRetryDelay, delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		delivery.LastError = truncate(err.Error(), maxErrorLength)
+	}
+
+	// the outcome is recorded even when ctx is done, the attempt was made
+	if err := w.WebhookRepository.EditDelivery(context.Background(), delivery, leaseUntil); err != nil {
+		logrus.Warnf("record webhook delivery %d: %v", delivery.ID, err)
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
+
+// maxAttempts returns how many times a delivery is attempted, WEBHOOK_MAX_ATTEMPTS, 8 by default.
+func maxAttempts() int {
+	attempts, err := strconv.Atoi(pkgutil.Getenv("WEBHOOK_MAX_ATTEMPTS", "8"))
+	if err != nil || attempts <= 0 {
+		logrus.Warnf("invalid WEBHOOK_MAX_ATTEMPTS, using 8: %v", err)
+		return 8
+	}
+	return attempts
+}
+
+// duration returns the duration of the environment variable name, fallback when it is not set.
+func duration(name string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(pkgutil.Getenv(name, fallback.String()))
+	if err != nil || d <= 0 {
+		logrus.Warnf("invalid %s, using %s: %v", name, fallback, err)
+		return fallback
+	}
+	return d
+}
@@ -0,0 +1,245 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"student-service/database/seeder"
+	"student-service/internal/dto"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/repository"
+	"student-service/pkg/events"
+	pkgwebhook "student-service/pkg/webhook"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ctx = context.Background()
+
+func createSubscription(t *testing.T, url, secret string, events ...enum.Event) model.WebhookSubscription {
+	names := make([]string, 0, len(events))
+	for _, event := range events {
+		names = append(names, string(event))
+	}
+	subscription := model.WebhookSubscription{URL: url, Secret: secret, Events: strings.Join(names, ","), Active: true}
+	if err := f.WebhookRepository.Save(ctx, &subscription); err != nil {
+		t.Fatal(err)
+	}
+	return subscription
+}
+
+// publish hands an event about data to the subscriber, as the relay of the outbox does.
+func publish(t *testing.T, event enum.Event, data interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aggregateType, _, _ := strings.Cut(string(event), ".")
+	err = NewSubscriber(&f).Handle(ctx, events.Event{
+		ID:            fmt.Sprintf("evt_%d", time.Now().UnixNano()),
+		Type:          string(event),
+		AggregateType: aggregateType,
+		AggregateID:   "1",
+		OccurredAt:    time.Now(),
+		Data:          body,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// receiver records the webhook requests it receives, answering with the statuses in turn.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+	headers  []http.Header
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	body, _ := io.ReadAll(req.Body)
+	r.bodies = append(r.bodies, string(body))
+	r.headers = append(r.headers, req.Header.Clone())
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+// newTestWorker returns a worker whose clock is moved with advance. It starts at the next
+// whole second, after the deliveries published so far and without fractions the database rounds.
+func newTestWorker() (*Worker, func(time.Duration)) {
+	now := time.Now().Add(time.Second).Truncate(time.Second)
+	w := &Worker{
+		WebhookRepository: f.WebhookRepository,
+		Sender:            pkgwebhook.NewSender(time.Second),
+		MaxAttempts:       3,
+		RetryBase:         time.Minute,
+		PollInterval:      time.Second,
+		now:               func() time.Time { return now },
+	}
+	return w, func(d time.Duration) { now = now.Add(d) }
+}
+
+func findDelivery(t *testing.T, subscriptionID uint) model.WebhookDelivery {
+	deliveries, _, err := f.WebhookRepository.FindDeliveries(ctx, &dto.SearchWebhookDeliveryRequest{SubscriptionID: subscriptionID})
+	if err != nil || len(deliveries) == 0 {
+		t.Fatalf("find delivery: %v, %d deliveries", err, len(deliveries))
+	}
+	return deliveries[0]
+}
+
+func TestSubscriberHandlesAnEventOnce(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	createSubscription(t, "http://localhost/first", "0123456789abcdef", enum.MajorCreated)
+	createSubscription(t, "http://localhost/second", "0123456789abcdef", enum.MajorCreated)
+
+	// the relay publishes an event again when a broker after the subscriber fails
+	event := events.Event{ID: "evt_1", Type: string(enum.MajorCreated), AggregateType: "major", AggregateID: "1", OccurredAt: time.Now(), Data: []byte(`{}`)}
+	asserts := assert.New(t)
+	asserts.NoError(NewSubscriber(&f).Handle(ctx, event))
+	asserts.NoError(NewSubscriber(&f).Handle(ctx, event))
+	var count int64
+	db.Model(&model.WebhookDelivery{}).Count(&count)
+	asserts.Equal(int64(2), count)
+
+	event.ID = "evt_2"
+	asserts.NoError(NewSubscriber(&f).Handle(ctx, event))
+	db.Model(&model.WebhookDelivery{}).Count(&count)
+	asserts.Equal(int64(4), count)
+}
+
+func TestWorkerDeliverDue(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	recv := &receiver{}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	subscription := createSubscription(t, server.URL, "0123456789abcdef", enum.StudentDeleted)
+	publish(t, enum.StudentDeleted, dto.StudentResponse{ID: 3, Fullname: "Devon"})
+	publish(t, enum.ClassCreated, dto.ClassResponse{ID: 1})
+
+	asserts := assert.New(t)
+	w, _ := newTestWorker()
+	asserts.Equal(1, w.DeliverDue(ctx))
+	asserts.Equal(0, w.DeliverDue(ctx))
+
+	if asserts.Len(recv.bodies, 1) {
+		asserts.Contains(recv.bodies[0], `"event":"student.deleted"`)
+		asserts.Contains(recv.bodies[0], `"fullname":"Devon"`)
+		asserts.Equal("student.deleted", recv.headers[0].Get(pkgwebhook.HeaderEvent))
+		asserts.NoError(pkgwebhook.Verify("0123456789abcdef", recv.headers[0].Get(pkgwebhook.HeaderSignature), []byte(recv.bodies[0]), time.Minute, time.Now()))
+	}
+
+	delivery := findDelivery(t, subscription.ID)
+	asserts.Equal(string(enum.DeliverySucceeded), delivery.Status)
+	asserts.Equal(1, delivery.Attempts)
+	asserts.Equal(200, delivery.ResponseStatus)
+	asserts.NotNil(delivery.DeliveredAt)
+	asserts.Nil(delivery.NextAttemptAt)
+}
+
+func TestWorkerRetriesUntilMaxAttempts(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	recv := &receiver{statuses: []int{500, 503, 500}}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	subscription := createSubscription(t, server.URL, "0123456789abcdef", enum.MajorDeleted)
+	publish(t, enum.MajorDeleted, dto.MajorResponse{ID: 2})
+
+	asserts := assert.New(t)
+	w, advance := newTestWorker()
+	asserts.Equal(1, w.DeliverDue(ctx))
+	delivery := findDelivery(t, subscription.ID)
+	asserts.Equal(string(enum.DeliveryPending), delivery.Status)
+	asserts.Equal(1, delivery.Attempts)
+	asserts.Equal(500, delivery.ResponseStatus)
+	asserts.Contains(delivery.LastError, "response status 500")
+
+	// not due before the backoff
+	advance(59 * time.Second)
+	asserts.Equal(0, w.DeliverDue(ctx))
+	advance(time.Second)
+	asserts.Equal(1, w.DeliverDue(ctx))
+	asserts.Equal(2, findDelivery(t, subscription.ID).Attempts)
+
+	advance(2 * time.Minute)
+	asserts.Equal(1, w.DeliverDue(ctx))
+	delivery = findDelivery(t, subscription.ID)
+	asserts.Equal(string(enum.DeliveryFailed), delivery.Status)
+	asserts.Equal(3, delivery.Attempts)
+	asserts.Nil(delivery.NextAttemptAt)
+
+	advance(time.Hour)
+	asserts.Equal(0, w.DeliverDue(ctx))
+	asserts.Len(recv.bodies, 3)
+}
+
+func TestWorkerKeepsTheOutcomeOfTheLatestClaim(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	subscription := createSubscription(t, "http://localhost/hook", "0123456789abcdef", enum.MajorDeleted)
+	publish(t, enum.MajorDeleted, dto.MajorResponse{ID: 2})
+
+	asserts := assert.New(t)
+	now := time.Now().Add(time.Second)
+	first, err := f.WebhookRepository.ClaimDueDeliveries(ctx, now, time.Minute, batchSize)
+	if err != nil || len(first) != 1 {
+		t.Fatalf("claim: %v, %d deliveries", err, len(first))
+	}
+
+	// the first lease ended before its attempt was recorded, another worker claimed it again
+	second, err := f.WebhookRepository.ClaimDueDeliveries(ctx, now.Add(time.Minute), time.Minute, batchSize)
+	if err != nil || len(second) != 1 {
+		t.Fatalf("claim again: %v, %d deliveries", err, len(second))
+	}
+	second[0].Status = string(enum.DeliverySucceeded)
+	second[0].Attempts = 1
+	asserts.NoError(f.WebhookRepository.EditDelivery(ctx, &second[0], *second[0].NextAttemptAt))
+
+	first[0].LastError = "response status 500"
+	first[0].Attempts = 1
+	err = f.WebhookRepository.EditDelivery(ctx, &first[0], *first[0].NextAttemptAt)
+	asserts.ErrorIs(err, repository.ErrStaleVersion)
+
+	delivery := findDelivery(t, subscription.ID)
+	asserts.Equal(string(enum.DeliverySucceeded), delivery.Status)
+	asserts.Empty(delivery.LastError)
+}
+
+func TestWorkerFailsDeliveriesOfInactiveSubscriptions(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	recv := &receiver{}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	subscription := createSubscription(t, server.URL, "0123456789abcdef", enum.ClassUpdated)
+	publish(t, enum.ClassUpdated, dto.ClassResponse{ID: 1})
+	if _, err := f.WebhookRepository.Destroy(ctx, &subscription); err != nil {
+		t.Fatal(err)
+	}
+
+	asserts := assert.New(t)
+	w, _ := newTestWorker()
+	asserts.Equal(1, w.DeliverDue(ctx))
+	asserts.Empty(recv.bodies)
+
+	var delivery model.WebhookDelivery
+	if asserts.NoError(db.Where("subscription_id = ?", subscription.ID).First(&delivery).Error) {
+		asserts.Equal(string(enum.DeliveryFailed), delivery.Status)
+		asserts.Equal(0, delivery.Attempts)
+		asserts.Equal(errInactiveSubscription.Error(), delivery.LastError)
+	}
+}
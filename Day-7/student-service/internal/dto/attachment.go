@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+type (
+	// UploadAttachmentRequest uploads a file of a student, the authenticated student when
+	// StudentID is not set. Uploading a photo replaces the photo of the student.
+	UploadAttachmentRequest struct {
+		StudentID *uint                 `form:"student_id" validate:"omitempty,exists=students"`
+		Kind      string                `form:"kind" validate:"required,oneof=id_scan certificate photo"`
+		File      *multipart.FileHeader `form:"file" validate:"required"`
+	}
+	// SearchAttachmentRequest lists the attachments of a student, of every student when
+	// StudentID is not set.
+	SearchAttachmentRequest struct {
+		StudentID *uint  `query:"student_id" validate:"omitempty"`
+		Kind      string `query:"kind" validate:"omitempty,oneof=id_scan certificate photo"`
+	}
+	// AttachmentByIDRequest only finds attachments of OwnerStudentID when it is set.
+	AttachmentByIDRequest struct {
+		ID             uint   `param:"id" validate:"required"`
+		IfMatch        string `json:"-"`
+		OwnerStudentID *uint  `json:"-"`
+	}
+	// DownloadAttachmentRequest is a signed download URL of an attachment.
+	DownloadAttachmentRequest struct {
+		ID        uint   `param:"id" validate:"required"`
+		Expires   string `query:"expires" validate:"required"`
+		Signature string `query:"signature" validate:"required"`
+	}
+	// AttachmentResponse has signed URLs to download the file, and the thumbnail of a photo,
+	// until URLExpiresAt.
+	AttachmentResponse struct {
+		ID           uint      `json:"id"`
+		StudentID    uint      `json:"student_id"`
+		Kind         string    `json:"kind"`
+		Filename     string    `json:"filename"`
+		ContentType  string    `json:"content_type"`
+		Size         int64     `json:"size"`
+		URL          string    `json:"url"`
+		ThumbnailURL *string   `json:"thumbnail_url"`
+		URLExpiresAt time.Time `json:"url_expires_at"`
+		CreatedAt    time.Time `json:"created_at"`
+		Version      uint      `json:"version"`
+	}
+)
@@ -4,11 +4,15 @@ import "github.com/golang-jwt/jwt/v4"
 
 type (
 	RegisterStudentRequestBody struct {
-		Fullname string `json:"fullname" validate:"required"`
-		Email    string `json:"email" validate:"required,email"`
-		Password string `json:"password" validate:"required"`
-		ClassID  *uint  `json:"class_id"`
-		MajorID  *uint  `json:"major_id" validate:"required"`
+		Fullname  string  `json:"fullname" validate:"required"`
+		Email     string  `json:"email" validate:"required,email"`
+		Password  string  `json:"password" validate:"required"`
+		ClassID   *uint   `json:"class_id" validate:"omitempty,exists=classes"`
+		MajorID   *uint   `json:"major_id" validate:"required,exists=majors"`
+		Phone     *string `json:"phone" validate:"omitempty,e164"`
+		BirthDate *string `json:"birth_date" validate:"omitempty,date"`
+		Gender    *string `json:"gender" validate:"omitempty,oneof=male female"`
+		Address   *string `json:"address" validate:"omitempty,max=255"`
 	}
 
 	ByEmailAndPasswordRequest struct {
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"time"
+
+	pkgdto "student-service/pkg/dto"
+)
+
+type (
+	// SearchBookingRequest lists bookings ordered by start, only those overlapping the window
+	// From to To when they are set. Cancelled bookings are left out unless Cancelled is set.
+	SearchBookingRequest struct {
+		pkgdto.SearchGetRequest
+		RoomID    *uint      `query:"room_id" validate:"omitempty"`
+		StudentID *uint      `query:"student_id" validate:"omitempty"`
+		ClassID   *uint      `query:"class_id" validate:"omitempty"`
+		From      *time.Time `query:"from" validate:"omitempty"`
+		To        *time.Time `query:"to" validate:"omitempty"`
+		Cancelled bool       `query:"cancelled"`
+	}
+	RecurrenceRequest struct {
+		Frequency   *string `json:"frequency" validate:"required,oneof=daily weekly"`
+		Occurrences *uint   `json:"occurrences" validate:"required,min=2,max=52"`
+	}
+	// CreateBookingRequestBody books a room for a student or a class, the authenticated
+	// student when neither is set. A booking with a recurrence repeats every day or week.
+	CreateBookingRequestBody struct {
+		RoomID     *uint              `json:"room_id" validate:"required,exists=rooms"`
+		StudentID  *uint              `json:"student_id" validate:"omitempty,exists=students"`
+		ClassID    *uint              `json:"class_id" validate:"omitempty,exists=classes"`
+		StartsAt   *time.Time         `json:"starts_at" validate:"required"`
+		EndsAt     *time.Time         `json:"ends_at" validate:"required"`
+		Purpose    string             `json:"purpose" validate:"max=100"`
+		Recurrence *RecurrenceRequest `json:"recurrence" validate:"omitempty"`
+	}
+	// CancelBookingRequest cancels the booking, and its following occurrences when Series is
+	// set. Only bookings of OwnerStudentID or OwnerClassID can be cancelled when one is set.
+	CancelBookingRequest struct {
+		ID             uint   `param:"id" validate:"required"`
+		Series         bool   `json:"series"`
+		IfMatch        string `json:"-"`
+		OwnerStudentID *uint  `json:"-"`
+		OwnerClassID   *uint  `json:"-"`
+	}
+	BookingResponse struct {
+		ID          uint       `json:"id"`
+		RoomID      uint       `json:"room_id"`
+		StudentID   *uint      `json:"student_id"`
+		ClassID     *uint      `json:"class_id"`
+		StartsAt    time.Time  `json:"starts_at"`
+		EndsAt      time.Time  `json:"ends_at"`
+		Purpose     string     `json:"purpose"`
+		SeriesID    *uint      `json:"series_id"`
+		CancelledAt *time.Time `json:"cancelled_at"`
+		Version     uint       `json:"version"`
+	}
+)
@@ -11,12 +11,21 @@ type (
 		Name *string `json:"name" validate:"required"`
 	}
 	UpdateClassRequestBody struct {
-		ID   *uint   `param:"id" validate:"required"`
-		Name *string `json:"name" validate:"required"`
+		ID      *uint   `param:"id" validate:"required"`
+		Name    *string `json:"name" validate:"required"`
+		IfMatch string  `json:"-"`
+		// Version is the version a batch item expects, like If-Match.
+		Version *uint `json:"version"`
+	}
+	// ClassPatchDocument is the document a PATCH is applied to.
+	ClassPatchDocument struct {
+		ID   uint   `json:"-"`
+		Name string `json:"name" validate:"required"`
 	}
 	ClassResponse struct {
-		ID   uint   `json:"id"`
-		Name string `json:"name"`
+		ID      uint   `json:"id"`
+		Name    string `json:"name"`
+		Version uint   `json:"version"`
 	}
 	ClassWithCUDResponse struct {
 		ClassResponse
@@ -25,3 +34,8 @@ type (
 		DeletedAt *gorm.DeletedAt `json:"deleted_at"`
 	}
 )
+
+// Precondition returns the If-Match of the request and the version of the batch item.
+func (r *UpdateClassRequestBody) Precondition() (*string, *uint) {
+	return &r.IfMatch, r.Version
+}
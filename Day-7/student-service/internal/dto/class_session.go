@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"time"
+
+	pkgdto "student-service/pkg/dto"
+
+	"gorm.io/gorm"
+)
+
+type (
+	// SearchClassSessionRequest lists class sessions in the term, the active term when TermID
+	// is not set.
+	SearchClassSessionRequest struct {
+		pkgdto.SearchGetRequest
+		ClassID *uint `query:"class_id" validate:"omitempty,exists=classes"`
+		TermID  *uint `query:"term_id" validate:"omitempty,exists=terms"`
+	}
+	// CreateClassSessionRequestBody schedules a session of the class in the term, the active
+	// term when TermID is not set.
+	CreateClassSessionRequestBody struct {
+		ClassID *uint   `json:"class_id" validate:"required,exists=classes"`
+		TermID  *uint   `json:"term_id" validate:"omitempty,exists=terms"`
+		Date    *string `json:"date" validate:"required,date"`
+		Topic   string  `json:"topic" validate:"max=100"`
+	}
+	UpdateClassSessionRequestBody struct {
+		ID      *uint   `param:"id" validate:"required"`
+		Date    *string `json:"date" validate:"omitempty,date"`
+		Topic   *string `json:"topic" validate:"omitempty,max=100"`
+		IfMatch string  `json:"-"`
+	}
+	AttendanceRequest struct {
+		StudentID *uint   `json:"student_id" validate:"required"`
+		Status    *string `json:"status" validate:"required,oneof=present absent late excused"`
+		Note      string  `json:"note" validate:"max=255"`
+	}
+	// MarkAttendanceRequestBody marks the attendance of students in a session, students who
+	// are not in the records keep their attendance.
+	MarkAttendanceRequestBody struct {
+		ID      uint                `param:"id" validate:"required"`
+		Records []AttendanceRequest `json:"records" validate:"required,min=1,max=200,dive"`
+		IfMatch string              `json:"-"`
+	}
+	AttendanceResponse struct {
+		StudentID uint      `json:"student_id"`
+		Status    string    `json:"status"`
+		Note      string    `json:"note"`
+		MarkedAt  time.Time `json:"marked_at"`
+	}
+	ClassSessionResponse struct {
+		ID          uint                 `json:"id"`
+		ClassID     uint                 `json:"class_id"`
+		TermID      uint                 `json:"term_id"`
+		Date        string               `json:"date"`
+		Topic       string               `json:"topic"`
+		Attendances []AttendanceResponse `json:"attendances"`
+		Version     uint                 `json:"version"`
+	}
+	ClassSessionWithCUDResponse struct {
+		ClassSessionResponse
+		CreatedAt time.Time       `json:"created_at"`
+		UpdatedAt time.Time       `json:"updated_at"`
+		DeletedAt *gorm.DeletedAt `json:"deleted_at"`
+	}
+	// AttendanceSummaryResponse counts attendances by status. Rate is the percentage of
+	// sessions attended, where late counts as attended and excused sessions are left out. It
+	// is nil when no attendance counts.
+	AttendanceSummaryResponse struct {
+		Present uint     `json:"present"`
+		Late    uint     `json:"late"`
+		Absent  uint     `json:"absent"`
+		Excused uint     `json:"excused"`
+		Rate    *float64 `json:"rate"`
+	}
+	// StudentAttendanceResponse is the attendance of a student in the sessions of a term.
+	StudentAttendanceResponse struct {
+		Student StudentResponse `json:"student"`
+		TermID  uint            `json:"term_id"`
+		AttendanceSummaryResponse
+	}
+	// ClassAttendanceResponse is the attendance in the sessions of a class in a term, in total
+	// and for each student of the class.
+	ClassAttendanceResponse struct {
+		ClassID  uint `json:"class_id"`
+		TermID   uint `json:"term_id"`
+		Sessions uint `json:"sessions"`
+		AttendanceSummaryResponse
+		Students []StudentAttendanceResponse `json:"students"`
+	}
+)
@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"time"
+
+	pkgdto "student-service/pkg/dto"
+
+	"gorm.io/gorm"
+)
+
+type (
+	// SearchCourseRequest lists courses with their enrollments in the term, the active term
+	// when TermID is not set.
+	SearchCourseRequest struct {
+		pkgdto.SearchGetRequest
+		MajorID *uint `query:"major_id" validate:"omitempty"`
+		TermID  *uint `query:"term_id" validate:"omitempty,exists=terms"`
+	}
+	CreateCourseRequestBody struct {
+		Code     *string `json:"code" validate:"required,max=20,unique=courses.code"`
+		Name     *string `json:"name" validate:"required"`
+		MajorID  *uint   `json:"major_id" validate:"required,exists=majors"`
+		Credits  *uint   `json:"credits" validate:"required,min=1,max=24"`
+		Capacity *uint   `json:"capacity" validate:"required,min=1"`
+	}
+	UpdateCourseRequestBody struct {
+		ID       *uint   `param:"id" validate:"required"`
+		Code     *string `json:"code" validate:"omitempty,max=20,unique=courses.code"`
+		Name     *string `json:"name" validate:"omitempty"`
+		MajorID  *uint   `json:"major_id" validate:"omitempty,exists=majors"`
+		Credits  *uint   `json:"credits" validate:"omitempty,min=1,max=24"`
+		Capacity *uint   `json:"capacity" validate:"omitempty,min=1"`
+		IfMatch  string  `json:"-"`
+	}
+	// EnrollRequestBody enrolls a student in the course, the authenticated student when
+	// StudentID is not set, in the active term when TermID is not set.
+	EnrollRequestBody struct {
+		CourseID  uint  `param:"id" validate:"required"`
+		StudentID *uint `json:"student_id" validate:"omitempty,exists=students"`
+		TermID    *uint `json:"term_id" validate:"omitempty,exists=terms"`
+	}
+	// WithdrawRequest withdraws a student from the course in the term, the active term when
+	// TermID is not set.
+	WithdrawRequest struct {
+		CourseID  uint  `param:"id" validate:"required"`
+		StudentID uint  `param:"student_id" validate:"required"`
+		TermID    *uint `query:"term_id" validate:"omitempty,exists=terms"`
+	}
+	// CourseResponse is a course with the number of students enrolled in the term TermID. TermID
+	// is nil when no term was asked for and none is active.
+	CourseResponse struct {
+		ID       uint   `json:"id"`
+		Code     string `json:"code"`
+		Name     string `json:"name"`
+		MajorID  uint   `json:"major_id"`
+		Credits  uint   `json:"credits"`
+		Capacity uint   `json:"capacity"`
+		TermID   *uint  `json:"term_id"`
+		Enrolled uint   `json:"enrolled"`
+		Version  uint   `json:"version"`
+	}
+	CourseWithCUDResponse struct {
+		CourseResponse
+		CreatedAt time.Time       `json:"created_at"`
+		UpdatedAt time.Time       `json:"updated_at"`
+		DeletedAt *gorm.DeletedAt `json:"deleted_at"`
+	}
+	EnrollmentResponse struct {
+		CourseID   uint      `json:"course_id"`
+		StudentID  uint      `json:"student_id"`
+		TermID     uint      `json:"term_id"`
+		EnrolledAt time.Time `json:"enrolled_at"`
+	}
+	// EnrolledCourseResponse is a course in the list of courses of a student.
+	EnrolledCourseResponse struct {
+		CourseResponse
+		EnrolledAt time.Time `json:"enrolled_at"`
+	}
+	// EnrolledStudentResponse is a student in the roster of a course.
+	EnrolledStudentResponse struct {
+		StudentResponse
+		EnrolledAt time.Time `json:"enrolled_at"`
+	}
+)
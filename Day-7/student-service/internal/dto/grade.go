@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type (
+	AssessmentRequest struct {
+		Name   *string  `json:"name" validate:"required,max=50"`
+		Weight *float64 `json:"weight" validate:"required,gt=0,lte=100"`
+		Score  *float64 `json:"score" validate:"required,gte=0,lte=100"`
+	}
+	// CreateGradeRequestBody records a grade. The letter is derived from the assessments
+	// when it is not set.
+	CreateGradeRequestBody struct {
+		StudentID   *uint               `json:"student_id" validate:"required,exists=students"`
+		CourseID    *uint               `json:"course_id" validate:"required,exists=courses"`
+		TermID      *uint               `json:"term_id" validate:"required,exists=terms"`
+		Letter      string              `json:"letter" validate:"required_without=Assessments,max=5"`
+		Assessments []AssessmentRequest `json:"assessments" validate:"omitempty,max=20,dive"`
+	}
+	// UpdateGradeRequestBody replaces the assessments when they are set.
+	UpdateGradeRequestBody struct {
+		ID          *uint               `param:"id" validate:"required"`
+		Letter      *string             `json:"letter" validate:"omitempty,max=5"`
+		Assessments []AssessmentRequest `json:"assessments" validate:"omitempty,max=20,dive"`
+		IfMatch     string              `json:"-"`
+	}
+	AssessmentResponse struct {
+		Name   string  `json:"name"`
+		Weight float64 `json:"weight"`
+		Score  float64 `json:"score"`
+	}
+	GradeResponse struct {
+		ID          uint                 `json:"id"`
+		StudentID   uint                 `json:"student_id"`
+		CourseID    uint                 `json:"course_id"`
+		TermID      uint                 `json:"term_id"`
+		Score       *float64             `json:"score"`
+		Letter      string               `json:"letter"`
+		Assessments []AssessmentResponse `json:"assessments"`
+		Version     uint                 `json:"version"`
+	}
+	GradeWithCUDResponse struct {
+		GradeResponse
+		CreatedAt time.Time       `json:"created_at"`
+		UpdatedAt time.Time       `json:"updated_at"`
+		DeletedAt *gorm.DeletedAt `json:"deleted_at"`
+	}
+	TranscriptCourseResponse struct {
+		CourseID uint     `json:"course_id"`
+		Code     string   `json:"code"`
+		Name     string   `json:"name"`
+		Credits  uint     `json:"credits"`
+		Score    *float64 `json:"score"`
+		Letter   string   `json:"letter"`
+		Points   float64  `json:"points"`
+	}
+	TranscriptTermResponse struct {
+		TermID  uint                       `json:"term_id"`
+		Term    string                     `json:"term"`
+		Courses []TranscriptCourseResponse `json:"courses"`
+		Credits uint                       `json:"credits"`
+		GPA     float64                    `json:"gpa"`
+	}
+	// TranscriptResponse lists the grades of a student by term. The cumulative GPA counts the
+	// latest grade of every course.
+	TranscriptResponse struct {
+		Student StudentResponse          `json:"student"`
+		Scale   string                   `json:"scale"`
+		Terms   []TranscriptTermResponse `json:"terms"`
+		Credits uint                     `json:"credits"`
+		GPA     float64                  `json:"gpa"`
+	}
+)
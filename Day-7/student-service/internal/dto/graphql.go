@@ -0,0 +1,11 @@
+package dto
+
+type (
+	// GraphQLRequest is the body of POST /graphql. OperationName chooses the operation to
+	// execute when the query has several.
+	GraphQLRequest struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+)
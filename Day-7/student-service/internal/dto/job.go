@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	pkgdto "student-service/pkg/dto"
+)
+
+type (
+	// SearchJobRequest lists the jobs, newest first, optionally only those with a status or type.
+	SearchJobRequest struct {
+		pkgdto.Pagination
+		Status string `query:"status" validate:"omitempty,oneof=pending running succeeded dead"`
+		Type   string `query:"type" validate:"omitempty,max=64"`
+	}
+	JobResponse struct {
+		ID          uint            `json:"id"`
+		Type        string          `json:"type"`
+		Payload     json.RawMessage `json:"payload"`
+		Status      string          `json:"status"`
+		Attempts    int             `json:"attempts"`
+		MaxAttempts int             `json:"max_attempts"`
+		RunAt       time.Time       `json:"run_at"`
+		LockedUntil *time.Time      `json:"locked_until"`
+		LastError   string          `json:"last_error"`
+		FinishedAt  *time.Time      `json:"finished_at"`
+		CreatedAt   time.Time       `json:"created_at"`
+		UpdatedAt   time.Time       `json:"updated_at"`
+	}
+	// JobStatsResponse counts the jobs of every status.
+	JobStatsResponse struct {
+		Pending   int64 `json:"pending"`
+		Running   int64 `json:"running"`
+		Succeeded int64 `json:"succeeded"`
+		Dead      int64 `json:"dead"`
+	}
+	// WelcomeEmailJob is the payload of an email.welcome job, Language is the language of the
+	// registration request.
+	WelcomeEmailJob struct {
+		Fullname string `json:"fullname"`
+		Email    string `json:"email"`
+		Language string `json:"language"`
+	}
+)
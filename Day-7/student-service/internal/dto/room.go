@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	pkgdto "student-service/pkg/dto"
+
+	"gorm.io/gorm"
+)
+
+type (
+	// SearchRoomRequest lists rooms with at least Capacity seats and all the facilities.
+	SearchRoomRequest struct {
+		pkgdto.SearchGetRequest
+		Capacity   *uint    `query:"capacity" validate:"omitempty,min=1"`
+		Facilities []string `query:"facility" validate:"omitempty,max=10,dive,max=50"`
+	}
+	// AvailableRoomRequest searches the rooms that are not booked between StartsAt and EndsAt.
+	AvailableRoomRequest struct {
+		StartsAt   *time.Time `query:"starts_at" validate:"required"`
+		EndsAt     *time.Time `query:"ends_at" validate:"required"`
+		Capacity   *uint      `query:"capacity" validate:"omitempty,min=1"`
+		Facilities []string   `query:"facility" validate:"omitempty,max=10,dive,max=50"`
+	}
+	CreateRoomRequestBody struct {
+		Name       *string  `json:"name" validate:"required,max=50,unique=rooms.name"`
+		Capacity   *uint    `json:"capacity" validate:"required,min=1"`
+		Facilities []string `json:"facilities" validate:"omitempty,max=20,dive,required,max=50"`
+	}
+	// UpdateRoomRequestBody replaces the facilities when they are set.
+	UpdateRoomRequestBody struct {
+		ID         *uint    `param:"id" validate:"required"`
+		Name       *string  `json:"name" validate:"omitempty,max=50,unique=rooms.name"`
+		Capacity   *uint    `json:"capacity" validate:"omitempty,min=1"`
+		Facilities []string `json:"facilities" validate:"omitempty,max=20,dive,required,max=50"`
+		IfMatch    string   `json:"-"`
+	}
+	RoomResponse struct {
+		ID         uint     `json:"id"`
+		Name       string   `json:"name"`
+		Capacity   uint     `json:"capacity"`
+		Facilities []string `json:"facilities"`
+		Version    uint     `json:"version"`
+	}
+	RoomWithCUDResponse struct {
+		RoomResponse
+		CreatedAt time.Time       `json:"created_at"`
+		UpdatedAt time.Time       `json:"updated_at"`
+		DeletedAt *gorm.DeletedAt `json:"deleted_at"`
+	}
+)
@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+type (
+	// StatisticsResponse counts the records that are not deleted, as of ComputedAt.
+	StatisticsResponse struct {
+		Students         int64             `json:"students"`
+		Classes          int64             `json:"classes"`
+		Majors           int64             `json:"majors"`
+		Courses          int64             `json:"courses"`
+		StudentsPerMajor []StudentsPerItem `json:"students_per_major"`
+		StudentsPerClass []StudentsPerItem `json:"students_per_class"`
+		ComputedAt       time.Time         `json:"computed_at"`
+	}
+	// StudentsPerItem is how many students a major or a class has.
+	StudentsPerItem struct {
+		ID       uint   `json:"id"`
+		Name     string `json:"name"`
+		Students int64  `json:"students"`
+	}
+)
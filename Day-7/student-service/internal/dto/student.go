@@ -3,22 +3,58 @@ package dto
 import (
 	"time"
 
+	pkgdto "student-service/pkg/dto"
+
 	"gorm.io/gorm"
 )
 
 type (
+	// SearchStudentRequest lists students, only those enrolled in a course in the term when
+	// TermID is set. Search ranks the students by how well their name, email and student number
+	// match it, words may be prefixes or have a typo.
+	SearchStudentRequest struct {
+		pkgdto.SearchGetRequest
+		TermID     *uint  `query:"term_id" validate:"omitempty,exists=terms"`
+		MajorID    *uint  `query:"major_id" validate:"omitempty"`
+		IntakeYear *int   `query:"intake_year" validate:"omitempty"`
+		Gender     string `query:"gender" validate:"omitempty,oneof=male female"`
+	}
 	UpdateStudentRequestBody struct {
-		ID       *uint   `param:"id" validate:"required"`
-		Fullname *string `json:"fullname" validate:"omitempty"`
-		Email    *string `json:"email" validate:"omitempty,email"`
-		Password *string `json:"password" validate:"omitempty"`
-		ClassID  *uint   `json:"class_id" validate:"omitempty"`
-		MajorID  *uint   `json:"major_id" validate:"omitempty"`
+		ID        *uint   `param:"id" validate:"required"`
+		Fullname  *string `json:"fullname" validate:"omitempty"`
+		Email     *string `json:"email" validate:"omitempty,email,unique=students.email"`
+		Password  *string `json:"password" validate:"omitempty"`
+		ClassID   *uint   `json:"class_id" validate:"omitempty,exists=classes"`
+		MajorID   *uint   `json:"major_id" validate:"omitempty,exists=majors"`
+		Phone     *string `json:"phone" validate:"omitempty,e164"`
+		BirthDate *string `json:"birth_date" validate:"omitempty,date"`
+		Gender    *string `json:"gender" validate:"omitempty,oneof=male female"`
+		Address   *string `json:"address" validate:"omitempty,max=255"`
+		IfMatch   string  `json:"-"`
+	}
+	// StudentPatchDocument is the document a PATCH is applied to. The password is write only,
+	// it is only present when the patch sets it.
+	StudentPatchDocument struct {
+		ID       uint    `json:"-"`
+		Fullname string  `json:"fullname" validate:"required"`
+		Email    string  `json:"email" validate:"required,email,unique=students.email"`
+		Password *string `json:"password,omitempty" validate:"omitempty"`
+		ClassID  *uint   `json:"class_id" validate:"omitempty,exists=classes"`
+		MajorID  uint    `json:"major_id" validate:"required,exists=majors"`
+
+		Phone     string  `json:"phone" validate:"omitempty,e164"`
+		BirthDate *string `json:"birth_date" validate:"omitempty,date"`
+		Gender    string  `json:"gender" validate:"omitempty,oneof=male female"`
+		Address   string  `json:"address" validate:"max=255"`
 	}
 	StudentResponse struct {
-		ID       uint   `json:"id"`
-		Fullname string `json:"fullname"`
-		Email    string `json:"email"`
+		ID            uint    `json:"id"`
+		StudentNumber *string `json:"student_number"`
+		Fullname      string  `json:"fullname"`
+		Email         string  `json:"email"`
+		Version       uint    `json:"version"`
+		// Highlights has the fields matching a search, with the matching words in <em> tags.
+		Highlights map[string]string `json:"highlights,omitempty"`
 	}
 	StudentWithJWTResponse struct {
 		StudentResponse
@@ -32,7 +68,12 @@ type (
 	}
 	StudentDetailResponse struct {
 		StudentResponse
-		Class ClassResponse `json:"class"`
-		Major MajorResponse `json:"major"`
+		IntakeYear int            `json:"intake_year"`
+		Phone      string         `json:"phone"`
+		BirthDate  *string        `json:"birth_date"`
+		Gender     string         `json:"gender"`
+		Address    string         `json:"address"`
+		Class      *ClassResponse `json:"class"`
+		Major      MajorResponse  `json:"major"`
 	}
 )
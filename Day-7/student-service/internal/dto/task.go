@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"time"
+
+	pkgdto "student-service/pkg/dto"
+)
+
+type (
+	// SearchTaskRunRequest lists the runs of the scheduled tasks, newest first, optionally only
+	// those of a task or with a status.
+	SearchTaskRunRequest struct {
+		pkgdto.Pagination
+		Task   string `query:"task" validate:"omitempty,max=100"`
+		Status string `query:"status" validate:"omitempty,oneof=running succeeded failed skipped"`
+	}
+	TaskRunResponse struct {
+		ID          uint       `json:"id"`
+		Task        string     `json:"task"`
+		ScheduledAt time.Time  `json:"scheduled_at"`
+		Owner       string     `json:"owner"`
+		Status      string     `json:"status"`
+		Result      string     `json:"result"`
+		Error       string     `json:"error"`
+		StartedAt   time.Time  `json:"started_at"`
+		FinishedAt  *time.Time `json:"finished_at"`
+	}
+	// TaskResponse is a scheduled task with its cron expression, when it runs next and its last
+	// run, if any.
+	TaskResponse struct {
+		Name      string           `json:"name"`
+		Schedule  string           `json:"schedule"`
+		NextRunAt time.Time        `json:"next_run_at"`
+		LastRun   *TaskRunResponse `json:"last_run"`
+	}
+)
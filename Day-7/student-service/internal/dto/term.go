@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type (
+	// CreateTermRequestBody creates an open term. Dates are formatted as YYYY-MM-DD.
+	CreateTermRequestBody struct {
+		Name      *string `json:"name" validate:"required,max=50,unique=terms.name"`
+		StartDate *string `json:"start_date" validate:"required,date"`
+		EndDate   *string `json:"end_date" validate:"required,date"`
+	}
+	UpdateTermRequestBody struct {
+		ID        *uint   `param:"id" validate:"required"`
+		Name      *string `json:"name" validate:"omitempty,max=50,unique=terms.name"`
+		StartDate *string `json:"start_date" validate:"omitempty,date"`
+		EndDate   *string `json:"end_date" validate:"omitempty,date"`
+		IfMatch   string  `json:"-"`
+	}
+	// ByIDInTermRequest selects a record and a term. Every endpoint tells which term it uses
+	// when TermID is not set.
+	ByIDInTermRequest struct {
+		ID     uint  `param:"id" validate:"required"`
+		TermID *uint `query:"term_id" validate:"omitempty,exists=terms"`
+	}
+	TermResponse struct {
+		ID        uint   `json:"id"`
+		Name      string `json:"name"`
+		StartDate string `json:"start_date"`
+		EndDate   string `json:"end_date"`
+		Closed    bool   `json:"closed"`
+		Version   uint   `json:"version"`
+	}
+	TermWithCUDResponse struct {
+		TermResponse
+		CreatedAt time.Time       `json:"created_at"`
+		UpdatedAt time.Time       `json:"updated_at"`
+		DeletedAt *gorm.DeletedAt `json:"deleted_at"`
+	}
+)
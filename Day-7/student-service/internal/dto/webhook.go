@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	pkgdto "student-service/pkg/dto"
+)
+
+type (
+	// CreateWebhookRequestBody subscribes URL to events. A secret is generated when Secret is not
+	// set, it is only returned by the creation.
+	CreateWebhookRequestBody struct {
+		URL    *string  `json:"url" validate:"required,url,max=2048"`
+		Events []string `json:"events" validate:"required,min=1,dive,oneof=student.registered student.updated student.class_changed student.deleted class.created class.updated class.deleted major.created major.updated major.deleted"`
+		Secret *string  `json:"secret" validate:"omitempty,min=16,max=255"`
+		Active *bool    `json:"active"`
+	}
+	// UpdateWebhookRequestBody changes the fields that are set, setting Secret rotates it.
+	UpdateWebhookRequestBody struct {
+		ID      *uint    `param:"id" validate:"required"`
+		URL     *string  `json:"url" validate:"omitempty,url,max=2048"`
+		Events  []string `json:"events" validate:"omitempty,min=1,dive,oneof=student.registered student.updated student.class_changed student.deleted class.created class.updated class.deleted major.created major.updated major.deleted"`
+		Secret  *string  `json:"secret" validate:"omitempty,min=16,max=255"`
+		Active  *bool    `json:"active"`
+		IfMatch string   `json:"-"`
+	}
+	// SearchWebhookDeliveryRequest lists the deliveries of a subscription, newest first.
+	SearchWebhookDeliveryRequest struct {
+		pkgdto.Pagination
+		SubscriptionID uint   `param:"id" validate:"required"`
+		Status         string `query:"status" validate:"omitempty,oneof=pending succeeded failed"`
+	}
+	// RedeliverWebhookRequest sends the payload of a delivery of the subscription again.
+	RedeliverWebhookRequest struct {
+		SubscriptionID uint `param:"id" validate:"required"`
+		DeliveryID     uint `param:"delivery_id" validate:"required"`
+	}
+	WebhookResponse struct {
+		ID        uint      `json:"id"`
+		URL       string    `json:"url"`
+		Events    []string  `json:"events"`
+		Active    bool      `json:"active"`
+		CreatedAt time.Time `json:"created_at"`
+		Version   uint      `json:"version"`
+	}
+	WebhookWithSecretResponse struct {
+		WebhookResponse
+		Secret string `json:"secret"`
+	}
+	WebhookDeliveryResponse struct {
+		ID             uint            `json:"id"`
+		SubscriptionID uint            `json:"subscription_id"`
+		Event          string          `json:"event"`
+		Payload        json.RawMessage `json:"payload"`
+		Status         string          `json:"status"`
+		Attempts       int             `json:"attempts"`
+		NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+		LastAttemptAt  *time.Time      `json:"last_attempt_at"`
+		ResponseStatus int             `json:"response_status"`
+		LastError      string          `json:"last_error"`
+		DeliveredAt    *time.Time      `json:"delivered_at"`
+		RedeliveryOfID *uint           `json:"redelivery_of_id"`
+		CreatedAt      time.Time       `json:"created_at"`
+	}
+	// WebhookEventPayload is the body of a webhook request. ID is the same for every delivery of
+	// an event, so receivers can drop the ones they already handled.
+	WebhookEventPayload struct {
+		ID        string      `json:"id"`
+		Event     string      `json:"event"`
+		CreatedAt time.Time   `json:"created_at"`
+		Data      interface{} `json:"data"`
+	}
+	// StudentClassChangedData is the data of a student.class_changed event.
+	StudentClassChangedData struct {
+		StudentID  uint  `json:"student_id"`
+		OldClassID *uint `json:"old_class_id"`
+		NewClassID *uint `json:"new_class_id"`
+	}
+)
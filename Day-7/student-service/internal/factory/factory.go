@@ -3,12 +3,39 @@ package factory
 import (
 	"student-service/database"
 	"student-service/internal/repository"
+	"student-service/pkg/cache"
+	"student-service/pkg/events"
+	"student-service/pkg/mailer"
+	"student-service/pkg/storage"
 )
 
 type Factory struct {
 	StudentRepository repository.Student
 	MajorRepository   repository.Major
 	ClassRepository   repository.Class
+	CourseRepository  repository.Course
+	GradeRepository   repository.Grade
+	TermRepository    repository.Term
+
+	ClassSessionRepository repository.ClassSession
+	RoomRepository         repository.Room
+	BookingRepository      repository.Booking
+	AttachmentRepository   repository.Attachment
+	Mailer                 mailer.Mailer
+	Storage                storage.Storage
+	Cache                  cache.Cache
+	WebhookRepository      repository.Webhook
+
+	IdempotencyKeyRepository repository.IdempotencyKey
+	Transactor               repository.Transactor
+	OutboxRepository         repository.Outbox
+	LeaseRepository          repository.Lease
+	EventBus                 *events.Bus
+	EventBroker              events.Broker
+	JobRepository            repository.Job
+	TaskRunRepository        repository.TaskRun
+	MaintenanceRepository    repository.Maintenance
+	StatisticRepository      repository.Statistic
 }
 
 func NewFactory() *Factory {
@@ -17,5 +44,26 @@ func NewFactory() *Factory {
 		repository.NewStudentRepository(db),
 		repository.NewMajorRepository(db),
 		repository.NewClassRepository(db),
+		repository.NewCourseRepository(db),
+		repository.NewGradeRepository(db),
+		repository.NewTermRepository(db),
+		repository.NewClassSessionRepository(db),
+		repository.NewRoomRepository(db),
+		repository.NewBookingRepository(db),
+		repository.NewAttachmentRepository(db),
+		mailer.NewMailer(),
+		storage.NewStorage(),
+		cache.NewCache(),
+		repository.NewWebhookRepository(db),
+		repository.NewIdempotencyKeyRepository(db),
+		repository.NewTransactor(db),
+		repository.NewOutboxRepository(db),
+		repository.NewLeaseRepository(db),
+		events.NewBus(),
+		events.NewBroker(),
+		repository.NewJobRepository(db),
+		repository.NewTaskRunRepository(db),
+		repository.NewMaintenanceRepository(db),
+		repository.NewStatisticRepository(db),
 	}
 }
@@ -0,0 +1,34 @@
+package factory
+
+import (
+	"student-service/pkg/util"
+)
+
+// NewValidator returns the request validator with the `exists` and `unique` lookups
+// backed by the factory repositories.
+func (f *Factory) NewValidator() *util.CustomValidator {
+	v := util.NewCustomValidator()
+	if f.ClassRepository != nil {
+		v.RegisterExists("classes", f.ClassRepository.ExistByID)
+	}
+	if f.MajorRepository != nil {
+		v.RegisterExists("majors", f.MajorRepository.ExistByID)
+	}
+	if f.CourseRepository != nil {
+		v.RegisterExists("courses", f.CourseRepository.ExistByID)
+		v.RegisterUnique("courses.code", f.CourseRepository.ExistByCodeExceptID)
+	}
+	if f.TermRepository != nil {
+		v.RegisterExists("terms", f.TermRepository.ExistByID)
+		v.RegisterUnique("terms.name", f.TermRepository.ExistByNameExceptID)
+	}
+	if f.RoomRepository != nil {
+		v.RegisterExists("rooms", f.RoomRepository.ExistByID)
+		v.RegisterUnique("rooms.name", f.RoomRepository.ExistByNameExceptID)
+	}
+	if f.StudentRepository != nil {
+		v.RegisterExists("students", f.StudentRepository.ExistByID)
+		v.RegisterUnique("students.email", f.StudentRepository.ExistByEmailExceptID)
+	}
+	return v
+}
@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	"context"
+
+	"student-service/internal/app/auth"
+	"student-service/internal/dto"
+	"student-service/pkg/pb"
+	pkgutil "student-service/pkg/util"
+)
+
+type authServer struct {
+	pb.UnimplementedAuthServiceServer
+	service   auth.Service
+	validator *pkgutil.CustomValidator
+}
+
+func (s *authServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.AuthResponse, error) {
+	payload := &dto.ByEmailAndPasswordRequest{Email: req.GetEmail(), Password: req.GetPassword()}
+	if err := validate(ctx, s.validator, payload); err != nil {
+		return nil, err
+	}
+
+	result, err := s.service.LoginByEmailAndPassword(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AuthResponse{Student: studentMessage(&result.StudentResponse), Jwt: result.JWT}, nil
+}
+
+func (s *authServer) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.AuthResponse, error) {
+	payload := &dto.RegisterStudentRequestBody{
+		Fullname:  req.GetFullname(),
+		Email:     req.GetEmail(),
+		Password:  req.GetPassword(),
+		ClassID:   uintPointer(req.ClassId),
+		Phone:     req.Phone,
+		BirthDate: req.BirthDate,
+		Gender:    req.Gender,
+		Address:   req.Address,
+	}
+	if majorID := uint(req.GetMajorId()); majorID != 0 {
+		payload.MajorID = &majorID
+	}
+	if err := validate(ctx, s.validator, payload); err != nil {
+		return nil, err
+	}
+	payload.FillDefaults()
+
+	result, err := s.service.RegisterByEmailAndPassword(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AuthResponse{Student: studentMessage(&result.StudentResponse), Jwt: result.JWT}, nil
+}
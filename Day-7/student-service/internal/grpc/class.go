@@ -0,0 +1,102 @@
+package grpc
+
+import (
+	"context"
+
+	"student-service/internal/app/class"
+	"student-service/internal/dto"
+	pkgdto "student-service/pkg/dto"
+	"student-service/pkg/pb"
+	pkgutil "student-service/pkg/util"
+)
+
+type classServer struct {
+	pb.UnimplementedClassServiceServer
+	service   class.Service
+	validator *pkgutil.CustomValidator
+}
+
+func (s *classServer) ListClasses(ctx context.Context, req *pb.ListRequest) (*pb.ListClassesResponse, error) {
+	if err := requireClassA(ctx); err != nil {
+		return nil, err
+	}
+	payload := searchGetRequest(req)
+	if err := validate(ctx, s.validator, &payload); err != nil {
+		return nil, err
+	}
+
+	result, err := s.service.Find(ctx, &payload)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.ListClassesResponse{Pagination: paginationInfo(result.PaginationInfo)}
+	for i := range result.Data {
+		resp.Data = append(resp.Data, classMessage(&result.Data[i]))
+	}
+	return resp, nil
+}
+
+func (s *classServer) GetClass(ctx context.Context, req *pb.GetByIdRequest) (*pb.Class, error) {
+	if err := requireClassA(ctx); err != nil {
+		return nil, err
+	}
+	payload := &pkgdto.ByIDRequest{ID: uint(req.GetId())}
+	if err := validate(ctx, s.validator, payload); err != nil {
+		return nil, err
+	}
+
+	result, err := s.service.FindByID(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	return classMessage(result), nil
+}
+
+func (s *classServer) CreateClass(ctx context.Context, req *pb.CreateClassRequest) (*pb.Class, error) {
+	if err := requireClassA(ctx); err != nil {
+		return nil, err
+	}
+	payload := &dto.CreateClassRequestBody{Name: stringPointer(req.GetName())}
+	if err := validate(ctx, s.validator, payload); err != nil {
+		return nil, err
+	}
+
+	result, err := s.service.Store(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	return classMessage(result), nil
+}
+
+func (s *classServer) UpdateClass(ctx context.Context, req *pb.UpdateClassRequest) (*pb.Class, error) {
+	if err := requireClassA(ctx); err != nil {
+		return nil, err
+	}
+	id := uint(req.GetId())
+	payload := &dto.UpdateClassRequestBody{ID: &id, Name: stringPointer(req.GetName()), IfMatch: req.GetIfMatch()}
+	if err := validate(ctx, s.validator, payload); err != nil {
+		return nil, err
+	}
+
+	result, err := s.service.UpdateById(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	return classMessage(result), nil
+}
+
+func (s *classServer) DeleteClass(ctx context.Context, req *pb.DeleteByIdRequest) (*pb.DeleteClassResponse, error) {
+	if err := requireClassA(ctx); err != nil {
+		return nil, err
+	}
+	payload := &pkgdto.ByIDRequest{ID: uint(req.GetId()), IfMatch: req.GetIfMatch()}
+	if err := validate(ctx, s.validator, payload); err != nil {
+		return nil, err
+	}
+
+	result, err := s.service.DeleteById(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.DeleteClassResponse{Class: classMessage(&result.ClassResponse), DeletedAt: deletedAt(result.DeletedAt)}, nil
+}
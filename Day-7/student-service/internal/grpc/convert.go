@@ -0,0 +1,108 @@
+package grpc
+
+import (
+	"student-service/internal/dto"
+	pkgdto "student-service/pkg/dto"
+	"student-service/pkg/pb"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+func searchGetRequest(r *pb.ListRequest) pkgdto.SearchGetRequest {
+	payload := pkgdto.SearchGetRequest{
+		Search:   r.GetSearch(),
+		AscField: r.GetAscField(),
+		DscField: r.GetDscField(),
+	}
+	if r.GetPage() > 0 {
+		page := int(r.GetPage())
+		payload.Page = &page
+	}
+	if r.GetPageSize() > 0 {
+		pageSize := int(r.GetPageSize())
+		payload.PageSize = &pageSize
+	}
+	return payload
+}
+
+func paginationInfo(info pkgdto.PaginationInfo) *pb.PaginationInfo {
+	message := &pb.PaginationInfo{
+		Count:       int32(info.Count),
+		MoreRecords: info.MoreRecords,
+		TotalPage:   int32(info.TotalPage),
+	}
+	if info.Pagination != nil {
+		if info.Page != nil {
+			message.Page = int32(*info.Page)
+		}
+		if info.PageSize != nil {
+			message.PageSize = int32(*info.PageSize)
+		}
+	}
+	return message
+}
+
+func classMessage(class *dto.ClassResponse) *pb.Class {
+	return &pb.Class{Id: uint64(class.ID), Name: class.Name, Version: uint64(class.Version)}
+}
+
+func majorMessage(major *dto.MajorResponse) *pb.Major {
+	return &pb.Major{Id: uint64(major.ID), Name: major.Name, Version: uint64(major.Version)}
+}
+
+func studentMessage(student *dto.StudentResponse) *pb.Student {
+	return &pb.Student{
+		Id:            uint64(student.ID),
+		StudentNumber: stringValue(student.StudentNumber),
+		Fullname:      student.Fullname,
+		Email:         student.Email,
+		Version:       uint64(student.Version),
+		Highlights:    student.Highlights,
+	}
+}
+
+func studentDetailMessage(student *dto.StudentDetailResponse) *pb.Student {
+	message := studentMessage(&student.StudentResponse)
+	message.IntakeYear = int32(student.IntakeYear)
+	message.Phone = student.Phone
+	message.BirthDate = stringValue(student.BirthDate)
+	message.Gender = student.Gender
+	message.Address = student.Address
+	if student.Class != nil {
+		message.Class = classMessage(student.Class)
+	}
+	message.Major = majorMessage(&student.Major)
+	return message
+}
+
+func deletedAt(deletedAt *gorm.DeletedAt) *timestamppb.Timestamp {
+	if deletedAt == nil || !deletedAt.Valid {
+		return nil
+	}
+	return timestamppb.New(deletedAt.Time)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// stringPointer converts a required string of a request, proto3 sends a missing string as "".
+func stringPointer(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// uintPointer converts an optional id of a request.
+func uintPointer(id *uint64) *uint {
+	if id == nil {
+		return nil
+	}
+	value := uint(*id)
+	return &value
+}
@@ -0,0 +1,83 @@
+// Package grpc serves the student, class, major and auth services to other backend services over
+// gRPC. The methods call the same services as the REST handlers and apply the same permissions,
+// errors are reported with the gRPC status code of their kind.
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"student-service/internal/app/auth"
+	"student-service/internal/app/class"
+	"student-service/internal/app/major"
+	"student-service/internal/app/student"
+	"student-service/internal/factory"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/pkg/pb"
+	pkgutil "student-service/pkg/util"
+	res "student-service/pkg/util/response"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// shutdownTimeout is how long the calls in progress are waited for when the server stops.
+const shutdownTimeout = 30 * time.Second
+
+func NewServer(f *factory.Factory) *grpc.Server {
+	s := grpc.NewServer(grpc.ChainUnaryInterceptor(errorInterceptor, authInterceptor))
+	validator := f.NewValidator()
+
+	pb.RegisterAuthServiceServer(s, &authServer{service: auth.NewService(f), validator: validator})
+	pb.RegisterStudentServiceServer(s, &studentServer{service: student.NewService(f), validator: validator})
+	pb.RegisterClassServiceServer(s, &classServer{service: class.NewService(f), validator: validator})
+	pb.RegisterMajorServiceServer(s, &majorServer{service: major.NewService(f), validator: validator})
+	reflection.Register(s)
+	return s
+}
+
+// Serve serves the gRPC API on addr until ctx is done, then stops accepting calls and returns
+// once the calls in progress have finished.
+func Serve(ctx context.Context, addr string, f *factory.Factory) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return serve(ctx, NewServer(f), listener)
+}
+
+func serve(ctx context.Context, s *grpc.Server, listener net.Listener) error {
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		// calls still running after shutdownTimeout are cancelled
+		timer := time.AfterFunc(shutdownTimeout, s.Stop)
+		defer timer.Stop()
+		s.GracefulStop()
+	}()
+	// ctx may be done before the server starts serving
+	if err := s.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	<-stopped
+	return nil
+}
+
+// validate validates a payload like echo.Context.Validate in the REST handlers.
+func validate(ctx context.Context, validator *pkgutil.CustomValidator, payload interface{}) error {
+	if err := validator.ValidateCtx(ctx, payload); err != nil {
+		return res.ValidationErrorBuilder(err)
+	}
+	return nil
+}
+
+func requireClassA(ctx context.Context) error {
+	if util.JWTClaimsFromContext(ctx).ClassID != uint(enum.A) {
+		return res.ErrorBuilder(res.ErrorConstant.Unauthorized, errors.New("not in class A"))
+	}
+	return nil
+}
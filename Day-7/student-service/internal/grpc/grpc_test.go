@@ -0,0 +1,196 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"student-service/database"
+	"student-service/database/seeder"
+	"student-service/internal/factory"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	"student-service/pkg/pb"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var (
+	db = database.GetConnection()
+	f  = factory.Factory{
+		StudentRepository: repository.NewStudentRepository(db),
+		MajorRepository:   repository.NewMajorRepository(db),
+		ClassRepository:   repository.NewClassRepository(db),
+		CourseRepository:  repository.NewCourseRepository(db),
+		GradeRepository:   repository.NewGradeRepository(db),
+		TermRepository:    repository.NewTermRepository(db),
+		Transactor:        repository.NewTransactor(db),
+	}
+	testEmail    = "vincentlhubbard@edu.ac.id"
+	testPassword = "123abcABC!"
+)
+
+// dial serves the API on an in-memory listener and returns a connection to it.
+func dial(t *testing.T) *grpc.ClientConn {
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(&f)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func withToken(t *testing.T, classID uint) context.Context {
+	token, err := util.CreateJWTToken(util.CreateJWTClaims(testEmail, 1, classID, uint(enum.Finance)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestGRPCLoginAndGetStudent(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	conn := dial(t)
+	asserts := assert.New(t)
+
+	login, err := pb.NewAuthServiceClient(conn).Login(context.Background(), &pb.LoginRequest{Email: testEmail, Password: testPassword})
+	if !asserts.NoError(err) {
+		return
+	}
+	asserts.Equal(testEmail, login.Student.Email)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+login.Jwt)
+	student, err := pb.NewStudentServiceClient(conn).GetStudent(ctx, &pb.GetByIdRequest{Id: login.Student.Id})
+	if asserts.NoError(err) {
+		asserts.Equal("Vincent L. Hubbard", student.Fullname)
+		asserts.NotEmpty(student.Class.GetName())
+		asserts.NotEmpty(student.Major.GetName())
+	}
+
+	list, err := pb.NewStudentServiceClient(conn).ListStudents(ctx, &pb.ListStudentsRequest{List: &pb.ListRequest{PageSize: 2}})
+	if asserts.NoError(err) {
+		asserts.Len(list.Data, 2)
+		asserts.Equal(int32(1), list.Pagination.Page)
+		asserts.True(list.Pagination.MoreRecords)
+	}
+}
+
+func TestGRPCUnauthenticated(t *testing.T) {
+	conn := dial(t)
+
+	_, err := pb.NewStudentServiceClient(conn).ListStudents(context.Background(), &pb.ListStudentsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = pb.NewAuthServiceClient(conn).Login(context.Background(), &pb.LoginRequest{Email: testEmail, Password: "wrong"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCClassAOnly(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	conn := dial(t)
+	ctx := withToken(t, uint(enum.B))
+
+	_, err := pb.NewClassServiceClient(conn).ListClasses(ctx, &pb.ListRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	majors, err := pb.NewMajorServiceClient(conn).ListMajors(ctx, &pb.ListRequest{})
+	if assert.NoError(t, err) {
+		assert.NotEmpty(t, majors.Data)
+	}
+	_, err = pb.NewMajorServiceClient(conn).CreateMajor(ctx, &pb.CreateMajorRequest{Name: "Astronomy"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestGRPCErrors(t *testing.T) {
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+	conn := dial(t)
+	ctx := withToken(t, uint(enum.A))
+	client := pb.NewMajorServiceClient(conn)
+	asserts := assert.New(t)
+
+	_, err := client.GetMajor(ctx, &pb.GetByIdRequest{Id: 99999})
+	asserts.Equal(codes.NotFound, status.Code(err))
+
+	_, err = client.CreateMajor(ctx, &pb.CreateMajorRequest{})
+	s := status.Convert(err)
+	if asserts.Equal(codes.InvalidArgument, s.Code()) && asserts.Len(s.Details(), 1) {
+		violations := s.Details()[0].(*errdetails.BadRequest).FieldViolations
+		asserts.Equal("name", violations[0].Field)
+	}
+
+	major, err := client.CreateMajor(ctx, &pb.CreateMajorRequest{Name: "Astronomy"})
+	if asserts.NoError(err) {
+		_, err = client.UpdateMajor(ctx, &pb.UpdateMajorRequest{Id: major.Id, Name: "Cosmology", IfMatch: `"99"`})
+		asserts.Equal(codes.FailedPrecondition, status.Code(err))
+
+		ctx = metadata.AppendToOutgoingContext(ctx, "accept-language", "id")
+		_, err = client.CreateMajor(ctx, &pb.CreateMajorRequest{Name: "Astronomy"})
+		asserts.Equal(codes.AlreadyExists, status.Code(err))
+		asserts.Equal("Data yang dibuat sudah ada", status.Convert(err).Message())
+	}
+}
+
+func TestGRPCServeStopsWithTheContext(t *testing.T) {
+	listener := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- serve(ctx, NewServer(&f), listener) }()
+
+	asserts := assert.New(t)
+	cancel()
+	select {
+	case err := <-served:
+		asserts.NoError(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop")
+	}
+	_, err := listener.Dial()
+	asserts.Error(err)
+}
+
+func TestGRPCReflection(t *testing.T) {
+	conn := dial(t)
+
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&rpb.ServerReflectionRequest{MessageRequest: &rpb.ServerReflectionRequest_ListServices{}}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var services []string
+	for _, service := range resp.GetListServicesResponse().Service {
+		services = append(services, service.Name)
+	}
+	assert.Subset(t, services, []string{
+		"studentservice.v1.AuthService",
+		"studentservice.v1.ClassService",
+		"studentservice.v1.MajorService",
+		"studentservice.v1.StudentService",
+	})
+}
@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"student-service/internal/pkg/util"
+	"student-service/pkg/i18n"
+	res "student-service/pkg/util/response"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// publicServices can be called without a token.
+var publicServices = []string{"/studentservice.v1.AuthService/"}
+
+// errorInterceptor stores the language of the "accept-language" metadata in the context, and
+// converts the errors of the services to gRPC statuses in that language.
+func errorInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	lang := i18n.Match(firstMetadata(ctx, "accept-language"))
+	ctx = i18n.WithLanguage(ctx, lang)
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, res.GRPCStatus(err, lang).Err()
+	}
+	return resp, nil
+}
+
+// authInterceptor rejects the calls without a valid token in the "authorization" metadata, and
+// stores the claims of the token in the context.
+func authInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	for _, service := range publicServices {
+		if strings.HasPrefix(info.FullMethod, service) {
+			return handler(ctx, req)
+		}
+	}
+
+	claims, err := util.ParseJWTToken(firstMetadata(ctx, "authorization"))
+	if err != nil {
+		message := res.ErrorConstant.Unauthorized.LocalizedMessage(i18n.FromContext(ctx))
+		return nil, status.Error(codes.Unauthenticated, message)
+	}
+	return handler(util.WithJWTClaims(ctx, claims), req)
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+package grpc
+
+import (
+	"context"
+
+	"student-service/internal/app/major"
+	"student-service/internal/dto"
+	pkgdto "student-service/pkg/dto"
+	"student-service/pkg/pb"
+	pkgutil "student-service/pkg/util"
+)
+
+type majorServer struct {
+	pb.UnimplementedMajorServiceServer
+	service   major.Service
+	validator *pkgutil.CustomValidator
+}
+
+func (s *majorServer) ListMajors(ctx context.Context, req *pb.ListRequest) (*pb.ListMajorsResponse, error) {
+	payload := searchGetRequest(req)
+	if err := validate(ctx, s.validator, &payload); err != nil {
+		return nil, err
+	}
+
+	result, err := s.service.Find(ctx, &payload)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.ListMajorsResponse{Pagination: paginationInfo(result.PaginationInfo)}
+	for i := range result.Data {
+		resp.Data = append(resp.Data, majorMessage(&result.Data[i]))
+	}
+	return resp, nil
+}
+
+func (s *majorServer) GetMajor(ctx context.Context, req *pb.GetByIdRequest) (*pb.Major, error) {
+	payload := &pkgdto.ByIDRequest{ID: uint(req.GetId())}
+	if err := validate(ctx, s.validator, payload); err != nil {
+		return nil, err
+	}
+
+	result, err := s.service.FindByID(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	return majorMessage(result), nil
+}
+
+func (s *majorServer) CreateMajor(ctx context.Context, req *pb.CreateMajorRequest) (*pb.Major, error) {
+	if err := requireClassA(ctx); err != nil {
+		return nil, err
+	}
+	payload := &dto.CreateMajorRequestBody{Name: stringPointer(req.GetName())}
+	if err := validate(ctx, s.validator, payload); err != nil {
+		return nil, err
+	}
+
+	result, err := s.service.Store(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	return majorMessage(result), nil
+}
+
+func (s *majorServer) UpdateMajor(ctx context.Context, req *pb.UpdateMajorRequest) (*pb.Major, error) {
+	if err := requireClassA(ctx); err != nil {
+		return nil, err
+	}
+	id := uint(req.GetId())
+	payload := &dto.UpdateMajorRequestBody{ID: &id, Name: stringPointer(req.GetName()), IfMatch: req.GetIfMatch()}
+	if err := validate(ctx, s.validator, payload); err != nil {
+		return nil, err
+	}
+
+	result, err := s.service.UpdateById(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	return majorMessage(result), nil
+}
+
+func (s *majorServer) DeleteMajor(ctx context.Context, req *pb.DeleteByIdRequest) (*pb.DeleteMajorResponse, error) {
+	if err := requireClassA(ctx); err != nil {
+		return nil, err
+	}
+	payload := &pkgdto.ByIDRequest{ID: uint(req.GetId()), IfMatch: req.GetIfMatch()}
+	if err := validate(ctx, s.validator, payload); err != nil {
+		return nil, err
+	}
+
+	result, err := s.service.DeleteById(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.DeleteMajorResponse{Major: majorMessage(&result.MajorResponse), DeletedAt: deletedAt(result.DeletedAt)}, nil
+}
@@ -0,0 +1,109 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"student-service/internal/app/student"
+	"student-service/internal/dto"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/pkg/util"
+	pkgdto "student-service/pkg/dto"
+	"student-service/pkg/pb"
+	pkgutil "student-service/pkg/util"
+	res "student-service/pkg/util/response"
+)
+
+type studentServer struct {
+	pb.UnimplementedStudentServiceServer
+	service   student.Service
+	validator *pkgutil.CustomValidator
+}
+
+func (s *studentServer) ListStudents(ctx context.Context, req *pb.ListStudentsRequest) (*pb.ListStudentsResponse, error) {
+	payload := &dto.SearchStudentRequest{
+		SearchGetRequest: searchGetRequest(req.GetList()),
+		TermID:           uintPointer(req.TermId),
+		MajorID:          uintPointer(req.MajorId),
+		Gender:           req.GetGender(),
+	}
+	if req.IntakeYear != nil {
+		intakeYear := int(req.GetIntakeYear())
+		payload.IntakeYear = &intakeYear
+	}
+	if err := validate(ctx, s.validator, payload); err != nil {
+		return nil, err
+	}
+
+	result, err := s.service.Find(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.ListStudentsResponse{Pagination: paginationInfo(result.PaginationInfo)}
+	for i := range result.Data {
+		resp.Data = append(resp.Data, studentMessage(&result.Data[i]))
+	}
+	return resp, nil
+}
+
+func (s *studentServer) GetStudent(ctx context.Context, req *pb.GetByIdRequest) (*pb.Student, error) {
+	payload := &pkgdto.ByIDRequest{ID: uint(req.GetId())}
+	if err := validate(ctx, s.validator, payload); err != nil {
+		return nil, err
+	}
+
+	result, err := s.service.FindByID(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	return studentDetailMessage(result), nil
+}
+
+// UpdateStudent updates the calling student, only class A can update other students.
+func (s *studentServer) UpdateStudent(ctx context.Context, req *pb.UpdateStudentRequest) (*pb.Student, error) {
+	id := uint(req.GetId())
+	payload := &dto.UpdateStudentRequestBody{
+		ID:        &id,
+		Fullname:  req.Fullname,
+		Email:     req.Email,
+		Password:  req.Password,
+		ClassID:   uintPointer(req.ClassId),
+		MajorID:   uintPointer(req.MajorId),
+		Phone:     req.Phone,
+		BirthDate: req.BirthDate,
+		Gender:    req.Gender,
+		Address:   req.Address,
+		IfMatch:   req.GetIfMatch(),
+	}
+	if err := validate(ctx, s.validator, payload); err != nil {
+		return nil, err
+	}
+	claims := util.JWTClaimsFromContext(ctx)
+	if claims.BID != id && claims.ClassID != uint(enum.A) {
+		return nil, res.ErrorBuilder(res.ErrorConstant.Unauthorized, errors.New("updating someone else"))
+	}
+
+	result, err := s.service.UpdateById(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	return studentDetailMessage(result), nil
+}
+
+// DeleteStudent deletes the calling student if they are in class A, like DELETE /students/:id.
+func (s *studentServer) DeleteStudent(ctx context.Context, req *pb.DeleteByIdRequest) (*pb.DeleteStudentResponse, error) {
+	payload := &pkgdto.ByIDRequest{ID: uint(req.GetId()), IfMatch: req.GetIfMatch()}
+	if err := validate(ctx, s.validator, payload); err != nil {
+		return nil, err
+	}
+	claims := util.JWTClaimsFromContext(ctx)
+	if claims.BID != payload.ID || claims.ClassID != uint(enum.A) {
+		return nil, res.ErrorBuilder(res.ErrorConstant.Unauthorized, errors.New("deleting a student"))
+	}
+
+	result, err := s.service.DeleteById(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.DeleteStudentResponse{Student: studentMessage(&result.StudentResponse), DeletedAt: deletedAt(result.DeletedAt)}, nil
+}
@@ -1,19 +1,31 @@
 package http
 
 import (
+	"student-service/internal/app/attachment"
 	"student-service/internal/app/auth"
+	"student-service/internal/app/booking"
 	"student-service/internal/app/class"
+	"student-service/internal/app/course"
+	"student-service/internal/app/grade"
+	"student-service/internal/app/graphql"
+	"student-service/internal/app/job"
 	"student-service/internal/app/major"
+	"student-service/internal/app/room"
+	"student-service/internal/app/session"
+	"student-service/internal/app/statistics"
 	"student-service/internal/app/student"
+	"student-service/internal/app/task"
+	"student-service/internal/app/term"
+	"student-service/internal/app/webhook"
 	"student-service/internal/factory"
-	"student-service/pkg/util"
+	res "student-service/pkg/util/response"
 
-	"github.com/go-playground/validator"
 	"github.com/labstack/echo/v4"
 )
 
 func NewHttp(e *echo.Echo, f *factory.Factory) {
-	e.Validator = &util.CustomValidator{Validator: validator.New()}
+	e.Validator = f.NewValidator()
+	e.HTTPErrorHandler = res.HTTPErrorHandler
 
 	e.GET("/status", func(c echo.Context) error {
 		return c.JSON(200, map[string]string{"status": "OK"})
@@ -23,4 +35,16 @@ func NewHttp(e *echo.Echo, f *factory.Factory) {
 	auth.NewHandler(f).Route(v1.Group("/auth"))
 	major.NewHandler(f).Route(v1.Group("/majors"))
 	class.NewHandler(f).Route(v1.Group("/classes"))
+	course.NewHandler(f).Route(v1.Group("/courses"))
+	grade.NewHandler(f).Route(v1.Group("/grades"))
+	term.NewHandler(f).Route(v1.Group("/terms"))
+	session.NewHandler(f).Route(v1.Group("/sessions"))
+	room.NewHandler(f).Route(v1.Group("/rooms"))
+	booking.NewHandler(f).Route(v1.Group("/bookings"))
+	attachment.NewHandler(f).Route(v1.Group("/attachments"))
+	graphql.NewHandler(f).Route(v1.Group("/graphql"))
+	webhook.NewHandler(f).Route(v1.Group("/webhooks"))
+	job.NewHandler(f).Route(v1.Group("/jobs"))
+	task.NewHandler(f).Route(v1.Group("/tasks"))
+	statistics.NewHandler(f).Route(v1.Group("/statistics"))
 }
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	pkgutil "student-service/pkg/util"
+	res "student-service/pkg/util/response"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const HeaderCacheControl = "Cache-Control"
+
+// HTTPCacheMiddleware makes successful GET responses cacheable by the client. They get an
+// ETag, the one set by the handler or a hash of the body, and a private Cache-Control header
+// that allows reusing them for maxAge without asking again, and asks to revalidate them when
+// maxAge is 0. A request whose If-None-Match has the ETag is answered with 304 Not Modified.
+func HTTPCacheMiddleware(maxAge time.Duration) echo.MiddlewareFunc {
+	cacheControl := "private, no-cache"
+	if maxAge > 0 {
+		cacheControl = fmt.Sprintf("private, max-age=%d", int(maxAge.Seconds()))
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Method != http.MethodGet {
+				return next(c)
+			}
+
+			response := c.Response()
+			writer := response.Writer
+			buffer := &bufferedWriter{ResponseWriter: writer, status: http.StatusOK}
+			response.Writer = buffer
+			err := next(c)
+			response.Writer = writer
+			if err != nil {
+				return err
+			}
+
+			header := response.Header()
+			if buffer.status != http.StatusOK {
+				writer.WriteHeader(buffer.status)
+				_, err := writer.Write(buffer.body.Bytes())
+				return err
+			}
+
+			etag := header.Get(res.HeaderETag)
+			if etag == "" {
+				sum := sha256.Sum256(buffer.body.Bytes())
+				etag = `W/"` + hex.EncodeToString(sum[:16]) + `"`
+				header.Set(res.HeaderETag, etag)
+			}
+			if header.Get(HeaderCacheControl) == "" {
+				header.Set(HeaderCacheControl, cacheControl)
+			}
+			header.Add(echo.HeaderVary, echo.HeaderAuthorization)
+			header.Add(echo.HeaderVary, "Accept-Language")
+
+			if res.IfNoneMatch(c.Request().Header.Get(res.HeaderIfNoneMatch), etag) {
+				header.Del(echo.HeaderContentType)
+				header.Del(echo.HeaderContentLength)
+				response.Status = http.StatusNotModified
+				writer.WriteHeader(http.StatusNotModified)
+				return nil
+			}
+			writer.WriteHeader(http.StatusOK)
+			_, err = writer.Write(buffer.body.Bytes())
+			return err
+		}
+	}
+}
+
+// ReferenceDataMaxAge returns how long clients may reuse reference data such as classes and
+// majors without revalidating it, HTTP_CACHE_MAX_AGE, a minute by default.
+func ReferenceDataMaxAge() time.Duration {
+	maxAge, err := time.ParseDuration(pkgutil.Getenv("HTTP_CACHE_MAX_AGE", "1m"))
+	if err != nil || maxAge < 0 {
+		logrus.Warnf("invalid HTTP_CACHE_MAX_AGE, using 1m: %v", err)
+		return time.Minute
+	}
+	return maxAge
+}
+
+// bufferedWriter holds the response back, so that it can be replaced by a 304.
+type bufferedWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	res "student-service/pkg/util/response"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// newCachedEcho returns a server with GET /classes behind the HTTP cache middleware. The
+// handler answers with status and body, and sets etag when it is not empty.
+func newCachedEcho(maxAge time.Duration, status int, body, etag string) *echo.Echo {
+	e := echo.New()
+	e.GET("/classes", func(c echo.Context) error {
+		if etag != "" {
+			c.Response().Header().Set(res.HeaderETag, etag)
+		}
+		return c.JSON(status, map[string]string{"name": body})
+	}, HTTPCacheMiddleware(maxAge))
+	return e
+}
+
+func cachedRequest(e *echo.Echo, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/classes", nil)
+	if ifNoneMatch != "" {
+		req.Header.Set(res.HeaderIfNoneMatch, ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHTTPCacheMiddlewareNotModified(t *testing.T) {
+	e := newCachedEcho(time.Minute, http.StatusOK, "A", "")
+	asserts := assert.New(t)
+
+	first := cachedRequest(e, "")
+	etag := first.Header().Get(res.HeaderETag)
+	asserts.Equal(http.StatusOK, first.Code)
+	asserts.Contains(first.Body.String(), `"name":"A"`)
+	asserts.NotEmpty(etag)
+	asserts.Equal("private, max-age=60", first.Header().Get(HeaderCacheControl))
+	asserts.Equal([]string{echo.HeaderAuthorization, "Accept-Language"}, first.Header().Values(echo.HeaderVary))
+
+	second := cachedRequest(e, etag)
+	asserts.Equal(http.StatusNotModified, second.Code)
+	asserts.Empty(second.Body.String())
+	asserts.Equal(etag, second.Header().Get(res.HeaderETag))
+
+	changed := cachedRequest(newCachedEcho(time.Minute, http.StatusOK, "B", ""), etag)
+	asserts.Equal(http.StatusOK, changed.Code)
+	asserts.NotEqual(etag, changed.Header().Get(res.HeaderETag))
+}
+
+func TestHTTPCacheMiddlewareHandlerETag(t *testing.T) {
+	e := newCachedEcho(0, http.StatusOK, "A", `"3"`)
+	asserts := assert.New(t)
+
+	rec := cachedRequest(e, `W/"2", W/"3"`)
+	asserts.Equal(http.StatusNotModified, rec.Code)
+	asserts.Equal(`"3"`, rec.Header().Get(res.HeaderETag))
+	asserts.Equal("private, no-cache", rec.Header().Get(HeaderCacheControl))
+}
+
+func TestHTTPCacheMiddlewareError(t *testing.T) {
+	e := newCachedEcho(time.Minute, http.StatusNotFound, "missing", "")
+	asserts := assert.New(t)
+
+	rec := cachedRequest(e, "*")
+	asserts.Equal(http.StatusNotFound, rec.Code)
+	asserts.Contains(rec.Body.String(), "missing")
+	asserts.Empty(rec.Header().Get(res.HeaderETag))
+	asserts.Empty(rec.Header().Get(HeaderCacheControl))
+}
@@ -0,0 +1,272 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"student-service/internal/model"
+	"student-service/internal/pkg/util"
+	"student-service/internal/repository"
+	pkgutil "student-service/pkg/util"
+	res "student-service/pkg/util/response"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// replayedHeaders are the response headers stored with the response and sent again on replay.
+var replayedHeaders = []string{
+	echo.HeaderContentType,
+	echo.HeaderLocation,
+	res.HeaderContentLanguage,
+	res.HeaderETag,
+}
+
+// IdempotencyMiddleware makes a route safe to retry with an Idempotency-Key header. The first
+// response per key, user and route is stored for IDEMPOTENCY_KEY_TTL and sent again for
+// retries with the same body. Reusing a key with another body is rejected, and so is a retry
+// sent while the first request is still being processed. Server errors are not stored, so the
+// request can be retried with the same key. The body is read in memory to be hashed, so bodies
+// larger than IDEMPOTENCY_MAX_BODY_SIZE are rejected.
+//
+// The key is renewed while the request is processed, a key not renewed for
+// IDEMPOTENCY_LOCK_TIMEOUT belongs to a request that never completed, e.g. because the server
+// stopped, and is taken over by a retry.
+func IdempotencyMiddleware(keys repository.IdempotencyKey) echo.MiddlewareFunc {
+	ttl := idempotencyKeyTTL()
+	maxBodySize := idempotencyMaxBodySize()
+	lockTimeout := idempotencyLockTimeout()
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(HeaderIdempotencyKey)
+			if key == "" {
+				return next(c)
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				return res.ErrorBuilder(res.ErrorConstant.BadRequest, errors.New("idempotency key is too long")).Send(c)
+			}
+
+			if c.Request().ContentLength > maxBodySize {
+				return requestTooLargeError(maxBodySize).Send(c)
+			}
+			body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxBodySize+1))
+			if err != nil {
+				// the body may already be limited by a route, e.g. with http.MaxBytesReader
+				if strings.Contains(err.Error(), "request body too large") {
+					return requestTooLargeError(maxBodySize).Send(c)
+				}
+				return res.ErrorBuilder(res.ErrorConstant.BadRequest, err).Send(c)
+			}
+			if int64(len(body)) > maxBodySize {
+				return requestTooLargeError(maxBodySize).Send(c)
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+			hash := sha256.Sum256(body)
+
+			record := &model.IdempotencyKey{
+				Key:         key,
+				UserID:      idempotencyUserID(c),
+				Route:       c.Request().Method + " " + c.Path(),
+				RequestHash: hex.EncodeToString(hash[:]),
+				ExpiresAt:   time.Now().Add(ttl),
+			}
+			existing, err := reserveIdempotencyKey(c.Request().Context(), keys, record, lockTimeout)
+			if err != nil {
+				return util.RepositoryErrorBuilder(err).Send(c)
+			}
+			if existing != nil {
+				switch {
+				case existing.RequestHash != record.RequestHash:
+					return res.ErrorBuilder(res.ErrorConstant.IdempotencyKeyReused, nil).Send(c)
+				case existing.Status == 0:
+					return res.ErrorBuilder(res.ErrorConstant.IdempotencyKeyInProgress, nil).Send(c)
+				}
+				return replayResponse(c, existing)
+			}
+
+			// the key is released or completed even when the client went away
+			ctx := context.Background()
+
+			recorder := &bodyRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+			stopRenewing := renewIdempotencyKey(keys, record, lockTimeout)
+			err = next(c)
+			stopRenewing()
+			c.Response().Writer = recorder.ResponseWriter
+
+			if err != nil || !c.Response().Committed || c.Response().Status >= http.StatusInternalServerError {
+				if err := keys.Delete(ctx, record); err != nil {
+					logrus.Errorf("cannot release idempotency key %q: %v", key, err)
+				}
+				return err
+			}
+
+			header := map[string]string{}
+			for _, name := range replayedHeaders {
+				if value := c.Response().Header().Get(name); value != "" {
+					header[name] = value
+				}
+			}
+			encoded, _ := json.Marshal(header)
+
+			record.Status = c.Response().Status
+			record.Header = string(encoded)
+			record.Body = recorder.body.Bytes()
+			if err := keys.Complete(ctx, record); err != nil {
+				logrus.Errorf("cannot store response of idempotency key %q: %v", key, err)
+			}
+			return nil
+		}
+	}
+}
+
+// reserveIdempotencyKey inserts record. When the key is already used it returns the stored
+// record instead, after taking it over once if it is expired or abandoned: not renewed for
+// lockTimeout.
+func reserveIdempotencyKey(ctx context.Context, keys repository.IdempotencyKey, record *model.IdempotencyKey, lockTimeout time.Duration) (*model.IdempotencyKey, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		err := keys.Create(ctx, record)
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, repository.ErrDuplicate) {
+			return nil, err
+		}
+
+		existing, err := keys.Find(ctx, record.Key, record.UserID, record.Route)
+		if errors.Is(err, repository.ErrNotFound) {
+			// released by a failed request in the meantime
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		abandoned := existing.Status == 0 && existing.UpdatedAt.Add(lockTimeout).Before(now)
+		if attempt == 0 && (existing.ExpiresAt.Before(now) || abandoned) {
+			if err := keys.Delete(ctx, &existing); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		return &existing, nil
+	}
+	// other requests keep taking the key, report it as in progress
+	return &model.IdempotencyKey{RequestHash: record.RequestHash}, nil
+}
+
+// renewIdempotencyKey renews record three times per lockTimeout until stop is called, so that
+// a request running longer than lockTimeout keeps its key.
+func renewIdempotencyKey(keys repository.IdempotencyKey, record *model.IdempotencyKey, lockTimeout time.Duration) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(lockTimeout / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := keys.Renew(context.Background(), record.ID); err != nil {
+					logrus.Warnf("cannot renew idempotency key %q: %v", record.Key, err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func replayResponse(c echo.Context, record *model.IdempotencyKey) error {
+	header := map[string]string{}
+	if record.Header != "" {
+		if err := json.Unmarshal([]byte(record.Header), &header); err != nil {
+			return res.ErrorBuilder(res.ErrorConstant.InternalServerError, err).Send(c)
+		}
+	}
+	for name, value := range header {
+		c.Response().Header().Set(name, value)
+	}
+	c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+	c.Response().WriteHeader(record.Status)
+	_, err := c.Response().Write(record.Body)
+	return err
+}
+
+// idempotencyUserID returns the id of the authenticated student, or 0 for anonymous requests
+// (e.g. registration).
+func idempotencyUserID(c echo.Context) uint {
+	claims, err := util.ParseJWTToken(c.Request().Header.Get(echo.HeaderAuthorization))
+	if err != nil {
+		return 0
+	}
+	return claims.BID
+}
+
+func idempotencyKeyTTL() time.Duration {
+	ttl, err := time.ParseDuration(pkgutil.Getenv("IDEMPOTENCY_KEY_TTL", "24h"))
+	if err != nil || ttl <= 0 {
+		logrus.Warnf("invalid IDEMPOTENCY_KEY_TTL, using 24h: %v", err)
+		return 24 * time.Hour
+	}
+	return ttl
+}
+
+// idempotencyLockTimeout returns how long a key stays reserved without being renewed,
+// IDEMPOTENCY_LOCK_TIMEOUT, 1m by default.
+func idempotencyLockTimeout() time.Duration {
+	timeout, err := time.ParseDuration(pkgutil.Getenv("IDEMPOTENCY_LOCK_TIMEOUT", "1m"))
+	if err != nil || timeout <= 0 {
+		logrus.Warnf("invalid IDEMPOTENCY_LOCK_TIMEOUT, using 1m: %v", err)
+		return time.Minute
+	}
+	return timeout
+}
+
+// idempotencyMaxBodySize returns the largest request body in bytes read by the middleware,
+// IDEMPOTENCY_MAX_BODY_SIZE, 10 MiB by default. It must not be lower than ATTACHMENT_MAX_SIZE.
+func idempotencyMaxBodySize() int64 {
+	size, err := strconv.ParseInt(pkgutil.Getenv("IDEMPOTENCY_MAX_BODY_SIZE", "10485760"), 10, 64)
+	if err != nil || size <= 0 {
+		logrus.Warnf("invalid IDEMPOTENCY_MAX_BODY_SIZE, using 10485760: %v", err)
+		return 10 << 20
+	}
+	return size
+}
+
+func requestTooLargeError(maxSize int64) *res.Error {
+	return res.ErrorBuilder(res.ErrorConstant.RequestTooLarge, fmt.Errorf("request body is larger than %d bytes", maxSize))
+}
+
+// bodyRecorder keeps a copy of the response body.
+type bodyRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *bodyRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
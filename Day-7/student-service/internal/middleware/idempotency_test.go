@@ -0,0 +1,187 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"student-service/database"
+	"student-service/internal/repository"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// newIdempotentEcho returns a server with POST /classes behind the idempotency middleware.
+// The handler answers with status and counts its calls.
+func newIdempotentEcho(t *testing.T, calls *int32, status func(call int32) int, delay time.Duration) *echo.Echo {
+	db := database.GetConnection()
+	if err := db.Exec("DELETE FROM idempotency_keys").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	e := echo.New()
+	e.POST("/classes", func(c echo.Context) error {
+		call := atomic.AddInt32(calls, 1)
+		time.Sleep(delay)
+		return c.JSON(status(call), map[string]int32{"call": call})
+	}, IdempotencyMiddleware(repository.NewIdempotencyKeyRepository(db)))
+	return e
+}
+
+func idempotentRequest(e *echo.Echo, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/classes", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func created(int32) int {
+	return http.StatusCreated
+}
+
+func TestIdempotencyMiddlewareReplay(t *testing.T) {
+	var calls int32
+	e := newIdempotentEcho(t, &calls, created, 0)
+	asserts := assert.New(t)
+
+	first := idempotentRequest(e, "key-1", `{"name":"C"}`)
+	second := idempotentRequest(e, "key-1", `{"name":"C"}`)
+
+	asserts.Equal(int32(1), calls)
+	asserts.Equal(http.StatusCreated, first.Code)
+	asserts.Equal(http.StatusCreated, second.Code)
+	asserts.Equal(first.Body.String(), second.Body.String())
+	asserts.Equal(first.Header().Get(echo.HeaderContentType), second.Header().Get(echo.HeaderContentType))
+	asserts.Empty(first.Header().Get(HeaderIdempotentReplayed))
+	asserts.Equal("true", second.Header().Get(HeaderIdempotentReplayed))
+
+	idempotentRequest(e, "key-2", `{"name":"C"}`)
+	idempotentRequest(e, "", `{"name":"C"}`)
+	asserts.Equal(int32(3), calls)
+}
+
+func TestIdempotencyMiddlewareDifferentBody(t *testing.T) {
+	var calls int32
+	e := newIdempotentEcho(t, &calls, created, 0)
+	asserts := assert.New(t)
+
+	idempotentRequest(e, "key-1", `{"name":"C"}`)
+	rec := idempotentRequest(e, "key-1", `{"name":"D"}`)
+
+	asserts.Equal(int32(1), calls)
+	asserts.Equal(http.StatusUnprocessableEntity, rec.Code)
+	asserts.Contains(rec.Body.String(), "unprocessable_entity")
+}
+
+func TestIdempotencyMiddlewareServerErrorIsNotStored(t *testing.T) {
+	var calls int32
+	e := newIdempotentEcho(t, &calls, func(call int32) int {
+		if call == 1 {
+			return http.StatusInternalServerError
+		}
+		return http.StatusCreated
+	}, 0)
+	asserts := assert.New(t)
+
+	asserts.Equal(http.StatusInternalServerError, idempotentRequest(e, "key-1", `{}`).Code)
+	asserts.Equal(http.StatusCreated, idempotentRequest(e, "key-1", `{}`).Code)
+	asserts.Equal(http.StatusCreated, idempotentRequest(e, "key-1", `{}`).Code)
+	asserts.Equal(int32(2), calls)
+}
+
+func TestIdempotencyMiddlewareExpired(t *testing.T) {
+	t.Setenv("IDEMPOTENCY_KEY_TTL", "10ms")
+	var calls int32
+	e := newIdempotentEcho(t, &calls, created, 0)
+
+	idempotentRequest(e, "key-1", `{}`)
+	time.Sleep(20 * time.Millisecond)
+	rec := idempotentRequest(e, "key-1", `{}`)
+
+	assert.Equal(t, int32(2), calls)
+	assert.Empty(t, rec.Header().Get(HeaderIdempotentReplayed))
+}
+
+func TestIdempotencyMiddlewareBodyTooLarge(t *testing.T) {
+	t.Setenv("IDEMPOTENCY_MAX_BODY_SIZE", "16")
+	var calls int32
+	e := newIdempotentEcho(t, &calls, created, 0)
+	asserts := assert.New(t)
+
+	rec := idempotentRequest(e, "key-1", `{"name":"Computer Science"}`)
+	asserts.Equal(http.StatusRequestEntityTooLarge, rec.Code)
+	asserts.Contains(rec.Body.String(), "Request body is larger than allowed")
+
+	// the length of the body is not always known up front
+	req := httptest.NewRequest(http.MethodPost, "/classes", strings.NewReader(`{"name":"Computer Science"}`))
+	req.Header.Set(HeaderIdempotencyKey, "key-2")
+	req.ContentLength = -1
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	asserts.Equal(http.StatusRequestEntityTooLarge, rec.Code)
+
+	rec = idempotentRequest(e, "key-3", `{"name":"C"}`)
+	asserts.Equal(http.StatusCreated, rec.Code)
+	asserts.Equal(int32(1), calls)
+}
+
+func TestIdempotencyMiddlewareKeepsTheKeyOfALongRequest(t *testing.T) {
+	t.Setenv("IDEMPOTENCY_LOCK_TIMEOUT", "600ms")
+	var calls int32
+	e := newIdempotentEcho(t, &calls, created, 1500*time.Millisecond)
+	asserts := assert.New(t)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- idempotentRequest(e, "key-1", `{}`)
+	}()
+
+	// the first request runs longer than the lock timeout, its key is renewed meanwhile
+	time.Sleep(time.Second)
+	rec := idempotentRequest(e, "key-1", `{}`)
+	asserts.Equal(http.StatusConflict, rec.Code)
+	asserts.Contains(rec.Body.String(), "still being processed")
+
+	asserts.Equal(http.StatusCreated, (<-done).Code)
+	asserts.Equal(int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestIdempotencyMiddlewareConcurrentDuplicates(t *testing.T) {
+	var calls int32
+	e := newIdempotentEcho(t, &calls, created, 100*time.Millisecond)
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		codes = map[int]int{}
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := idempotentRequest(e, "key-1", `{"name":"C"}`)
+			mu.Lock()
+			codes[rec.Code]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	asserts := assert.New(t)
+	asserts.Equal(int32(1), calls)
+	asserts.Equal(1, codes[http.StatusCreated])
+	asserts.Equal(7, codes[http.StatusConflict])
+
+	rec := idempotentRequest(e, "key-1", `{"name":"C"}`)
+	asserts.Equal(http.StatusCreated, rec.Code)
+	asserts.Equal("true", rec.Header().Get(HeaderIdempotentReplayed))
+}
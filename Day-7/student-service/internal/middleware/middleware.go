@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"student-service/internal/dto"
+	"student-service/pkg/i18n"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -26,12 +27,31 @@ func LogMiddlewares(e *echo.Echo) {
 	// 	panic(fmt.Sprintf("error opening file: %v", err))
 	// }
 	e.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{
-		Format:           `[${time_rfc3339}] ${status} ${method} ${host}${uri} ${latency_human}` + "\n",
+		Format:           `[${time_rfc3339}] ${id} ${status} ${method} ${host}${uri} ${latency_human}` + "\n",
 		CustomTimeFormat: "2006/01/02 15:04:05",
 		// Output:           f,
 	}))
 }
 
+// RequestIDMiddlewares sets the X-Request-ID response header, reusing the one sent by the
+// client (or the gateway) when present.
+func RequestIDMiddlewares(e *echo.Echo) {
+	e.Use(middleware.RequestID())
+}
+
+// LanguageMiddlewares stores the language matching the Accept-Language header in the request
+// context, for the responses and for anything the services send (e.g. emails).
+func LanguageMiddlewares(e *echo.Echo) {
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			lang := i18n.Match(req.Header.Get("Accept-Language"))
+			c.SetRequest(req.WithContext(i18n.WithLanguage(req.Context(), lang)))
+			return next(c)
+		}
+	})
+}
+
 func JWTMiddleware(claims dto.JWTClaims, signingKey []byte) echo.MiddlewareFunc {
 	config := middleware.JWTConfig{
 		Claims:     &dto.JWTClaims{},
@@ -3,19 +3,24 @@ package mocks
 import (
 	"io"
 	"net/http/httptest"
+	"sync"
 
-	"student-service/pkg/util"
+	"student-service/internal/factory"
 
-	"github.com/go-playground/validator"
 	"github.com/labstack/echo/v4"
 )
 
 type EchoMock struct {
 	E *echo.Echo
+
+	// once sets the validator of E with the first request, requests may run in parallel
+	once sync.Once
 }
 
 func (em *EchoMock) RequestMock(method, path string, body io.Reader) (echo.Context, *httptest.ResponseRecorder) {
-	em.E.Validator = &util.CustomValidator{Validator: validator.New()}
+	em.once.Do(func() {
+		em.E.Validator = factory.NewFactory().NewValidator()
+	})
 	req := httptest.NewRequest(method, path, body)
 	rec := httptest.NewRecorder()
 	c := em.E.NewContext(req, rec)
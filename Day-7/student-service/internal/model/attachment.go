@@ -0,0 +1,15 @@
+package model
+
+// Attachment is a file of a student kept in the storage under StorageKey. A photo also has a
+// thumbnail under ThumbnailKey.
+type Attachment struct {
+	StudentID    uint `json:"student_id" gorm:"index"`
+	Student      Student
+	Kind         string  `json:"kind" gorm:"type:varchar(20)"`
+	Filename     string  `json:"filename" gorm:"type:varchar(255)"`
+	ContentType  string  `json:"content_type" gorm:"type:varchar(100)"`
+	Size         int64   `json:"size"`
+	StorageKey   string  `json:"storage_key" gorm:"type:varchar(255);uniqueIndex"`
+	ThumbnailKey *string `json:"thumbnail_key" gorm:"type:varchar(255)"`
+	Common
+}
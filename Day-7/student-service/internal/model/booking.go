@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Booking reserves a room from StartsAt until EndsAt for a student or for a class, exactly one
+// of StudentID and ClassID is set. A cancelled booking keeps its record and no longer blocks
+// the room.
+type Booking struct {
+	RoomID      uint `json:"room_id" gorm:"not null;index:idx_bookings_room_starts_at"`
+	Room        Room
+	StudentID   *uint `json:"student_id" gorm:"index"`
+	Student     *Student
+	ClassID     *uint `json:"class_id" gorm:"index"`
+	Class       *Class
+	StartsAt    time.Time `json:"starts_at" gorm:"not null;index:idx_bookings_room_starts_at"`
+	EndsAt      time.Time `json:"ends_at" gorm:"not null"`
+	Purpose     string    `json:"purpose" gorm:"type:varchar(100)"`
+	SeriesID    *uint     `json:"series_id" gorm:"index"`
+	Series      *BookingSeries
+	CancelledAt *time.Time `json:"cancelled_at"`
+	Common
+}
+
+// BookingSeries groups the occurrences of a recurring booking, they repeat every day or every
+// week.
+type BookingSeries struct {
+	ID          uint      `json:"id"`
+	Frequency   string    `json:"frequency" gorm:"type:varchar(10);not null"`
+	Occurrences uint      `json:"occurrences" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+}
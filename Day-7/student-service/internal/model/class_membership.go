@@ -0,0 +1,14 @@
+package model
+
+// ClassMembership is the class a student was in during a closed term. The memberships of a
+// term are recorded when it is closed, the class of a student in an open term is its current
+// class.
+type ClassMembership struct {
+	ID        uint `json:"id"`
+	TermID    uint `json:"term_id" gorm:"not null;uniqueIndex:idx_class_memberships_term_student"`
+	Term      Term
+	StudentID uint `json:"student_id" gorm:"not null;uniqueIndex:idx_class_memberships_term_student"`
+	Student   Student
+	ClassID   uint `json:"class_id" gorm:"not null;index"`
+	Class     Class
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// ClassSession is a meeting of a class on a day of a term, attendance is taken per session.
+type ClassSession struct {
+	ClassID     uint `json:"class_id" gorm:"not null;index:idx_class_sessions_class_term"`
+	Class       Class
+	TermID      uint `json:"term_id" gorm:"not null;index:idx_class_sessions_class_term"`
+	Term        Term
+	Date        time.Time    `json:"date" gorm:"type:date;not null"`
+	Topic       string       `json:"topic" gorm:"type:varchar(100)"`
+	Attendances []Attendance `json:"attendances" gorm:"foreignKey:SessionID"`
+	Common
+}
+
+// Attendance is the status of a student in a class session, one of the enum.AttendanceStatus
+// values. Students without an attendance were not marked yet.
+type Attendance struct {
+	ID        uint `json:"id"`
+	SessionID uint `json:"session_id" gorm:"not null;uniqueIndex:idx_attendances_session_student"`
+	StudentID uint `json:"student_id" gorm:"not null;uniqueIndex:idx_attendances_session_student;index"`
+	Student   Student
+	Status    string    `json:"status" gorm:"type:varchar(10);not null"`
+	Note      string    `json:"note" gorm:"type:varchar(255)"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -11,12 +11,17 @@ type Common struct {
 	CreatedAt time.Time       `json:"created_at"`
 	UpdatedAt time.Time       `json:"updated_at"`
 	DeletedAt *gorm.DeletedAt `json:"deleted_at"`
+	// Version is incremented on every update, updates and deletes are conditional on it.
+	Version uint `json:"version" gorm:"not null;default:1"`
 }
 
 func (c *Common) BeforeCreate(tx *gorm.DB) (err error) {
 	now := time.Now()
 	c.CreatedAt = now
 	c.UpdatedAt = now
+	if c.Version == 0 {
+		c.Version = 1
+	}
 	return
 }
 
@@ -0,0 +1,12 @@
+package model
+
+type Course struct {
+	Code    string `json:"code" gorm:"varchar;not_null;unique"`
+	Name    string `json:"name" gorm:"varchar;not_null"`
+	MajorID uint   `json:"major_id"`
+	Major   Major
+	Credits uint `json:"credits" gorm:"not null"`
+	// Capacity is the number of students who can enroll in the course in one term.
+	Capacity uint `json:"capacity" gorm:"not null"`
+	Common
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// Enrollment is a student taking a course in a term, it is deleted when the student withdraws.
+type Enrollment struct {
+	ID        uint `json:"id"`
+	StudentID uint `json:"student_id" gorm:"not null;uniqueIndex:idx_enrollments_student_course_term"`
+	Student   Student
+	CourseID  uint `json:"course_id" gorm:"not null;uniqueIndex:idx_enrollments_student_course_term;index:idx_enrollments_course_term"`
+	Course    Course
+	TermID    uint `json:"term_id" gorm:"not null;uniqueIndex:idx_enrollments_student_course_term;index:idx_enrollments_course_term"`
+	Term      Term
+	CreatedAt time.Time `json:"created_at"`
+}
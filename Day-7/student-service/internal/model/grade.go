@@ -0,0 +1,26 @@
+package model
+
+// Grade is the final result of a student in a course in one term. Score is the weighted
+// average of the assessments, it is nil when the grade has no assessments.
+type Grade struct {
+	StudentID   uint `json:"student_id" gorm:"not null;uniqueIndex:idx_grades_student_course_term"`
+	Student     Student
+	CourseID    uint `json:"course_id" gorm:"not null;uniqueIndex:idx_grades_student_course_term"`
+	Course      Course
+	TermID      uint `json:"term_id" gorm:"not null;uniqueIndex:idx_grades_student_course_term"`
+	Term        Term
+	Score       *float64     `json:"score"`
+	Letter      string       `json:"letter" gorm:"type:varchar(5);not null"`
+	Assessments []Assessment `json:"assessments"`
+	Common
+}
+
+// Assessment is a scored part of a grade, e.g. an exam. Weight is the share of the final
+// score in percent.
+type Assessment struct {
+	ID      uint    `json:"id"`
+	GradeID uint    `json:"grade_id" gorm:"not null;index"`
+	Name    string  `json:"name" gorm:"varchar;not null"`
+	Weight  float64 `json:"weight" gorm:"not null"`
+	Score   float64 `json:"score" gorm:"not null"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// IdempotencyKey stores the first response sent for an Idempotency-Key, per user and route.
+// Status is 0 while the first request is still being processed.
+type IdempotencyKey struct {
+	ID          uint      `json:"id"`
+	Key         string    `json:"key" gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_keys_key_user_route"`
+	UserID      uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_idempotency_keys_key_user_route"`
+	Route       string    `json:"route" gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_keys_key_user_route"`
+	RequestHash string    `json:"request_hash" gorm:"type:char(64);not null"`
+	Status      int       `json:"status" gorm:"not null;default:0"`
+	Header      string    `json:"header" gorm:"type:text"`
+	Body        []byte    `json:"body"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
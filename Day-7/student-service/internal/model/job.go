@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Job is slow work run by the job workers out of the request. A pending job runs from RunAt, a
+// running one is claimed by LockedBy until LockedUntil and runs again if it is not done by then.
+// Jobs that failed MaxAttempts times are dead.
+type Job struct {
+	ID          uint       `json:"id"`
+	Type        string     `json:"type" gorm:"type:varchar(64);not null;index"`
+	Payload     string     `json:"payload" gorm:"type:text;not null"`
+	Status      string     `json:"status" gorm:"type:varchar(20);not null;index:idx_jobs_status_run_at"`
+	Attempts    int        `json:"attempts" gorm:"not null;default:0"`
+	MaxAttempts int        `json:"max_attempts" gorm:"not null"`
+	RunAt       time.Time  `json:"run_at" gorm:"not null;index:idx_jobs_status_run_at"`
+	LockedBy    string     `json:"locked_by" gorm:"type:varchar(100)"`
+	LockedUntil *time.Time `json:"locked_until"`
+	LastError   string     `json:"last_error" gorm:"type:text"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Lease is held by one replica until ExpiresAt, for the work that only one replica must do at
+// a time. The owner renews it before it expires, other replicas can take it over afterwards.
+type Lease struct {
+	Name      string    `json:"name" gorm:"primaryKey;type:varchar(100)"`
+	Owner     string    `json:"owner" gorm:"type:varchar(100);not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// OutboxEvent is a domain event recorded in the transaction of the change it reports, the relay
+// publishes it once the change is committed. The events of an aggregate are published in the
+// order of their ids, a failed one holds back the next ones until NextAttemptAt.
+type OutboxEvent struct {
+	ID            uint       `json:"id"`
+	EventID       string     `json:"event_id" gorm:"type:varchar(64);not null;uniqueIndex:idx_outbox_events_event_id"`
+	Type          string     `json:"type" gorm:"type:varchar(64);not null"`
+	AggregateType string     `json:"aggregate_type" gorm:"type:varchar(32);not null;index:idx_outbox_events_aggregate"`
+	AggregateID   string     `json:"aggregate_id" gorm:"type:varchar(64);not null;index:idx_outbox_events_aggregate"`
+	Data          string     `json:"data" gorm:"type:text;not null"`
+	OccurredAt    time.Time  `json:"occurred_at" gorm:"not null"`
+	PublishedAt   *time.Time `json:"published_at" gorm:"index:idx_outbox_events_published_at"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt *time.Time `json:"next_attempt_at"`
+	LastError     string     `json:"last_error" gorm:"type:text"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
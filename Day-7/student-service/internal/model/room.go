@@ -0,0 +1,16 @@
+package model
+
+// Room is a meeting room that students and classes can book.
+type Room struct {
+	Name       string         `json:"name" gorm:"type:varchar(50);not null;uniqueIndex"`
+	Capacity   uint           `json:"capacity" gorm:"not null"`
+	Facilities []RoomFacility `json:"facilities"`
+	Common
+}
+
+// RoomFacility is equipment available in a room, e.g. a projector. Names are lower case.
+type RoomFacility struct {
+	ID     uint   `json:"id"`
+	RoomID uint   `json:"room_id" gorm:"not null;uniqueIndex:idx_room_facilities_room_name"`
+	Name   string `json:"name" gorm:"type:varchar(50);not null;uniqueIndex:idx_room_facilities_room_name"`
+}
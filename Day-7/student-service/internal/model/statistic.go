@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// Statistic is a statistic computed by a scheduled task rather than on every request. Data is
+// its JSON value as of ComputedAt.
+type Statistic struct {
+	Name       string    `json:"name" gorm:"primaryKey;type:varchar(100)"`
+	Data       string    `json:"data" gorm:"type:text;not null"`
+	ComputedAt time.Time `json:"computed_at" gorm:"not null"`
+}
@@ -1,12 +1,29 @@
 package model
 
+import "time"
+
 type Student struct {
-	Fullname string `json:"fullname" gorm:"varchar;not_null"`
-	Email    string `json:"email" gorm:"varchar;not_null;unique"`
+	Fullname string `json:"fullname" gorm:"varchar;not_null;index:idx_students_fulltext,class:FULLTEXT"`
+	Email    string `json:"email" gorm:"varchar;not_null;unique;index:idx_students_fulltext,class:FULLTEXT"`
 	Password string `json:"password" gorm:"varchar;not_null"`
-	ClassID  uint   `json:"class_id"`
+	ClassID  *uint  `json:"class_id"`
 	Class    Class
 	MajorID  uint `json:"major_id"`
 	Major    Major
+	// StudentNumber is generated when the student registers and never changes. Students
+	// registered before numbers were introduced have none.
+	StudentNumber *string    `json:"student_number" gorm:"type:varchar(30);uniqueIndex;index:idx_students_fulltext,class:FULLTEXT"`
+	IntakeYear    int        `json:"intake_year"`
+	Phone         string     `json:"phone" gorm:"type:varchar(20)"`
+	BirthDate     *time.Time `json:"birth_date" gorm:"type:date"`
+	Gender        string     `json:"gender" gorm:"type:varchar(10)"`
+	Address       string     `json:"address" gorm:"type:varchar(255)"`
 	Common
 }
+
+// StudentNumberSequence is the last sequence number given to a student in the scope of a
+// student number pattern.
+type StudentNumberSequence struct {
+	Scope string `gorm:"type:varchar(50);primaryKey"`
+	Last  uint
+}
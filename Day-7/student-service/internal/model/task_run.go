@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// TaskRun is a run of a scheduled task at its ScheduledAt time. A task runs once per scheduled
+// time, on the replica that records the run first.
+type TaskRun struct {
+	ID          uint       `json:"id"`
+	Task        string     `json:"task" gorm:"type:varchar(100);not null;uniqueIndex:idx_task_runs_task_scheduled_at"`
+	ScheduledAt time.Time  `json:"scheduled_at" gorm:"not null;uniqueIndex:idx_task_runs_task_scheduled_at"`
+	Owner       string     `json:"owner" gorm:"type:varchar(100);not null"`
+	Status      string     `json:"status" gorm:"type:varchar(20);not null;index"`
+	Result      string     `json:"result" gorm:"type:text"`
+	Error       string     `json:"error" gorm:"type:text"`
+	StartedAt   time.Time  `json:"started_at" gorm:"not null"`
+	FinishedAt  *time.Time `json:"finished_at"`
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// Term is an academic term, e.g. a semester. Enrollments and grades belong to a term, they
+// are read-only once the term is closed.
+type Term struct {
+	Name      string    `json:"name" gorm:"type:varchar(50);not null;unique"`
+	StartDate time.Time `json:"start_date" gorm:"type:date;not null"`
+	EndDate   time.Time `json:"end_date" gorm:"type:date;not null"`
+	Closed    bool      `json:"closed" gorm:"not null;default:false"`
+	Common
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// WebhookSubscription receives the events it subscribes to as POST requests to URL, signed with
+// Secret. Events is a comma separated list of enum.Event.
+type WebhookSubscription struct {
+	URL    string `json:"url" gorm:"type:varchar(2048);not null"`
+	Events string `json:"events" gorm:"type:varchar(1024);not null"`
+	Secret string `json:"-" gorm:"type:varchar(255);not null"`
+	Active bool   `json:"active" gorm:"not null"`
+	Common
+}
+
+// WebhookDelivery is an event sent to a subscription, with the outcome of its last attempt.
+// Pending deliveries are attempted again from NextAttemptAt. A redelivery is a new delivery of
+// the payload of RedeliveryOfID. EventID is the outbox event a delivery was created for, so that
+// an event published again is delivered once per subscription; redeliveries have none.
+type WebhookDelivery struct {
+	ID             uint                `json:"id"`
+	SubscriptionID uint                `json:"subscription_id" gorm:"not null;index;uniqueIndex:idx_webhook_deliveries_subscription_event"`
+	Subscription   WebhookSubscription `json:"-"`
+	EventID        *string             `json:"event_id" gorm:"type:varchar(64);uniqueIndex:idx_webhook_deliveries_subscription_event"`
+	Event          string              `json:"event" gorm:"type:varchar(64);not null"`
+	Payload        string              `json:"payload" gorm:"type:text;not null"`
+	Status         string              `json:"status" gorm:"type:varchar(20);not null;index:idx_webhook_deliveries_status_next_attempt"`
+	Attempts       int                 `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  *time.Time          `json:"next_attempt_at" gorm:"index:idx_webhook_deliveries_status_next_attempt"`
+	LastAttemptAt  *time.Time          `json:"last_attempt_at"`
+	ResponseStatus int                 `json:"response_status"`
+	LastError      string              `json:"last_error" gorm:"type:text"`
+	DeliveredAt    *time.Time          `json:"delivered_at"`
+	RedeliveryOfID *uint               `json:"redelivery_of_id"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
@@ -0,0 +1,10 @@
+package enum
+
+// AttachmentKind is what a file attached to a student is.
+type AttachmentKind string
+
+const (
+	IDScan      AttachmentKind = "id_scan"
+	Certificate AttachmentKind = "certificate"
+	Photo       AttachmentKind = "photo"
+)
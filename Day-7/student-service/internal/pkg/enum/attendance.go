@@ -0,0 +1,11 @@
+package enum
+
+// AttendanceStatus is the attendance of a student in a class session.
+type AttendanceStatus string
+
+const (
+	Present AttendanceStatus = "present"
+	Absent  AttendanceStatus = "absent"
+	Late    AttendanceStatus = "late"
+	Excused AttendanceStatus = "excused"
+)
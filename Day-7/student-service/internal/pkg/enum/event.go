@@ -0,0 +1,18 @@
+package enum
+
+// Event is a domain event, a change of a student, class or major. Events are recorded in the
+// outbox with the change they report, then relayed to the subscribers such as the webhooks.
+type Event string
+
+const (
+	StudentRegistered   Event = "student.registered"
+	StudentUpdated      Event = "student.updated"
+	StudentClassChanged Event = "student.class_changed"
+	StudentDeleted      Event = "student.deleted"
+	ClassCreated        Event = "class.created"
+	ClassUpdated        Event = "class.updated"
+	ClassDeleted        Event = "class.deleted"
+	MajorCreated        Event = "major.created"
+	MajorUpdated        Event = "major.updated"
+	MajorDeleted        Event = "major.deleted"
+)
@@ -0,0 +1,9 @@
+package enum
+
+// Gender is the gender in the profile of a student.
+type Gender string
+
+const (
+	Male   Gender = "male"
+	Female Gender = "female"
+)
@@ -0,0 +1,22 @@
+package enum
+
+// JobType names the handler of a job.
+type JobType string
+
+const (
+	JobWelcomeEmail JobType = "email.welcome"
+)
+
+// JobStatus is where a job is in its life.
+type JobStatus string
+
+const (
+	// JobPending is waiting to run, from its run_at.
+	JobPending JobStatus = "pending"
+	// JobRunning is claimed by a worker until its locked_until.
+	JobRunning JobStatus = "running"
+	// JobSucceeded is done.
+	JobSucceeded JobStatus = "succeeded"
+	// JobDead failed all its attempts, it only runs again when retried.
+	JobDead JobStatus = "dead"
+)
@@ -0,0 +1,24 @@
+package enum
+
+// TaskName names a scheduled task.
+type TaskName string
+
+const (
+	TaskPurgeDeleted      TaskName = "purge.deleted"
+	TaskPurgeHistory      TaskName = "purge.history"
+	TaskComputeStatistics TaskName = "statistics.compute"
+)
+
+// TaskRunStatus is the outcome of a run of a scheduled task.
+type TaskRunStatus string
+
+const (
+	// TaskRunRunning is still running, or its replica stopped before recording the outcome.
+	TaskRunRunning TaskRunStatus = "running"
+	// TaskRunSucceeded is done.
+	TaskRunSucceeded TaskRunStatus = "succeeded"
+	// TaskRunFailed returned an error, the task runs again at its next scheduled time.
+	TaskRunFailed TaskRunStatus = "failed"
+	// TaskRunSkipped did not run because the previous run of the task was still running.
+	TaskRunSkipped TaskRunStatus = "skipped"
+)
@@ -0,0 +1,13 @@
+package enum
+
+// WebhookDeliveryStatus is the outcome of sending an event to a subscription.
+type WebhookDeliveryStatus string
+
+const (
+	// DeliveryPending is waiting for its next attempt.
+	DeliveryPending WebhookDeliveryStatus = "pending"
+	// DeliverySucceeded got a 2xx response.
+	DeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// DeliveryFailed ran out of attempts, it is only sent again when redelivered.
+	DeliveryFailed WebhookDeliveryStatus = "failed"
+)
@@ -0,0 +1,34 @@
+package util
+
+import (
+	"math"
+
+	"student-service/internal/dto"
+	"student-service/internal/pkg/enum"
+	"student-service/internal/repository"
+)
+
+// AttendanceSummary adds up the attendance counts by status and computes the attendance rate,
+// rounded to two decimals.
+func AttendanceSummary(counts ...repository.AttendanceCount) dto.AttendanceSummaryResponse {
+	var summary dto.AttendanceSummaryResponse
+	for _, count := range counts {
+		switch enum.AttendanceStatus(count.Status) {
+		case enum.Present:
+			summary.Present += count.Count
+		case enum.Late:
+			summary.Late += count.Count
+		case enum.Absent:
+			summary.Absent += count.Count
+		case enum.Excused:
+			summary.Excused += count.Count
+		}
+	}
+
+	attended := summary.Present + summary.Late
+	if counted := attended + summary.Absent; counted > 0 {
+		rate := math.Round(float64(attended)/float64(counted)*10000) / 100
+		summary.Rate = &rate
+	}
+	return summary
+}
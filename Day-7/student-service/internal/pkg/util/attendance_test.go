@@ -0,0 +1,31 @@
+package util
+
+import (
+	"testing"
+
+	"student-service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttendanceSummary(t *testing.T) {
+	summary := AttendanceSummary(
+		repository.AttendanceCount{StudentID: 1, Status: "present", Count: 4},
+		repository.AttendanceCount{StudentID: 2, Status: "present", Count: 3},
+		repository.AttendanceCount{StudentID: 1, Status: "late", Count: 1},
+		repository.AttendanceCount{StudentID: 1, Status: "absent", Count: 1},
+		repository.AttendanceCount{StudentID: 2, Status: "excused", Count: 2},
+	)
+	assert.Equal(t, uint(7), summary.Present)
+	assert.Equal(t, uint(1), summary.Late)
+	assert.Equal(t, uint(1), summary.Absent)
+	assert.Equal(t, uint(2), summary.Excused)
+	if assert.NotNil(t, summary.Rate) {
+		// excused sessions are left out, late counts as attended
+		assert.Equal(t, 88.89, *summary.Rate)
+	}
+
+	onlyExcused := AttendanceSummary(repository.AttendanceCount{StudentID: 1, Status: "excused", Count: 2})
+	assert.Nil(t, onlyExcused.Rate)
+	assert.Nil(t, AttendanceSummary().Rate)
+}
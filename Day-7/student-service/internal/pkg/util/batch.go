@@ -0,0 +1,98 @@
+package util
+
+import (
+	"context"
+
+	"student-service/internal/repository"
+	pkgdto "student-service/pkg/dto"
+	res "student-service/pkg/util/response"
+)
+
+// RunBatch validates every item of payload and applies the valid ones, status is the status of
+// an applied item. In atomic mode the items are applied in one transaction, and none is applied
+// when one of them is invalid or fails: the other items are reported as aborted.
+//
+// The version of a pkgdto.Versioned item becomes its If-Match, so that apply checks it like
+// the single-item route does.
+func RunBatch[T any](
+	ctx context.Context,
+	transactor repository.Transactor,
+	payload *pkgdto.BatchRequest[T],
+	status int,
+	validate func(ctx context.Context, item interface{}) error,
+	apply func(ctx context.Context, item *T) (interface{}, error),
+) *res.Batch {
+	batch := &res.Batch{
+		Mode:   pkgdto.BatchModeBestEffort,
+		Status: status,
+		Items:  make([]res.BatchItem, len(payload.Items)),
+	}
+
+	valid := true
+	for i := range payload.Items {
+		if item, ok := any(&payload.Items[i]).(pkgdto.Versioned); ok {
+			if ifMatch, version := item.Precondition(); version != nil {
+				*ifMatch = res.ETag(*version)
+			}
+		}
+		if err := validate(ctx, &payload.Items[i]); err != nil {
+			batch.Items[i].Err = res.ValidationErrorBuilder(err)
+			valid = false
+		}
+	}
+
+	if !payload.IsAtomic() {
+		for i := range payload.Items {
+			if batch.Items[i].Err == nil {
+				batch.Items[i] = applyBatchItem(ctx, apply, &payload.Items[i], status)
+			}
+		}
+		return batch
+	}
+
+	batch.Mode = pkgdto.BatchModeAtomic
+	if !valid {
+		return abortBatch(batch)
+	}
+
+	failed := false
+	err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for i := range payload.Items {
+			batch.Items[i] = applyBatchItem(ctx, apply, &payload.Items[i], status)
+			if batch.Items[i].Err != nil {
+				failed = true
+				return batch.Items[i].Err
+			}
+		}
+		return nil
+	})
+	if err != nil && !failed {
+		// every item was applied but the transaction could not be committed
+		for i := range batch.Items {
+			batch.Items[i] = res.BatchItem{Err: RepositoryErrorBuilder(err)}
+		}
+	}
+	if err != nil {
+		return abortBatch(batch)
+	}
+	return batch
+}
+
+func applyBatchItem[T any](ctx context.Context, apply func(ctx context.Context, item *T) (interface{}, error), item *T, status int) res.BatchItem {
+	data, err := apply(ctx, item)
+	if err != nil {
+		return res.BatchItem{Err: res.ErrorResponse(err)}
+	}
+	return res.BatchItem{Status: status, Data: data}
+}
+
+// abortBatch reports the items of a failed atomic batch that did not fail themselves as aborted,
+// whether they were rolled back or never applied.
+func abortBatch(batch *res.Batch) *res.Batch {
+	for i, item := range batch.Items {
+		if item.Err == nil {
+			batch.Items[i] = res.BatchItem{Err: res.ErrorBuilder(res.ErrorConstant.BatchAborted, nil)}
+		}
+	}
+	return batch
+}
@@ -0,0 +1,9 @@
+package util
+
+// Namespaces of the server-side cache entries of the services. Student responses include the
+// name of the class and major of the student, so changing those invalidates students too.
+const (
+	ClassCachePrefix   = "classes:"
+	MajorCachePrefix   = "majors:"
+	StudentCachePrefix = "students:"
+)
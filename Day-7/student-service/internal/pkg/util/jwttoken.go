@@ -1,6 +1,7 @@
 package util
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -74,3 +75,19 @@ func ParseJWTToken(authHeader string) (*dto.JWTClaims, error) {
 		return nil, fmt.Errorf("invalid token")
 	}
 }
+
+type jwtClaimsKey struct{}
+
+// WithJWTClaims returns a context carrying the claims of the token of a request, for the APIs
+// that do not go through echo.
+func WithJWTClaims(ctx context.Context, claims *dto.JWTClaims) context.Context {
+	return context.WithValue(ctx, jwtClaimsKey{}, claims)
+}
+
+// JWTClaimsFromContext returns the claims stored by WithJWTClaims, or empty claims.
+func JWTClaimsFromContext(ctx context.Context) *dto.JWTClaims {
+	if claims, ok := ctx.Value(jwtClaimsKey{}).(*dto.JWTClaims); ok {
+		return claims
+	}
+	return &dto.JWTClaims{}
+}
@@ -0,0 +1,42 @@
+package util
+
+import (
+	"errors"
+	"io"
+
+	pkgdto "student-service/pkg/dto"
+	pkgutil "student-service/pkg/util"
+	res "student-service/pkg/util/response"
+
+	"github.com/labstack/echo/v4"
+)
+
+// BindPatch binds the id path parameter, the If-Match and Content-Type headers and the raw
+// body of a PATCH request. The body is not decoded, its format depends on the content type.
+func BindPatch(c echo.Context, payload *pkgdto.PatchByIDRequest) error {
+	if err := (&echo.DefaultBinder{}).BindPathParams(c, payload); err != nil {
+		return err
+	}
+	payload.IfMatch = c.Request().Header.Get(res.HeaderIfMatch)
+	payload.ContentType = c.Request().Header.Get(echo.HeaderContentType)
+
+	patch, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return err
+	}
+	payload.Patch = patch
+	return nil
+}
+
+// PatchErrorBuilder maps an error returned by pkgutil.ApplyPatch to the response error.
+func PatchErrorBuilder(err error) *res.Error {
+	switch {
+	case errors.Is(err, pkgutil.ErrUnsupportedPatch):
+		return res.ErrorBuilder(res.ErrorConstant.UnsupportedMediaType, err)
+	case errors.Is(err, pkgutil.ErrMalformedPatch):
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err)
+	case errors.Is(err, pkgutil.ErrPatchFailed):
+		return res.ErrorBuilder(res.ErrorConstant.UnprocessableEntity, err)
+	}
+	return res.ErrorBuilder(res.ErrorConstant.InternalServerError, err)
+}
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"errors"
+
+	"student-service/internal/repository"
+	res "student-service/pkg/util/response"
+)
+
+// RepositoryErrorBuilder maps an error returned by a repository to the response error. A
+// response error, e.g. returned by a transaction, is returned as it is.
+func RepositoryErrorBuilder(err error) *res.Error {
+	var resErr *res.Error
+	switch {
+	case errors.As(err, &resErr):
+		return resErr
+	case errors.Is(err, repository.ErrNotFound):
+		return res.ErrorBuilder(res.ErrorConstant.NotFound, err)
+	case errors.Is(err, repository.ErrDuplicate):
+		return res.ErrorBuilder(res.ErrorConstant.Duplicate, err)
+	case errors.Is(err, repository.ErrForeignKeyViolation):
+		return res.ErrorBuilder(res.ErrorConstant.InvalidReference, err)
+	case errors.Is(err, repository.ErrStaleVersion):
+		return res.ErrorBuilder(res.ErrorConstant.PreconditionFailed, err)
+	case errors.Is(err, repository.ErrCapacityExceeded):
+		return res.ErrorBuilder(res.ErrorConstant.CourseFull, err)
+	case errors.Is(err, repository.ErrBookingConflict):
+		return res.ErrorBuilder(res.ErrorConstant.BookingConflict, err)
+	case errors.Is(err, repository.ErrRetryable):
+		return res.ErrorBuilder(res.ErrorConstant.Conflict, err)
+	}
+	return res.ErrorBuilder(res.ErrorConstant.InternalServerError, err)
+}
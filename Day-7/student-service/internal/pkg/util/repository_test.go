@@ -0,0 +1,34 @@
+package util
+
+import (
+	"errors"
+	"testing"
+
+	"student-service/internal/repository"
+	res "student-service/pkg/util/response"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoryErrorBuilder(t *testing.T) {
+	cases := []struct {
+		err    error
+		kind   *res.Kind
+		status int
+	}{
+		{&repository.Error{Kind: repository.ErrNotFound, Err: errors.New("record not found")}, res.ErrorConstant.NotFound, 404},
+		{&repository.Error{Kind: repository.ErrDuplicate, Err: errors.New("duplicate entry")}, res.ErrorConstant.Duplicate, 409},
+		{&repository.Error{Kind: repository.ErrForeignKeyViolation, Err: errors.New("fk")}, res.ErrorConstant.InvalidReference, 422},
+		{repository.ErrStaleVersion, res.ErrorConstant.PreconditionFailed, 412},
+		{repository.ErrCapacityExceeded, res.ErrorConstant.CourseFull, 409},
+		{&repository.Error{Kind: repository.ErrRetryable, Err: errors.New("deadlock")}, res.ErrorConstant.Conflict, 409},
+		{errors.New("connection refused"), res.ErrorConstant.InternalServerError, 500},
+		{res.ErrorBuilder(res.ErrorConstant.TermClosed, errors.New("term 1 is closed")), res.ErrorConstant.TermClosed, 409},
+	}
+	for _, c := range cases {
+		err := RepositoryErrorBuilder(c.err)
+		assert.True(t, errors.Is(err, c.kind), c.err.Error())
+		assert.Equal(t, c.status, err.Kind.Status())
+		assert.True(t, errors.Is(err, c.err))
+	}
+}
@@ -0,0 +1,26 @@
+package util
+
+import (
+	"fmt"
+	"time"
+
+	pkgutil "student-service/pkg/util"
+	res "student-service/pkg/util/response"
+)
+
+// CheckBirthDate returns an InvalidBirthDate error when the birth date is set and not before
+// today. The date must already be validated with the `date` tag.
+func CheckBirthDate(value *string) error {
+	if value == nil || *value == "" {
+		return nil
+	}
+	birthDate, err := pkgutil.ParseDate(*value)
+	if err != nil {
+		return res.ErrorBuilder(res.ErrorConstant.BadRequest, err)
+	}
+	now := time.Now()
+	if !birthDate.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)) {
+		return res.ErrorBuilder(res.ErrorConstant.InvalidBirthDate, fmt.Errorf("birth date %s is not in the past", *value))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"student-service/internal/model"
+	"student-service/internal/repository"
+	res "student-service/pkg/util/response"
+)
+
+// FindTerm returns the term with the given id, or the active term when id is nil. It returns
+// a NoActiveTerm error when id is nil and no term is active.
+func FindTerm(ctx context.Context, terms repository.Term, id *uint) (*model.Term, error) {
+	if id != nil {
+		term, err := terms.FindByID(ctx, *id)
+		if err != nil {
+			return nil, RepositoryErrorBuilder(err)
+		}
+		return &term, nil
+	}
+
+	term, err := terms.FindActive(ctx, time.Now())
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, res.ErrorBuilder(res.ErrorConstant.NoActiveTerm, err)
+	}
+	if err != nil {
+		return nil, RepositoryErrorBuilder(err)
+	}
+	return &term, nil
+}
+
+// CheckTermOpen returns a TermClosed error when the term is closed, closed terms are read-only.
+func CheckTermOpen(term *model.Term) error {
+	if term.Closed {
+		return res.ErrorBuilder(res.ErrorConstant.TermClosed, fmt.Errorf("term %d is closed", term.ID))
+	}
+	return nil
+}
+
+// WithinOpenTerm runs fn in a transaction that holds a share lock on the term, and returns a
+// TermClosed error without running fn when the term is closed. A term checked before the
+// transaction could be closed before the writes of fn commit, closing it waits for the lock.
+func WithinOpenTerm(ctx context.Context, transactor repository.Transactor, terms repository.Term, id uint, fn func(ctx context.Context) error) error {
+	return transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		term, err := terms.Lock(ctx, id)
+		if err != nil {
+			return RepositoryErrorBuilder(err)
+		}
+		if err := CheckTermOpen(&term); err != nil {
+			return err
+		}
+		return fn(ctx)
+	})
+}
@@ -0,0 +1,20 @@
+package util
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+)
+
+type contextValidator interface {
+	ValidateCtx(ctx context.Context, i interface{}) error
+}
+
+// Validate validates the payload of a request with the validator of echo. The `exists` and
+// `unique` lookups run with the context of the request, they stop when it is cancelled.
+func Validate(c echo.Context, payload interface{}) error {
+	if v, ok := c.Echo().Validator.(contextValidator); ok {
+		return v.ValidateCtx(c.Request().Context(), payload)
+	}
+	return c.Validate(payload)
+}
@@ -0,0 +1,42 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pkgutil "student-service/pkg/util"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateWithRequestContext(t *testing.T) {
+	type payload struct {
+		ClassID uint `json:"class_id" validate:"exists=classes"`
+	}
+	var lookupCtx context.Context
+	v := pkgutil.NewCustomValidator()
+	v.RegisterExists("classes", func(ctx context.Context, id uint) (bool, error) {
+		lookupCtx = ctx
+		return true, ctx.Err()
+	})
+	e := echo.New()
+	e.Validator = v
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodPost, "/", nil).WithContext(ctx)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	// testing
+	err := Validate(c, &payload{ClassID: 1})
+	var httpErr *echo.HTTPError
+	if assert.True(t, errors.As(err, &httpErr)) {
+		assert.Equal(t, http.StatusInternalServerError, httpErr.Code)
+		assert.True(t, errors.Is(httpErr.Internal, context.Canceled))
+	}
+	assert.Equal(t, context.Canceled, lookupCtx.Err())
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+
+	"student-service/internal/dto"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Attachment interface {
+	FindAll(ctx context.Context, payload *dto.SearchAttachmentRequest) ([]model.Attachment, error)
+	FindByID(ctx context.Context, id uint) (model.Attachment, error)
+	Save(ctx context.Context, attachment *model.Attachment) ([]model.Attachment, error)
+	Destroy(ctx context.Context, attachment *model.Attachment) (*model.Attachment, error)
+}
+
+type attachment struct {
+	Db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) *attachment {
+	return &attachment{
+		db,
+	}
+}
+
+func (r *attachment) FindAll(ctx context.Context, payload *dto.SearchAttachmentRequest) ([]model.Attachment, error) {
+	var attachments []model.Attachment
+
+	query := dbFrom(ctx, r.Db).Model(&model.Attachment{})
+	if payload.StudentID != nil {
+		query = query.Where("student_id = ?", *payload.StudentID)
+	}
+	if payload.Kind != "" {
+		query = query.Where("kind = ?", payload.Kind)
+	}
+
+	err := query.Order("student_id, id").Find(&attachments).Error
+	return attachments, translateError(r.Db, err)
+}
+
+func (r *attachment) FindByID(ctx context.Context, id uint) (model.Attachment, error) {
+	var attachment model.Attachment
+	if err := dbFrom(ctx, r.Db).Model(&model.Attachment{}).Where("id = ?", id).First(&attachment).Error; err != nil {
+		return attachment, translateError(r.Db, err)
+	}
+	return attachment, nil
+}
+
+// Save creates the attachment. A student has one photo, so saving a photo deletes the previous
+// photo of the student in the same transaction and returns it, its files are left to the caller.
+func (r *attachment) Save(ctx context.Context, attachment *model.Attachment) ([]model.Attachment, error) {
+	var replaced []model.Attachment
+	err := NewTransactor(r.Db).WithinTransaction(ctx, func(ctx context.Context) error {
+		if attachment.Kind == string(enum.Photo) {
+			err := dbFrom(ctx, r.Db).
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("student_id = ? AND kind = ?", attachment.StudentID, attachment.Kind).
+				Find(&replaced).Error
+			if err != nil {
+				return translateError(r.Db, err)
+			}
+			if len(replaced) > 0 {
+				if err := dbFrom(ctx, r.Db).Unscoped().Delete(&replaced).Error; err != nil {
+					return translateError(r.Db, err)
+				}
+			}
+		}
+		return translateError(r.Db, dbFrom(ctx, r.Db).Create(attachment).Error)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return replaced, nil
+}
+
+// Destroy deletes the attachment for good, since its files are deleted with it.
+func (r *attachment) Destroy(ctx context.Context, attachment *model.Attachment) (*model.Attachment, error) {
+	result := dbFrom(ctx, r.Db).Unscoped().Where("version = ?", attachment.Version).Delete(attachment)
+	if result.Error != nil {
+		return nil, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrStaleVersion
+	}
+	return attachment, nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"student-service/internal/dto"
+	"student-service/internal/model"
+	pkgdto "student-service/pkg/dto"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Booking interface {
+	FindAll(ctx context.Context, payload *dto.SearchBookingRequest, pagination *pkgdto.Pagination) ([]model.Booking, *pkgdto.PaginationInfo, error)
+	FindByID(ctx context.Context, id uint) (model.Booking, error)
+	Book(ctx context.Context, bookings []model.Booking, series *model.BookingSeries) error
+	Cancel(ctx context.Context, booking *model.Booking, series bool, at time.Time) ([]model.Booking, error)
+}
+
+type booking struct {
+	Db *gorm.DB
+}
+
+func NewBookingRepository(db *gorm.DB) *booking {
+	return &booking{
+		db,
+	}
+}
+
+func (r *booking) FindAll(ctx context.Context, payload *dto.SearchBookingRequest, pagination *pkgdto.Pagination) ([]model.Booking, *pkgdto.PaginationInfo, error) {
+	var bookings []model.Booking
+	var count int64
+
+	query := dbFrom(ctx, r.Db).Model(&model.Booking{})
+
+	if payload.RoomID != nil {
+		query = query.Where("room_id = ?", *payload.RoomID)
+	}
+	if payload.StudentID != nil {
+		query = query.Where("student_id = ?", *payload.StudentID)
+	}
+	if payload.ClassID != nil {
+		query = query.Where("class_id = ?", *payload.ClassID)
+	}
+	if payload.From != nil {
+		query = query.Where("ends_at > ?", *payload.From)
+	}
+	if payload.To != nil {
+		query = query.Where("starts_at < ?", *payload.To)
+	}
+	if !payload.Cancelled {
+		query = query.Where("cancelled_at IS NULL")
+	}
+
+	countQuery := query
+	if err := countQuery.Count(&count).Error; err != nil {
+		return nil, nil, translateError(r.Db, err)
+	}
+
+	limit, offset := pkgdto.GetLimitOffset(pagination)
+
+	err := query.Order("starts_at, id").Limit(limit).Offset(offset).Find(&bookings).Error
+
+	return bookings, pkgdto.CheckInfoPagination(pagination, count), translateError(r.Db, err)
+}
+
+func (r *booking) FindByID(ctx context.Context, id uint) (model.Booking, error) {
+	var booking model.Booking
+	if err := dbFrom(ctx, r.Db).Model(&model.Booking{}).Where("id = ?", id).First(&booking).Error; err != nil {
+		return booking, translateError(r.Db, err)
+	}
+	return booking, nil
+}
+
+// Book creates the bookings of one room, and the series they belong to when it is not nil, in
+// one transaction. The room row is locked first, so concurrent bookings of the room are checked
+// one after another and never overlap. It returns ErrBookingConflict when a booking overlaps a
+// booking of the room that is not cancelled, and ErrNotFound when the room does not exist.
+func (r *booking) Book(ctx context.Context, bookings []model.Booking, series *model.BookingSeries) error {
+	if len(bookings) == 0 {
+		return nil
+	}
+	return NewTransactor(r.Db).WithinTransaction(ctx, func(ctx context.Context) error {
+		var room model.Room
+		err := dbFrom(ctx, r.Db).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", bookings[0].RoomID).
+			First(&room).Error
+		if err != nil {
+			return translateError(r.Db, err)
+		}
+
+		for _, booking := range bookings {
+			var count int64
+			err := dbFrom(ctx, r.Db).Model(&model.Booking{}).
+				Scopes(overlapping(booking.StartsAt, booking.EndsAt)).
+				Where("room_id = ?", room.ID).
+				Count(&count).Error
+			if err != nil {
+				return translateError(r.Db, err)
+			}
+			if count > 0 {
+				return fmt.Errorf("%w: room %d from %s to %s", ErrBookingConflict, room.ID,
+					booking.StartsAt.Format(time.RFC3339), booking.EndsAt.Format(time.RFC3339))
+			}
+		}
+
+		if series != nil {
+			if err := dbFrom(ctx, r.Db).Create(series).Error; err != nil {
+				return translateError(r.Db, err)
+			}
+			for i := range bookings {
+				bookings[i].SeriesID = &series.ID
+			}
+		}
+		return translateError(r.Db, dbFrom(ctx, r.Db).Create(&bookings).Error)
+	})
+}
+
+// Cancel cancels the booking at the given time if it was not changed since it was read, and
+// the following occurrences of its series that are not cancelled when series is set. It
+// returns the cancelled bookings ordered by start.
+func (r *booking) Cancel(ctx context.Context, booking *model.Booking, series bool, at time.Time) ([]model.Booking, error) {
+	var cancelled []model.Booking
+	err := NewTransactor(r.Db).WithinTransaction(ctx, func(ctx context.Context) error {
+		result := dbFrom(ctx, r.Db).Model(&model.Booking{}).
+			Where("id = ? AND version = ?", booking.ID, booking.Version).
+			Updates(map[string]interface{}{
+				"cancelled_at": at,
+				"version":      gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return translateError(r.Db, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrStaleVersion
+		}
+
+		ids := []uint{booking.ID}
+		if series && booking.SeriesID != nil {
+			var following []uint
+			err := dbFrom(ctx, r.Db).Model(&model.Booking{}).
+				Where("series_id = ? AND starts_at > ? AND cancelled_at IS NULL", *booking.SeriesID, booking.StartsAt).
+				Pluck("id", &following).Error
+			if err != nil {
+				return translateError(r.Db, err)
+			}
+			if len(following) > 0 {
+				err := dbFrom(ctx, r.Db).Model(&model.Booking{}).
+					Where("id IN ? AND cancelled_at IS NULL", following).
+					Updates(map[string]interface{}{
+						"cancelled_at": at,
+						"version":      gorm.Expr("version + 1"),
+					}).Error
+				if err != nil {
+					return translateError(r.Db, err)
+				}
+				ids = append(ids, following...)
+			}
+		}
+		return translateError(r.Db, dbFrom(ctx, r.Db).Where("id IN ?", ids).Order("starts_at, id").Find(&cancelled).Error)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cancelled, nil
+}
+
+// overlapping keeps the bookings that are not cancelled and overlap the time from start to end.
+func overlapping(start, end time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("cancelled_at IS NULL AND starts_at < ? AND ends_at > ?", end, start)
+	}
+}
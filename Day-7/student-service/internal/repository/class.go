@@ -14,10 +14,13 @@ import (
 type Class interface {
 	FindAll(ctx context.Context, payload *pkgdto.SearchGetRequest, p *pkgdto.Pagination) ([]model.Class, *pkgdto.PaginationInfo, error)
 	FindByID(ctx context.Context, id uint) (model.Class, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]model.Class, error)
 	Save(ctx context.Context, class *dto.CreateClassRequestBody) (model.Class, error)
 	Edit(ctx context.Context, oldclass *model.Class, updateData *dto.UpdateClassRequestBody) (*model.Class, error)
+	Patch(ctx context.Context, oldClass *model.Class, document *dto.ClassPatchDocument) (*model.Class, error)
 	Destroy(ctx context.Context, class *model.Class) (*model.Class, error)
 	ExistByName(ctx context.Context, name string) (bool, error)
+	ExistByID(ctx context.Context, id uint) (bool, error)
 }
 
 type class struct {
@@ -34,7 +37,7 @@ func (r *class) FindAll(ctx context.Context, payload *pkgdto.SearchGetRequest, p
 	var classes []model.Class
 	var count int64
 
-	query := r.Db.WithContext(ctx).Model(&model.Class{})
+	query := dbFrom(ctx, r.Db).Model(&model.Class{})
 
 	if payload.Search != "" {
 		search := "%" + strings.ToLower(payload.Search) + "%"
@@ -43,49 +46,83 @@ func (r *class) FindAll(ctx context.Context, payload *pkgdto.SearchGetRequest, p
 
 	countQuery := query
 	if err := countQuery.Count(&count).Error; err != nil {
-		return nil, nil, err
+		return nil, nil, translateError(r.Db, err)
 	}
 
 	limit, offset := pkgdto.GetLimitOffset(pagination)
 
 	err := query.Limit(limit).Offset(offset).Find(&classes).Error
 
-	return classes, pkgdto.CheckInfoPagination(pagination, count), err
+	return classes, pkgdto.CheckInfoPagination(pagination, count), translateError(r.Db, err)
 }
 
 func (r *class) FindByID(ctx context.Context, id uint) (model.Class, error) {
 	var class model.Class
-	if err := r.Db.WithContext(ctx).Model(&model.Class{}).Where("id = ?", id).First(&class).Error; err != nil {
-		return class, err
+	if err := dbFrom(ctx, r.Db).Model(&model.Class{}).Where("id = ?", id).First(&class).Error; err != nil {
+		return class, translateError(r.Db, err)
 	}
 	return class, nil
 }
 
+// FindByIDs returns the classes with the ids ordered by id, missing ones are left out.
+func (r *class) FindByIDs(ctx context.Context, ids []uint) ([]model.Class, error) {
+	var classes []model.Class
+	if len(ids) == 0 {
+		return classes, nil
+	}
+	err := dbFrom(ctx, r.Db).Where("id IN ?", ids).Order("id").Find(&classes).Error
+	return classes, translateError(r.Db, err)
+}
+
 func (r *class) Save(ctx context.Context, class *dto.CreateClassRequestBody) (model.Class, error) {
 	newClass := model.Class{
 		Name: *class.Name,
 	}
-	if err := r.Db.WithContext(ctx).Save(&newClass).Error; err != nil {
-		return newClass, err
+	if err := dbFrom(ctx, r.Db).Save(&newClass).Error; err != nil {
+		return newClass, translateError(r.Db, err)
 	}
 	return newClass, nil
 }
 
 func (r *class) Edit(ctx context.Context, oldClass *model.Class, updateData *dto.UpdateClassRequestBody) (*model.Class, error) {
+	updates := map[string]interface{}{}
 	if updateData.Name != nil {
-		oldClass.Name = *updateData.Name
+		updates["name"] = *updateData.Name
 	}
+	return r.update(ctx, oldClass, updates)
+}
 
-	if err := r.Db.WithContext(ctx).Save(oldClass).Find(oldClass).Error; err != nil {
-		return nil, err
+func (r *class) Patch(ctx context.Context, oldClass *model.Class, document *dto.ClassPatchDocument) (*model.Class, error) {
+	return r.update(ctx, oldClass, map[string]interface{}{"name": document.Name})
+}
+
+// update writes updates to oldClass if it was not changed since it was read, and reloads it.
+func (r *class) update(ctx context.Context, oldClass *model.Class, updates map[string]interface{}) (*model.Class, error) {
+	updates["version"] = gorm.Expr("version + 1")
+	result := dbFrom(ctx, r.Db).Model(&model.Class{}).
+		Where("id = ? AND version = ?", oldClass.ID, oldClass.Version).
+		Updates(updates)
+	if result.Error != nil {
+		return nil, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrStaleVersion
+	}
+
+	if err := dbFrom(ctx, r.Db).First(oldClass, oldClass.ID).Error; err != nil {
+		return nil, translateError(r.Db, err)
 	}
 
 	return oldClass, nil
 }
 
 func (r *class) Destroy(ctx context.Context, class *model.Class) (*model.Class, error) {
-	if err := r.Db.WithContext(ctx).Delete(class).Error; err != nil {
-		return nil, err
+	result := dbFrom(ctx, r.Db).Where("version = ?", class.Version).Delete(class)
+	if result.Error != nil {
+		return nil, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrStaleVersion
 	}
 	return class, nil
 }
@@ -95,8 +132,22 @@ func (r *class) ExistByName(ctx context.Context, name string) (bool, error) {
 		count   int64
 		isExist bool
 	)
-	if err := r.Db.WithContext(ctx).Model(&model.Class{}).Where("name = ?", name).Count(&count).Error; err != nil {
-		return isExist, err
+	if err := dbFrom(ctx, r.Db).Model(&model.Class{}).Where("name = ?", name).Count(&count).Error; err != nil {
+		return isExist, translateError(r.Db, err)
+	}
+	if count > 0 {
+		isExist = true
+	}
+	return isExist, nil
+}
+
+func (r *class) ExistByID(ctx context.Context, id uint) (bool, error) {
+	var (
+		count   int64
+		isExist bool
+	)
+	if err := dbFrom(ctx, r.Db).Model(&model.Class{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return isExist, translateError(r.Db, err)
 	}
 	if count > 0 {
 		isExist = true
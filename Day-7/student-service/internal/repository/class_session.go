@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"student-service/internal/dto"
+	"student-service/internal/model"
+	pkgdto "student-service/pkg/dto"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ClassSession interface {
+	FindAll(ctx context.Context, payload *dto.SearchClassSessionRequest, pagination *pkgdto.Pagination) ([]model.ClassSession, *pkgdto.PaginationInfo, error)
+	FindByID(ctx context.Context, id uint) (model.ClassSession, error)
+	Save(ctx context.Context, session *model.ClassSession) error
+	Edit(ctx context.Context, oldSession *model.ClassSession, updateData *model.ClassSession) (*model.ClassSession, error)
+	Destroy(ctx context.Context, session *model.ClassSession) (*model.ClassSession, error)
+	MarkAttendance(ctx context.Context, session *model.ClassSession, attendances []model.Attendance) (*model.ClassSession, error)
+	CountSessions(ctx context.Context, classID, termID uint) (uint, error)
+	CountAttendance(ctx context.Context, termID uint, classID, studentID *uint) ([]AttendanceCount, error)
+}
+
+// AttendanceCount is the number of attendances of a student with a status.
+type AttendanceCount struct {
+	StudentID uint
+	Status    string
+	Count     uint
+}
+
+type classSession struct {
+	Db *gorm.DB
+}
+
+func NewClassSessionRepository(db *gorm.DB) *classSession {
+	return &classSession{
+		db,
+	}
+}
+
+// FindAll returns the sessions ordered by date, without their attendances.
+func (r *classSession) FindAll(ctx context.Context, payload *dto.SearchClassSessionRequest, pagination *pkgdto.Pagination) ([]model.ClassSession, *pkgdto.PaginationInfo, error) {
+	var sessions []model.ClassSession
+	var count int64
+
+	query := dbFrom(ctx, r.Db).Model(&model.ClassSession{})
+
+	if payload.Search != "" {
+		search := "%" + strings.ToLower(payload.Search) + "%"
+		query = query.Where("lower(topic) LIKE ?", search)
+	}
+	if payload.ClassID != nil {
+		query = query.Where("class_id = ?", *payload.ClassID)
+	}
+	if payload.TermID != nil {
+		query = query.Where("term_id = ?", *payload.TermID)
+	}
+
+	countQuery := query
+	if err := countQuery.Count(&count).Error; err != nil {
+		return nil, nil, translateError(r.Db, err)
+	}
+
+	limit, offset := pkgdto.GetLimitOffset(pagination)
+
+	err := query.Order("date, id").Limit(limit).Offset(offset).Find(&sessions).Error
+
+	return sessions, pkgdto.CheckInfoPagination(pagination, count), translateError(r.Db, err)
+}
+
+// FindByID returns the session with its term and its attendances ordered by student.
+func (r *classSession) FindByID(ctx context.Context, id uint) (model.ClassSession, error) {
+	var session model.ClassSession
+	err := dbFrom(ctx, r.Db).Model(&model.ClassSession{}).
+		Preload("Term", unscoped).
+		Preload("Attendances", func(db *gorm.DB) *gorm.DB { return db.Order("student_id") }).
+		Where("id = ?", id).
+		First(&session).Error
+	if err != nil {
+		return session, translateError(r.Db, err)
+	}
+	return session, nil
+}
+
+func (r *classSession) Save(ctx context.Context, session *model.ClassSession) error {
+	return translateError(r.Db, dbFrom(ctx, r.Db).Create(session).Error)
+}
+
+// Edit writes the date and topic of updateData to oldSession.
+func (r *classSession) Edit(ctx context.Context, oldSession *model.ClassSession, updateData *model.ClassSession) (*model.ClassSession, error) {
+	err := r.update(ctx, oldSession, map[string]interface{}{
+		"date":  updateData.Date,
+		"topic": updateData.Topic,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.reload(ctx, oldSession)
+}
+
+func (r *classSession) Destroy(ctx context.Context, session *model.ClassSession) (*model.ClassSession, error) {
+	result := dbFrom(ctx, r.Db).Where("version = ?", session.Version).Delete(session)
+	if result.Error != nil {
+		return nil, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrStaleVersion
+	}
+	return session, nil
+}
+
+// MarkAttendance writes the attendances to the session if it was not changed since it was
+// read, replacing the status and note of students who were already marked. session is
+// reloaded.
+func (r *classSession) MarkAttendance(ctx context.Context, session *model.ClassSession, attendances []model.Attendance) (*model.ClassSession, error) {
+	err := NewTransactor(r.Db).WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := r.update(ctx, session, map[string]interface{}{}); err != nil {
+			return err
+		}
+		for i := range attendances {
+			attendances[i].SessionID = session.ID
+		}
+		err := dbFrom(ctx, r.Db).
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "session_id"}, {Name: "student_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"status", "note", "updated_at"}),
+			}).
+			Create(&attendances).Error
+		return translateError(r.Db, err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.reload(ctx, session)
+}
+
+// update writes updates to oldSession if it was not changed since it was read.
+func (r *classSession) update(ctx context.Context, oldSession *model.ClassSession, updates map[string]interface{}) error {
+	updates["version"] = gorm.Expr("version + 1")
+	result := dbFrom(ctx, r.Db).Model(&model.ClassSession{}).
+		Where("id = ? AND version = ?", oldSession.ID, oldSession.Version).
+		Updates(updates)
+	if result.Error != nil {
+		return translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrStaleVersion
+	}
+	return nil
+}
+
+func (r *classSession) reload(ctx context.Context, session *model.ClassSession) (*model.ClassSession, error) {
+	reloaded, err := r.FindByID(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	*session = reloaded
+	return session, nil
+}
+
+// CountSessions returns the number of sessions of the class in the term.
+func (r *classSession) CountSessions(ctx context.Context, classID, termID uint) (uint, error) {
+	var count int64
+	err := dbFrom(ctx, r.Db).Model(&model.ClassSession{}).
+		Where("class_id = ? AND term_id = ?", classID, termID).
+		Count(&count).Error
+	return uint(count), translateError(r.Db, err)
+}
+
+// CountAttendance counts the attendances in the sessions of the term by student and status,
+// only in the sessions of the class when classID is set and only of the student when
+// studentID is set. Attendances in deleted sessions are left out.
+func (r *classSession) CountAttendance(ctx context.Context, termID uint, classID, studentID *uint) ([]AttendanceCount, error) {
+	var counts []AttendanceCount
+	query := dbFrom(ctx, r.Db).Model(&model.Attendance{}).
+		Select("attendances.student_id, attendances.status, COUNT(*) AS count").
+		Joins("JOIN class_sessions ON class_sessions.id = attendances.session_id AND class_sessions.deleted_at IS NULL").
+		Where("class_sessions.term_id = ?", termID)
+	if classID != nil {
+		query = query.Where("class_sessions.class_id = ?", *classID)
+	}
+	if studentID != nil {
+		query = query.Where("attendances.student_id = ?", *studentID)
+	}
+	err := query.Group("attendances.student_id, attendances.status").Scan(&counts).Error
+	return counts, translateError(r.Db, err)
+}
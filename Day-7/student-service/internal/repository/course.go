@@ -0,0 +1,320 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"student-service/internal/dto"
+	"student-service/internal/model"
+	pkgdto "student-service/pkg/dto"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Course interface {
+	FindAll(ctx context.Context, payload *dto.SearchCourseRequest, pagination *pkgdto.Pagination) ([]model.Course, *pkgdto.PaginationInfo, error)
+	FindByID(ctx context.Context, id uint) (model.Course, error)
+	Save(ctx context.Context, course *dto.CreateCourseRequestBody) (model.Course, error)
+	Edit(ctx context.Context, oldCourse *model.Course, updateData *dto.UpdateCourseRequestBody) (*model.Course, error)
+	Destroy(ctx context.Context, course *model.Course) (*model.Course, error)
+	ExistByID(ctx context.Context, id uint) (bool, error)
+	ExistByCodeExceptID(ctx context.Context, code string, exceptID uint) (bool, error)
+	CountEnrollments(ctx context.Context, termID uint, courseIDs ...uint) (map[uint]uint, error)
+	MaxEnrolled(ctx context.Context, courseID uint) (uint, error)
+	Enroll(ctx context.Context, courseID, studentID, termID uint) (model.Enrollment, error)
+	Withdraw(ctx context.Context, courseID, studentID, termID uint) error
+	FindEnrollmentsByCourseID(ctx context.Context, courseID, termID uint) ([]model.Enrollment, error)
+	FindEnrollmentsByStudentID(ctx context.Context, studentID uint, termID *uint) ([]model.Enrollment, error)
+}
+
+type course struct {
+	Db *gorm.DB
+}
+
+func NewCourseRepository(db *gorm.DB) *course {
+	return &course{
+		db,
+	}
+}
+
+func (r *course) FindAll(ctx context.Context, payload *dto.SearchCourseRequest, pagination *pkgdto.Pagination) ([]model.Course, *pkgdto.PaginationInfo, error) {
+	var courses []model.Course
+	var count int64
+
+	query := dbFrom(ctx, r.Db).Model(&model.Course{})
+
+	if payload.Search != "" {
+		search := "%" + strings.ToLower(payload.Search) + "%"
+		query = query.Where("lower(code) LIKE ? or lower(name) LIKE ?", search, search)
+	}
+	if payload.MajorID != nil {
+		query = query.Where("major_id = ?", *payload.MajorID)
+	}
+
+	countQuery := query
+	if err := countQuery.Count(&count).Error; err != nil {
+		return nil, nil, translateError(r.Db, err)
+	}
+
+	limit, offset := pkgdto.GetLimitOffset(pagination)
+
+	err := query.Limit(limit).Offset(offset).Find(&courses).Error
+
+	return courses, pkgdto.CheckInfoPagination(pagination, count), translateError(r.Db, err)
+}
+
+func (r *course) FindByID(ctx context.Context, id uint) (model.Course, error) {
+	var course model.Course
+	if err := dbFrom(ctx, r.Db).Model(&model.Course{}).Where("id = ?", id).First(&course).Error; err != nil {
+		return course, translateError(r.Db, err)
+	}
+	return course, nil
+}
+
+func (r *course) Save(ctx context.Context, course *dto.CreateCourseRequestBody) (model.Course, error) {
+	newCourse := model.Course{
+		Code:     *course.Code,
+		Name:     *course.Name,
+		MajorID:  *course.MajorID,
+		Credits:  *course.Credits,
+		Capacity: *course.Capacity,
+	}
+	if err := dbFrom(ctx, r.Db).Save(&newCourse).Error; err != nil {
+		return newCourse, translateError(r.Db, err)
+	}
+	return newCourse, nil
+}
+
+func (r *course) Edit(ctx context.Context, oldCourse *model.Course, updateData *dto.UpdateCourseRequestBody) (*model.Course, error) {
+	updates := map[string]interface{}{}
+	if updateData.Code != nil {
+		updates["code"] = *updateData.Code
+	}
+	if updateData.Name != nil {
+		updates["name"] = *updateData.Name
+	}
+	if updateData.MajorID != nil {
+		updates["major_id"] = *updateData.MajorID
+	}
+	if updateData.Credits != nil {
+		updates["credits"] = *updateData.Credits
+	}
+	if updateData.Capacity != nil {
+		updates["capacity"] = *updateData.Capacity
+	}
+	return r.update(ctx, oldCourse, updates)
+}
+
+// update writes updates to oldCourse if it was not changed since it was read, and reloads it.
+// Enrollments change the version too, so a capacity checked against MaxEnrolled is never
+// written below the current number of enrollments.
+func (r *course) update(ctx context.Context, oldCourse *model.Course, updates map[string]interface{}) (*model.Course, error) {
+	updates["version"] = gorm.Expr("version + 1")
+	result := dbFrom(ctx, r.Db).Model(&model.Course{}).
+		Where("id = ? AND version = ?", oldCourse.ID, oldCourse.Version).
+		Updates(updates)
+	if result.Error != nil {
+		return nil, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrStaleVersion
+	}
+
+	if err := dbFrom(ctx, r.Db).First(oldCourse, oldCourse.ID).Error; err != nil {
+		return nil, translateError(r.Db, err)
+	}
+
+	return oldCourse, nil
+}
+
+func (r *course) Destroy(ctx context.Context, course *model.Course) (*model.Course, error) {
+	result := dbFrom(ctx, r.Db).Where("version = ?", course.Version).Delete(course)
+	if result.Error != nil {
+		return nil, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrStaleVersion
+	}
+	return course, nil
+}
+
+func (r *course) ExistByID(ctx context.Context, id uint) (bool, error) {
+	var (
+		count   int64
+		isExist bool
+	)
+	if err := dbFrom(ctx, r.Db).Model(&model.Course{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return isExist, translateError(r.Db, err)
+	}
+	if count > 0 {
+		isExist = true
+	}
+	return isExist, nil
+}
+
+func (r *course) ExistByCodeExceptID(ctx context.Context, code string, exceptID uint) (bool, error) {
+	var (
+		count   int64
+		isExist bool
+	)
+	query := dbFrom(ctx, r.Db).Model(&model.Course{}).Where("code = ?", code)
+	if exceptID != 0 {
+		query = query.Where("id <> ?", exceptID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return isExist, translateError(r.Db, err)
+	}
+	if count > 0 {
+		isExist = true
+	}
+	return isExist, nil
+}
+
+// CountEnrollments returns the number of enrollments in the term of each of the courses.
+// Courses without enrollments are left out.
+func (r *course) CountEnrollments(ctx context.Context, termID uint, courseIDs ...uint) (map[uint]uint, error) {
+	var rows []struct {
+		CourseID uint
+		Enrolled uint
+	}
+	counts := map[uint]uint{}
+	if len(courseIDs) == 0 {
+		return counts, nil
+	}
+	err := dbFrom(ctx, r.Db).Model(&model.Enrollment{}).
+		Select("course_id, COUNT(*) AS enrolled").
+		Where("term_id = ? AND course_id IN ?", termID, courseIDs).
+		Group("course_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, translateError(r.Db, err)
+	}
+	for _, row := range rows {
+		counts[row.CourseID] = row.Enrolled
+	}
+	return counts, nil
+}
+
+// MaxEnrolled returns the largest number of enrollments of the course in a term that is not
+// closed.
+func (r *course) MaxEnrolled(ctx context.Context, courseID uint) (uint, error) {
+	var rows []struct {
+		Enrolled uint
+	}
+	err := dbFrom(ctx, r.Db).Model(&model.Enrollment{}).
+		Select("COUNT(*) AS enrolled").
+		Joins("JOIN terms ON terms.id = enrollments.term_id AND terms.closed = ?", false).
+		Where("enrollments.course_id = ?", courseID).
+		Group("enrollments.term_id").
+		Scan(&rows).Error
+	if err != nil {
+		return 0, translateError(r.Db, err)
+	}
+	var max uint
+	for _, row := range rows {
+		if row.Enrolled > max {
+			max = row.Enrolled
+		}
+	}
+	return max, nil
+}
+
+// Enroll takes a seat of the course in the term and records the enrollment in one
+// transaction. The course row is locked first by bumping its version, so concurrent
+// enrollments in the course are counted one after another and never exceed the capacity.
+// It returns ErrDuplicate when the student is already enrolled, and ErrCapacityExceeded when
+// the course is full otherwise.
+func (r *course) Enroll(ctx context.Context, courseID, studentID, termID uint) (model.Enrollment, error) {
+	enrollment := model.Enrollment{CourseID: courseID, StudentID: studentID, TermID: termID}
+	err := NewTransactor(r.Db).WithinTransaction(ctx, func(ctx context.Context) error {
+		course, err := r.lock(ctx, courseID)
+		if err != nil {
+			return err
+		}
+
+		var enrollments []model.Enrollment
+		err = dbFrom(ctx, r.Db).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("course_id = ? AND term_id = ?", courseID, termID).
+			Find(&enrollments).Error
+		if err != nil {
+			return translateError(r.Db, err)
+		}
+		for _, other := range enrollments {
+			if other.StudentID == studentID {
+				return ErrDuplicate
+			}
+		}
+		if uint(len(enrollments)) >= course.Capacity {
+			return ErrCapacityExceeded
+		}
+
+		return translateError(r.Db, dbFrom(ctx, r.Db).Create(&enrollment).Error)
+	})
+	return enrollment, err
+}
+
+// Withdraw deletes the enrollment in the term, freeing its seat.
+func (r *course) Withdraw(ctx context.Context, courseID, studentID, termID uint) error {
+	return NewTransactor(r.Db).WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := r.lock(ctx, courseID); err != nil {
+			return err
+		}
+
+		result := dbFrom(ctx, r.Db).
+			Where("course_id = ? AND student_id = ? AND term_id = ?", courseID, studentID, termID).
+			Delete(&model.Enrollment{})
+		if result.Error != nil {
+			return translateError(r.Db, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return &Error{Kind: ErrNotFound, Err: gorm.ErrRecordNotFound}
+		}
+		return nil
+	})
+}
+
+// lock bumps the version of the course, which locks its row until the end of the transaction,
+// and returns the course.
+func (r *course) lock(ctx context.Context, courseID uint) (model.Course, error) {
+	result := dbFrom(ctx, r.Db).Model(&model.Course{}).
+		Where("id = ?", courseID).
+		UpdateColumn("version", gorm.Expr("version + 1"))
+	if result.Error != nil {
+		return model.Course{}, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return model.Course{}, &Error{Kind: ErrNotFound, Err: gorm.ErrRecordNotFound}
+	}
+	return r.FindByID(ctx, courseID)
+}
+
+// FindEnrollmentsByCourseID returns the enrollments of the course in the term with their
+// students, enrollments of deleted students are left out.
+func (r *course) FindEnrollmentsByCourseID(ctx context.Context, courseID, termID uint) ([]model.Enrollment, error) {
+	var enrollments []model.Enrollment
+	err := dbFrom(ctx, r.Db).
+		Joins("JOIN students ON students.id = enrollments.student_id AND students.deleted_at IS NULL").
+		Preload("Student").
+		Where("enrollments.course_id = ? AND enrollments.term_id = ?", courseID, termID).
+		Order("enrollments.id").
+		Find(&enrollments).Error
+	return enrollments, translateError(r.Db, err)
+}
+
+// FindEnrollmentsByStudentID returns the enrollments of the student with their courses and
+// terms, only those in the term when termID is set. Enrollments in deleted courses are left out.
+func (r *course) FindEnrollmentsByStudentID(ctx context.Context, studentID uint, termID *uint) ([]model.Enrollment, error) {
+	var enrollments []model.Enrollment
+	query := dbFrom(ctx, r.Db).
+		Joins("JOIN courses ON courses.id = enrollments.course_id AND courses.deleted_at IS NULL").
+		Preload("Course").
+		Preload("Term", unscoped).
+		Where("enrollments.student_id = ?", studentID)
+	if termID != nil {
+		query = query.Where("enrollments.term_id = ?", *termID)
+	}
+	err := query.Order("enrollments.id").Find(&enrollments).Error
+	return enrollments, translateError(r.Db, err)
+}
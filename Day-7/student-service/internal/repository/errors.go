@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Typed errors returned by the repositories, check them with errors.Is.
+// The driver error stays available through errors.Unwrap. ErrStaleVersion is returned when
+// an update or delete conditional on the record version matched no row, ErrCapacityExceeded
+// when a course has no seat left, ErrBookingConflict when a room is already booked.
+var (
+	ErrNotFound            = errors.New("record not found")
+	ErrDuplicate           = errors.New("duplicate record")
+	ErrForeignKeyViolation = errors.New("foreign key violation")
+	ErrRetryable           = errors.New("retryable error")
+	ErrStaleVersion        = errors.New("record version is stale")
+	ErrCapacityExceeded    = errors.New("capacity exceeded")
+	ErrBookingConflict     = errors.New("booking conflict")
+)
+
+// Error is a driver error translated to one of the typed errors.
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
+
+// ErrorTranslator maps the errors of one database driver to the typed errors.
+// Translate returns nil when it does not know err.
+type ErrorTranslator interface {
+	Translate(err error) error
+}
+
+// translators is keyed by gorm dialector name.
+var translators = map[string]ErrorTranslator{
+	"mysql": mysqlErrorTranslator{},
+}
+
+// RegisterErrorTranslator sets the translator used for the given gorm dialector name.
+func RegisterErrorTranslator(dialect string, translator ErrorTranslator) {
+	translators[dialect] = translator
+}
+
+// translateError wraps err in an *Error when it is known for the dialect of db,
+// and returns it unchanged otherwise.
+func translateError(db *gorm.DB, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &Error{Kind: ErrNotFound, Err: err}
+	}
+	if translator, ok := translators[db.Dialector.Name()]; ok {
+		if kind := translator.Translate(err); kind != nil {
+			return &Error{Kind: kind, Err: err}
+		}
+	}
+	return err
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+var mysqlErrors = map[uint16]error{
+	1062: ErrDuplicate,           // ER_DUP_ENTRY
+	1169: ErrDuplicate,           // ER_DUP_UNIQUE
+	1216: ErrForeignKeyViolation, // ER_NO_REFERENCED_ROW
+	1217: ErrForeignKeyViolation, // ER_ROW_IS_REFERENCED
+	1451: ErrForeignKeyViolation, // ER_ROW_IS_REFERENCED_2
+	1452: ErrForeignKeyViolation, // ER_NO_REFERENCED_ROW_2
+	1205: ErrRetryable,           // ER_LOCK_WAIT_TIMEOUT
+	1213: ErrRetryable,           // ER_LOCK_DEADLOCK
+}
+
+type mysqlErrorTranslator struct{}
+
+func (mysqlErrorTranslator) Translate(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return nil
+	}
+	return mysqlErrors[mysqlErr.Number]
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	gormmysql "gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+var testMysqlDB = &gorm.DB{Config: &gorm.Config{Dialector: gormmysql.Dialector{}}}
+
+func TestTranslateErrorMysql(t *testing.T) {
+	cases := []struct {
+		number uint16
+		kind   error
+	}{
+		{1062, ErrDuplicate},
+		{1451, ErrForeignKeyViolation},
+		{1452, ErrForeignKeyViolation},
+		{1213, ErrRetryable},
+		{1205, ErrRetryable},
+	}
+	for _, c := range cases {
+		driverErr := &mysql.MySQLError{Number: c.number, Message: "driver message"}
+		err := translateError(testMysqlDB, fmt.Errorf("save: %w", driverErr))
+
+		assert.True(t, errors.Is(err, c.kind), "error %d", c.number)
+		var unwrapped *mysql.MySQLError
+		if assert.True(t, errors.As(err, &unwrapped)) {
+			assert.Equal(t, c.number, unwrapped.Number)
+		}
+	}
+}
+
+func TestTranslateErrorRecordNotFound(t *testing.T) {
+	err := translateError(testMysqlDB, gorm.ErrRecordNotFound)
+
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
+
+func TestTranslateErrorUnknown(t *testing.T) {
+	var (
+		unknownNumber = &mysql.MySQLError{Number: 1045, Message: "access denied"}
+		other         = errors.New("connection refused")
+	)
+
+	assert.Same(t, unknownNumber, translateError(testMysqlDB, unknownNumber))
+	assert.Same(t, other, translateError(testMysqlDB, other))
+	assert.Nil(t, translateError(testMysqlDB, nil))
+}
+
+type testErrorTranslator struct{}
+
+func (testErrorTranslator) Translate(err error) error {
+	return ErrRetryable
+}
+
+func TestTranslateErrorPerDialect(t *testing.T) {
+	RegisterErrorTranslator("test", testErrorTranslator{})
+	defer delete(translators, "test")
+
+	db := &gorm.DB{Config: &gorm.Config{Dialector: testDialector{}}}
+	assert.True(t, errors.Is(translateError(db, errors.New("busy")), ErrRetryable))
+	assert.False(t, errors.Is(translateError(testMysqlDB, errors.New("busy")), ErrRetryable))
+}
+
+type testDialector struct {
+	gorm.Dialector
+}
+
+func (testDialector) Name() string {
+	return "test"
+}
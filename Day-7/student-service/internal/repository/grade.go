@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+
+	"student-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type Grade interface {
+	FindByID(ctx context.Context, id uint) (model.Grade, error)
+	FindByStudentID(ctx context.Context, studentID uint) ([]model.Grade, error)
+	Save(ctx context.Context, grade *model.Grade) error
+	Edit(ctx context.Context, oldGrade *model.Grade, updateData *model.Grade) (*model.Grade, error)
+	Destroy(ctx context.Context, grade *model.Grade) (*model.Grade, error)
+}
+
+type grade struct {
+	Db *gorm.DB
+}
+
+func NewGradeRepository(db *gorm.DB) *grade {
+	return &grade{
+		db,
+	}
+}
+
+func (r *grade) FindByID(ctx context.Context, id uint) (model.Grade, error) {
+	var grade model.Grade
+	err := dbFrom(ctx, r.Db).Model(&model.Grade{}).
+		Preload("Term", unscoped).
+		Preload("Assessments", orderByID).
+		Where("id = ?", id).
+		First(&grade).Error
+	if err != nil {
+		return grade, translateError(r.Db, err)
+	}
+	return grade, nil
+}
+
+// FindByStudentID returns the grades of the student ordered by the start of their term, with
+// their courses and terms even when those were deleted since.
+func (r *grade) FindByStudentID(ctx context.Context, studentID uint) ([]model.Grade, error) {
+	var grades []model.Grade
+	err := dbFrom(ctx, r.Db).Model(&model.Grade{}).
+		Joins("JOIN terms ON terms.id = grades.term_id").
+		Preload("Course", unscoped).
+		Preload("Term", unscoped).
+		Preload("Assessments", orderByID).
+		Where("grades.student_id = ?", studentID).
+		Order("terms.start_date, grades.term_id, grades.id").
+		Find(&grades).Error
+	return grades, translateError(r.Db, err)
+}
+
+// Save creates the grade with its assessments.
+func (r *grade) Save(ctx context.Context, grade *model.Grade) error {
+	return translateError(r.Db, dbFrom(ctx, r.Db).Create(grade).Error)
+}
+
+// Edit writes the score and letter of updateData to oldGrade if it was not changed since it
+// was read. Its assessments are replaced unless updateData.Assessments is nil, an empty slice
+// removes them. oldGrade is reloaded.
+func (r *grade) Edit(ctx context.Context, oldGrade *model.Grade, updateData *model.Grade) (*model.Grade, error) {
+	err := NewTransactor(r.Db).WithinTransaction(ctx, func(ctx context.Context) error {
+		result := dbFrom(ctx, r.Db).Model(&model.Grade{}).
+			Where("id = ? AND version = ?", oldGrade.ID, oldGrade.Version).
+			Updates(map[string]interface{}{
+				"score":   updateData.Score,
+				"letter":  updateData.Letter,
+				"version": gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return translateError(r.Db, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrStaleVersion
+		}
+
+		if updateData.Assessments == nil {
+			return nil
+		}
+		if err := dbFrom(ctx, r.Db).Where("grade_id = ?", oldGrade.ID).Delete(&model.Assessment{}).Error; err != nil {
+			return translateError(r.Db, err)
+		}
+		if len(updateData.Assessments) == 0 {
+			return nil
+		}
+		for i := range updateData.Assessments {
+			updateData.Assessments[i].GradeID = oldGrade.ID
+		}
+		return translateError(r.Db, dbFrom(ctx, r.Db).Create(&updateData.Assessments).Error)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reloaded, err := r.FindByID(ctx, oldGrade.ID)
+	if err != nil {
+		return nil, err
+	}
+	*oldGrade = reloaded
+	return oldGrade, nil
+}
+
+func (r *grade) Destroy(ctx context.Context, grade *model.Grade) (*model.Grade, error) {
+	result := dbFrom(ctx, r.Db).Where("version = ?", grade.Version).Delete(grade)
+	if result.Error != nil {
+		return nil, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrStaleVersion
+	}
+	return grade, nil
+}
+
+func orderByID(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
+
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"student-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type IdempotencyKey interface {
+	Create(ctx context.Context, record *model.IdempotencyKey) error
+	Find(ctx context.Context, key string, userID uint, route string) (model.IdempotencyKey, error)
+	Complete(ctx context.Context, record *model.IdempotencyKey) error
+	Renew(ctx context.Context, id uint) error
+	Delete(ctx context.Context, record *model.IdempotencyKey) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type idempotencyKey struct {
+	Db *gorm.DB
+}
+
+func NewIdempotencyKeyRepository(db *gorm.DB) *idempotencyKey {
+	return &idempotencyKey{
+		db,
+	}
+}
+
+// Create inserts record, it returns ErrDuplicate when the key is already used by the user
+// on the route.
+func (r *idempotencyKey) Create(ctx context.Context, record *model.IdempotencyKey) error {
+	return translateError(r.Db, dbFrom(ctx, r.Db).Create(record).Error)
+}
+
+func (r *idempotencyKey) Find(ctx context.Context, key string, userID uint, route string) (model.IdempotencyKey, error) {
+	var record model.IdempotencyKey
+	err := dbFrom(ctx, r.Db).
+		Where("`key` = ? AND user_id = ? AND route = ?", key, userID, route).
+		First(&record).
+		Error
+	return record, translateError(r.Db, err)
+}
+
+// Complete stores the response of record.
+func (r *idempotencyKey) Complete(ctx context.Context, record *model.IdempotencyKey) error {
+	err := dbFrom(ctx, r.Db).Model(record).
+		Select("status", "header", "body", "updated_at").
+		Updates(record).
+		Error
+	return translateError(r.Db, err)
+}
+
+// Renew marks the key with the given id as still being processed by its request.
+func (r *idempotencyKey) Renew(ctx context.Context, id uint) error {
+	err := dbFrom(ctx, r.Db).Model(&model.IdempotencyKey{}).
+		Where("id = ? AND status = 0", id).
+		Update("updated_at", time.Now()).
+		Error
+	return translateError(r.Db, err)
+}
+
+// Delete removes record, unless it was replaced by another request in the meantime.
+func (r *idempotencyKey) Delete(ctx context.Context, record *model.IdempotencyKey) error {
+	err := dbFrom(ctx, r.Db).
+		Where("id = ?", record.ID).
+		Delete(&model.IdempotencyKey{}).
+		Error
+	return translateError(r.Db, err)
+}
+
+func (r *idempotencyKey) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := dbFrom(ctx, r.Db).Where("expires_at <= ?", now).Delete(&model.IdempotencyKey{})
+	return result.RowsAffected, translateError(r.Db, result.Error)
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"student-service/internal/dto"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	pkgdto "student-service/pkg/dto"
+
+	"gorm.io/gorm"
+)
+
+type Job interface {
+	FindAll(ctx context.Context, payload *dto.SearchJobRequest) ([]model.Job, *pkgdto.PaginationInfo, error)
+	FindByID(ctx context.Context, id uint) (model.Job, error)
+	CountByStatus(ctx context.Context) (map[string]int64, error)
+	Save(ctx context.Context, job *model.Job) error
+	Claim(ctx context.Context, owner string, now time.Time, visibility time.Duration) (*model.Job, error)
+	Edit(ctx context.Context, job *model.Job) error
+	Retry(ctx context.Context, job *model.Job, now time.Time) error
+	PurgeSucceeded(ctx context.Context, before time.Time) (int64, error)
+}
+
+type job struct {
+	Db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) *job {
+	return &job{
+		db,
+	}
+}
+
+func (r *job) FindAll(ctx context.Context, payload *dto.SearchJobRequest) ([]model.Job, *pkgdto.PaginationInfo, error) {
+	var jobs []model.Job
+	var count int64
+
+	query := dbFrom(ctx, r.Db).Model(&model.Job{})
+	if payload.Status != "" {
+		query = query.Where("status = ?", payload.Status)
+	}
+	if payload.Type != "" {
+		query = query.Where("type = ?", payload.Type)
+	}
+	countQuery := query
+	if err := countQuery.Count(&count).Error; err != nil {
+		return nil, nil, translateError(r.Db, err)
+	}
+
+	limit, offset := pkgdto.GetLimitOffset(&payload.Pagination)
+	err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&jobs).Error
+
+	return jobs, pkgdto.CheckInfoPagination(&payload.Pagination, count), translateError(r.Db, err)
+}
+
+func (r *job) FindByID(ctx context.Context, id uint) (model.Job, error) {
+	var job model.Job
+	err := dbFrom(ctx, r.Db).Where("id = ?", id).First(&job).Error
+	return job, translateError(r.Db, err)
+}
+
+// CountByStatus returns how many jobs have each status, statuses without jobs are left out.
+func (r *job) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := dbFrom(ctx, r.Db).Model(&model.Job{}).Select("status, COUNT(*) AS count").Group("status").Scan(&rows).Error
+	if err != nil {
+		return nil, translateError(r.Db, err)
+	}
+
+	counts := map[string]int64{}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// Save enqueues the job, with the transaction of ctx so that it only runs when the change that
+// asked for it is committed.
+func (r *job) Save(ctx context.Context, job *model.Job) error {
+	return translateError(r.Db, dbFrom(ctx, r.Db).Create(job).Error)
+}
+
+// Claim takes the oldest job that is due at now, or whose worker did not finish it within its
+// visibility timeout, for owner until now plus visibility and counts the attempt. It returns nil
+// when no job is due.
+func (r *job) Claim(ctx context.Context, owner string, now time.Time, visibility time.Duration) (*model.Job, error) {
+	for {
+		var due model.Job
+		result := dbFrom(ctx, r.Db).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?)", enum.JobPending, now, enum.JobRunning, now).
+			Order("id").
+			Limit(1).
+			Find(&due)
+		if result.Error != nil {
+			return nil, translateError(r.Db, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil, nil
+		}
+
+		lockedUntil := now.Add(visibility)
+		// the attempts tell whether another worker claimed the job since it was read
+		result = dbFrom(ctx, r.Db).Model(&model.Job{}).
+			Where("id = ? AND status = ? AND attempts = ?", due.ID, due.Status, due.Attempts).
+			Updates(map[string]interface{}{
+				"status":       enum.JobRunning,
+				"attempts":     due.Attempts + 1,
+				"locked_by":    owner,
+				"locked_until": lockedUntil,
+			})
+		if result.Error != nil {
+			return nil, translateError(r.Db, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		due.Status = string(enum.JobRunning)
+		due.Attempts++
+		due.LockedBy = owner
+		due.LockedUntil = &lockedUntil
+		return &due, nil
+	}
+}
+
+// Edit writes the outcome of the attempt of job. It returns ErrStaleVersion when the job was
+// claimed again since, its visibility timeout having passed.
+func (r *job) Edit(ctx context.Context, job *model.Job) error {
+	result := dbFrom(ctx, r.Db).Model(&model.Job{}).
+		Where("id = ? AND status = ? AND attempts = ? AND locked_by = ?", job.ID, enum.JobRunning, job.Attempts, job.LockedBy).
+		Updates(map[string]interface{}{
+			"status":       job.Status,
+			"run_at":       job.RunAt,
+			"locked_by":    "",
+			"locked_until": nil,
+			"last_error":   job.LastError,
+			"finished_at":  job.FinishedAt,
+		})
+	if result.Error != nil {
+		return translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrStaleVersion
+	}
+	job.LockedBy = ""
+	job.LockedUntil = nil
+	return nil
+}
+
+// Retry makes the dead job pending again from now, with all its attempts. It returns
+// ErrStaleVersion when the job is no longer dead.
+func (r *job) Retry(ctx context.Context, job *model.Job, now time.Time) error {
+	result := dbFrom(ctx, r.Db).Model(&model.Job{}).
+		Where("id = ? AND status = ?", job.ID, enum.JobDead).
+		Updates(map[string]interface{}{
+			"status":      enum.JobPending,
+			"attempts":    0,
+			"run_at":      now,
+			"finished_at": nil,
+		})
+	if result.Error != nil {
+		return translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrStaleVersion
+	}
+	return translateError(r.Db, dbFrom(ctx, r.Db).First(job, job.ID).Error)
+}
+
+// PurgeSucceeded deletes the jobs that succeeded before, and returns how many it deleted. Dead
+// jobs are kept until they are retried.
+func (r *job) PurgeSucceeded(ctx context.Context, before time.Time) (int64, error) {
+	result := dbFrom(ctx, r.Db).Where("status = ? AND finished_at < ?", enum.JobSucceeded, before).Delete(&model.Job{})
+	return result.RowsAffected, translateError(r.Db, result.Error)
+}
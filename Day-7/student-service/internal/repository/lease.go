@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"student-service/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Lease interface {
+	Acquire(ctx context.Context, name, owner string, now time.Time, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, name, owner string) error
+}
+
+type lease struct {
+	Db *gorm.DB
+}
+
+func NewLeaseRepository(db *gorm.DB) *lease {
+	return &lease{
+		db,
+	}
+}
+
+// Acquire takes the lease for ttl from now and reports whether owner holds it. The owner renews
+// it by acquiring it again, other owners get it once it expired.
+func (r *lease) Acquire(ctx context.Context, name, owner string, now time.Time, ttl time.Duration) (bool, error) {
+	// a taken lease is left as it is, rather than failing and logging a duplicate key on every try
+	result := dbFrom(ctx, r.Db).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.Lease{Name: name, Owner: owner, ExpiresAt: now.Add(ttl)})
+	if result.Error != nil {
+		return false, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 1 {
+		return true, nil
+	}
+
+	result = dbFrom(ctx, r.Db).Model(&model.Lease{}).
+		Where("name = ? AND (owner = ? OR expires_at < ?)", name, owner, now).
+		Updates(map[string]interface{}{"owner": owner, "expires_at": now.Add(ttl)})
+	return result.RowsAffected == 1, translateError(r.Db, result.Error)
+}
+
+// Release gives the lease up if owner holds it, so another owner does not wait for it to expire.
+func (r *lease) Release(ctx context.Context, name, owner string) error {
+	err := dbFrom(ctx, r.Db).Where("name = ? AND owner = ?", name, owner).Delete(&model.Lease{}).Error
+	return translateError(r.Db, err)
+}
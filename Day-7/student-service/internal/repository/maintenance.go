@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type Maintenance interface {
+	PurgeDeleted(ctx context.Context, before time.Time) (map[string]int64, int64, error)
+}
+
+type owned struct {
+	table  string
+	column string
+}
+
+// purgeable are the tables whose rows are soft deleted, with the tables of the rows they own.
+// Rows referencing others come first, so that those are no longer referenced when their turn
+// comes. Attachments are deleted for good right away.
+var purgeable = []struct {
+	table string
+	owned []owned
+}{
+	{"grades", []owned{{"assessments", "grade_id"}}},
+	{"bookings", nil},
+	{"class_sessions", []owned{{"attendances", "session_id"}}},
+	{"webhook_subscriptions", []owned{{"webhook_deliveries", "subscription_id"}}},
+	{"students", nil},
+	{"courses", nil},
+	{"terms", nil},
+	{"rooms", []owned{{"room_facilities", "room_id"}}},
+	{"classes", nil},
+	{"majors", nil},
+}
+
+type maintenance struct {
+	Db *gorm.DB
+}
+
+func NewMaintenanceRepository(db *gorm.DB) *maintenance {
+	return &maintenance{
+		db,
+	}
+}
+
+// PurgeDeleted deletes for good the rows soft deleted before, with the rows they own. It returns
+// how many rows it deleted per table, and how many it kept because other rows still reference
+// them, such as the course of a grade in a transcript.
+func (r *maintenance) PurgeDeleted(ctx context.Context, before time.Time) (map[string]int64, int64, error) {
+	purged := map[string]int64{}
+	var kept int64
+	transactor := NewTransactor(r.Db)
+	for _, t := range purgeable {
+		var ids []uint
+		if err := dbFrom(ctx, r.Db).Table(t.table).Where("deleted_at < ?", before).Order("id").Pluck("id", &ids).Error; err != nil {
+			return purged, kept, translateError(r.Db, err)
+		}
+
+		for _, id := range ids {
+			var deleted int64
+			// a row at a time, so that a referenced row only keeps itself
+			err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				db := dbFrom(ctx, r.Db)
+				for _, o := range t.owned {
+					if err := db.Exec("DELETE FROM "+o.table+" WHERE "+o.column+" = ?", id).Error; err != nil {
+						return translateError(r.Db, err)
+					}
+				}
+				// the row may have been restored since it was read. A referenced row is expected, its
+				// error is not logged, other errors are returned.
+				result := db.Session(&gorm.Session{Logger: r.Db.Logger.LogMode(logger.Silent)}).Exec("DELETE FROM "+t.table+" WHERE id = ? AND deleted_at < ?", id, before)
+				deleted = result.RowsAffected
+				return translateError(r.Db, result.Error)
+			})
+			if errors.Is(err, ErrForeignKeyViolation) {
+				kept++
+				continue
+			}
+			if err != nil {
+				return purged, kept, err
+			}
+			purged[t.table] += deleted
+		}
+	}
+	return purged, kept, nil
+}
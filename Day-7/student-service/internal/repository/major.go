@@ -14,10 +14,13 @@ import (
 type Major interface {
 	FindAll(ctx context.Context, payload *pkgdto.SearchGetRequest, pagination *pkgdto.Pagination) ([]model.Major, *pkgdto.PaginationInfo, error)
 	FindByID(ctx context.Context, id uint) (model.Major, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]model.Major, error)
 	Save(ctx context.Context, major *dto.CreateMajorRequestBody) (model.Major, error)
 	Edit(ctx context.Context, oldStudent *model.Major, updateData *dto.UpdateMajorRequestBody) (*model.Major, error)
+	Patch(ctx context.Context, oldMajor *model.Major, document *dto.MajorPatchDocument) (*model.Major, error)
 	Destroy(ctx context.Context, major *model.Major) (*model.Major, error)
 	ExistByName(ctx context.Context, name string) (bool, error)
+	ExistByID(ctx context.Context, id uint) (bool, error)
 }
 
 type major struct {
@@ -34,7 +37,7 @@ func (r *major) FindAll(ctx context.Context, payload *pkgdto.SearchGetRequest, p
 	var majors []model.Major
 	var count int64
 
-	query := r.Db.WithContext(ctx).Model(&model.Major{})
+	query := dbFrom(ctx, r.Db).Model(&model.Major{})
 
 	if payload.Search != "" {
 		search := "%" + strings.ToLower(payload.Search) + "%"
@@ -43,49 +46,83 @@ func (r *major) FindAll(ctx context.Context, payload *pkgdto.SearchGetRequest, p
 
 	countQuery := query
 	if err := countQuery.Count(&count).Error; err != nil {
-		return nil, nil, err
+		return nil, nil, translateError(r.Db, err)
 	}
 
 	limit, offset := pkgdto.GetLimitOffset(pagination)
 
 	err := query.Limit(limit).Offset(offset).Find(&majors).Error
 
-	return majors, pkgdto.CheckInfoPagination(pagination, count), err
+	return majors, pkgdto.CheckInfoPagination(pagination, count), translateError(r.Db, err)
 }
 
 func (r *major) FindByID(ctx context.Context, id uint) (model.Major, error) {
 	var major model.Major
-	if err := r.Db.WithContext(ctx).Model(&model.Major{}).Where("id = ?", id).First(&major).Error; err != nil {
-		return major, err
+	if err := dbFrom(ctx, r.Db).Model(&model.Major{}).Where("id = ?", id).First(&major).Error; err != nil {
+		return major, translateError(r.Db, err)
 	}
 	return major, nil
 }
 
+// FindByIDs returns the majors with the ids ordered by id, missing ones are left out.
+func (r *major) FindByIDs(ctx context.Context, ids []uint) ([]model.Major, error) {
+	var majors []model.Major
+	if len(ids) == 0 {
+		return majors, nil
+	}
+	err := dbFrom(ctx, r.Db).Where("id IN ?", ids).Order("id").Find(&majors).Error
+	return majors, translateError(r.Db, err)
+}
+
 func (r *major) Save(ctx context.Context, major *dto.CreateMajorRequestBody) (model.Major, error) {
 	newMajor := model.Major{
 		Name: *major.Name,
 	}
-	if err := r.Db.WithContext(ctx).Save(&newMajor).Error; err != nil {
-		return newMajor, err
+	if err := dbFrom(ctx, r.Db).Save(&newMajor).Error; err != nil {
+		return newMajor, translateError(r.Db, err)
 	}
 	return newMajor, nil
 }
 
 func (r *major) Edit(ctx context.Context, oldMajor *model.Major, updateData *dto.UpdateMajorRequestBody) (*model.Major, error) {
+	updates := map[string]interface{}{}
 	if updateData.Name != nil {
-		oldMajor.Name = *updateData.Name
+		updates["name"] = *updateData.Name
 	}
+	return r.update(ctx, oldMajor, updates)
+}
 
-	if err := r.Db.WithContext(ctx).Save(oldMajor).Find(oldMajor).Error; err != nil {
-		return nil, err
+func (r *major) Patch(ctx context.Context, oldMajor *model.Major, document *dto.MajorPatchDocument) (*model.Major, error) {
+	return r.update(ctx, oldMajor, map[string]interface{}{"name": document.Name})
+}
+
+// update writes updates to oldMajor if it was not changed since it was read, and reloads it.
+func (r *major) update(ctx context.Context, oldMajor *model.Major, updates map[string]interface{}) (*model.Major, error) {
+	updates["version"] = gorm.Expr("version + 1")
+	result := dbFrom(ctx, r.Db).Model(&model.Major{}).
+		Where("id = ? AND version = ?", oldMajor.ID, oldMajor.Version).
+		Updates(updates)
+	if result.Error != nil {
+		return nil, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrStaleVersion
+	}
+
+	if err := dbFrom(ctx, r.Db).First(oldMajor, oldMajor.ID).Error; err != nil {
+		return nil, translateError(r.Db, err)
 	}
 
 	return oldMajor, nil
 }
 
 func (r *major) Destroy(ctx context.Context, major *model.Major) (*model.Major, error) {
-	if err := r.Db.WithContext(ctx).Delete(major).Error; err != nil {
-		return nil, err
+	result := dbFrom(ctx, r.Db).Where("version = ?", major.Version).Delete(major)
+	if result.Error != nil {
+		return nil, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrStaleVersion
 	}
 	return major, nil
 }
@@ -95,8 +132,22 @@ func (r *major) ExistByName(ctx context.Context, name string) (bool, error) {
 		count   int64
 		isExist bool
 	)
-	if err := r.Db.WithContext(ctx).Model(&model.Major{}).Where("name = ?", name).Count(&count).Error; err != nil {
-		return isExist, err
+	if err := dbFrom(ctx, r.Db).Model(&model.Major{}).Where("name = ?", name).Count(&count).Error; err != nil {
+		return isExist, translateError(r.Db, err)
+	}
+	if count > 0 {
+		isExist = true
+	}
+	return isExist, nil
+}
+
+func (r *major) ExistByID(ctx context.Context, id uint) (bool, error) {
+	var (
+		count   int64
+		isExist bool
+	)
+	if err := dbFrom(ctx, r.Db).Model(&model.Major{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return isExist, translateError(r.Db, err)
 	}
 	if count > 0 {
 		isExist = true
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"student-service/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type Outbox interface {
+	Save(ctx context.Context, event *model.OutboxEvent) error
+	FindUnpublished(ctx context.Context, now time.Time, limit int) ([]model.OutboxEvent, error)
+	Edit(ctx context.Context, event *model.OutboxEvent) error
+	PurgePublished(ctx context.Context, before time.Time) (int64, error)
+}
+
+type outbox struct {
+	Db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *outbox {
+	return &outbox{
+		db,
+	}
+}
+
+// Save records the event, with the transaction of ctx so that it is only published when the
+// change it reports is committed.
+func (r *outbox) Save(ctx context.Context, event *model.OutboxEvent) error {
+	return translateError(r.Db, dbFrom(ctx, r.Db).Create(event).Error)
+}
+
+// FindUnpublished returns the oldest limit events that are not published yet and due at now, in
+// the order they were recorded. The events of an aggregate after one that is not due are left
+// out, so that they do not fill the batch while they are held back.
+func (r *outbox) FindUnpublished(ctx context.Context, now time.Time, limit int) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+	err := dbFrom(ctx, r.Db).
+		Where("published_at IS NULL").
+		Where(`NOT EXISTS (SELECT 1 FROM outbox_events held
+			WHERE held.aggregate_type = outbox_events.aggregate_type AND held.aggregate_id = outbox_events.aggregate_id
+			AND held.id <= outbox_events.id AND held.published_at IS NULL AND held.next_attempt_at > ?)`, now).
+		Order("id").
+		Limit(limit).
+		Find(&events).Error
+	return events, translateError(r.Db, err)
+}
+
+// Edit writes the outcome of an attempt to publish the event.
+func (r *outbox) Edit(ctx context.Context, event *model.OutboxEvent) error {
+	err := dbFrom(ctx, r.Db).Model(&model.OutboxEvent{}).
+		Where("id = ?", event.ID).
+		Updates(map[string]interface{}{
+			"published_at":    event.PublishedAt,
+			"attempts":        event.Attempts,
+			"next_attempt_at": event.NextAttemptAt,
+			"last_error":      event.LastError,
+		}).Error
+	return translateError(r.Db, err)
+}
+
+// PurgePublished deletes the events published before, and returns how many it deleted.
+func (r *outbox) PurgePublished(ctx context.Context, before time.Time) (int64, error) {
+	result := dbFrom(ctx, r.Db).Where("published_at < ?", before).Delete(&model.OutboxEvent{})
+	return result.RowsAffected, translateError(r.Db, result.Error)
+}
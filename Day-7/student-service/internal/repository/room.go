@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"student-service/internal/dto"
+	"student-service/internal/model"
+	pkgdto "student-service/pkg/dto"
+
+	"gorm.io/gorm"
+)
+
+type Room interface {
+	FindAll(ctx context.Context, payload *dto.SearchRoomRequest, pagination *pkgdto.Pagination) ([]model.Room, *pkgdto.PaginationInfo, error)
+	FindAvailable(ctx context.Context, payload *dto.AvailableRoomRequest) ([]model.Room, error)
+	FindByID(ctx context.Context, id uint) (model.Room, error)
+	Save(ctx context.Context, room *model.Room) error
+	Edit(ctx context.Context, oldRoom *model.Room, updateData *model.Room) (*model.Room, error)
+	Destroy(ctx context.Context, room *model.Room) (*model.Room, error)
+	ExistByID(ctx context.Context, id uint) (bool, error)
+	ExistByNameExceptID(ctx context.Context, name string, exceptID uint) (bool, error)
+	HasBookingsAfter(ctx context.Context, id uint, at time.Time) (bool, error)
+}
+
+type room struct {
+	Db *gorm.DB
+}
+
+func NewRoomRepository(db *gorm.DB) *room {
+	return &room{
+		db,
+	}
+}
+
+func (r *room) FindAll(ctx context.Context, payload *dto.SearchRoomRequest, pagination *pkgdto.Pagination) ([]model.Room, *pkgdto.PaginationInfo, error) {
+	var rooms []model.Room
+	var count int64
+
+	query := r.filter(ctx, dbFrom(ctx, r.Db).Model(&model.Room{}), payload.Capacity, payload.Facilities)
+
+	if payload.Search != "" {
+		search := "%" + strings.ToLower(payload.Search) + "%"
+		query = query.Where("lower(name) LIKE ?", search)
+	}
+
+	countQuery := query
+	if err := countQuery.Count(&count).Error; err != nil {
+		return nil, nil, translateError(r.Db, err)
+	}
+
+	limit, offset := pkgdto.GetLimitOffset(pagination)
+
+	err := query.Preload("Facilities", orderByName).Order("id").Limit(limit).Offset(offset).Find(&rooms).Error
+
+	return rooms, pkgdto.CheckInfoPagination(pagination, count), translateError(r.Db, err)
+}
+
+// FindAvailable returns the rooms with the capacity and facilities of payload that have no
+// booking overlapping its time window.
+func (r *room) FindAvailable(ctx context.Context, payload *dto.AvailableRoomRequest) ([]model.Room, error) {
+	var rooms []model.Room
+	query := r.filter(ctx, dbFrom(ctx, r.Db).Model(&model.Room{}), payload.Capacity, payload.Facilities).
+		Where("id NOT IN (?)", dbFrom(ctx, r.Db).Model(&model.Booking{}).
+			Select("room_id").
+			Scopes(overlapping(*payload.StartsAt, *payload.EndsAt)))
+	err := query.Preload("Facilities", orderByName).Order("capacity, id").Find(&rooms).Error
+	return rooms, translateError(r.Db, err)
+}
+
+// filter keeps the rooms with at least capacity seats that have all the facilities.
+func (r *room) filter(ctx context.Context, query *gorm.DB, capacity *uint, facilities []string) *gorm.DB {
+	if capacity != nil {
+		query = query.Where("capacity >= ?", *capacity)
+	}
+	if len(facilities) > 0 {
+		names := map[string]bool{}
+		for _, facility := range facilities {
+			names[strings.ToLower(strings.TrimSpace(facility))] = true
+		}
+		distinct := make([]string, 0, len(names))
+		for name := range names {
+			distinct = append(distinct, name)
+		}
+		query = query.Where("id IN (?)", dbFrom(ctx, r.Db).Model(&model.RoomFacility{}).
+			Select("room_id").
+			Where("name IN ?", distinct).
+			Group("room_id").
+			Having("COUNT(*) = ?", len(distinct)))
+	}
+	return query
+}
+
+func (r *room) FindByID(ctx context.Context, id uint) (model.Room, error) {
+	var room model.Room
+	err := dbFrom(ctx, r.Db).Model(&model.Room{}).
+		Preload("Facilities", orderByName).
+		Where("id = ?", id).
+		First(&room).Error
+	if err != nil {
+		return room, translateError(r.Db, err)
+	}
+	return room, nil
+}
+
+// Save creates the room with its facilities.
+func (r *room) Save(ctx context.Context, room *model.Room) error {
+	return translateError(r.Db, dbFrom(ctx, r.Db).Create(room).Error)
+}
+
+// Edit writes the name and capacity of updateData to oldRoom if it was not changed since it
+// was read. Its facilities are replaced unless updateData.Facilities is nil. oldRoom is
+// reloaded.
+func (r *room) Edit(ctx context.Context, oldRoom *model.Room, updateData *model.Room) (*model.Room, error) {
+	err := NewTransactor(r.Db).WithinTransaction(ctx, func(ctx context.Context) error {
+		result := dbFrom(ctx, r.Db).Model(&model.Room{}).
+			Where("id = ? AND version = ?", oldRoom.ID, oldRoom.Version).
+			Updates(map[string]interface{}{
+				"name":     updateData.Name,
+				"capacity": updateData.Capacity,
+				"version":  gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return translateError(r.Db, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrStaleVersion
+		}
+
+		if updateData.Facilities == nil {
+			return nil
+		}
+		if err := dbFrom(ctx, r.Db).Where("room_id = ?", oldRoom.ID).Delete(&model.RoomFacility{}).Error; err != nil {
+			return translateError(r.Db, err)
+		}
+		if len(updateData.Facilities) == 0 {
+			return nil
+		}
+		for i := range updateData.Facilities {
+			updateData.Facilities[i].RoomID = oldRoom.ID
+		}
+		return translateError(r.Db, dbFrom(ctx, r.Db).Create(&updateData.Facilities).Error)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reloaded, err := r.FindByID(ctx, oldRoom.ID)
+	if err != nil {
+		return nil, err
+	}
+	*oldRoom = reloaded
+	return oldRoom, nil
+}
+
+func (r *room) Destroy(ctx context.Context, room *model.Room) (*model.Room, error) {
+	result := dbFrom(ctx, r.Db).Where("version = ?", room.Version).Delete(room)
+	if result.Error != nil {
+		return nil, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrStaleVersion
+	}
+	return room, nil
+}
+
+func (r *room) ExistByID(ctx context.Context, id uint) (bool, error) {
+	var (
+		count   int64
+		isExist bool
+	)
+	if err := dbFrom(ctx, r.Db).Model(&model.Room{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return isExist, translateError(r.Db, err)
+	}
+	if count > 0 {
+		isExist = true
+	}
+	return isExist, nil
+}
+
+func (r *room) ExistByNameExceptID(ctx context.Context, name string, exceptID uint) (bool, error) {
+	var (
+		count   int64
+		isExist bool
+	)
+	query := dbFrom(ctx, r.Db).Model(&model.Room{}).Where("name = ?", name)
+	if exceptID != 0 {
+		query = query.Where("id <> ?", exceptID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return isExist, translateError(r.Db, err)
+	}
+	if count > 0 {
+		isExist = true
+	}
+	return isExist, nil
+}
+
+// HasBookingsAfter reports whether the room has a booking that is not cancelled and ends after at.
+func (r *room) HasBookingsAfter(ctx context.Context, id uint, at time.Time) (bool, error) {
+	var count int64
+	err := dbFrom(ctx, r.Db).Model(&model.Booking{}).
+		Where("room_id = ? AND cancelled_at IS NULL AND ends_at > ?", id, at).
+		Count(&count).Error
+	return count > 0, translateError(r.Db, err)
+}
+
+func orderByName(db *gorm.DB) *gorm.DB {
+	return db.Order("name")
+}
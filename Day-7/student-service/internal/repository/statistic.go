@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+
+	"student-service/internal/dto"
+	"student-service/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Statistic interface {
+	Compute(ctx context.Context) (dto.StatisticsResponse, error)
+	Find(ctx context.Context, name string) (model.Statistic, error)
+	Save(ctx context.Context, statistic *model.Statistic) error
+}
+
+type statistic struct {
+	Db *gorm.DB
+}
+
+func NewStatisticRepository(db *gorm.DB) *statistic {
+	return &statistic{
+		db,
+	}
+}
+
+// Compute counts the records that are not deleted, it reads every student so it is only run by
+// the scheduled task.
+func (r *statistic) Compute(ctx context.Context) (dto.StatisticsResponse, error) {
+	var stats dto.StatisticsResponse
+	db := dbFrom(ctx, r.Db)
+	for table, count := range map[string]*int64{"students": &stats.Students, "classes": &stats.Classes, "majors": &stats.Majors, "courses": &stats.Courses} {
+		if err := db.Table(table).Where("deleted_at IS NULL").Count(count).Error; err != nil {
+			return stats, translateError(r.Db, err)
+		}
+	}
+
+	err := db.Table("majors").
+		Select("majors.id, majors.name, COUNT(students.id) AS students").
+		Joins("LEFT JOIN students ON students.major_id = majors.id AND students.deleted_at IS NULL").
+		Where("majors.deleted_at IS NULL").
+		Group("majors.id, majors.name").
+		Order("majors.id").
+		Scan(&stats.StudentsPerMajor).Error
+	if err != nil {
+		return stats, translateError(r.Db, err)
+	}
+
+	err = db.Table("classes").
+		Select("classes.id, classes.name, COUNT(students.id) AS students").
+		Joins("LEFT JOIN students ON students.class_id = classes.id AND students.deleted_at IS NULL").
+		Where("classes.deleted_at IS NULL").
+		Group("classes.id, classes.name").
+		Order("classes.id").
+		Scan(&stats.StudentsPerClass).Error
+	return stats, translateError(r.Db, err)
+}
+
+func (r *statistic) Find(ctx context.Context, name string) (model.Statistic, error) {
+	var statistic model.Statistic
+	err := dbFrom(ctx, r.Db).Where("name = ?", name).First(&statistic).Error
+	return statistic, translateError(r.Db, err)
+}
+
+// Save writes the statistic over its previous value.
+func (r *statistic) Save(ctx context.Context, statistic *model.Statistic) error {
+	err := dbFrom(ctx, r.Db).Clauses(clause.OnConflict{UpdateAll: true}).Create(statistic).Error
+	return translateError(r.Db, err)
+}
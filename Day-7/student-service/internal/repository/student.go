@@ -3,74 +3,185 @@ package repository
 import (
 	"context"
 	"strings"
+	"time"
 
 	"student-service/internal/dto"
 	"student-service/internal/model"
 	pkgdto "student-service/pkg/dto"
+	"student-service/pkg/studentnumber"
 	"student-service/pkg/util"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Student interface {
-	FindAll(ctx context.Context, payload *pkgdto.SearchGetRequest, p *pkgdto.Pagination) ([]model.Student, *pkgdto.PaginationInfo, error)
+	FindAll(ctx context.Context, payload *dto.SearchStudentRequest, p *pkgdto.Pagination) ([]model.Student, *pkgdto.PaginationInfo, error)
 	FindByID(ctx context.Context, id uint, usePreload bool) (model.Student, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]model.Student, error)
+	FindByClassID(ctx context.Context, classID uint, closedTermID *uint) ([]model.Student, error)
+	FindByClassIDs(ctx context.Context, classIDs []uint) ([]model.Student, error)
 	FindByEmail(ctx context.Context, email *string) (*model.Student, error)
 	ExistByEmail(ctx context.Context, email *string) (bool, error)
+	ExistByEmailExceptID(ctx context.Context, email string, exceptID uint) (bool, error)
 	ExistByID(ctx context.Context, id uint) (bool, error)
-	Save(ctx context.Context, student *dto.RegisterStudentRequestBody) (model.Student, error)
+	Save(ctx context.Context, student *dto.RegisterStudentRequestBody, numbers *studentnumber.Pattern) (model.Student, error)
 	Edit(ctx context.Context, oldStudent *model.Student, updateData *dto.UpdateStudentRequestBody) (*model.Student, error)
+	Patch(ctx context.Context, oldStudent *model.Student, document *dto.StudentPatchDocument) (*model.Student, error)
 	Destroy(ctx context.Context, student *model.Student) (*model.Student, error)
 }
 
 type student struct {
-	Db *gorm.DB
+	Db     *gorm.DB
+	search *studentSearch
 }
 
 func NewStudentRepository(db *gorm.DB) *student {
 	return &student{
 		db,
+		newStudentSearch(db),
 	}
 }
 
-func (r *student) FindAll(ctx context.Context, payload *pkgdto.SearchGetRequest, pagination *pkgdto.Pagination) ([]model.Student, *pkgdto.PaginationInfo, error) {
+// FindAll lists students. When payload.Search is set they are ranked by how well they match it
+// and only the best matches are listed.
+func (r *student) FindAll(ctx context.Context, payload *dto.SearchStudentRequest, pagination *pkgdto.Pagination) ([]model.Student, *pkgdto.PaginationInfo, error) {
 	var users []model.Student
 	var count int64
 
-	query := r.Db.WithContext(ctx).Model(&model.Student{})
+	query := dbFrom(ctx, r.Db).Model(&model.Student{})
 
-	if payload.Search != "" {
-		search := "%" + strings.ToLower(payload.Search) + "%"
-		query = query.Where("lower(fullname) LIKE ? or lower(email) Like ? ", search, search)
+	var ranked []uint
+	if strings.TrimSpace(payload.Search) != "" {
+		var err error
+		if ranked, err = r.search.Search(ctx, payload.Search); err != nil {
+			return nil, nil, err
+		}
+		query = query.Where("id IN ?", append(ranked, 0))
+	}
+	if payload.MajorID != nil {
+		query = query.Where("major_id = ?", *payload.MajorID)
+	}
+	if payload.IntakeYear != nil {
+		query = query.Where("intake_year = ?", *payload.IntakeYear)
+	}
+	if payload.Gender != "" {
+		query = query.Where("gender = ?", payload.Gender)
+	}
+	if payload.TermID != nil {
+		query = query.Where("id IN (?)", dbFrom(ctx, r.Db).Model(&model.Enrollment{}).Select("student_id").Where("term_id = ?", *payload.TermID))
+	}
+
+	limit, offset := pkgdto.GetLimitOffset(pagination)
+	if ranked != nil {
+		return r.findRanked(ctx, query, ranked, pagination, limit, offset)
 	}
 
 	countQuery := query
 	if err := countQuery.Count(&count).Error; err != nil {
-		return nil, nil, err
+		return nil, nil, translateError(r.Db, err)
 	}
 
-	limit, offset := pkgdto.GetLimitOffset(pagination)
-
 	err := query.Limit(limit).Offset(offset).Find(&users).Error
 
-	return users, pkgdto.CheckInfoPagination(pagination, count), err
+	return users, pkgdto.CheckInfoPagination(pagination, count), translateError(r.Db, err)
+}
+
+// findRanked returns the page of the students of query in the order of ranked.
+func (r *student) findRanked(ctx context.Context, query *gorm.DB, ranked []uint, pagination *pkgdto.Pagination, limit, offset int) ([]model.Student, *pkgdto.PaginationInfo, error) {
+	var ids []uint
+	if err := query.Pluck("id", &ids).Error; err != nil {
+		return nil, nil, translateError(r.Db, err)
+	}
+	found := map[uint]bool{}
+	for _, id := range ids {
+		found[id] = true
+	}
+
+	var page []uint
+	var count int64
+	for _, id := range ranked {
+		if !found[id] {
+			continue
+		}
+		if count >= int64(offset) && len(page) < limit {
+			page = append(page, id)
+		}
+		count++
+	}
+
+	students := make([]model.Student, 0, len(page))
+	if len(page) > 0 {
+		var rows []model.Student
+		if err := dbFrom(ctx, r.Db).Where("id IN ?", page).Find(&rows).Error; err != nil {
+			return nil, nil, translateError(r.Db, err)
+		}
+		byID := map[uint]model.Student{}
+		for _, row := range rows {
+			byID[row.ID] = row
+		}
+		for _, id := range page {
+			if student, ok := byID[id]; ok {
+				students = append(students, student)
+			}
+		}
+	}
+
+	return students, pkgdto.CheckInfoPagination(pagination, count), nil
 }
 
 func (r *student) FindByID(ctx context.Context, id uint, usePreload bool) (model.Student, error) {
 	var user model.Student
-	q := r.Db.WithContext(ctx).Model(&model.Student{}).Where("id = ?", id)
+	q := dbFrom(ctx, r.Db).Model(&model.Student{}).Where("id = ?", id)
 	if usePreload {
 		q = q.Preload("Major").Preload("Class")
 	}
 	err := q.First(&user).Error
-	return user, err
+	return user, translateError(r.Db, err)
+}
+
+// FindByIDs returns the students with the ids ordered by id, missing ones are left out.
+func (r *student) FindByIDs(ctx context.Context, ids []uint) ([]model.Student, error) {
+	var students []model.Student
+	if len(ids) == 0 {
+		return students, nil
+	}
+	err := dbFrom(ctx, r.Db).Where("id IN ?", ids).Order("id").Find(&students).Error
+	return students, translateError(r.Db, err)
+}
+
+// FindByClassID returns the students of the class ordered by id. When closedTermID is set, they
+// are the students who were in the class when the term was closed, otherwise the students
+// currently in the class.
+func (r *student) FindByClassID(ctx context.Context, classID uint, closedTermID *uint) ([]model.Student, error) {
+	var students []model.Student
+	query := dbFrom(ctx, r.Db).Model(&model.Student{})
+	if closedTermID != nil {
+		query = query.
+			Joins("JOIN class_memberships ON class_memberships.student_id = students.id").
+			Where("class_memberships.term_id = ? AND class_memberships.class_id = ?", *closedTermID, classID)
+	} else {
+		query = query.Where("students.class_id = ?", classID)
+	}
+	err := query.Order("students.id").Find(&students).Error
+	return students, translateError(r.Db, err)
+}
+
+// FindByClassIDs returns the students currently in the classes ordered by id.
+func (r *student) FindByClassIDs(ctx context.Context, classIDs []uint) ([]model.Student, error) {
+	var students []model.Student
+	if len(classIDs) == 0 {
+		return students, nil
+	}
+	err := dbFrom(ctx, r.Db).Where("class_id IN ?", classIDs).Order("id").Find(&students).Error
+	return students, translateError(r.Db, err)
 }
 
 func (r *student) FindByEmail(ctx context.Context, email *string) (*model.Student, error) {
 	var data model.Student
-	err := r.Db.WithContext(ctx).Where("email = ?", email).First(&data).Error
+	err := dbFrom(ctx, r.Db).Where("email = ?", email).First(&data).Error
 	if err != nil {
-		return nil, err
+		return nil, translateError(r.Db, err)
 	}
 	return &data, nil
 }
@@ -80,8 +191,26 @@ func (r *student) ExistByEmail(ctx context.Context, email *string) (bool, error)
 		count   int64
 		isExist bool
 	)
-	if err := r.Db.WithContext(ctx).Model(&model.Student{}).Where("email = ?", email).Count(&count).Error; err != nil {
-		return isExist, err
+	if err := dbFrom(ctx, r.Db).Model(&model.Student{}).Where("email = ?", email).Count(&count).Error; err != nil {
+		return isExist, translateError(r.Db, err)
+	}
+	if count > 0 {
+		isExist = true
+	}
+	return isExist, nil
+}
+
+func (r *student) ExistByEmailExceptID(ctx context.Context, email string, exceptID uint) (bool, error) {
+	var (
+		count   int64
+		isExist bool
+	)
+	query := dbFrom(ctx, r.Db).Model(&model.Student{}).Where("email = ?", email)
+	if exceptID != 0 {
+		query = query.Where("id <> ?", exceptID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return isExist, translateError(r.Db, err)
 	}
 	if count > 0 {
 		isExist = true
@@ -94,8 +223,8 @@ func (r *student) ExistByID(ctx context.Context, id uint) (bool, error) {
 		count   int64
 		isExist bool
 	)
-	if err := r.Db.WithContext(ctx).Model(&model.Student{}).Where("id = ?", id).Count(&count).Error; err != nil {
-		return isExist, err
+	if err := dbFrom(ctx, r.Db).Model(&model.Student{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return isExist, translateError(r.Db, err)
 	}
 	if count > 0 {
 		isExist = true
@@ -103,57 +232,179 @@ func (r *student) ExistByID(ctx context.Context, id uint) (bool, error) {
 	return isExist, nil
 }
 
-func (r *student) Save(ctx context.Context, student *dto.RegisterStudentRequestBody) (model.Student, error) {
+// Save registers the student with the next student number of its major in the current intake
+// year. The sequence row is locked until the student is saved, so concurrent registrations
+// never get the same number.
+func (r *student) Save(ctx context.Context, student *dto.RegisterStudentRequestBody, numbers *studentnumber.Pattern) (model.Student, error) {
 	newStudent := model.Student{
-		Fullname: student.Fullname,
-		Email:    student.Email,
-		Password: student.Password,
-		ClassID:  *student.ClassID,
-		MajorID:  *student.MajorID,
+		Fullname:   student.Fullname,
+		Email:      student.Email,
+		Password:   student.Password,
+		ClassID:    student.ClassID,
+		MajorID:    *student.MajorID,
+		IntakeYear: time.Now().Year(),
+	}
+	if student.Phone != nil {
+		newStudent.Phone = *student.Phone
+	}
+	if student.BirthDate != nil {
+		var err error
+		if newStudent.BirthDate, err = parseBirthDate(*student.BirthDate); err != nil {
+			return newStudent, err
+		}
+	}
+	if student.Gender != nil {
+		newStudent.Gender = *student.Gender
 	}
-	if err := r.Db.WithContext(ctx).Save(&newStudent).Error; err != nil {
-		return newStudent, err
+	if student.Address != nil {
+		newStudent.Address = *student.Address
 	}
-	return newStudent, nil
+
+	err := NewTransactor(r.Db).WithinTransaction(ctx, func(ctx context.Context) error {
+		seq, err := r.nextSequence(ctx, numbers.Scope(newStudent.IntakeYear, newStudent.MajorID))
+		if err != nil {
+			return err
+		}
+		number, err := numbers.Format(newStudent.IntakeYear, newStudent.MajorID, seq)
+		if err != nil {
+			return err
+		}
+		newStudent.StudentNumber = &number
+		return translateError(r.Db, dbFrom(ctx, r.Db).Save(&newStudent).Error)
+	})
+	return newStudent, err
+}
+
+// nextSequence increments the sequence of the scope and returns it, the first one is 1.
+func (r *student) nextSequence(ctx context.Context, scope string) (uint, error) {
+	sequence := model.StudentNumberSequence{Scope: scope, Last: 1}
+	err := dbFrom(ctx, r.Db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "scope"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"last": gorm.Expr("last + 1")}),
+		}).
+		Create(&sequence).Error
+	if err != nil {
+		return 0, translateError(r.Db, err)
+	}
+	if err := dbFrom(ctx, r.Db).Where("scope = ?", scope).First(&sequence).Error; err != nil {
+		return 0, translateError(r.Db, err)
+	}
+	return sequence.Last, nil
 }
 
 func (r *student) Edit(ctx context.Context, oldStudent *model.Student, updateData *dto.UpdateStudentRequestBody) (*model.Student, error) {
+	updates := map[string]interface{}{}
 	if updateData.Fullname != nil {
-		oldStudent.Fullname = *updateData.Fullname
+		updates["fullname"] = *updateData.Fullname
 	}
 	if updateData.Email != nil {
-		oldStudent.Email = *updateData.Email
+		updates["email"] = *updateData.Email
 	}
 	if updateData.Password != nil {
 		hashedPassword, err := util.HashPassword(*updateData.Password)
 		if err != nil {
 			return nil, err
 		}
-		oldStudent.Password = hashedPassword
+		updates["password"] = hashedPassword
 	}
 	if updateData.MajorID != nil {
-		oldStudent.MajorID = *updateData.MajorID
+		updates["major_id"] = *updateData.MajorID
 	}
 	if updateData.ClassID != nil {
-		oldStudent.ClassID = *updateData.ClassID
+		updates["class_id"] = *updateData.ClassID
 	}
+	if updateData.Phone != nil {
+		updates["phone"] = *updateData.Phone
+	}
+	if updateData.BirthDate != nil {
+		birthDate, err := parseBirthDate(*updateData.BirthDate)
+		if err != nil {
+			return nil, err
+		}
+		updates["birth_date"] = birthDate
+	}
+	if updateData.Gender != nil {
+		updates["gender"] = *updateData.Gender
+	}
+	if updateData.Address != nil {
+		updates["address"] = *updateData.Address
+	}
+	return r.update(ctx, oldStudent, updates)
+}
 
-	if err := r.Db.
-		WithContext(ctx).
-		Save(oldStudent).
+func (r *student) Patch(ctx context.Context, oldStudent *model.Student, document *dto.StudentPatchDocument) (*model.Student, error) {
+	updates := map[string]interface{}{
+		"fullname": document.Fullname,
+		"email":    document.Email,
+		"class_id": document.ClassID,
+		"major_id": document.MajorID,
+		"phone":    document.Phone,
+		"gender":   document.Gender,
+		"address":  document.Address,
+	}
+	birthDate := ""
+	if document.BirthDate != nil {
+		birthDate = *document.BirthDate
+	}
+	var err error
+	if updates["birth_date"], err = parseBirthDate(birthDate); err != nil {
+		return nil, err
+	}
+	if document.Password != nil {
+		hashedPassword, err := util.HashPassword(*document.Password)
+		if err != nil {
+			return nil, err
+		}
+		updates["password"] = hashedPassword
+	}
+	return r.update(ctx, oldStudent, updates)
+}
+
+// parseBirthDate parses a birth date, an empty value (cleared by a PATCH) is no birth date.
+func parseBirthDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	birthDate, err := util.ParseDate(value)
+	if err != nil {
+		return nil, err
+	}
+	return &birthDate, nil
+}
+
+// update writes updates to oldStudent if it was not changed since it was read, and reloads it
+// with its major and class.
+func (r *student) update(ctx context.Context, oldStudent *model.Student, updates map[string]interface{}) (*model.Student, error) {
+	updates["version"] = gorm.Expr("version + 1")
+	result := dbFrom(ctx, r.Db).Model(&model.Student{}).
+		Where("id = ? AND version = ?", oldStudent.ID, oldStudent.Version).
+		Updates(updates)
+	if result.Error != nil {
+		return nil, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrStaleVersion
+	}
+
+	if err := dbFrom(ctx, r.Db).
 		Preload("Major").
 		Preload("Class").
-		Find(oldStudent).
+		First(oldStudent, oldStudent.ID).
 		Error; err != nil {
-		return nil, err
+		return nil, translateError(r.Db, err)
 	}
 
 	return oldStudent, nil
 }
 
 func (r *student) Destroy(ctx context.Context, student *model.Student) (*model.Student, error) {
-	if err := r.Db.WithContext(ctx).Delete(student).Error; err != nil {
-		return nil, err
+	result := dbFrom(ctx, r.Db).Where("version = ?", student.Version).Delete(student)
+	if result.Error != nil {
+		return nil, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrStaleVersion
 	}
 	return student, nil
 }
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"student-service/internal/model"
+	"student-service/pkg/search"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// maxSearchHits is the number of best matches a search returns, further matches are too
+	// poor to be worth paging through.
+	maxSearchHits = 1000
+	// searchSyncOverlap is how far back changes are read again when the embedded index is
+	// synced, for transactions that committed after a later change was synced.
+	searchSyncOverlap = time.Minute
+)
+
+// studentSearchFields are the indexed columns of students, in the order of the FULLTEXT index.
+var studentSearchFields = []search.Field{
+	{Name: "fullname", Weight: 3},
+	{Name: "email", Weight: 2},
+	{Name: "student_number", Weight: 2},
+}
+
+// fullTextNotSupported are the errors of servers without FULLTEXT search in boolean mode, Dolt
+// answers ER_UNKNOWN_ERROR (1105) "not supported yet" instead. Any other error of the probe is
+// temporary.
+var fullTextNotSupported = map[uint16]bool{
+	1064: true, // ER_PARSE_ERROR
+	1191: true, // ER_FT_MATCHING_KEY_NOT_FOUND
+	1214: true, // ER_TABLE_CANT_HANDLE_FT
+	1235: true, // ER_NOT_SUPPORTED_YET
+}
+
+// studentSearch ranks the students matching a full-text query. It uses the FULLTEXT index of
+// MySQL when the server supports it in boolean mode, and an embedded index otherwise (e.g. on
+// Dolt), which is synced with the students changed since the previous search. The embedded index
+// also finds the words with a typo the FULLTEXT index found nothing for.
+type studentSearch struct {
+	db       *gorm.DB
+	detectMu sync.Mutex
+	detected bool
+	fullText bool
+
+	mu       sync.Mutex
+	index    *search.Index
+	syncedAt time.Time
+}
+
+func newStudentSearch(db *gorm.DB) *studentSearch {
+	return &studentSearch{db: db, index: search.NewIndex(studentSearchFields...)}
+}
+
+// Search returns the ids of the students matching every word of the query, best first.
+func (s *studentSearch) Search(ctx context.Context, query string) ([]uint, error) {
+	fullText, err := s.supportsFullText(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if fullText {
+		ids, err := s.searchFullText(ctx, query)
+		if err != nil || len(ids) > 0 {
+			return ids, err
+		}
+	}
+	return s.searchIndex(ctx, query)
+}
+
+// supportsFullText probes the server once it answers, a probe failing for another reason than
+// the lack of support is made again by the next search.
+func (s *studentSearch) supportsFullText(ctx context.Context) (bool, error) {
+	s.detectMu.Lock()
+	defer s.detectMu.Unlock()
+	if s.detected {
+		return s.fullText, nil
+	}
+
+	var id uint
+	err := dbFrom(ctx, s.db).Raw("SELECT id FROM students WHERE "+s.match()+" LIMIT 1", "probe").Scan(&id).Error
+	var mysqlErr *mysql.MySQLError
+	switch {
+	case err == nil:
+		s.fullText = true
+	case errors.As(err, &mysqlErr) && (fullTextNotSupported[mysqlErr.Number] ||
+		mysqlErr.Number == 1105 && strings.Contains(mysqlErr.Message, "not supported")):
+		logrus.Infof("full-text search is not supported, searching students in an embedded index: %v", err)
+	default:
+		return false, translateError(s.db, err)
+	}
+	s.detected = true
+	return s.fullText, nil
+}
+
+func (s *studentSearch) match() string {
+	columns := make([]string, len(studentSearchFields))
+	for i, field := range studentSearchFields {
+		columns[i] = field.Name
+	}
+	return "MATCH(" + strings.Join(columns, ", ") + ") AGAINST (? IN BOOLEAN MODE)"
+}
+
+// searchFullText requires every word, or a word it is the prefix of, like the embedded index.
+// Typos are not matched, and words shorter than the minimum token size of the server are
+// ignored.
+func (s *studentSearch) searchFullText(ctx context.Context, query string) ([]uint, error) {
+	words := search.Terms(query)
+	if len(words) == 0 {
+		return nil, nil
+	}
+	against := "+" + strings.Join(words, "* +") + "*"
+
+	var ids []uint
+	err := dbFrom(ctx, s.db).Model(&model.Student{}).
+		Where(s.match(), against).
+		Clauses(clause.OrderBy{Expression: clause.Expr{SQL: s.match() + " DESC, id", Vars: []interface{}{against}}}).
+		Limit(maxSearchHits).
+		Pluck("id", &ids).Error
+	return ids, translateError(s.db, err)
+}
+
+func (s *studentSearch) searchIndex(ctx context.Context, query string) ([]uint, error) {
+	if err := s.sync(ctx); err != nil {
+		return nil, err
+	}
+
+	var ids []uint
+	for _, hit := range s.index.Search(query, maxSearchHits) {
+		ids = append(ids, hit.ID)
+	}
+	return ids, nil
+}
+
+// sync indexes the students created, updated or deleted since the previous sync, all of them
+// the first time.
+func (s *studentSearch) sync(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	syncedAt := time.Now()
+	query := dbFrom(ctx, s.db).Unscoped().Model(&model.Student{})
+	if !s.syncedAt.IsZero() {
+		since := s.syncedAt.Add(-searchSyncOverlap)
+		query = query.Where("updated_at >= ? OR deleted_at >= ?", since, since)
+	}
+
+	var students []model.Student
+	if err := query.Find(&students).Error; err != nil {
+		return translateError(s.db, err)
+	}
+	for _, student := range students {
+		if student.DeletedAt != nil && student.DeletedAt.Valid {
+			s.index.Delete(student.ID)
+			continue
+		}
+		number := ""
+		if student.StudentNumber != nil {
+			number = *student.StudentNumber
+		}
+		s.index.Put(student.ID, student.Fullname, student.Email, number)
+	}
+	s.syncedAt = syncedAt
+	return nil
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"student-service/database"
+	"student-service/database/seeder"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStudentSearchProbesAgainAfterAnError(t *testing.T) {
+	db := database.GetConnection()
+	seeder.NewSeeder().DeleteAll()
+	seeder.NewSeeder().SeedAll()
+
+	var (
+		asserts = assert.New(t)
+		search  = newStudentSearch(db)
+	)
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := search.Search(cancelled, "devon")
+	asserts.Error(err)
+	asserts.False(search.detected)
+
+	// a word with a typo is found whether the server supports FULLTEXT or not
+	ids, err := search.Search(context.Background(), "devn")
+	asserts.NoError(err)
+	asserts.True(search.detected)
+	asserts.Len(ids, 1)
+}
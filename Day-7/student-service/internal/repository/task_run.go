@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"student-service/internal/dto"
+	"student-service/internal/model"
+	pkgdto "student-service/pkg/dto"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TaskRun interface {
+	FindAll(ctx context.Context, payload *dto.SearchTaskRunRequest) ([]model.TaskRun, *pkgdto.PaginationInfo, error)
+	FindLatest(ctx context.Context, task string) (*model.TaskRun, error)
+	Start(ctx context.Context, run *model.TaskRun) (bool, error)
+	Edit(ctx context.Context, run *model.TaskRun) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+type taskRun struct {
+	Db *gorm.DB
+}
+
+func NewTaskRunRepository(db *gorm.DB) *taskRun {
+	return &taskRun{
+		db,
+	}
+}
+
+func (r *taskRun) FindAll(ctx context.Context, payload *dto.SearchTaskRunRequest) ([]model.TaskRun, *pkgdto.PaginationInfo, error) {
+	var runs []model.TaskRun
+	var count int64
+
+	query := dbFrom(ctx, r.Db).Model(&model.TaskRun{})
+	if payload.Task != "" {
+		query = query.Where("task = ?", payload.Task)
+	}
+	if payload.Status != "" {
+		query = query.Where("status = ?", payload.Status)
+	}
+	countQuery := query
+	if err := countQuery.Count(&count).Error; err != nil {
+		return nil, nil, translateError(r.Db, err)
+	}
+
+	limit, offset := pkgdto.GetLimitOffset(&payload.Pagination)
+	err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&runs).Error
+
+	return runs, pkgdto.CheckInfoPagination(&payload.Pagination, count), translateError(r.Db, err)
+}
+
+// FindLatest returns the last run of the task, or nil when it never ran.
+func (r *taskRun) FindLatest(ctx context.Context, task string) (*model.TaskRun, error) {
+	var run model.TaskRun
+	result := dbFrom(ctx, r.Db).Where("task = ?", task).Order("scheduled_at DESC").Limit(1).Find(&run)
+	if result.Error != nil {
+		return nil, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &run, nil
+}
+
+// Start records the run and reports whether it was recorded, false when another replica already
+// recorded the run of the task at the same scheduled time.
+func (r *taskRun) Start(ctx context.Context, run *model.TaskRun) (bool, error) {
+	result := dbFrom(ctx, r.Db).Clauses(clause.OnConflict{DoNothing: true}).Create(run)
+	if result.Error != nil {
+		return false, translateError(r.Db, result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Edit writes the outcome of the run.
+func (r *taskRun) Edit(ctx context.Context, run *model.TaskRun) error {
+	err := dbFrom(ctx, r.Db).Model(run).Updates(map[string]interface{}{
+		"status":      run.Status,
+		"result":      run.Result,
+		"error":       run.Error,
+		"finished_at": run.FinishedAt,
+	}).Error
+	return translateError(r.Db, err)
+}
+
+// Purge deletes the finished runs that started before, and returns how many it deleted.
+func (r *taskRun) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result := dbFrom(ctx, r.Db).Where("finished_at IS NOT NULL AND started_at < ?", before).Delete(&model.TaskRun{})
+	return result.RowsAffected, translateError(r.Db, result.Error)
+}
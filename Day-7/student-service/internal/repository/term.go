@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"student-service/internal/model"
+	pkgdto "student-service/pkg/dto"
+	"student-service/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+type Term interface {
+	FindAll(ctx context.Context, payload *pkgdto.SearchGetRequest, p *pkgdto.Pagination) ([]model.Term, *pkgdto.PaginationInfo, error)
+	FindByID(ctx context.Context, id uint) (model.Term, error)
+	Lock(ctx context.Context, id uint) (model.Term, error)
+	FindActive(ctx context.Context, at time.Time) (model.Term, error)
+	Save(ctx context.Context, term *model.Term) error
+	Edit(ctx context.Context, oldTerm *model.Term, updateData *model.Term) (*model.Term, error)
+	Close(ctx context.Context, term *model.Term) (*model.Term, error)
+	Reopen(ctx context.Context, term *model.Term) (*model.Term, error)
+	Destroy(ctx context.Context, term *model.Term) (*model.Term, error)
+	ExistByID(ctx context.Context, id uint) (bool, error)
+	ExistByNameExceptID(ctx context.Context, name string, exceptID uint) (bool, error)
+	IsUsed(ctx context.Context, id uint) (bool, error)
+}
+
+type term struct {
+	Db *gorm.DB
+}
+
+func NewTermRepository(db *gorm.DB) *term {
+	return &term{
+		db,
+	}
+}
+
+func (r *term) FindAll(ctx context.Context, payload *pkgdto.SearchGetRequest, pagination *pkgdto.Pagination) ([]model.Term, *pkgdto.PaginationInfo, error) {
+	var terms []model.Term
+	var count int64
+
+	query := dbFrom(ctx, r.Db).Model(&model.Term{})
+
+	if payload.Search != "" {
+		search := "%" + strings.ToLower(payload.Search) + "%"
+		query = query.Where("lower(name) LIKE ?", search)
+	}
+
+	countQuery := query
+	if err := countQuery.Count(&count).Error; err != nil {
+		return nil, nil, translateError(r.Db, err)
+	}
+
+	limit, offset := pkgdto.GetLimitOffset(pagination)
+
+	err := query.Order("start_date DESC").Limit(limit).Offset(offset).Find(&terms).Error
+
+	return terms, pkgdto.CheckInfoPagination(pagination, count), translateError(r.Db, err)
+}
+
+func (r *term) FindByID(ctx context.Context, id uint) (model.Term, error) {
+	var term model.Term
+	if err := dbFrom(ctx, r.Db).Model(&model.Term{}).Where("id = ?", id).First(&term).Error; err != nil {
+		return term, translateError(r.Db, err)
+	}
+	return term, nil
+}
+
+// Lock returns the term and locks its row in share mode until the end of the transaction of
+// ctx, so that it cannot be closed before the writes of the transaction commit. LOCK IN SHARE
+// MODE is used rather than FOR SHARE, which MySQL 5.7 does not know.
+func (r *term) Lock(ctx context.Context, id uint) (model.Term, error) {
+	var term model.Term
+	result := dbFrom(ctx, r.Db).
+		Raw("SELECT * FROM terms WHERE id = ? AND deleted_at IS NULL LOCK IN SHARE MODE", id).
+		Scan(&term)
+	if result.Error != nil {
+		return term, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return term, &Error{Kind: ErrNotFound, Err: gorm.ErrRecordNotFound}
+	}
+	return term, nil
+}
+
+// FindActive returns the open term whose dates include the day of at. When terms overlap,
+// the one that started last is active.
+func (r *term) FindActive(ctx context.Context, at time.Time) (model.Term, error) {
+	var term model.Term
+	day := at.Format(util.DateLayout)
+	err := dbFrom(ctx, r.Db).Model(&model.Term{}).
+		Where("closed = ? AND start_date <= ? AND end_date >= ?", false, day, day).
+		Order("start_date DESC").
+		First(&term).Error
+	if err != nil {
+		return term, translateError(r.Db, err)
+	}
+	return term, nil
+}
+
+func (r *term) Save(ctx context.Context, term *model.Term) error {
+	return translateError(r.Db, dbFrom(ctx, r.Db).Create(term).Error)
+}
+
+// Edit writes the name and dates of updateData to oldTerm.
+func (r *term) Edit(ctx context.Context, oldTerm *model.Term, updateData *model.Term) (*model.Term, error) {
+	return r.update(ctx, oldTerm, map[string]interface{}{
+		"name":       updateData.Name,
+		"start_date": updateData.StartDate,
+		"end_date":   updateData.EndDate,
+	})
+}
+
+// Close closes the term and records the current class of every student as their class in
+// the term, in one transaction.
+func (r *term) Close(ctx context.Context, term *model.Term) (*model.Term, error) {
+	err := NewTransactor(r.Db).WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := r.update(ctx, term, map[string]interface{}{"closed": true}); err != nil {
+			return err
+		}
+		if err := dbFrom(ctx, r.Db).Where("term_id = ?", term.ID).Delete(&model.ClassMembership{}).Error; err != nil {
+			return translateError(r.Db, err)
+		}
+		err := dbFrom(ctx, r.Db).Exec(
+			"INSERT INTO class_memberships (term_id, student_id, class_id) "+
+				"SELECT ?, id, class_id FROM students WHERE class_id IS NOT NULL AND deleted_at IS NULL",
+			term.ID,
+		).Error
+		return translateError(r.Db, err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return term, nil
+}
+
+// Reopen opens the term again and drops the class memberships recorded when it was closed.
+func (r *term) Reopen(ctx context.Context, term *model.Term) (*model.Term, error) {
+	err := NewTransactor(r.Db).WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := r.update(ctx, term, map[string]interface{}{"closed": false}); err != nil {
+			return err
+		}
+		err := dbFrom(ctx, r.Db).Where("term_id = ?", term.ID).Delete(&model.ClassMembership{}).Error
+		return translateError(r.Db, err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return term, nil
+}
+
+// update writes updates to oldTerm if it was not changed since it was read, and reloads it.
+func (r *term) update(ctx context.Context, oldTerm *model.Term, updates map[string]interface{}) (*model.Term, error) {
+	updates["version"] = gorm.Expr("version + 1")
+	result := dbFrom(ctx, r.Db).Model(&model.Term{}).
+		Where("id = ? AND version = ?", oldTerm.ID, oldTerm.Version).
+		Updates(updates)
+	if result.Error != nil {
+		return nil, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrStaleVersion
+	}
+
+	if err := dbFrom(ctx, r.Db).First(oldTerm, oldTerm.ID).Error; err != nil {
+		return nil, translateError(r.Db, err)
+	}
+
+	return oldTerm, nil
+}
+
+func (r *term) Destroy(ctx context.Context, term *model.Term) (*model.Term, error) {
+	result := dbFrom(ctx, r.Db).Where("version = ?", term.Version).Delete(term)
+	if result.Error != nil {
+		return nil, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrStaleVersion
+	}
+	return term, nil
+}
+
+func (r *term) ExistByID(ctx context.Context, id uint) (bool, error) {
+	var (
+		count   int64
+		isExist bool
+	)
+	if err := dbFrom(ctx, r.Db).Model(&model.Term{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return isExist, translateError(r.Db, err)
+	}
+	if count > 0 {
+		isExist = true
+	}
+	return isExist, nil
+}
+
+func (r *term) ExistByNameExceptID(ctx context.Context, name string, exceptID uint) (bool, error) {
+	var (
+		count   int64
+		isExist bool
+	)
+	query := dbFrom(ctx, r.Db).Model(&model.Term{}).Where("name = ?", name)
+	if exceptID != 0 {
+		query = query.Where("id <> ?", exceptID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return isExist, translateError(r.Db, err)
+	}
+	if count > 0 {
+		isExist = true
+	}
+	return isExist, nil
+}
+
+// IsUsed reports whether enrollments or grades belong to the term.
+func (r *term) IsUsed(ctx context.Context, id uint) (bool, error) {
+	for _, table := range []interface{}{&model.Enrollment{}, &model.Grade{}} {
+		var count int64
+		if err := dbFrom(ctx, r.Db).Model(table).Where("term_id = ?", id).Count(&count).Error; err != nil {
+			return false, translateError(r.Db, err)
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
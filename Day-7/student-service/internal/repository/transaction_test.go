@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"student-service/database"
+	"student-service/database/seeder"
+	"student-service/internal/dto"
+	"student-service/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactorWithinTransaction(t *testing.T) {
+	db := database.GetConnection()
+	seeder.NewSeeder().DeleteAll()
+
+	var (
+		asserts    = assert.New(t)
+		ctx        = context.Background()
+		transactor = NewTransactor(db)
+		classes    = NewClassRepository(db)
+		committed  = "Committed"
+		rolledBack = "Rolled back"
+		nested     = "Nested"
+		errFailed  = errors.New("failed")
+	)
+
+	err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := classes.Save(ctx, &dto.CreateClassRequestBody{Name: &committed})
+		return err
+	})
+	asserts.NoError(err)
+
+	err = transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := classes.Save(ctx, &dto.CreateClassRequestBody{Name: &rolledBack}); err != nil {
+			return err
+		}
+		// joins the outer transaction, it is rolled back with it
+		if err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			_, err := classes.Save(ctx, &dto.CreateClassRequestBody{Name: &nested})
+			return err
+		}); err != nil {
+			return err
+		}
+
+		isExist, err := classes.ExistByName(ctx, rolledBack)
+		asserts.True(isExist)
+		asserts.NoError(err)
+		return errFailed
+	})
+	asserts.ErrorIs(err, errFailed)
+
+	var names []string
+	db.Model(&model.Class{}).Order("id").Pluck("name", &names)
+	asserts.Equal([]string{committed}, names)
+}
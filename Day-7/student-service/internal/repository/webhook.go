@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"student-service/internal/dto"
+	"student-service/internal/model"
+	"student-service/internal/pkg/enum"
+	pkgdto "student-service/pkg/dto"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Webhook interface {
+	FindAll(ctx context.Context, pagination *pkgdto.Pagination) ([]model.WebhookSubscription, *pkgdto.PaginationInfo, error)
+	FindActive(ctx context.Context) ([]model.WebhookSubscription, error)
+	FindByID(ctx context.Context, id uint) (model.WebhookSubscription, error)
+	Save(ctx context.Context, subscription *model.WebhookSubscription) error
+	Edit(ctx context.Context, oldSubscription *model.WebhookSubscription, updateData *dto.UpdateWebhookRequestBody) (*model.WebhookSubscription, error)
+	Destroy(ctx context.Context, subscription *model.WebhookSubscription) (*model.WebhookSubscription, error)
+	FindDeliveries(ctx context.Context, payload *dto.SearchWebhookDeliveryRequest) ([]model.WebhookDelivery, *pkgdto.PaginationInfo, error)
+	FindDeliveryByID(ctx context.Context, subscriptionID, id uint) (model.WebhookDelivery, error)
+	SaveDeliveries(ctx context.Context, deliveries []model.WebhookDelivery) error
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error)
+	EditDelivery(ctx context.Context, delivery *model.WebhookDelivery, leaseUntil time.Time) error
+}
+
+type webhook struct {
+	Db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *webhook {
+	return &webhook{
+		db,
+	}
+}
+
+func (r *webhook) FindAll(ctx context.Context, pagination *pkgdto.Pagination) ([]model.WebhookSubscription, *pkgdto.PaginationInfo, error) {
+	var subscriptions []model.WebhookSubscription
+	var count int64
+
+	query := dbFrom(ctx, r.Db).Model(&model.WebhookSubscription{})
+	countQuery := query
+	if err := countQuery.Count(&count).Error; err != nil {
+		return nil, nil, translateError(r.Db, err)
+	}
+
+	limit, offset := pkgdto.GetLimitOffset(pagination)
+	err := query.Order("id").Limit(limit).Offset(offset).Find(&subscriptions).Error
+
+	return subscriptions, pkgdto.CheckInfoPagination(pagination, count), translateError(r.Db, err)
+}
+
+func (r *webhook) FindActive(ctx context.Context) ([]model.WebhookSubscription, error) {
+	var subscriptions []model.WebhookSubscription
+	err := dbFrom(ctx, r.Db).Where("active = ?", true).Order("id").Find(&subscriptions).Error
+	return subscriptions, translateError(r.Db, err)
+}
+
+func (r *webhook) FindByID(ctx context.Context, id uint) (model.WebhookSubscription, error) {
+	var subscription model.WebhookSubscription
+	err := dbFrom(ctx, r.Db).Where("id = ?", id).First(&subscription).Error
+	return subscription, translateError(r.Db, err)
+}
+
+func (r *webhook) Save(ctx context.Context, subscription *model.WebhookSubscription) error {
+	return translateError(r.Db, dbFrom(ctx, r.Db).Create(subscription).Error)
+}
+
+// Edit writes the fields of updateData that are set to oldSubscription if it was not changed
+// since it was read, and reloads it.
+func (r *webhook) Edit(ctx context.Context, oldSubscription *model.WebhookSubscription, updateData *dto.UpdateWebhookRequestBody) (*model.WebhookSubscription, error) {
+	updates := map[string]interface{}{}
+	if updateData.URL != nil {
+		updates["url"] = *updateData.URL
+	}
+	if updateData.Events != nil {
+		updates["events"] = strings.Join(updateData.Events, ",")
+	}
+	if updateData.Secret != nil {
+		updates["secret"] = *updateData.Secret
+	}
+	if updateData.Active != nil {
+		updates["active"] = *updateData.Active
+	}
+	updates["version"] = gorm.Expr("version + 1")
+	result := dbFrom(ctx, r.Db).Model(&model.WebhookSubscription{}).
+		Where("id = ? AND version = ?", oldSubscription.ID, oldSubscription.Version).
+		Updates(updates)
+	if result.Error != nil {
+		return nil, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrStaleVersion
+	}
+
+	if err := dbFrom(ctx, r.Db).First(oldSubscription, oldSubscription.ID).Error; err != nil {
+		return nil, translateError(r.Db, err)
+	}
+	return oldSubscription, nil
+}
+
+func (r *webhook) Destroy(ctx context.Context, subscription *model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	result := dbFrom(ctx, r.Db).Where("version = ?", subscription.Version).Delete(subscription)
+	if result.Error != nil {
+		return nil, translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrStaleVersion
+	}
+	return subscription, nil
+}
+
+func (r *webhook) FindDeliveries(ctx context.Context, payload *dto.SearchWebhookDeliveryRequest) ([]model.WebhookDelivery, *pkgdto.PaginationInfo, error) {
+	var deliveries []model.WebhookDelivery
+	var count int64
+
+	query := dbFrom(ctx, r.Db).Model(&model.WebhookDelivery{}).Where("subscription_id = ?", payload.SubscriptionID)
+	if payload.Status != "" {
+		query = query.Where("status = ?", payload.Status)
+	}
+	countQuery := query
+	if err := countQuery.Count(&count).Error; err != nil {
+		return nil, nil, translateError(r.Db, err)
+	}
+
+	limit, offset := pkgdto.GetLimitOffset(&payload.Pagination)
+	err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&deliveries).Error
+
+	return deliveries, pkgdto.CheckInfoPagination(&payload.Pagination, count), translateError(r.Db, err)
+}
+
+func (r *webhook) FindDeliveryByID(ctx context.Context, subscriptionID, id uint) (model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	err := dbFrom(ctx, r.Db).Where("id = ? AND subscription_id = ?", id, subscriptionID).First(&delivery).Error
+	return delivery, translateError(r.Db, err)
+}
+
+// SaveDeliveries creates the deliveries, skipping those already created for their subscription
+// and event.
+func (r *webhook) SaveDeliveries(ctx context.Context, deliveries []model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	err := dbFrom(ctx, r.Db).Clauses(clause.OnConflict{DoNothing: true}).Omit("Subscription").Create(&deliveries).Error
+	return translateError(r.Db, err)
+}
+
+// ClaimDueDeliveries returns up to limit pending deliveries due at now, with their subscription
+// (also when deleted). Their next attempt is moved lease later, so that other replicas do not
+// claim them while they are being sent and they are retried if this one stops before recording
+// the outcome.
+func (r *webhook) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error) {
+	var due []model.WebhookDelivery
+	err := dbFrom(ctx, r.Db).
+		Where("status = ? AND next_attempt_at <= ?", enum.DeliveryPending, now).
+		Order("next_attempt_at, id").
+		Limit(limit).
+		Find(&due).Error
+	if err != nil {
+		return nil, translateError(r.Db, err)
+	}
+
+	claimed := make([]model.WebhookDelivery, 0, len(due))
+	// in whole seconds, which the database keeps exactly: EditDelivery compares it
+	leaseUntil := now.Add(lease).Truncate(time.Second)
+	for _, delivery := range due {
+		result := dbFrom(ctx, r.Db).Model(&model.WebhookDelivery{}).
+			Where("id = ? AND status = ? AND next_attempt_at = ?", delivery.ID, enum.DeliveryPending, delivery.NextAttemptAt).
+			Update("next_attempt_at", leaseUntil)
+		if result.Error != nil {
+			return nil, translateError(r.Db, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		delivery.NextAttemptAt = &leaseUntil
+		if err := dbFrom(ctx, r.Db).Unscoped().Where("id = ?", delivery.SubscriptionID).First(&delivery.Subscription).Error; err != nil {
+			return nil, translateError(r.Db, err)
+		}
+		claimed = append(claimed, delivery)
+	}
+	return claimed, nil
+}
+
+// EditDelivery writes the outcome of an attempt of delivery, claimed until leaseUntil. It returns
+// ErrStaleVersion and writes nothing when the delivery was claimed again since, the outcome of
+// the other attempt is kept.
+func (r *webhook) EditDelivery(ctx context.Context, delivery *model.WebhookDelivery, leaseUntil time.Time) error {
+	result := dbFrom(ctx, r.Db).Model(&model.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", delivery.ID, enum.DeliveryPending, leaseUntil).
+		Updates(map[string]interface{}{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"last_attempt_at": delivery.LastAttemptAt,
+			"response_status": delivery.ResponseStatus,
+			"last_error":      delivery.LastError,
+			"delivered_at":    delivery.DeliveredAt,
+		})
+	if result.Error != nil {
+		return translateError(r.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrStaleVersion
+	}
+	return nil
+}
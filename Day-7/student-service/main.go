@@ -1,15 +1,27 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"student-service/database"
 	"student-service/database/migration"
 	"student-service/database/seeder"
+	"student-service/internal/app/auth"
+	"student-service/internal/app/job"
+	"student-service/internal/app/outbox"
+	"student-service/internal/app/task"
+	"student-service/internal/app/webhook"
 	"student-service/internal/factory"
+	"student-service/internal/grpc"
 	"student-service/internal/http"
 	"student-service/internal/middleware"
+	"student-service/internal/pkg/enum"
 
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
@@ -66,9 +78,53 @@ to use this flag:
 	f := factory.NewFactory()
 	e := echo.New()
 
+	middleware.RequestIDMiddlewares(e)
 	middleware.LogMiddlewares(e)
+	middleware.LanguageMiddlewares(e)
 
 	http.NewHttp(e, f)
 
-	e.Logger.Fatal(e.Start(":" + os.Getenv("APP_PORT")))
+	webhook.NewSubscriber(f).Subscribe(f.EventBus)
+	jobs := job.NewWorker(f)
+	jobs.Handle(enum.JobWelcomeEmail, auth.WelcomeEmailHandler(f))
+
+	// SIGINT and SIGTERM stop the servers, then the workers once their current work is done
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var workers sync.WaitGroup
+	for _, run := range []func(context.Context){outbox.NewRelay(f).Run, webhook.NewWorker(f).Run, jobs.Run, task.NewScheduler(f).Run} {
+		workers.Add(1)
+		go func(run func(context.Context)) {
+			defer workers.Done()
+			run(ctx)
+		}(run)
+	}
+
+	if port := os.Getenv("GRPC_PORT"); port != "" {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			// the HTTP server and the workers are stopped too, rather than serving without gRPC
+			if err := grpc.Serve(ctx, ":"+port, f); err != nil {
+				e.Logger.Error(err)
+				stop()
+			}
+		}()
+	}
+
+	go func() {
+		// Start returns once the server is shut down too
+		if err := e.Start(":" + os.Getenv("APP_PORT")); ctx.Err() == nil {
+			e.Logger.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		e.Logger.Error(err)
+	}
+	workers.Wait()
 }
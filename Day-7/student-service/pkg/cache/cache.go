@@ -0,0 +1,111 @@
+// Package cache caches serialized responses on the server, so that services can skip the
+// database for data that rarely changes and drop the entries when they change it.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"student-service/pkg/util"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Cache stores values by key until they expire, are evicted or deleted. The in-memory LRU is
+// local to one instance, a shared backend (e.g. Redis) implementing Cache lets instances share
+// entries and see each other's invalidations.
+type Cache interface {
+	// Get returns the value of key and whether it was found.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Generation returns the generation of key, which changes every time DeletePrefix is called
+	// with a prefix of key.
+	Generation(ctx context.Context, key string) (uint64, error)
+	// Set sets the value of key if its generation is still generation. A value loaded before
+	// the entry was invalidated may be stale, it is dropped.
+	Set(ctx context.Context, key string, value []byte, generation uint64) error
+	// DeletePrefix deletes the entries whose key starts with prefix.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// NewCache returns an in-memory LRU cache of CACHE_SIZE entries that expire after CACHE_TTL,
+// or nil, which disables caching, when CACHE_SIZE is 0.
+func NewCache() Cache {
+	size, err := strconv.Atoi(util.Getenv("CACHE_SIZE", "1000"))
+	if err != nil || size < 0 {
+		logrus.Warnf("invalid CACHE_SIZE, using 1000: %v", err)
+		size = 1000
+	}
+	if size == 0 {
+		return nil
+	}
+
+	ttl, err := time.ParseDuration(util.Getenv("CACHE_TTL", "5m"))
+	if err != nil || ttl <= 0 {
+		logrus.Warnf("invalid CACHE_TTL, using 5m: %v", err)
+		ttl = 5 * time.Minute
+	}
+	return NewLRU(size, ttl)
+}
+
+// Key returns the key of the entry for the request in the namespace of prefix.
+func Key(prefix string, request interface{}) string {
+	b, err := json.Marshal(request)
+	if err != nil {
+		return prefix + err.Error()
+	}
+	sum := sha256.Sum256(b)
+	return prefix + hex.EncodeToString(sum[:16])
+}
+
+// Fetch returns the cached value of key, or loads it and caches it. Errors are not cached, and
+// a failing cache only makes every call load the value. A nil cache always loads it.
+//
+// The value is not cached when key is invalidated while it loads: it could have been read
+// before a write that invalidated the key committed.
+func Fetch[T any](ctx context.Context, c Cache, key string, load func() (T, error)) (T, error) {
+	if c == nil {
+		return load()
+	}
+
+	generation, err := c.Generation(ctx, key)
+	if err != nil {
+		logrus.Warnf("get generation of %s from cache: %v", key, err)
+		return load()
+	}
+	if b, ok, err := c.Get(ctx, key); err != nil {
+		logrus.Warnf("get %s from cache: %v", key, err)
+	} else if ok {
+		var value T
+		if err := json.Unmarshal(b, &value); err == nil {
+			return value, nil
+		}
+		logrus.Warnf("decode %s from cache: %v", key, err)
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	if b, err := json.Marshal(value); err != nil {
+		logrus.Warnf("encode %s for cache: %v", key, err)
+	} else if err := c.Set(ctx, key, b, generation); err != nil {
+		logrus.Warnf("set %s in cache: %v", key, err)
+	}
+	return value, nil
+}
+
+// Invalidate deletes the entries in the namespaces of the prefixes. A nil cache does nothing.
+func Invalidate(ctx context.Context, c Cache, prefixes ...string) {
+	if c == nil {
+		return
+	}
+	for _, prefix := range prefixes {
+		if err := c.DeletePrefix(ctx, prefix); err != nil {
+			logrus.Errorf("invalidate %s in cache: %v", prefix, err)
+		}
+	}
+}
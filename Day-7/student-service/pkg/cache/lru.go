@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+type lru struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	now     func() time.Time
+	order   *list.List
+	entries map[string]*list.Element
+	// generations counts the calls to DeletePrefix by prefix, the generation of a key is the
+	// sum of the counts of its prefixes. Services invalidate a few constant prefixes, the map
+	// stays small.
+	generations map[string]uint64
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRU returns an in-memory cache of at most size entries, the least recently used entry is
+// evicted to make room. Entries expire ttl after they are set.
+func NewLRU(size int, ttl time.Duration) Cache {
+	return &lru{
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+
+		generations: map[string]uint64{},
+	}
+}
+
+func (c *lru) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := element.Value.(*lruEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.remove(element)
+		return nil, false, nil
+	}
+	c.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+func (c *lru) Generation(ctx context.Context, key string) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generation(key), nil
+}
+
+func (c *lru) generation(key string) uint64 {
+	var generation uint64
+	for prefix, count := range c.generations {
+		if strings.HasPrefix(key, prefix) {
+			generation += count
+		}
+	}
+	return generation
+}
+
+func (c *lru) Set(ctx context.Context, key string, value []byte, generation uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generation(key) != generation {
+		return nil
+	}
+
+	entry := &lruEntry{key: key, value: value, expiresAt: c.now().Add(c.ttl)}
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return nil
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+func (c *lru) DeletePrefix(ctx context.Context, prefix string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generations[prefix]++
+	for key, element := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(element)
+		}
+	}
+	return nil
+}
+
+func (c *lru) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*lruEntry).key)
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ctx = context.Background()
+
+func TestLRU(t *testing.T) {
+	asserts := assert.New(t)
+	now := time.Now()
+	c := NewLRU(2, time.Minute).(*lru)
+	c.now = func() time.Time { return now }
+
+	asserts.NoError(c.Set(ctx, "classes:1", []byte("A"), 0))
+	asserts.NoError(c.Set(ctx, "classes:2", []byte("B"), 0))
+	value, ok, err := c.Get(ctx, "classes:1")
+	asserts.NoError(err)
+	asserts.True(ok)
+	asserts.Equal([]byte("A"), value)
+
+	// classes:2 is the least recently used
+	asserts.NoError(c.Set(ctx, "majors:1", []byte("C"), 0))
+	_, ok, _ = c.Get(ctx, "classes:2")
+	asserts.False(ok)
+
+	asserts.NoError(c.DeletePrefix(ctx, "classes:"))
+	_, ok, _ = c.Get(ctx, "classes:1")
+	asserts.False(ok)
+	_, ok, _ = c.Get(ctx, "majors:1")
+	asserts.True(ok)
+
+	now = now.Add(time.Minute)
+	_, ok, _ = c.Get(ctx, "majors:1")
+	asserts.False(ok)
+}
+
+func TestFetch(t *testing.T) {
+	asserts := assert.New(t)
+	c := NewLRU(10, time.Minute)
+
+	loads := 0
+	load := func() ([]string, error) {
+		loads++
+		return []string{"A", "B"}, nil
+	}
+	for i := 0; i < 2; i++ {
+		value, err := Fetch(ctx, c, Key("classes:", map[string]int{"page": 1}), load)
+		asserts.NoError(err)
+		asserts.Equal([]string{"A", "B"}, value)
+	}
+	asserts.Equal(1, loads)
+
+	_, err := Fetch(ctx, c, Key("classes:", map[string]int{"page": 2}), func() ([]string, error) {
+		return nil, errors.New("database is down")
+	})
+	asserts.Error(err)
+	value, err := Fetch(ctx, c, Key("classes:", map[string]int{"page": 2}), load)
+	asserts.NoError(err)
+	asserts.Equal([]string{"A", "B"}, value)
+	asserts.Equal(2, loads)
+
+	Invalidate(ctx, c, "classes:")
+	_, _ = Fetch(ctx, c, Key("classes:", map[string]int{"page": 1}), load)
+	asserts.Equal(3, loads)
+
+	// a value loaded while the key is invalidated is not cached, it may be older than the write
+	Invalidate(ctx, c, "classes:")
+	_, _ = Fetch(ctx, c, Key("classes:", map[string]int{"page": 1}), func() ([]string, error) {
+		loads++
+		Invalidate(ctx, c, "classes:")
+		return []string{"stale"}, nil
+	})
+	value, err = Fetch(ctx, c, Key("classes:", map[string]int{"page": 1}), load)
+	asserts.NoError(err)
+	asserts.Equal([]string{"A", "B"}, value)
+	asserts.Equal(5, loads)
+
+	// without a cache every call loads
+	_, _ = Fetch(ctx, nil, "classes:", load)
+	Invalidate(ctx, nil, "classes:")
+	asserts.Equal(6, loads)
+}
@@ -0,0 +1,83 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]string{
+		"":                "expression \"\" must have 5 fields, it has 0",
+		"* * * *":         "must have 5 fields, it has 4",
+		"60 * * * *":      "invalid value \"60\" in minute field, it must be between 0 and 59",
+		"* 24 * * *":      "invalid value \"24\" in hour field",
+		"* * 0 * *":       "invalid value \"0\" in day of month field",
+		"* * * 13 *":      "invalid value \"13\" in month field",
+		"* * * * 8":       "invalid value \"8\" in day of week field",
+		"*/0 * * * *":     "invalid step \"0\" in minute field",
+		"5-1 * * * *":     "invalid range \"5-1\" in minute field",
+		"* * * FOO *":     "invalid value \"FOO\" in month field",
+		"@fortnightly":    "unknown descriptor \"@fortnightly\"",
+		"1,,2 * * * *":    "invalid value \"\" in minute field",
+		"* * * * MON-FOO": "invalid value \"FOO\" in day of week field",
+	}
+	for spec, want := range cases {
+		_, err := Parse(spec)
+		if assert.Error(t, err, spec) {
+			assert.Contains(t, err.Error(), want, spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	// a Monday
+	from := time.Date(2024, time.January, 15, 10, 20, 30, 0, time.UTC)
+	cases := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.January, 15, 10, 21, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)},
+		{"20 10 * * *", time.Date(2024, time.January, 16, 10, 20, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2024, time.January, 16, 3, 0, 0, 0, time.UTC)},
+		{"5,45 9-11 * * *", time.Date(2024, time.January, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * FRI", time.Date(2024, time.January, 19, 12, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2024, time.January, 21, 12, 0, 0, 0, time.UTC)},
+		{"0 12 * * 1-5/2", time.Date(2024, time.January, 15, 12, 0, 0, 0, time.UTC)},
+		{"30 8 1 JUN *", time.Date(2024, time.June, 1, 8, 30, 0, 0, time.UTC)},
+		// leap day
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// the day of month or the day of week
+		{"0 0 20 * MON", time.Date(2024, time.January, 20, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * MON", time.Date(2024, time.January, 22, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, time.January, 15, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2024, time.January, 21, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tc := range cases {
+		schedule, err := Parse(tc.spec)
+		if assert.NoError(t, err, tc.spec) {
+			assert.Equal(t, tc.next, schedule.Next(from), tc.spec)
+			assert.Equal(t, tc.spec, schedule.String())
+		}
+	}
+}
+
+func TestNextKeepsTheLocation(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	schedule, _ := Parse("0 3 * * *")
+
+	next := schedule.Next(time.Date(2024, time.January, 15, 2, 0, 0, 0, jakarta))
+	assert.Equal(t, time.Date(2024, time.January, 15, 3, 0, 0, 0, jakarta), next)
+	assert.Equal(t, jakarta, next.Location())
+
+	// an offset of half an hour
+	kolkata := time.FixedZone("IST", 5*60*60+30*60)
+	schedule, _ = Parse("15 * * * *")
+	next = schedule.Next(time.Date(2024, time.January, 15, 2, 20, 0, 0, kolkata))
+	assert.Equal(t, time.Date(2024, time.January, 15, 3, 15, 0, 0, kolkata), next)
+}
@@ -29,7 +29,24 @@ type PaginationInfo struct {
 }
 
 type ByIDRequest struct {
-	ID uint `param:"id" validate:"required"`
+	ID      uint   `param:"id" validate:"required"`
+	IfMatch string `json:"-"`
+	// Version is the version a batch item expects the record to be at, the If-Match header of
+	// the single-item routes.
+	Version *uint `json:"version"`
+}
+
+// Precondition returns the If-Match of the request and the version of the batch item.
+func (r *ByIDRequest) Precondition() (*string, *uint) {
+	return &r.IfMatch, r.Version
+}
+
+// PatchByIDRequest is a PATCH of the record with the given id. Patch is the raw request body,
+// ContentType tells which patch format it is.
+type PatchByIDRequest struct {
+	ByIDRequest
+	ContentType string `json:"-"`
+	Patch       []byte `json:"-"`
 }
 
 func GetLimitOffset(p *Pagination) (limit, offset int) {
@@ -71,3 +88,25 @@ func CheckInfoPagination(p *Pagination, count int64) *PaginationInfo {
 
 	return &info
 }
+
+const (
+	BatchModeAtomic     = "atomic"
+	BatchModeBestEffort = "best_effort"
+)
+
+// BatchRequest applies the same operation to every item. In atomic mode (the default) all items
+// are applied in one transaction or none is, in best effort mode every item is applied on its own.
+type BatchRequest[T any] struct {
+	Mode  string `json:"mode" validate:"omitempty,oneof=atomic best_effort"`
+	Items []T    `json:"items" validate:"required,min=1,max=100"`
+}
+
+func (r *BatchRequest[T]) IsAtomic() bool {
+	return r.Mode != BatchModeBestEffort
+}
+
+// Versioned is a write with a precondition on the version of its record: the If-Match header
+// of a single-item route, or the version of a batch item.
+type Versioned interface {
+	Precondition() (ifMatch *string, version *uint)
+}
@@ -0,0 +1,109 @@
+// Package events publishes domain events to in-process subscribers and to a message broker.
+//
+// Events are published at least once: subscribers and brokers may see an event again after a
+// failure, and should use its ID to drop the ones they already handled.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"student-service/pkg/util"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Event is a change of an aggregate, such as a student, a class or a major.
+type Event struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// Broker publishes events outside of the service. Publish returns once the broker accepted the
+// event.
+type Broker interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// NewBroker returns the broker of EVENT_BROKER, nil when it is not set. "log" writes the events
+// to the standard output.
+func NewBroker() Broker {
+	switch kind := util.Getenv("EVENT_BROKER", ""); kind {
+	case "":
+		return nil
+	case "log":
+		return NewLogBroker(os.Stdout)
+	default:
+		logrus.Warnf("invalid EVENT_BROKER %q, publishing to no broker", kind)
+		return nil
+	}
+}
+
+// Handler handles an event published to a Bus.
+type Handler func(ctx context.Context, event Event) error
+
+// All subscribes a handler to every event type.
+const All = "*"
+
+// Bus is a Broker that publishes events to in-process handlers.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{handlers: map[string][]Handler{}}
+}
+
+// Subscribe calls handler for the events of eventType, or for every event with All.
+func (b *Bus) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish calls the handlers of the event in the order they subscribed, those of All last. Every
+// handler is called even when one fails, the first error is returned.
+func (b *Bus) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	handlers := append(append([]Handler{}, b.handlers[event.Type]...), b.handlers[All]...)
+	b.mu.RUnlock()
+
+	var first error
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil && first == nil {
+			first = fmt.Errorf("handle %s %s: %w", event.Type, event.ID, err)
+		}
+	}
+	return first
+}
+
+// LogBroker writes every event as a line of JSON.
+type LogBroker struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewLogBroker(w io.Writer) *LogBroker {
+	return &LogBroker{w: w}
+}
+
+func (b *LogBroker) Publish(ctx context.Context, event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, err = b.w.Write(append(line, '\n'))
+	return err
+}
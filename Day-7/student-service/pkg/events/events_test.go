@@ -0,0 +1,54 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBusPublish(t *testing.T) {
+	var calls []string
+	record := func(name string, err error) Handler {
+		return func(ctx context.Context, event Event) error {
+			calls = append(calls, name+":"+event.ID)
+			return err
+		}
+	}
+
+	bus := NewBus()
+	bus.Subscribe(All, record("all", nil))
+	bus.Subscribe("major.deleted", record("first", errors.New("unavailable")))
+	bus.Subscribe("major.deleted", record("second", nil))
+	bus.Subscribe("major.created", record("created", nil))
+
+	asserts := assert.New(t)
+	err := bus.Publish(context.Background(), Event{ID: "evt_1", Type: "major.deleted"})
+	asserts.EqualError(err, "handle major.deleted evt_1: unavailable")
+	asserts.Equal([]string{"first:evt_1", "second:evt_1", "all:evt_1"}, calls)
+
+	calls = nil
+	asserts.NoError(bus.Publish(context.Background(), Event{ID: "evt_2", Type: "class.created"}))
+	asserts.Equal([]string{"all:evt_2"}, calls)
+}
+
+func TestLogBroker(t *testing.T) {
+	var buf bytes.Buffer
+	broker := NewLogBroker(&buf)
+	event := Event{
+		ID:            "evt_1",
+		Type:          "student.registered",
+		AggregateType: "student",
+		AggregateID:   "3",
+		OccurredAt:    time.Unix(1700000000, 0).UTC(),
+		Data:          json.RawMessage(`{"id":3}`),
+	}
+
+	asserts := assert.New(t)
+	asserts.NoError(broker.Publish(context.Background(), event))
+	asserts.Equal(`{"id":"evt_1","type":"student.registered","aggregate_type":"student","aggregate_id":"3","occurred_at":"2023-11-14T22:13:20Z","data":{"id":3}}`+"\n", buf.String())
+}
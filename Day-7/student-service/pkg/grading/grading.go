@@ -0,0 +1,161 @@
+package grading
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"student-service/pkg/util"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Grade is a letter grade of a scale. A final score of at least MinScore earns the letter.
+type Grade struct {
+	Letter   string  `json:"letter"`
+	Points   float64 `json:"points"`
+	MinScore float64 `json:"min_score"`
+}
+
+// Scale maps letter grades to grade points.
+type Scale struct {
+	Name   string  `json:"name"`
+	Grades []Grade `json:"grades"`
+}
+
+var scales = map[string]*Scale{
+	"4.0": {
+		Name: "4.0",
+		Grades: []Grade{
+			{Letter: "A", Points: 4, MinScore: 93},
+			{Letter: "A-", Points: 3.7, MinScore: 90},
+			{Letter: "B+", Points: 3.3, MinScore: 87},
+			{Letter: "B", Points: 3, MinScore: 83},
+			{Letter: "B-", Points: 2.7, MinScore: 80},
+			{Letter: "C+", Points: 2.3, MinScore: 77},
+			{Letter: "C", Points: 2, MinScore: 73},
+			{Letter: "C-", Points: 1.7, MinScore: 70},
+			{Letter: "D", Points: 1, MinScore: 60},
+			{Letter: "F", Points: 0, MinScore: 0},
+		},
+	},
+	"id": {
+		Name: "id",
+		Grades: []Grade{
+			{Letter: "A", Points: 4, MinScore: 80},
+			{Letter: "AB", Points: 3.5, MinScore: 75},
+			{Letter: "B", Points: 3, MinScore: 70},
+			{Letter: "BC", Points: 2.5, MinScore: 65},
+			{Letter: "C", Points: 2, MinScore: 60},
+			{Letter: "D", Points: 1, MinScore: 50},
+			{Letter: "E", Points: 0, MinScore: 0},
+		},
+	},
+}
+
+// Lookup returns the built-in scale with the given name ("4.0" or "id").
+func Lookup(name string) (*Scale, bool) {
+	scale, ok := scales[name]
+	return scale, ok
+}
+
+// DefaultScale returns the scale configured with GRADE_SCALE_FILE, a JSON encoded Scale, or
+// else the built-in scale named by GRADE_SCALE. It falls back to the "4.0" scale when the
+// configuration is invalid.
+func DefaultScale() *Scale {
+	scale, err := loadScale()
+	if err != nil {
+		logrus.Warnf("invalid grade scale configuration, using 4.0: %v", err)
+		return scales["4.0"]
+	}
+	return scale
+}
+
+func loadScale() (*Scale, error) {
+	if path := util.Getenv("GRADE_SCALE_FILE", ""); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		scale := new(Scale)
+		if err := json.Unmarshal(data, scale); err != nil {
+			return nil, err
+		}
+		return scale, scale.validate()
+	}
+
+	name := util.Getenv("GRADE_SCALE", "4.0")
+	scale, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown grade scale %q", name)
+	}
+	return scale, nil
+}
+
+// validate checks the letters are unique and one of them is earned by every score, and
+// orders the grades from the highest minimum score.
+func (s *Scale) validate() error {
+	if len(s.Grades) == 0 {
+		return errors.New("grade scale has no grades")
+	}
+	letters := map[string]bool{}
+	for _, grade := range s.Grades {
+		if grade.Letter == "" || letters[grade.Letter] {
+			return fmt.Errorf("grade scale has an empty or duplicate letter %q", grade.Letter)
+		}
+		letters[grade.Letter] = true
+	}
+	sort.SliceStable(s.Grades, func(i, j int) bool {
+		return s.Grades[i].MinScore > s.Grades[j].MinScore
+	})
+	if s.Grades[len(s.Grades)-1].MinScore > 0 {
+		return errors.New("grade scale has no grade for a score of 0")
+	}
+	return nil
+}
+
+// Points returns the grade points of letter, false when the scale has no such letter.
+func (s *Scale) Points(letter string) (float64, bool) {
+	for _, grade := range s.Grades {
+		if grade.Letter == letter {
+			return grade.Points, true
+		}
+	}
+	return 0, false
+}
+
+// Letter returns the letter earned by a final score between 0 and 100.
+func (s *Scale) Letter(score float64) string {
+	for _, grade := range s.Grades {
+		if score >= grade.MinScore {
+			return grade.Letter
+		}
+	}
+	return s.Grades[len(s.Grades)-1].Letter
+}
+
+// Result is a graded course counted in a GPA.
+type Result struct {
+	Credits uint
+	Points  float64
+}
+
+// GPA returns the credit weighted average of the grade points rounded to two decimals, and
+// the credits it is computed from.
+func GPA(results []Result) (float64, uint) {
+	var (
+		credits uint
+		total   float64
+	)
+	for _, result := range results {
+		credits += result.Credits
+		total += result.Points * float64(result.Credits)
+	}
+	if credits == 0 {
+		return 0, 0
+	}
+	return math.Round(total/float64(credits)*100) / 100, credits
+}
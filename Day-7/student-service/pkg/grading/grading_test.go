@@ -0,0 +1,61 @@
+package grading
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScaleLetterAndPoints(t *testing.T) {
+	scale, _ := Lookup("id")
+	asserts := assert.New(t)
+
+	asserts.Equal("A", scale.Letter(100))
+	asserts.Equal("AB", scale.Letter(75))
+	asserts.Equal("BC", scale.Letter(69.9))
+	asserts.Equal("E", scale.Letter(0))
+
+	points, ok := scale.Points("AB")
+	asserts.True(ok)
+	asserts.Equal(3.5, points)
+	_, ok = scale.Points("A-")
+	asserts.False(ok)
+}
+
+func TestGPA(t *testing.T) {
+	gpa, credits := GPA([]Result{{Credits: 3, Points: 4}, {Credits: 4, Points: 3}, {Credits: 2, Points: 2.7}})
+	assert.Equal(t, 3.27, gpa)
+	assert.Equal(t, uint(9), credits)
+
+	gpa, credits = GPA(nil)
+	assert.Equal(t, 0.0, gpa)
+	assert.Equal(t, uint(0), credits)
+}
+
+func TestDefaultScale(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Setenv("GRADE_SCALE", "id")
+	asserts.Equal("id", DefaultScale().Name)
+
+	t.Setenv("GRADE_SCALE", "unknown")
+	asserts.Equal("4.0", DefaultScale().Name)
+
+	path := filepath.Join(t.TempDir(), "scale.json")
+	scale := `{"name":"pass-fail","grades":[{"letter":"F","points":0,"min_score":0},{"letter":"P","points":4,"min_score":50}]}`
+	if err := os.WriteFile(path, []byte(scale), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GRADE_SCALE_FILE", path)
+	custom := DefaultScale()
+	asserts.Equal("pass-fail", custom.Name)
+	asserts.Equal("P", custom.Letter(50))
+	asserts.Equal("F", custom.Letter(49))
+
+	if err := os.WriteFile(path, []byte(`{"name":"broken","grades":[{"letter":"P","points":4,"min_score":50}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	asserts.Equal("4.0", DefaultScale().Name)
+}
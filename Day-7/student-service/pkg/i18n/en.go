@@ -0,0 +1,81 @@
+package i18n
+
+var en = Messages{
+	"error.duplicate":                   "Created value already exists",
+	"error.batch-aborted":               "Not applied because another item of the batch failed",
+	"error.conflict":                    "Request conflicted with another change, please retry",
+	"error.course-full":                 "Course has no seat left",
+	"error.already-enrolled":            "Student is already enrolled in the course",
+	"error.capacity-below-enrolled":     "Capacity cannot be lower than the number of enrolled students",
+	"error.unknown-grade-letter":        "Letter grade is not in the grade scale",
+	"error.invalid-assessment-weights":  "Assessment weights must add up to 100",
+	"error.term-closed":                 "Term is closed and can no longer be changed",
+	"error.no-active-term":              "No term is active, please choose a term",
+	"error.invalid-term-dates":          "Term cannot end before it starts",
+	"error.invalid-session-date":        "Session date is outside of its term",
+	"error.student-not-in-class":        "Student is not in the class of the session",
+	"error.booking-conflict":            "Room is already booked at this time",
+	"error.invalid-booking-time":        "Booking must end after it starts and before it repeats",
+	"error.invalid-booking-owner":       "Booking is either for a student or for a class",
+	"error.invalid-birth-date":          "Birth date must be in the past",
+	"error.student-numbers-exhausted":   "No student number is left for the major and intake year",
+	"error.file-too-large":              "File is larger than allowed",
+	"error.unsupported-file-type":       "File type is not allowed for this attachment",
+	"error.invalid-download-url":        "Download link is invalid or has expired",
+	"error.invalid-reference":           "Referenced data does not exist or is still in use",
+	"error.idempotency-key-reused":      "Idempotency-Key was already used with a different payload",
+	"error.idempotency-key-in-progress": "A request with this Idempotency-Key is still being processed",
+	"error.job-not-dead":                "Only dead jobs can be retried",
+	"error.precondition-failed":         "Data has been modified, please reload and try again",
+	"error.email-or-password-incorrect": "Email or password is incorrect",
+	"error.not-found":                   "Data not found",
+	"error.route-not-found":             "Route not found",
+	"error.unprocessable-entity":        "Invalid parameters or payload",
+	"error.unsupported-media-type":      "Unsupported media type",
+	"error.request-too-large":           "Request body is larger than allowed",
+	"error.unauthorized":                "Unauthorized, please login or use different class",
+	"error.bad-request":                 "Bad Request",
+	"error.validation":                  "Invalid parameters or payload",
+	"error.server-error":                "Something bad happened",
+
+	RequestSuccess:              "Request successfully proceed",
+	GetStudentsSuccess:          "Get students success",
+	GetClassesSuccess:           "Get classes success",
+	GetMajorsSuccess:            "Get majors success",
+	GetCoursesSuccess:           "Get courses success",
+	GetTermsSuccess:             "Get terms success",
+	GetClassSessionsSuccess:     "Get class sessions success",
+	GetRoomsSuccess:             "Get rooms success",
+	GetBookingsSuccess:          "Get bookings success",
+	GetAttachmentsSuccess:       "Get attachments success",
+	GetWebhooksSuccess:          "Get webhooks success",
+	GetWebhookDeliveriesSuccess: "Get webhook deliveries success",
+	GetJobsSuccess:              "Get jobs success",
+	GetTaskRunsSuccess:          "Get task runs success",
+
+	BatchPartialSuccess: "Some items of the batch failed",
+	BatchRolledBack:     "The batch failed, no item was applied",
+
+	// arguments: field, param, tag
+	"validation.required":         "%[1]s is required",
+	"validation.email":            "%[1]s must be a valid email address",
+	"validation.exists":           "%[1]s does not refer to an existing record in %[2]s",
+	"validation.unique":           "%[1]s is already taken",
+	"validation.min":              "%[1]s must be at least %[2]s",
+	"validation.max":              "%[1]s must be at most %[2]s",
+	"validation.len":              "%[1]s must be exactly %[2]s long",
+	"validation.gt":               "%[1]s must be greater than %[2]s",
+	"validation.gte":              "%[1]s must be greater than or equal to %[2]s",
+	"validation.lt":               "%[1]s must be less than %[2]s",
+	"validation.lte":              "%[1]s must be less than or equal to %[2]s",
+	"validation.required_without": "%[1]s is required when %[2]s is not present",
+	"validation.oneof":            "%[1]s must be one of [%[2]s]",
+	"validation.numeric":          "%[1]s must be numeric",
+	"validation.url":              "%[1]s must be a valid URL",
+	"validation.date":             "%[1]s must be a date formatted as YYYY-MM-DD",
+	"validation.default":          "%[1]s is invalid (%[3]s)",
+
+	// arguments: fullname, email
+	WelcomeEmailSubject: "Welcome to Student Service",
+	WelcomeEmailBody:    "Hi %[1]s,\n\nYour student account has been created. You can now log in with %[2]s.\n",
+}
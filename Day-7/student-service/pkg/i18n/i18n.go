@@ -0,0 +1,93 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/text/language"
+)
+
+// Messages maps message keys to a format string. Format strings use explicit argument
+// indexes (%[1]s) so translations can reorder them.
+type Messages map[string]string
+
+// Default is used when the client does not ask for a supported language,
+// and for keys missing in the requested language.
+var Default = language.English
+
+var (
+	supported = []language.Tag{language.English, language.Indonesian}
+	matcher   = language.NewMatcher(supported)
+	catalog   = map[language.Tag]Messages{
+		language.English:    en,
+		language.Indonesian: id,
+	}
+)
+
+type languageKey struct{}
+
+// Match returns the supported language that best matches an Accept-Language header.
+func Match(acceptLanguage string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	return supported[index]
+}
+
+func WithLanguage(ctx context.Context, lang language.Tag) context.Context {
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+// FromContext returns the language stored by WithLanguage, or Default.
+func FromContext(ctx context.Context) language.Tag {
+	if lang, ok := ctx.Value(languageKey{}).(language.Tag); ok {
+		return lang
+	}
+	return Default
+}
+
+// FromRequest returns the language of the request context, or the one matching its
+// Accept-Language header when the language middleware did not run.
+func FromRequest(r *http.Request) language.Tag {
+	if lang, ok := r.Context().Value(languageKey{}).(language.Tag); ok {
+		return lang
+	}
+	return Match(r.Header.Get("Accept-Language"))
+}
+
+// Lookup returns the message of key in lang, falling back to Default.
+func Lookup(lang language.Tag, key string, args ...interface{}) (string, bool) {
+	format, ok := catalog[lang][key]
+	if !ok {
+		format, ok = catalog[Default][key]
+	}
+	if !ok {
+		return "", false
+	}
+	if len(args) == 0 {
+		return format, true
+	}
+	return fmt.Sprintf(format, args...), true
+}
+
+// Translate returns the message of key in lang, or key itself when it is not in the catalog.
+func Translate(lang language.Tag, key string, args ...interface{}) string {
+	if message, ok := Lookup(lang, key, args...); ok {
+		return message
+	}
+	return key
+}
+
+// ValidationMessage returns the message for a field that failed the given validation tag.
+func ValidationMessage(lang language.Tag, field, tag, param string) string {
+	if message, ok := Lookup(lang, "validation."+tag, field, param, tag); ok {
+		return message
+	}
+	return Translate(lang, "validation.default", field, param, tag)
+}
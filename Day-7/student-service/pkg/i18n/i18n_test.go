@@ -0,0 +1,66 @@
+package i18n
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestMatch(t *testing.T) {
+	cases := map[string]language.Tag{
+		"":                        language.English,
+		"id":                      language.Indonesian,
+		"id-ID,id;q=0.9,en;q=0.8": language.Indonesian,
+		"en-US,en;q=0.9,id;q=0.8": language.English,
+		"fr-FR":                   language.English,
+		"fr-FR,id;q=0.5":          language.Indonesian,
+		"not a language":          language.English,
+	}
+	for header, expected := range cases {
+		assert.Equal(t, expected, Match(header), header)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	asserts := assert.New(t)
+
+	asserts.Equal("Data tidak ditemukan", Translate(language.Indonesian, "error.not-found"))
+	asserts.Equal("Data not found", Translate(language.English, "error.not-found"))
+	asserts.Equal("Berhasil mengambil data kelas", Translate(language.Indonesian, GetClassesSuccess))
+	asserts.Equal("Data not found", Translate(language.French, "error.not-found"))
+	asserts.Equal("Some literal message", Translate(language.Indonesian, "Some literal message"))
+}
+
+func TestValidationMessage(t *testing.T) {
+	asserts := assert.New(t)
+
+	asserts.Equal("email wajib diisi", ValidationMessage(language.Indonesian, "email", "required", ""))
+	asserts.Equal("page must be at least 1", ValidationMessage(language.English, "page", "min", "1"))
+	asserts.Equal("name tidak valid (alphanum)", ValidationMessage(language.Indonesian, "name", "alphanum", ""))
+}
+
+func TestCatalogIsComplete(t *testing.T) {
+	for _, lang := range supported {
+		for key := range catalog[Default] {
+			_, ok := catalog[lang][key]
+			assert.True(t, ok, "%s has no translation for %s", lang, key)
+		}
+		for key := range catalog[lang] {
+			_, ok := catalog[Default][key]
+			assert.True(t, ok, "%s translates %s which is not in the default catalog", lang, key)
+		}
+	}
+}
+
+func TestFromRequest(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", "id")
+	assert.Equal(t, language.Indonesian, FromRequest(req))
+
+	req = req.WithContext(WithLanguage(context.Background(), language.English))
+	assert.Equal(t, language.English, FromRequest(req))
+	assert.Equal(t, Default, FromContext(context.Background()))
+}
@@ -0,0 +1,81 @@
+package i18n
+
+var id = Messages{
+	"error.duplicate":                   "Data yang dibuat sudah ada",
+	"error.batch-aborted":               "Tidak diterapkan karena item lain dalam batch gagal",
+	"error.conflict":                    "Permintaan bentrok dengan perubahan lain, silakan coba lagi",
+	"error.course-full":                 "Kuota mata kuliah sudah penuh",
+	"error.already-enrolled":            "Mahasiswa sudah terdaftar di mata kuliah ini",
+	"error.capacity-below-enrolled":     "Kuota tidak boleh lebih kecil dari jumlah mahasiswa yang terdaftar",
+	"error.unknown-grade-letter":        "Nilai huruf tidak ada dalam skala nilai",
+	"error.invalid-assessment-weights":  "Jumlah bobot penilaian harus 100",
+	"error.term-closed":                 "Semester sudah ditutup dan tidak dapat diubah lagi",
+	"error.no-active-term":              "Tidak ada semester yang aktif, silakan pilih semester",
+	"error.invalid-term-dates":          "Semester tidak boleh berakhir sebelum dimulai",
+	"error.invalid-session-date":        "Tanggal pertemuan berada di luar semesternya",
+	"error.student-not-in-class":        "Mahasiswa tidak terdaftar di kelas pertemuan ini",
+	"error.booking-conflict":            "Ruangan sudah dipesan pada waktu ini",
+	"error.invalid-booking-time":        "Pemesanan harus berakhir setelah dimulai dan sebelum berulang",
+	"error.invalid-booking-owner":       "Pemesanan hanya untuk satu mahasiswa atau satu kelas",
+	"error.invalid-birth-date":          "Tanggal lahir harus di masa lalu",
+	"error.student-numbers-exhausted":   "Nomor induk mahasiswa untuk jurusan dan angkatan ini sudah habis",
+	"error.file-too-large":              "Ukuran berkas melebihi batas",
+	"error.unsupported-file-type":       "Jenis berkas tidak diizinkan untuk lampiran ini",
+	"error.invalid-download-url":        "Tautan unduhan tidak valid atau sudah kedaluwarsa",
+	"error.invalid-reference":           "Data yang dirujuk tidak ada atau masih digunakan",
+	"error.idempotency-key-reused":      "Idempotency-Key sudah digunakan dengan payload yang berbeda",
+	"error.idempotency-key-in-progress": "Permintaan dengan Idempotency-Key ini masih diproses",
+	"error.job-not-dead":                "Hanya job yang mati yang dapat dicoba lagi",
+	"error.precondition-failed":         "Data telah diubah, silakan muat ulang dan coba lagi",
+	"error.email-or-password-incorrect": "Email atau kata sandi salah",
+	"error.not-found":                   "Data tidak ditemukan",
+	"error.route-not-found":             "Rute tidak ditemukan",
+	"error.unprocessable-entity":        "Parameter atau payload tidak valid",
+	"error.unsupported-media-type":      "Tipe media tidak didukung",
+	"error.request-too-large":           "Ukuran permintaan melebihi batas",
+	"error.unauthorized":                "Tidak memiliki akses, silakan login atau gunakan kelas lain",
+	"error.bad-request":                 "Permintaan tidak valid",
+	"error.validation":                  "Parameter atau payload tidak valid",
+	"error.server-error":                "Terjadi kesalahan pada server",
+
+	RequestSuccess:              "Permintaan berhasil diproses",
+	GetStudentsSuccess:          "Berhasil mengambil data mahasiswa",
+	GetClassesSuccess:           "Berhasil mengambil data kelas",
+	GetMajorsSuccess:            "Berhasil mengambil data jurusan",
+	GetCoursesSuccess:           "Berhasil mengambil data mata kuliah",
+	GetTermsSuccess:             "Berhasil mengambil data semester",
+	GetClassSessionsSuccess:     "Berhasil mengambil data pertemuan kelas",
+	GetRoomsSuccess:             "Berhasil mengambil data ruangan",
+	GetBookingsSuccess:          "Berhasil mengambil data pemesanan",
+	GetAttachmentsSuccess:       "Berhasil mengambil data lampiran",
+	GetWebhooksSuccess:          "Berhasil mengambil data webhook",
+	GetWebhookDeliveriesSuccess: "Berhasil mengambil data pengiriman webhook",
+	GetJobsSuccess:              "Berhasil mengambil data job",
+	GetTaskRunsSuccess:          "Berhasil mengambil data riwayat tugas terjadwal",
+
+	BatchPartialSuccess: "Sebagian item dalam batch gagal",
+	BatchRolledBack:     "Batch gagal, tidak ada item yang diterapkan",
+
+	// arguments: field, param, tag
+	"validation.required":         "%[1]s wajib diisi",
+	"validation.email":            "%[1]s harus berupa alamat email yang valid",
+	"validation.exists":           "%[1]s tidak merujuk ke data yang ada di %[2]s",
+	"validation.unique":           "%[1]s sudah digunakan",
+	"validation.min":              "%[1]s minimal %[2]s",
+	"validation.max":              "%[1]s maksimal %[2]s",
+	"validation.len":              "panjang %[1]s harus tepat %[2]s",
+	"validation.gt":               "%[1]s harus lebih besar dari %[2]s",
+	"validation.gte":              "%[1]s harus lebih besar dari atau sama dengan %[2]s",
+	"validation.lt":               "%[1]s harus lebih kecil dari %[2]s",
+	"validation.lte":              "%[1]s harus lebih kecil dari atau sama dengan %[2]s",
+	"validation.required_without": "%[1]s wajib diisi jika %[2]s tidak ada",
+	"validation.oneof":            "%[1]s harus salah satu dari [%[2]s]",
+	"validation.numeric":          "%[1]s harus berupa angka",
+	"validation.url":              "%[1]s harus berupa URL yang valid",
+	"validation.date":             "%[1]s harus berupa tanggal dengan format YYYY-MM-DD",
+	"validation.default":          "%[1]s tidak valid (%[3]s)",
+
+	// arguments: fullname, email
+	WelcomeEmailSubject: "Selamat datang di Student Service",
+	WelcomeEmailBody:    "Halo %[1]s,\n\nAkun mahasiswa Anda telah dibuat. Sekarang Anda dapat login dengan %[2]s.\n",
+}
@@ -0,0 +1,25 @@
+package i18n
+
+// Message keys used outside of the error kinds and validation tags.
+const (
+	RequestSuccess              = "success.request"
+	GetStudentsSuccess          = "success.get_students"
+	GetClassesSuccess           = "success.get_classes"
+	GetMajorsSuccess            = "success.get_majors"
+	GetCoursesSuccess           = "success.get_courses"
+	GetTermsSuccess             = "success.get_terms"
+	GetClassSessionsSuccess     = "success.get_class_sessions"
+	GetRoomsSuccess             = "success.get_rooms"
+	GetBookingsSuccess          = "success.get_bookings"
+	GetAttachmentsSuccess       = "success.get_attachments"
+	GetWebhooksSuccess          = "success.get_webhooks"
+	GetWebhookDeliveriesSuccess = "success.get_webhook_deliveries"
+	GetJobsSuccess              = "success.get_jobs"
+	GetTaskRunsSuccess          = "success.get_task_runs"
+
+	BatchPartialSuccess = "batch.partial_success"
+	BatchRolledBack     = "batch.rolled_back"
+
+	WelcomeEmailSubject = "email.welcome.subject"
+	WelcomeEmailBody    = "email.welcome.body"
+)
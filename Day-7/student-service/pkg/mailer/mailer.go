@@ -0,0 +1,71 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"student-service/pkg/util"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// NewMailer returns an SMTP mailer when SMTP_HOST is set, and a mailer that only logs otherwise.
+func NewMailer() Mailer {
+	host := util.Getenv("SMTP_HOST", "")
+	if host == "" {
+		return logMailer{}
+	}
+	return &smtpMailer{
+		addr: host + ":" + util.Getenv("SMTP_PORT", "587"),
+		host: host,
+		user: util.Getenv("SMTP_USERNAME", ""),
+		pass: util.Getenv("SMTP_PASSWORD", ""),
+		from: util.Getenv("SMTP_FROM", "no-reply@edu.ac.id"),
+	}
+}
+
+type smtpMailer struct {
+	addr string
+	host string
+	user string
+	pass string
+	from string
+}
+
+func (m *smtpMailer) Send(ctx context.Context, message Message) error {
+	var auth smtp.Auth
+	if m.user != "" {
+		auth = smtp.PlainAuth("", m.user, m.pass, m.host)
+	}
+	return smtp.SendMail(m.addr, auth, m.from, []string{message.To}, m.build(message))
+}
+
+func (m *smtpMailer) build(message Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.from)
+	fmt.Fprintf(&b, "To: %s\r\n", message.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", message.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+type logMailer struct{}
+
+func (logMailer) Send(ctx context.Context, message Message) error {
+	logrus.WithFields(logrus.Fields{"to": message.To, "subject": message.Subject}).Info("email not sent, SMTP_HOST is not set")
+	return nil
+}
@@ -0,0 +1,29 @@
+package mailer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSMTPMailerBuild(t *testing.T) {
+	m := &smtpMailer{from: "no-reply@edu.ac.id"}
+
+	raw := string(m.build(Message{
+		To:      "azka@edu.ac.id",
+		Subject: "Welcome",
+		Body:    "Hi Azka,\n\nWelcome.\n",
+	}))
+
+	assert.Equal(t, "From: no-reply@edu.ac.id\r\n"+
+		"To: azka@edu.ac.id\r\n"+
+		"Subject: Welcome\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=UTF-8\r\n\r\n"+
+		"Hi Azka,\r\n\r\nWelcome.\r\n", raw)
+}
+
+func TestNewMailerWithoutSMTPHost(t *testing.T) {
+	t.Setenv("SMTP_HOST", "")
+	assert.IsType(t, logMailer{}, NewMailer())
+}
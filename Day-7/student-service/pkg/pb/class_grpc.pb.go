@@ -0,0 +1,249 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: studentservice/v1/class.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ClassServiceClient is the client API for ClassService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ClassServiceClient interface {
+	ListClasses(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListClassesResponse, error)
+	GetClass(ctx context.Context, in *GetByIdRequest, opts ...grpc.CallOption) (*Class, error)
+	CreateClass(ctx context.Context, in *CreateClassRequest, opts ...grpc.CallOption) (*Class, error)
+	UpdateClass(ctx context.Context, in *UpdateClassRequest, opts ...grpc.CallOption) (*Class, error)
+	DeleteClass(ctx context.Context, in *DeleteByIdRequest, opts ...grpc.CallOption) (*DeleteClassResponse, error)
+}
+
+type classServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewClassServiceClient(cc grpc.ClientConnInterface) ClassServiceClient {
+	return &classServiceClient{cc}
+}
+
+func (c *classServiceClient) ListClasses(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListClassesResponse, error) {
+	out := new(ListClassesResponse)
+	err := c.cc.Invoke(ctx, "/studentservice.v1.ClassService/ListClasses", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classServiceClient) GetClass(ctx context.Context, in *GetByIdRequest, opts ...grpc.CallOption) (*Class, error) {
+	out := new(Class)
+	err := c.cc.Invoke(ctx, "/studentservice.v1.ClassService/GetClass", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classServiceClient) CreateClass(ctx context.Context, in *CreateClassRequest, opts ...grpc.CallOption) (*Class, error) {
+	out := new(Class)
+	err := c.cc.Invoke(ctx, "/studentservice.v1.ClassService/CreateClass", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classServiceClient) UpdateClass(ctx context.Context, in *UpdateClassRequest, opts ...grpc.CallOption) (*Class, error) {
+	out := new(Class)
+	err := c.cc.Invoke(ctx, "/studentservice.v1.ClassService/UpdateClass", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *classServiceClient) DeleteClass(ctx context.Context, in *DeleteByIdRequest, opts ...grpc.CallOption) (*DeleteClassResponse, error) {
+	out := new(DeleteClassResponse)
+	err := c.cc.Invoke(ctx, "/studentservice.v1.ClassService/DeleteClass", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ClassServiceServer is the server API for ClassService service.
+// All implementations must embed UnimplementedClassServiceServer
+// for forward compatibility
+type ClassServiceServer interface {
+	ListClasses(context.Context, *ListRequest) (*ListClassesResponse, error)
+	GetClass(context.Context, *GetByIdRequest) (*Class, error)
+	CreateClass(context.Context, *CreateClassRequest) (*Class, error)
+	UpdateClass(context.Context, *UpdateClassRequest) (*Class, error)
+	DeleteClass(context.Context, *DeleteByIdRequest) (*DeleteClassResponse, error)
+	mustEmbedUnimplementedClassServiceServer()
+}
+
+// UnimplementedClassServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedClassServiceServer struct {
+}
+
+func (UnimplementedClassServiceServer) ListClasses(context.Context, *ListRequest) (*ListClassesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListClasses not implemented")
+}
+func (UnimplementedClassServiceServer) GetClass(context.Context, *GetByIdRequest) (*Class, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClass not implemented")
+}
+func (UnimplementedClassServiceServer) CreateClass(context.Context, *CreateClassRequest) (*Class, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateClass not implemented")
+}
+func (UnimplementedClassServiceServer) UpdateClass(context.Context, *UpdateClassRequest) (*Class, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateClass not implemented")
+}
+func (UnimplementedClassServiceServer) DeleteClass(context.Context, *DeleteByIdRequest) (*DeleteClassResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteClass not implemented")
+}
+func (UnimplementedClassServiceServer) mustEmbedUnimplementedClassServiceServer() {}
+
+// UnsafeClassServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ClassServiceServer will
+// result in compilation errors.
+type UnsafeClassServiceServer interface {
+	mustEmbedUnimplementedClassServiceServer()
+}
+
+func RegisterClassServiceServer(s grpc.ServiceRegistrar, srv ClassServiceServer) {
+	s.RegisterService(&ClassService_ServiceDesc, srv)
+}
+
+func _ClassService_ListClasses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassServiceServer).ListClasses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/studentservice.v1.ClassService/ListClasses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassServiceServer).ListClasses(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClassService_GetClass_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetByIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassServiceServer).GetClass(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/studentservice.v1.ClassService/GetClass",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassServiceServer).GetClass(ctx, req.(*GetByIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClassService_CreateClass_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateClassRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassServiceServer).CreateClass(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/studentservice.v1.ClassService/CreateClass",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassServiceServer).CreateClass(ctx, req.(*CreateClassRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClassService_UpdateClass_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateClassRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassServiceServer).UpdateClass(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/studentservice.v1.ClassService/UpdateClass",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassServiceServer).UpdateClass(ctx, req.(*UpdateClassRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClassService_DeleteClass_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteByIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClassServiceServer).DeleteClass(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/studentservice.v1.ClassService/DeleteClass",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClassServiceServer).DeleteClass(ctx, req.(*DeleteByIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ClassService_ServiceDesc is the grpc.ServiceDesc for ClassService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ClassService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "studentservice.v1.ClassService",
+	HandlerType: (*ClassServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListClasses",
+			Handler:    _ClassService_ListClasses_Handler,
+		},
+		{
+			MethodName: "GetClass",
+			Handler:    _ClassService_GetClass_Handler,
+		},
+		{
+			MethodName: "CreateClass",
+			Handler:    _ClassService_CreateClass_Handler,
+		},
+		{
+			MethodName: "UpdateClass",
+			Handler:    _ClassService_UpdateClass_Handler,
+		},
+		{
+			MethodName: "DeleteClass",
+			Handler:    _ClassService_DeleteClass_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "studentservice/v1/class.proto",
+}
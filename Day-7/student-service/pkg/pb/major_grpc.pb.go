@@ -0,0 +1,249 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: studentservice/v1/major.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// MajorServiceClient is the client API for MajorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MajorServiceClient interface {
+	ListMajors(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListMajorsResponse, error)
+	GetMajor(ctx context.Context, in *GetByIdRequest, opts ...grpc.CallOption) (*Major, error)
+	CreateMajor(ctx context.Context, in *CreateMajorRequest, opts ...grpc.CallOption) (*Major, error)
+	UpdateMajor(ctx context.Context, in *UpdateMajorRequest, opts ...grpc.CallOption) (*Major, error)
+	DeleteMajor(ctx context.Context, in *DeleteByIdRequest, opts ...grpc.CallOption) (*DeleteMajorResponse, error)
+}
+
+type majorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMajorServiceClient(cc grpc.ClientConnInterface) MajorServiceClient {
+	return &majorServiceClient{cc}
+}
+
+func (c *majorServiceClient) ListMajors(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListMajorsResponse, error) {
+	out := new(ListMajorsResponse)
+	err := c.cc.Invoke(ctx, "/studentservice.v1.MajorService/ListMajors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *majorServiceClient) GetMajor(ctx context.Context, in *GetByIdRequest, opts ...grpc.CallOption) (*Major, error) {
+	out := new(Major)
+	err := c.cc.Invoke(ctx, "/studentservice.v1.MajorService/GetMajor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *majorServiceClient) CreateMajor(ctx context.Context, in *CreateMajorRequest, opts ...grpc.CallOption) (*Major, error) {
+	out := new(Major)
+	err := c.cc.Invoke(ctx, "/studentservice.v1.MajorService/CreateMajor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *majorServiceClient) UpdateMajor(ctx context.Context, in *UpdateMajorRequest, opts ...grpc.CallOption) (*Major, error) {
+	out := new(Major)
+	err := c.cc.Invoke(ctx, "/studentservice.v1.MajorService/UpdateMajor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *majorServiceClient) DeleteMajor(ctx context.Context, in *DeleteByIdRequest, opts ...grpc.CallOption) (*DeleteMajorResponse, error) {
+	out := new(DeleteMajorResponse)
+	err := c.cc.Invoke(ctx, "/studentservice.v1.MajorService/DeleteMajor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MajorServiceServer is the server API for MajorService service.
+// All implementations must embed UnimplementedMajorServiceServer
+// for forward compatibility
+type MajorServiceServer interface {
+	ListMajors(context.Context, *ListRequest) (*ListMajorsResponse, error)
+	GetMajor(context.Context, *GetByIdRequest) (*Major, error)
+	CreateMajor(context.Context, *CreateMajorRequest) (*Major, error)
+	UpdateMajor(context.Context, *UpdateMajorRequest) (*Major, error)
+	DeleteMajor(context.Context, *DeleteByIdRequest) (*DeleteMajorResponse, error)
+	mustEmbedUnimplementedMajorServiceServer()
+}
+
+// UnimplementedMajorServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedMajorServiceServer struct {
+}
+
+func (UnimplementedMajorServiceServer) ListMajors(context.Context, *ListRequest) (*ListMajorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMajors not implemented")
+}
+func (UnimplementedMajorServiceServer) GetMajor(context.Context, *GetByIdRequest) (*Major, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMajor not implemented")
+}
+func (UnimplementedMajorServiceServer) CreateMajor(context.Context, *CreateMajorRequest) (*Major, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMajor not implemented")
+}
+func (UnimplementedMajorServiceServer) UpdateMajor(context.Context, *UpdateMajorRequest) (*Major, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMajor not implemented")
+}
+func (UnimplementedMajorServiceServer) DeleteMajor(context.Context, *DeleteByIdRequest) (*DeleteMajorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMajor not implemented")
+}
+func (UnimplementedMajorServiceServer) mustEmbedUnimplementedMajorServiceServer() {}
+
+// UnsafeMajorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MajorServiceServer will
+// result in compilation errors.
+type UnsafeMajorServiceServer interface {
+	mustEmbedUnimplementedMajorServiceServer()
+}
+
+func RegisterMajorServiceServer(s grpc.ServiceRegistrar, srv MajorServiceServer) {
+	s.RegisterService(&MajorService_ServiceDesc, srv)
+}
+
+func _MajorService_ListMajors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MajorServiceServer).ListMajors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/studentservice.v1.MajorService/ListMajors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MajorServiceServer).ListMajors(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MajorService_GetMajor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetByIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MajorServiceServer).GetMajor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/studentservice.v1.MajorService/GetMajor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MajorServiceServer).GetMajor(ctx, req.(*GetByIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MajorService_CreateMajor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMajorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MajorServiceServer).CreateMajor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/studentservice.v1.MajorService/CreateMajor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MajorServiceServer).CreateMajor(ctx, req.(*CreateMajorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MajorService_UpdateMajor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMajorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MajorServiceServer).UpdateMajor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/studentservice.v1.MajorService/UpdateMajor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MajorServiceServer).UpdateMajor(ctx, req.(*UpdateMajorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MajorService_DeleteMajor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteByIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MajorServiceServer).DeleteMajor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/studentservice.v1.MajorService/DeleteMajor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MajorServiceServer).DeleteMajor(ctx, req.(*DeleteByIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MajorService_ServiceDesc is the grpc.ServiceDesc for MajorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MajorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "studentservice.v1.MajorService",
+	HandlerType: (*MajorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListMajors",
+			Handler:    _MajorService_ListMajors_Handler,
+		},
+		{
+			MethodName: "GetMajor",
+			Handler:    _MajorService_GetMajor_Handler,
+		},
+		{
+			MethodName: "CreateMajor",
+			Handler:    _MajorService_CreateMajor_Handler,
+		},
+		{
+			MethodName: "UpdateMajor",
+			Handler:    _MajorService_UpdateMajor_Handler,
+		},
+		{
+			MethodName: "DeleteMajor",
+			Handler:    _MajorService_DeleteMajor_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "studentservice/v1/major.proto",
+}
@@ -0,0 +1,82 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// fragmentLength is the length of the fragment of long values returned by Highlight.
+const fragmentLength = 80
+
+// Highlight returns the value with the words matching the query wrapped in <em> tags, and the
+// rest HTML escaped. Long values are cut to a fragment around the first match. It returns ""
+// when no word matches.
+func Highlight(query, value string) string {
+	words := Terms(query)
+	var matched []Token
+	for _, token := range Tokenize(value) {
+		for _, word := range words {
+			if Match(word, token.Term) > 0 {
+				matched = append(matched, token)
+				break
+			}
+		}
+	}
+	if len(matched) == 0 {
+		return ""
+	}
+
+	start, end := 0, len(value)
+	if utf8.RuneCountInString(value) > fragmentLength {
+		start = wordStart(value, matched[0].Start-fragmentLength/4)
+		end = wordEnd(value, start+fragmentLength)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	last := start
+	for _, token := range matched {
+		if token.Start < start || token.End > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(value[last:token.Start]))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(value[token.Start:token.End]))
+		b.WriteString("</em>")
+		last = token.End
+	}
+	b.WriteString(html.EscapeString(value[last:end]))
+	if end < len(value) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// wordStart returns the start of the word at offset i of value, or of the value.
+func wordStart(value string, i int) int {
+	if i <= 0 {
+		return 0
+	}
+	for _, token := range Tokenize(value) {
+		if token.End >= i {
+			return token.Start
+		}
+	}
+	return i
+}
+
+// wordEnd returns the end of the word at offset i of value, or of the value.
+func wordEnd(value string, i int) int {
+	if i >= len(value) {
+		return len(value)
+	}
+	for _, token := range Tokenize(value) {
+		if token.End >= i {
+			return token.End
+		}
+	}
+	return len(value)
+}
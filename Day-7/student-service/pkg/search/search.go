@@ -0,0 +1,316 @@
+// Package search is a small in-memory full-text index with ranked, prefix and typo tolerant
+// matching, for databases without a usable full-text index.
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Field is a field of the indexed documents, matches in fields with a higher weight rank higher.
+type Field struct {
+	Name   string
+	Weight float64
+}
+
+// Hit is a document matching a query.
+type Hit struct {
+	ID    uint
+	Score float64
+}
+
+// Token is a lower cased word of a text, Start and End are its byte offsets in the text.
+type Token struct {
+	Term       string
+	Start, End int
+}
+
+// Index is safe for concurrent use.
+type Index struct {
+	mu     sync.Mutex
+	fields []Field
+	docs   map[uint][]string
+	// postings holds the documents of each term, with the bit mask of the fields it is in.
+	postings map[string]map[uint]uint32
+	// terms is the sorted vocabulary for prefix matching, nil when it has to be rebuilt.
+	terms []string
+}
+
+// NewIndex returns an empty index of documents with the fields, at most 32.
+func NewIndex(fields ...Field) *Index {
+	return &Index{
+		fields:   fields,
+		docs:     map[uint][]string{},
+		postings: map[string]map[uint]uint32{},
+	}
+}
+
+// Put indexes the document, replacing the document with the same id. values are the values of
+// the fields of the index, in order.
+func (ix *Index) Put(id uint, values ...string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.delete(id)
+	ix.docs[id] = values
+	for i, value := range values {
+		for _, token := range Tokenize(value) {
+			docs, ok := ix.postings[token.Term]
+			if !ok {
+				docs = map[uint]uint32{}
+				ix.postings[token.Term] = docs
+				ix.terms = nil
+			}
+			docs[id] |= 1 << i
+		}
+	}
+}
+
+// Delete removes the document from the index, deleting a missing document does nothing.
+func (ix *Index) Delete(id uint) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.delete(id)
+}
+
+func (ix *Index) delete(id uint) {
+	values, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+	delete(ix.docs, id)
+	for _, value := range values {
+		for _, token := range Tokenize(value) {
+			docs := ix.postings[token.Term]
+			delete(docs, id)
+			if len(docs) == 0 {
+				delete(ix.postings, token.Term)
+				ix.terms = nil
+			}
+		}
+	}
+}
+
+// Len returns the number of documents in the index.
+func (ix *Index) Len() int {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return len(ix.docs)
+}
+
+// Search returns the documents matching every word of the query, best first, at most limit.
+// A word matches the same term, a term it is a prefix of, or a term with a typo in it. Rare
+// terms, exact matches and matches in fields with a higher weight rank higher.
+func (ix *Index) Search(query string, limit int) []Hit {
+	words := Terms(query)
+	if len(words) == 0 {
+		return nil
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if ix.terms == nil {
+		ix.terms = make([]string, 0, len(ix.postings))
+		for term := range ix.postings {
+			ix.terms = append(ix.terms, term)
+		}
+		sort.Strings(ix.terms)
+	}
+
+	var scores map[uint]float64
+	for _, word := range words {
+		wordScores := map[uint]float64{}
+		for term, quality := range ix.expand(word) {
+			docs := ix.postings[term]
+			idf := math.Log(1 + float64(len(ix.docs))/float64(len(docs)))
+			for id, mask := range docs {
+				score := quality * idf * ix.weight(mask)
+				if score > wordScores[id] {
+					wordScores[id] = score
+				}
+			}
+		}
+
+		if scores == nil {
+			scores = wordScores
+			continue
+		}
+		for id := range scores {
+			if score, ok := wordScores[id]; ok {
+				scores[id] += score
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{ID: id, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// expand returns the terms of the index the word matches, with the quality of the match.
+// Typos are only looked for when the word is no term nor the prefix of one, among the terms
+// starting with the same letter.
+func (ix *Index) expand(word string) map[string]float64 {
+	matches := map[string]float64{}
+	for i := sort.SearchStrings(ix.terms, word); i < len(ix.terms) && strings.HasPrefix(ix.terms[i], word); i++ {
+		matches[ix.terms[i]] = Match(word, ix.terms[i])
+	}
+	if len(matches) > 0 || maxTypos(word) == 0 {
+		return matches
+	}
+
+	first, _ := utf8.DecodeRuneInString(word)
+	prefix := string(first)
+	for i := sort.SearchStrings(ix.terms, prefix); i < len(ix.terms) && strings.HasPrefix(ix.terms[i], prefix); i++ {
+		if quality := Match(word, ix.terms[i]); quality > 0 {
+			matches[ix.terms[i]] = quality
+		}
+	}
+	return matches
+}
+
+// weight returns the highest weight of the fields in the mask.
+func (ix *Index) weight(mask uint32) float64 {
+	var weight float64
+	for i, field := range ix.fields {
+		if mask&(1<<i) != 0 && field.Weight > weight {
+			weight = field.Weight
+		}
+	}
+	return weight
+}
+
+// Match returns how well a word of a query matches a term, from 1 for the same term to 0 for
+// no match. Prefixes of a term match it for autocompletion, and words of 4 letters or more
+// may have a typo, 8 letters or more two, but not in their first letter. Numbers must be
+// typed right.
+func Match(word, term string) float64 {
+	switch {
+	case word == term:
+		return 1
+	case strings.HasPrefix(term, word):
+		return 0.5 + 0.4*float64(len(word))/float64(len(term))
+	}
+
+	typos := maxTypos(word)
+	if typos == 0 {
+		return 0
+	}
+	w, t := []rune(word), []rune(term)
+	if len(t) < len(w)-typos || w[0] != t[0] {
+		return 0
+	}
+	if len(t) <= len(w)+typos && distance(w, t, typos) <= typos {
+		return 0.4
+	}
+	// a typo in a prefix of the term
+	if len(t) > len(w) && distance(w, t[:len(w)], typos) <= typos {
+		return 0.3
+	}
+	return 0
+}
+
+func maxTypos(word string) int {
+	if strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+		return 0
+	}
+	switch n := len([]rune(word)); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	}
+	return 0
+}
+
+// distance returns the optimal string alignment distance of a and b, the number of inserted,
+// deleted, substituted or swapped adjacent letters to turn a into b. It gives up and returns
+// limit+1 once the distance is known to be over limit.
+func distance(a, b []rune, limit int) int {
+	previous2 := make([]int, len(b)+1)
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		best := current[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				current[j] = min(current[j], previous2[j-2]+1)
+			}
+			best = min(best, current[j])
+		}
+		if best > limit {
+			return limit + 1
+		}
+		previous2, previous, current = previous, current, previous2
+	}
+	return previous[len(b)]
+}
+
+func min(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return result
+}
+
+// Tokenize splits text into its lower cased words, runs of letters and digits.
+func Tokenize(text string) []Token {
+	var tokens []Token
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			tokens = append(tokens, Token{Term: strings.ToLower(text[start:i]), Start: start, End: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, Token{Term: strings.ToLower(text[start:]), Start: start, End: len(text)})
+	}
+	return tokens
+}
+
+// Terms returns the distinct words of a query.
+func Terms(query string) []string {
+	var terms []string
+	seen := map[string]bool{}
+	for _, token := range Tokenize(query) {
+		if !seen[token.Term] {
+			seen[token.Term] = true
+			terms = append(terms, token.Term)
+		}
+	}
+	return terms
+}
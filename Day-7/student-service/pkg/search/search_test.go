@@ -0,0 +1,103 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testIndex() *Index {
+	index := NewIndex(Field{Name: "name", Weight: 3}, Field{Name: "email", Weight: 2}, Field{Name: "address", Weight: 1})
+	index.Put(1, "Vincent L. Hubbard", "vincentlhubbard@edu.ac.id", "Jl. Thomas No. 1")
+	index.Put(2, "Devon C. Thomas", "devoncthomas@edu.ac.id", "Jl. Merdeka No. 10, Bandung")
+	index.Put(3, "Bettina Measter", "bettinameaster@edu.ac.id", "")
+	index.Put(4, "Thomas Devon", "thomas.devon@edu.ac.id", "")
+	return index
+}
+
+func ids(hits []Hit) []uint {
+	result := []uint{}
+	for _, hit := range hits {
+		result = append(result, hit.ID)
+	}
+	return result
+}
+
+func TestIndexSearch(t *testing.T) {
+	index := testIndex()
+	cases := []struct {
+		name  string
+		query string
+		ids   []uint
+	}{
+		{"word", "bettina", []uint{3}},
+		{"every word must match, in any order", "thomas devon", []uint{2, 4}},
+		{"name ranks above address", "thomas", []uint{2, 4, 1}},
+		{"prefix", "vin", []uint{1}},
+		{"prefix of the last word", "devon th", []uint{2, 4}},
+		{"typo", "betina", []uint{3}},
+		{"swapped letters", "thoams devon", []uint{2, 4}},
+		{"two typos in a long word", "hubbrad vincnet", []uint{1}},
+		{"typo in a prefix", "merdk", []uint{2}},
+		{"case and punctuation", "MERDEKA, bandung!", []uint{2}},
+		{"no match", "unknown", []uint{}},
+		{"no typo in the first letter", "tevon", []uint{}},
+		{"short words have no typos", "dev thomsa", []uint{2, 4}},
+		{"empty query", " ", []uint{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.ids, ids(index.Search(tc.query, 0)))
+		})
+	}
+}
+
+func TestIndexPutAndDelete(t *testing.T) {
+	asserts := assert.New(t)
+	index := testIndex()
+
+	index.Put(3, "Bettina Thomas", "bettinathomas@edu.ac.id", "")
+	asserts.Equal([]uint{}, ids(index.Search("measter", 0)))
+	asserts.Contains(ids(index.Search("thomas", 0)), uint(3))
+
+	index.Delete(2)
+	index.Delete(99)
+	asserts.Equal(3, index.Len())
+	asserts.Equal([]uint{}, ids(index.Search("merdeka", 0)))
+	asserts.Len(index.Search("thomas", 2), 2)
+}
+
+func TestHighlight(t *testing.T) {
+	cases := []struct {
+		query, value, expected string
+	}{
+		{"devon thomas", "Devon C. Thomas", "<em>Devon</em> C. <em>Thomas</em>"},
+		{"dev", "devoncthomas@edu.ac.id", "<em>devoncthomas</em>@edu.ac.id"},
+		{"merdka", "Jl. <Merdeka> No. 10", "Jl. &lt;<em>Merdeka</em>&gt; No. 10"},
+		{"unknown", "Devon C. Thomas", ""},
+		{"bandung", strings.Repeat("Jl. Merdeka ", 10) + "Bandung", "…Merdeka Jl. Merdeka <em>Bandung</em>"},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, Highlight(tc.query, tc.value), tc.query)
+	}
+}
+
+func BenchmarkIndexSearch(b *testing.B) {
+	first := []string{"Devon", "Vincent", "Bettina", "Ayu", "Budi", "Siti", "Agus", "Dewi", "Rizky", "Putri"}
+	last := []string{"Thomas", "Hubbard", "Measter", "Santoso", "Wijaya", "Pratama", "Lestari", "Saputra", "Hidayat", "Kusuma"}
+	index := NewIndex(Field{Name: "name", Weight: 3}, Field{Name: "email", Weight: 2}, Field{Name: "number", Weight: 2})
+	for i := 0; i < 100000; i++ {
+		name := fmt.Sprintf("%s %s %s", first[i%10], last[i/10%10], last[i/100%10])
+		index.Put(uint(i+1), name, fmt.Sprintf("%s%d@edu.ac.id", strings.ToLower(first[i%10]), i), fmt.Sprintf("2025%06d", i))
+	}
+
+	for _, query := range []string{"devon thomas", "dev", "thoams", "2025000"} {
+		b.Run(query, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				index.Search(query, 1000)
+			}
+		})
+	}
+}
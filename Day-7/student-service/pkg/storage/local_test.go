@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorage(t *testing.T) {
+	testStorage(t, NewLocalStorage(t.TempDir()))
+}
+
+// testStorage runs the behaviour every storage must have.
+func testStorage(t *testing.T, s Storage) {
+	ctx := context.Background()
+	asserts := assert.New(t)
+
+	_, err := s.Get(ctx, "students/2/missing.pdf")
+	asserts.ErrorIs(err, ErrNotFound)
+
+	if !asserts.NoError(s.Put(ctx, "students/2/card.pdf", strings.NewReader("%PDF-1.4"), 8, "application/pdf")) {
+		return
+	}
+	body, err := s.Get(ctx, "students/2/card.pdf")
+	if asserts.NoError(err) {
+		content, _ := io.ReadAll(body)
+		body.Close()
+		asserts.Equal("%PDF-1.4", string(content))
+	}
+
+	asserts.NoError(s.Put(ctx, "students/2/card.pdf", strings.NewReader("%PDF-1.7"), 8, "application/pdf"))
+	body, err = s.Get(ctx, "students/2/card.pdf")
+	if asserts.NoError(err) {
+		content, _ := io.ReadAll(body)
+		body.Close()
+		asserts.Equal("%PDF-1.7", string(content))
+	}
+
+	asserts.NoError(s.Delete(ctx, "students/2/card.pdf"))
+	asserts.NoError(s.Delete(ctx, "students/2/card.pdf"))
+	_, err = s.Get(ctx, "students/2/card.pdf")
+	asserts.ErrorIs(err, ErrNotFound)
+
+	for _, key := range []string{"", "/etc/passwd", "students/../../secret", "students//card.pdf"} {
+		asserts.Error(s.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"), key)
+	}
+}
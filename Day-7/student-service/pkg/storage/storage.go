@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"student-service/pkg/util"
+)
+
+// ErrNotFound is returned when the object with the key does not exist.
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage stores objects by key. Keys are slash separated paths such as
+// "students/2/attachments/photo.jpg".
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get returns the content of the object, the caller must close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete deletes the object, deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// NewStorage returns an S3 compatible storage when STORAGE_S3_BUCKET is set, and a local disk
+// storage in STORAGE_LOCAL_DIR otherwise.
+func NewStorage() Storage {
+	bucket := util.Getenv("STORAGE_S3_BUCKET", "")
+	if bucket == "" {
+		return NewLocalStorage(util.Getenv("STORAGE_LOCAL_DIR", "storage"))
+	}
+	return NewS3Storage(S3Config{
+		Endpoint:  util.Getenv("STORAGE_S3_ENDPOINT", "https://s3.amazonaws.com"),
+		Region:    util.Getenv("STORAGE_S3_REGION", "us-east-1"),
+		Bucket:    bucket,
+		AccessKey: util.Getenv("STORAGE_S3_ACCESS_KEY", ""),
+		SecretKey: util.Getenv("STORAGE_S3_SECRET_KEY", ""),
+		PathStyle: util.Getenv("STORAGE_S3_PATH_STYLE", "true") == "true",
+	})
+}
+
+// checkKey rejects keys that are empty, absolute or escape their root with "..".
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return fmt.Errorf("storage: invalid key %q", key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("storage: invalid key %q", key)
+		}
+	}
+	return nil
+}
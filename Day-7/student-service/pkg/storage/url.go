@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrURLExpired is returned when a signed URL is verified after it expired.
+	ErrURLExpired = errors.New("storage: signed URL expired")
+	// ErrURLSignature is returned when the signature of a URL does not match.
+	ErrURLSignature = errors.New("storage: invalid URL signature")
+)
+
+// URLSigner signs download URLs so they can be used without other credentials until they
+// expire.
+type URLSigner struct {
+	secret []byte
+	now    func() time.Time
+}
+
+func NewURLSigner(secret []byte) *URLSigner {
+	return &URLSigner{secret: secret, now: time.Now}
+}
+
+// Sign returns path with the expires and signature query parameters added.
+func (s *URLSigner) Sign(path string, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.signature(path, expires))
+	return path + "?" + query.Encode()
+}
+
+// Verify checks the expires and signature query parameters of a URL with path.
+func (s *URLSigner) Verify(path, expires, signature string) error {
+	if !hmac.Equal([]byte(signature), []byte(s.signature(path, expires))) {
+		return ErrURLSignature
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrURLSignature
+	}
+	if s.now().Unix() > unix {
+		return ErrURLExpired
+	}
+	return nil
+}
+
+func (s *URLSigner) signature(path, expires string) string {
+	return hex.EncodeToString(hmacSHA256(s.secret, path+"\n"+expires))
+}
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURLSigner(t *testing.T) {
+	asserts := assert.New(t)
+	signer := NewURLSigner([]byte("secret"))
+	now := time.Now()
+	signer.now = func() time.Time { return now }
+
+	signed := signer.Sign("/api/v1/attachments/1/download", now.Add(time.Minute))
+	path, rawQuery, _ := strings.Cut(signed, "?")
+	query, _ := url.ParseQuery(rawQuery)
+	asserts.Equal("/api/v1/attachments/1/download", path)
+	asserts.NoError(signer.Verify(path, query.Get("expires"), query.Get("signature")))
+
+	asserts.ErrorIs(signer.Verify("/api/v1/attachments/2/download", query.Get("expires"), query.Get("signature")), ErrURLSignature)
+	asserts.ErrorIs(signer.Verify(path, "9999999999", query.Get("signature")), ErrURLSignature)
+	asserts.ErrorIs(NewURLSigner([]byte("other")).Verify(path, query.Get("expires"), query.Get("signature")), ErrURLSignature)
+
+	signer.now = func() time.Time { return now.Add(2 * time.Minute) }
+	asserts.ErrorIs(signer.Verify(path, query.Get("expires"), query.Get("signature")), ErrURLExpired)
+}
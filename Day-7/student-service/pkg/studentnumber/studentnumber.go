@@ -0,0 +1,173 @@
+// Package studentnumber formats student numbers (NIM) from a pattern such as
+// "{year}{major:2}{seq:4}", which gives 2026010001 to the first student of major 1 in 2026.
+//
+// A pattern is made of literal text and placeholders:
+//
+//	{year}     the intake year, e.g. 2026
+//	{yy}       the last two digits of the intake year, e.g. 26
+//	{major:N}  the id of the major, zero padded to N digits
+//	{seq:N}    the sequence number of the student, zero padded to N digits
+//
+// N is optional, but a {major} or {seq} without it has no fixed number of digits, so it must
+// end the pattern or be followed by a literal that does not start with a digit. Otherwise the
+// number could not be split again: {major}{seq:3} gives 11001 to both student 1 of major 11
+// and student 1001 of major 1.
+//
+// Every pattern has exactly one {seq}, numbered within its scope: the number with the sequence
+// left out. A sequence with N digits is at most 10^N-1, once a scope runs out of numbers Format
+// returns ErrSequenceExhausted instead of a longer number, which could be the number of a
+// student of another scope. A major id with more than N digits is rejected with ErrMajorTooWide
+// for the same reason.
+package studentnumber
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"student-service/pkg/util"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrSequenceExhausted is returned by Format when the sequence does not fit in the width of
+// its {seq}.
+var ErrSequenceExhausted = errors.New("student number sequence exhausted")
+
+// ErrMajorTooWide is returned by Format when the major id does not fit in the width of its
+// {major}.
+var ErrMajorTooWide = errors.New("major id does not fit in the student number pattern")
+
+// DefaultPatternString is the pattern used when STUDENT_NUMBER_PATTERN is not set or invalid.
+const DefaultPatternString = "{year}{major:2}{seq:4}"
+
+var placeholder = regexp.MustCompile(`\{([a-z]+)(?::(\d+))?\}`)
+
+type part struct {
+	literal string
+	name    string
+	width   int
+}
+
+// Pattern formats student numbers.
+type Pattern struct {
+	raw   string
+	parts []part
+}
+
+// Parse parses a pattern, see the package documentation for its syntax.
+func Parse(pattern string) (*Pattern, error) {
+	p := &Pattern{raw: pattern}
+	seqs := 0
+	last := 0
+	for _, match := range placeholder.FindAllStringSubmatchIndex(pattern, -1) {
+		if match[0] > last {
+			p.parts = append(p.parts, part{literal: pattern[last:match[0]]})
+		}
+		last = match[1]
+
+		name := pattern[match[2]:match[3]]
+		width := 0
+		if match[4] >= 0 {
+			width, _ = strconv.Atoi(pattern[match[4]:match[5]])
+		}
+		switch name {
+		case "seq":
+			seqs++
+		case "year", "yy", "major":
+		default:
+			return nil, fmt.Errorf("unknown placeholder {%s} in student number pattern %q", name, pattern)
+		}
+		p.parts = append(p.parts, part{name: name, width: width})
+	}
+	if last < len(pattern) {
+		p.parts = append(p.parts, part{literal: pattern[last:]})
+	}
+	for _, part := range p.parts {
+		if strings.ContainsAny(part.literal, "{}") {
+			return nil, fmt.Errorf("malformed placeholder in student number pattern %q", pattern)
+		}
+	}
+	if seqs != 1 {
+		return nil, errors.New("student number pattern must have exactly one {seq}")
+	}
+	for i, part := range p.parts {
+		if !part.variableWidth() || i == len(p.parts)-1 {
+			continue
+		}
+		if next := p.parts[i+1].literal; next == "" || next[0] >= '0' && next[0] <= '9' {
+			return nil, fmt.Errorf("{%s} in student number pattern %q must have a width or be followed by a separator", part.name, pattern)
+		}
+	}
+	return p, nil
+}
+
+// variableWidth reports whether the part is a number without a fixed number of digits.
+func (p part) variableWidth() bool {
+	return (p.name == "major" || p.name == "seq") && p.width == 0
+}
+
+// DefaultPattern returns the pattern configured with STUDENT_NUMBER_PATTERN. It falls back to
+// DefaultPatternString when the configuration is invalid.
+func DefaultPattern() *Pattern {
+	pattern, err := Parse(util.Getenv("STUDENT_NUMBER_PATTERN", DefaultPatternString))
+	if err != nil {
+		logrus.Warnf("invalid student number pattern, using %s: %v", DefaultPatternString, err)
+		pattern, _ = Parse(DefaultPatternString)
+	}
+	return pattern
+}
+
+// String returns the pattern as it was parsed.
+func (p *Pattern) String() string {
+	return p.raw
+}
+
+// Scope returns the number of the student with the sequence left out. Students whose numbers
+// share a scope share a sequence.
+func (p *Pattern) Scope(year int, majorID uint) string {
+	return p.format(year, majorID, nil)
+}
+
+// Format returns the number of the seq-th student of the major in the intake year. It returns
+// ErrSequenceExhausted when seq has more digits than the width of {seq}, and ErrMajorTooWide
+// when majorID has more digits than the width of {major}.
+func (p *Pattern) Format(year int, majorID uint, seq uint) (string, error) {
+	for _, part := range p.parts {
+		if part.width == 0 {
+			continue
+		}
+		switch {
+		case part.name == "seq" && len(strconv.FormatUint(uint64(seq), 10)) > part.width:
+			return "", fmt.Errorf("%w: %d does not fit in {seq:%d} of %q", ErrSequenceExhausted, seq, part.width, p.raw)
+		case part.name == "major" && len(strconv.FormatUint(uint64(majorID), 10)) > part.width:
+			return "", fmt.Errorf("%w: %d does not fit in {major:%d} of %q", ErrMajorTooWide, majorID, part.width, p.raw)
+		}
+	}
+	return p.format(year, majorID, &seq), nil
+}
+
+func (p *Pattern) format(year int, majorID uint, seq *uint) string {
+	var b strings.Builder
+	for _, part := range p.parts {
+		switch part.name {
+		case "":
+			b.WriteString(part.literal)
+		case "year":
+			fmt.Fprintf(&b, "%0*d", part.width, year)
+		case "yy":
+			fmt.Fprintf(&b, "%02d", year%100)
+		case "major":
+			fmt.Fprintf(&b, "%0*d", part.width, majorID)
+		case "seq":
+			if seq == nil {
+				b.WriteString("{seq}")
+			} else {
+				fmt.Fprintf(&b, "%0*d", part.width, *seq)
+			}
+		}
+	}
+	return b.String()
+}
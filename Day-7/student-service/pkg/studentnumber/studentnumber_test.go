@@ -0,0 +1,79 @@
+package studentnumber
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatternFormat(t *testing.T) {
+	cases := []struct {
+		pattern string
+		number  string
+		scope   string
+	}{
+		{"{year}{major:2}{seq:4}", "2026030012", "202603{seq}"},
+		{"{yy}.{major}.{seq:3}", "26.3.012", "26.3.{seq}"},
+		{"S-{year}-{seq}", "S-2026-12", "S-2026-{seq}"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			pattern, err := Parse(tc.pattern)
+			if assert.NoError(t, err) {
+				number, err := pattern.Format(2026, 3, 12)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.number, number)
+				assert.Equal(t, tc.scope, pattern.Scope(2026, 3))
+			}
+		})
+	}
+}
+
+func TestPatternFormatSequenceExhausted(t *testing.T) {
+	pattern, err := Parse("{major}.{seq:2}")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	number, err := pattern.Format(2026, 1, 99)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.99", number)
+
+	_, err = pattern.Format(2026, 1, 101)
+	assert.True(t, errors.Is(err, ErrSequenceExhausted))
+}
+
+func TestPatternFormatMajorTooWide(t *testing.T) {
+	pattern, err := Parse("{major:1}{seq:2}")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	number, err := pattern.Format(2026, 9, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "901", number)
+
+	// 10 would give 1001, longer than the numbers of the other majors
+	_, err = pattern.Format(2026, 10, 1)
+	assert.True(t, errors.Is(err, ErrMajorTooWide))
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, pattern := range []string{
+		"{year}{major}", "{seq}{seq}", "{year}{term}{seq}", "{year}{seq",
+		// a number without width next to another number
+		"{year}{major}{seq:4}", "{seq}{major:2}", "{year}{major}0{seq:3}",
+	} {
+		_, err := Parse(pattern)
+		assert.Error(t, err, pattern)
+	}
+}
+
+func TestDefaultPattern(t *testing.T) {
+	t.Setenv("STUDENT_NUMBER_PATTERN", "{yy}{seq:5}")
+	assert.Equal(t, "{yy}{seq:5}", DefaultPattern().String())
+
+	t.Setenv("STUDENT_NUMBER_PATTERN", "{year}")
+	assert.Equal(t, DefaultPatternString, DefaultPattern().String())
+}
@@ -0,0 +1,102 @@
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png" // decode PNG photos
+	"io"
+)
+
+const (
+	// ContentType is the content type of generated thumbnails.
+	ContentType = "image/jpeg"
+	// MaxPixels is the largest image a thumbnail is generated of, 25 megapixels. A few bytes of
+	// compressed image can claim any size, decoding one takes 8 bytes per pixel.
+	MaxPixels = 25_000_000
+)
+
+// ErrTooLarge is returned for images with more than MaxPixels pixels.
+var ErrTooLarge = errors.New("image is too large")
+
+// Generate returns a JPEG thumbnail of a JPEG or PNG image that fits in a size by size square,
+// keeping its aspect ratio. Smaller images are not enlarged, transparent pixels turn white.
+// Images with more than MaxPixels pixels are rejected before they are decoded.
+func Generate(r io.Reader, size int) ([]byte, error) {
+	// the header read for the dimensions is decoded again with the rest of the image
+	var header bytes.Buffer
+	config, _, err := image.DecodeConfig(io.TeeReader(r, &header))
+	if err != nil {
+		return nil, err
+	}
+	if int64(config.Width)*int64(config.Height) > MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d pixels", ErrTooLarge, config.Width, config.Height)
+	}
+
+	src, _, err := image.Decode(io.MultiReader(&header, r))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > size || height > size {
+		if width >= height {
+			width, height = size, max(1, height*size/width)
+		} else {
+			width, height = max(1, width*size/height), size
+		}
+	}
+
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Over)
+
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, scale(rgba, width, height), &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// scale resizes src to width by height, every pixel is the average of the pixels it covers.
+func scale(src *image.RGBA, width, height int) *image.RGBA {
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+	if srcWidth == width && srcHeight == height {
+		return src
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*srcHeight/height, max(y*srcHeight/height+1, (y+1)*srcHeight/height)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*srcWidth/width, max(x*srcWidth/width+1, (x+1)*srcWidth/width)
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride+x0*4 : sy*src.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					sum[0] += int(row[i])
+					sum[1] += int(row[i+1])
+					sum[2] += int(row[i+2])
+					sum[3] += int(row[i+3])
+				}
+			}
+			n := (y1 - y0) * (x1 - x0)
+			offset := y*dst.Stride + x*4
+			for i := range sum {
+				dst.Pix[offset+i] = uint8(sum[i] / n)
+			}
+		}
+	}
+	return dst
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
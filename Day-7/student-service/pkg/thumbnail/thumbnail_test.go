@@ -0,0 +1,74 @@
+package thumbnail
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		name          string
+		width, height int
+		encode        func(*bytes.Buffer, image.Image) error
+		wantW, wantH  int
+	}{
+		{"landscape png", 800, 400, func(b *bytes.Buffer, i image.Image) error { return png.Encode(b, i) }, 200, 100},
+		{"portrait jpeg", 300, 600, func(b *bytes.Buffer, i image.Image) error { return jpeg.Encode(b, i, nil) }, 100, 200},
+		{"small image", 50, 40, func(b *bytes.Buffer, i image.Image) error { return png.Encode(b, i) }, 50, 40},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			src := image.NewRGBA(image.Rect(0, 0, tc.width, tc.height))
+			for y := 0; y < tc.height; y++ {
+				for x := 0; x < tc.width; x++ {
+					src.Set(x, y, color.RGBA{R: 200, G: 20, B: 20, A: 255})
+				}
+			}
+			var in bytes.Buffer
+			if err := tc.encode(&in, src); err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := Generate(&in, 200)
+			if !assert.NoError(t, err) {
+				return
+			}
+			thumb, format, err := image.Decode(bytes.NewReader(out))
+			if assert.NoError(t, err) {
+				assert.Equal(t, "jpeg", format)
+				assert.Equal(t, tc.wantW, thumb.Bounds().Dx())
+				assert.Equal(t, tc.wantH, thumb.Bounds().Dy())
+				r, _, _, _ := thumb.At(tc.wantW/2, tc.wantH/2).RGBA()
+				assert.InDelta(t, 200, r>>8, 8)
+			}
+		})
+	}
+}
+
+func TestGenerateNotAnImage(t *testing.T) {
+	_, err := Generate(bytes.NewReader([]byte("%PDF-1.4")), 200)
+	assert.Error(t, err)
+}
+
+func TestGenerateTooLarge(t *testing.T) {
+	// a PNG header claiming 30000x30000 pixels, without the pixels
+	var in bytes.Buffer
+	if err := png.Encode(&in, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	header := in.Bytes()[:33]
+	binary.BigEndian.PutUint32(header[16:], 30000)
+	binary.BigEndian.PutUint32(header[20:], 30000)
+	binary.BigEndian.PutUint32(header[29:], crc32.ChecksumIEEE(header[12:29]))
+
+	_, err := Generate(bytes.NewReader(header), 200)
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
@@ -0,0 +1,16 @@
+package util
+
+import "time"
+
+// Backoff returns how long to wait before retrying after the attempt-th failed attempt, base
+// doubled for every previous failure and at most max.
+func Backoff(base, max time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
@@ -0,0 +1,20 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	cases := map[int]time.Duration{
+		1: 30 * time.Second,
+		2: time.Minute,
+		3: 2 * time.Minute,
+		8: time.Hour,
+	}
+	for attempt, want := range cases {
+		assert.Equal(t, want, Backoff(30*time.Second, time.Hour, attempt), attempt)
+	}
+}
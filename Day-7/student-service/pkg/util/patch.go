@@ -0,0 +1,66 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+const (
+	MIMEApplicationMergePatchJSON = "application/merge-patch+json"
+	MIMEApplicationJSONPatchJSON  = "application/json-patch+json"
+)
+
+// Errors returned by ApplyPatch, check them with errors.Is.
+var (
+	ErrUnsupportedPatch = errors.New("unsupported patch media type")
+	ErrMalformedPatch   = errors.New("malformed patch document")
+	ErrPatchFailed      = errors.New("patch cannot be applied")
+)
+
+// ApplyPatch applies patch to the JSON encoding of document and decodes the result into patched.
+// contentType selects a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902). The patched
+// document must not contain fields unknown to patched.
+func ApplyPatch(contentType string, patch []byte, document interface{}, patched interface{}) error {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedPatch, err)
+	}
+
+	original, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	var result []byte
+	switch mediaType {
+	case MIMEApplicationMergePatchJSON:
+		if !json.Valid(patch) {
+			return fmt.Errorf("%w: invalid JSON", ErrMalformedPatch)
+		}
+		if result, err = jsonpatch.MergePatch(original, patch); err != nil {
+			return fmt.Errorf("%w: %v", ErrMalformedPatch, err)
+		}
+	case MIMEApplicationJSONPatchJSON:
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrMalformedPatch, err)
+		}
+		if result, err = operations.Apply(original); err != nil {
+			return fmt.Errorf("%w: %v", ErrPatchFailed, err)
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedPatch, mediaType)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(result))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(patched); err != nil {
+		return fmt.Errorf("%w: %v", ErrPatchFailed, err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package util
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPatchDocument struct {
+	Name    string `json:"name"`
+	ClassID *uint  `json:"class_id"`
+}
+
+func TestApplyPatch(t *testing.T) {
+	classID := uint(1)
+	document := testPatchDocument{Name: "Vincent", ClassID: &classID}
+
+	cases := []struct {
+		name        string
+		contentType string
+		patch       string
+		want        testPatchDocument
+		err         error
+	}{
+		{"merge patch", MIMEApplicationMergePatchJSON, `{"name":"Devon"}`, testPatchDocument{Name: "Devon", ClassID: &classID}, nil},
+		{"merge patch null", MIMEApplicationMergePatchJSON + "; charset=utf-8", `{"class_id":null}`, testPatchDocument{Name: "Vincent"}, nil},
+		{"json patch", MIMEApplicationJSONPatchJSON, `[{"op":"test","path":"/name","value":"Vincent"},{"op":"replace","path":"/name","value":"Devon"}]`, testPatchDocument{Name: "Devon", ClassID: &classID}, nil},
+		{"json patch remove", MIMEApplicationJSONPatchJSON, `[{"op":"remove","path":"/class_id"}]`, testPatchDocument{Name: "Vincent"}, nil},
+		{"failed test", MIMEApplicationJSONPatchJSON, `[{"op":"test","path":"/name","value":"Devon"}]`, testPatchDocument{}, ErrPatchFailed},
+		{"unknown field", MIMEApplicationMergePatchJSON, `{"id":2}`, testPatchDocument{}, ErrPatchFailed},
+		{"wrong type", MIMEApplicationMergePatchJSON, `{"name":1}`, testPatchDocument{}, ErrPatchFailed},
+		{"malformed merge patch", MIMEApplicationMergePatchJSON, `{"name":`, testPatchDocument{}, ErrMalformedPatch},
+		{"malformed json patch", MIMEApplicationJSONPatchJSON, `{"op":"remove"}`, testPatchDocument{}, ErrMalformedPatch},
+		{"plain json", "application/json", `{"name":"Devon"}`, testPatchDocument{}, ErrUnsupportedPatch},
+		{"no content type", "", `{"name":"Devon"}`, testPatchDocument{}, ErrUnsupportedPatch},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var patched testPatchDocument
+			err := ApplyPatch(c.contentType, []byte(c.patch), &document, &patched)
+			if c.err != nil {
+				assert.True(t, errors.Is(err, c.err), "%v", err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.want, patched)
+			}
+		})
+	}
+	assert.Equal(t, testPatchDocument{Name: "Vincent", ClassID: &classID}, document)
+}
@@ -0,0 +1,97 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+
+	"student-service/pkg/dto"
+	"student-service/pkg/i18n"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// BatchItem is the outcome of one item of a batch request, Err is nil when it was applied.
+type BatchItem struct {
+	Status int
+	Data   interface{}
+	Err    *Error
+}
+
+// Batch is the response of a batch request, Items are in the order of the request.
+// Status is sent when every item was applied.
+type Batch struct {
+	Mode   string
+	Status int
+	Items  []BatchItem
+}
+
+type batchItemResponse struct {
+	Index  int         `json:"index"`
+	Status int         `json:"status"`
+	Data   interface{} `json:"data,omitempty"`
+	Error  interface{} `json:"error,omitempty"`
+}
+
+type batchResponse struct {
+	Mode      string              `json:"mode"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Items     []batchItemResponse `json:"items"`
+}
+
+// Send writes the result of every item, failed items carry the same error body as the single
+// item endpoints. A failed atomic batch is sent with the status of the item that failed,
+// a best effort batch with failed items as 207 Multi-Status.
+func (b *Batch) Send(c echo.Context) error {
+	lang := i18n.FromRequest(c.Request())
+	c.Response().Header().Set(HeaderContentLanguage, lang.String())
+	wantsProblem := WantsProblem(c.Request())
+
+	data := batchResponse{
+		Mode:  b.Mode,
+		Items: make([]batchItemResponse, len(b.Items)),
+	}
+	var failure *Error
+	for i, item := range b.Items {
+		data.Items[i] = batchItemResponse{Index: i, Status: item.Status, Data: item.Data}
+		if item.Err == nil {
+			data.Succeeded++
+			continue
+		}
+
+		data.Failed++
+		if item.Err.Err != nil {
+			logrus.Error(fmt.Sprintf("item %d: %+v", i, errors.WithStack(item.Err.Err)))
+		}
+		if failure == nil && item.Err.Kind != ErrorConstant.BatchAborted {
+			failure = item.Err
+		}
+		data.Items[i].Status = item.Err.Kind.status
+		if wantsProblem {
+			data.Items[i].Error = item.Err.problem(c, lang)
+		} else {
+			data.Items[i].Error = item.Err.response(lang)
+		}
+	}
+
+	status, message := b.Status, i18n.RequestSuccess
+	switch {
+	case data.Failed == 0:
+	case b.Mode == dto.BatchModeBestEffort:
+		status, message = http.StatusMultiStatus, i18n.BatchPartialSuccess
+	case failure != nil:
+		status, message = failure.Kind.status, i18n.BatchRolledBack
+	default:
+		status, message = ErrorConstant.BatchAborted.status, i18n.BatchRolledBack
+	}
+
+	return c.JSON(status, successResponse{
+		Meta: Meta{
+			Success: data.Failed == 0,
+			Message: i18n.Translate(lang, message),
+		},
+		Data: data,
+	})
+}
@@ -5,167 +5,214 @@ import (
 
 	"net/http"
 
+	"student-service/pkg/i18n"
+	"student-service/pkg/util"
+
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/text/language"
 )
 
 type errorResponse struct {
-	Meta  Meta   `json:"meta"`
-	Error string `json:"error"`
+	Meta   Meta                  `json:"meta"`
+	Error  string                `json:"error"`
+	Errors util.ValidationErrors `json:"errors,omitempty"`
+}
+
+// Kind identifies a class of error: its error code, HTTP status and the key of its message in
+// the i18n catalog. Kinds are immutable and shared, a request specific error is an Error
+// wrapping a Kind.
+type Kind struct {
+	problemType string
+	code        string
+	status      int
+	messageKey  string
 }
 
+// Error is the error returned from services and handlers. It carries the Kind that decides
+// the response, and the cause that is logged but never sent to the client.
 type Error struct {
-	Response     errorResponse `json:"response"`
-	Code         int           `json:"code"`
-	ErrorMessage error
+	Kind *Kind
+	Err  error
 }
 
 const (
-	E_DUPLICATE            = "duplicate"
-	E_NOT_FOUND            = "not_found"
-	E_UNPROCESSABLE_ENTITY = "unprocessable_entity"
-	E_UNAUTHORIZED         = "unauthorized"
-	E_BAD_REQUEST          = "bad_request"
-	E_SERVER_ERROR         = "server_error"
+	E_DUPLICATE              = "duplicate"
+	E_FAILED_DEPENDENCY      = "failed_dependency"
+	E_CONFLICT               = "conflict"
+	E_NOT_FOUND              = "not_found"
+	E_PRECONDITION_FAILED    = "precondition_failed"
+	E_UNPROCESSABLE_ENTITY   = "unprocessable_entity"
+	E_UNSUPPORTED_MEDIA_TYPE = "unsupported_media_type"
+	E_PAYLOAD_TOO_LARGE      = "payload_too_large"
+	E_FORBIDDEN              = "forbidden"
+	E_UNAUTHORIZED           = "unauthorized"
+	E_BAD_REQUEST            = "bad_request"
+	E_SERVER_ERROR           = "server_error"
 )
 
 type errorConstant struct {
-	Duplicate                Error
-	NotFound                 Error
-	RouteNotFound            Error
-	UnprocessableEntity      Error
-	Unauthorized             Error
-	BadRequest               Error
-	Validation               Error
-	InternalServerError      Error
-	EmailOrPasswordIncorrect Error
-	ConvertionNotFound       Error
-	NotEnoughStock           Error
-}
-
-var ErrorConstant errorConstant = errorConstant{
-	Duplicate: Error{
-		Response: errorResponse{
-			Meta: Meta{
-				Success: false,
-				Message: "Created value already exists",
-			},
-			Error: E_DUPLICATE,
-		},
-		Code: http.StatusConflict,
-	},
-	EmailOrPasswordIncorrect: Error{
-		Response: errorResponse{
-			Meta: Meta{
-				Success: false,
-				Message: "Email or password is incorrect",
-			},
-			Error: E_BAD_REQUEST,
-		},
-		Code: http.StatusBadRequest,
-	},
-	NotFound: Error{
-		Response: errorResponse{
-			Meta: Meta{
-				Success: false,
-				Message: "Data not found",
-			},
-			Error: E_NOT_FOUND,
-		},
-		Code: http.StatusNotFound,
-	},
-	RouteNotFound: Error{
-		Response: errorResponse{
-			Meta: Meta{
-				Success: false,
-				Message: "Route not found",
-			},
-			Error: E_NOT_FOUND,
-		},
-		Code: http.StatusNotFound,
-	},
-	UnprocessableEntity: Error{
-		Response: errorResponse{
-			Meta: Meta{
-				Success: false,
-				Message: "Invalid parameters or payload",
-			},
-			Error: E_UNPROCESSABLE_ENTITY,
-		},
-		Code: http.StatusUnprocessableEntity,
-	},
-	Unauthorized: Error{
-		Response: errorResponse{
-			Meta: Meta{
-				Success: false,
-				Message: "Unauthorized, please login or use different class",
-			},
-			Error: E_UNAUTHORIZED,
-		},
-		Code: http.StatusUnauthorized,
-	},
-	BadRequest: Error{
-		Response: errorResponse{
-			Meta: Meta{
-				Success: false,
-				Message: "Bad Request",
-			},
-			Error: E_BAD_REQUEST,
-		},
-		Code: http.StatusBadRequest,
-	},
-	Validation: Error{
-		Response: errorResponse{
-			Meta: Meta{
-				Success: false,
-				Message: "Invalid parameters or payload",
-			},
-			Error: E_BAD_REQUEST,
-		},
-		Code: http.StatusBadRequest,
-	},
-	InternalServerError: Error{
-		Response: errorResponse{
-			Meta: Meta{
-				Success: false,
-				Message: "Something bad happened",
-			},
-			Error: E_SERVER_ERROR,
-		},
-		Code: http.StatusInternalServerError,
-	},
+	Duplicate                *Kind
+	BatchAborted             *Kind
+	Conflict                 *Kind
+	CourseFull               *Kind
+	AlreadyEnrolled          *Kind
+	CapacityBelowEnrolled    *Kind
+	UnknownGradeLetter       *Kind
+	InvalidAssessmentWeights *Kind
+	TermClosed               *Kind
+	NoActiveTerm             *Kind
+	InvalidTermDates         *Kind
+	InvalidSessionDate       *Kind
+	StudentNotInClass        *Kind
+	BookingConflict          *Kind
+	InvalidBookingTime       *Kind
+	InvalidBookingOwner      *Kind
+	InvalidBirthDate         *Kind
+	StudentNumbersExhausted  *Kind
+	FileTooLarge             *Kind
+	UnsupportedFileType      *Kind
+	InvalidDownloadURL       *Kind
+	InvalidReference         *Kind
+	IdempotencyKeyReused     *Kind
+	IdempotencyKeyInProgress *Kind
+	JobNotDead               *Kind
+	PreconditionFailed       *Kind
+	NotFound                 *Kind
+	RouteNotFound            *Kind
+	UnprocessableEntity      *Kind
+	UnsupportedMediaType     *Kind
+	RequestTooLarge          *Kind
+	Unauthorized             *Kind
+	BadRequest               *Kind
+	Validation               *Kind
+	InternalServerError      *Kind
+	EmailOrPasswordIncorrect *Kind
+}
+
+var ErrorConstant = errorConstant{
+	Duplicate:                newProblemKind("duplicate", E_DUPLICATE, http.StatusConflict),
+	BatchAborted:             newProblemKind("batch-aborted", E_FAILED_DEPENDENCY, http.StatusFailedDependency),
+	Conflict:                 newProblemKind("conflict", E_CONFLICT, http.StatusConflict),
+	CourseFull:               newProblemKind("course-full", E_CONFLICT, http.StatusConflict),
+	AlreadyEnrolled:          newProblemKind("already-enrolled", E_DUPLICATE, http.StatusConflict),
+	CapacityBelowEnrolled:    newProblemKind("capacity-below-enrolled", E_UNPROCESSABLE_ENTITY, http.StatusUnprocessableEntity),
+	UnknownGradeLetter:       newProblemKind("unknown-grade-letter", E_UNPROCESSABLE_ENTITY, http.StatusUnprocessableEntity),
+	InvalidAssessmentWeights: newProblemKind("invalid-assessment-weights", E_UNPROCESSABLE_ENTITY, http.StatusUnprocessableEntity),
+	TermClosed:               newProblemKind("term-closed", E_CONFLICT, http.StatusConflict),
+	NoActiveTerm:             newProblemKind("no-active-term", E_UNPROCESSABLE_ENTITY, http.StatusUnprocessableEntity),
+	InvalidTermDates:         newProblemKind("invalid-term-dates", E_UNPROCESSABLE_ENTITY, http.StatusUnprocessableEntity),
+	InvalidSessionDate:       newProblemKind("invalid-session-date", E_UNPROCESSABLE_ENTITY, http.StatusUnprocessableEntity),
+	StudentNotInClass:        newProblemKind("student-not-in-class", E_UNPROCESSABLE_ENTITY, http.StatusUnprocessableEntity),
+	BookingConflict:          newProblemKind("booking-conflict", E_CONFLICT, http.StatusConflict),
+	InvalidBookingTime:       newProblemKind("invalid-booking-time", E_UNPROCESSABLE_ENTITY, http.StatusUnprocessableEntity),
+	InvalidBookingOwner:      newProblemKind("invalid-booking-owner", E_UNPROCESSABLE_ENTITY, http.StatusUnprocessableEntity),
+	InvalidBirthDate:         newProblemKind("invalid-birth-date", E_UNPROCESSABLE_ENTITY, http.StatusUnprocessableEntity),
+	StudentNumbersExhausted:  newProblemKind("student-numbers-exhausted", E_CONFLICT, http.StatusConflict),
+	FileTooLarge:             newProblemKind("file-too-large", E_PAYLOAD_TOO_LARGE, http.StatusRequestEntityTooLarge),
+	UnsupportedFileType:      newProblemKind("unsupported-file-type", E_UNSUPPORTED_MEDIA_TYPE, http.StatusUnsupportedMediaType),
+	InvalidDownloadURL:       newProblemKind("invalid-download-url", E_FORBIDDEN, http.StatusForbidden),
+	InvalidReference:         newProblemKind("invalid-reference", E_UNPROCESSABLE_ENTITY, http.StatusUnprocessableEntity),
+	IdempotencyKeyReused:     newProblemKind("idempotency-key-reused", E_UNPROCESSABLE_ENTITY, http.StatusUnprocessableEntity),
+	IdempotencyKeyInProgress: newProblemKind("idempotency-key-in-progress", E_CONFLICT, http.StatusConflict),
+	JobNotDead:               newProblemKind("job-not-dead", E_CONFLICT, http.StatusConflict),
+	PreconditionFailed:       newProblemKind("precondition-failed", E_PRECONDITION_FAILED, http.StatusPreconditionFailed),
+	EmailOrPasswordIncorrect: newProblemKind("email-or-password-incorrect", E_BAD_REQUEST, http.StatusBadRequest),
+	NotFound:                 newProblemKind("not-found", E_NOT_FOUND, http.StatusNotFound),
+	RouteNotFound:            newProblemKind("route-not-found", E_NOT_FOUND, http.StatusNotFound),
+	UnprocessableEntity:      newProblemKind("unprocessable-entity", E_UNPROCESSABLE_ENTITY, http.StatusUnprocessableEntity),
+	UnsupportedMediaType:     newProblemKind("unsupported-media-type", E_UNSUPPORTED_MEDIA_TYPE, http.StatusUnsupportedMediaType),
+	RequestTooLarge:          newProblemKind("request-too-large", E_PAYLOAD_TOO_LARGE, http.StatusRequestEntityTooLarge),
+	Unauthorized:             newProblemKind("unauthorized", E_UNAUTHORIZED, http.StatusUnauthorized),
+	BadRequest:               newProblemKind("bad-request", E_BAD_REQUEST, http.StatusBadRequest),
+	Validation:               newProblemKind("validation", E_BAD_REQUEST, http.StatusBadRequest),
+	InternalServerError:      newProblemKind("server-error", E_SERVER_ERROR, http.StatusInternalServerError),
+}
+
+// NewKind returns a Kind without a problem type, it is rendered as "about:blank" in problem details.
+// message is a key of the i18n catalog, a message that is not in the catalog is sent as is.
+func NewKind(code string, status int, message string) *Kind {
+	return &Kind{code: code, status: status, messageKey: message}
+}
+
+// newProblemKind returns a Kind whose message is the "error.<problemType>" key of the i18n catalog.
+func newProblemKind(problemType, code string, status int) *Kind {
+	return &Kind{
+		problemType: problemType,
+		code:        code,
+		status:      status,
+		messageKey:  "error." + problemType,
+	}
+}
+
+func (k *Kind) Code() string {
+	return k.code
+}
+
+func (k *Kind) Status() int {
+	return k.status
 }
 
-func ErrorBuilder(res *Error, message error) *Error {
-	res.ErrorMessage = message
-	return res
+// Message returns the message in the default language.
+func (k *Kind) Message() string {
+	return k.LocalizedMessage(i18n.Default)
+}
+
+// LocalizedMessage returns the message in lang, falling back to the default language.
+func (k *Kind) LocalizedMessage(lang language.Tag) string {
+	return i18n.Translate(lang, k.messageKey)
+}
+
+// Error makes a Kind usable as the target of errors.Is.
+func (k *Kind) Error() string {
+	return k.Message()
+}
+
+func ErrorBuilder(kind *Kind, err error) *Error {
+	return &Error{Kind: kind, Err: err}
 }
 
 func CustomErrorBuilder(code int, err string, message string) *Error {
-	return &Error{
-		Response: errorResponse{
-			Meta: Meta{
-				Success: false,
-				Message: message,
-			},
-			Error: err,
-		},
-		Code: code,
+	return ErrorBuilder(NewKind(err, code, message), nil)
+}
+
+// ValidationErrorBuilder builds the response for an error returned by echo.Context.Validate.
+// Invalid payloads are reported as validation errors, anything else (e.g. a failing lookup) as
+// an internal server error.
+func ValidationErrorBuilder(err error) *Error {
+	var validationErrors util.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		return ErrorBuilder(ErrorConstant.Validation, err)
 	}
+	return ErrorBuilder(ErrorConstant.InternalServerError, err)
 }
 
 func ErrorResponse(err error) *Error {
-	re, ok := err.(*Error)
-	if ok {
+	var re *Error
+	if errors.As(err, &re) {
 		return re
-	} else {
-		return ErrorBuilder(&ErrorConstant.InternalServerError, err)
 	}
+	return ErrorBuilder(ErrorConstant.InternalServerError, err)
 }
 
 func (e *Error) Error() string {
-	return fmt.Sprintf("error code %d", e.Code)
+	return fmt.Sprintf("error code %d", e.Kind.status)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the Kind of e, or an Error of the same Kind.
+func (e *Error) Is(target error) bool {
+	switch t := target.(type) {
+	case *Kind:
+		return e.Kind == t
+	case *Error:
+		return e.Kind == t.Kind
+	}
+	return false
 }
 
 func (e *Error) ParseToError() error {
@@ -174,9 +221,31 @@ func (e *Error) ParseToError() error {
 
 func (e *Error) Send(c echo.Context) error {
 	var errorMessage string
-	if e.ErrorMessage != nil {
-		errorMessage = fmt.Sprintf("%+v", errors.WithStack(e.ErrorMessage))
+	if e.Err != nil {
+		errorMessage = fmt.Sprintf("%+v", errors.WithStack(e.Err))
 	}
 	logrus.Error(errorMessage)
-	return c.JSON(e.Code, e.Response)
+
+	lang := i18n.FromRequest(c.Request())
+	c.Response().Header().Set(HeaderContentLanguage, lang.String())
+
+	if WantsProblem(c.Request()) {
+		return e.sendProblem(c, lang)
+	}
+	return c.JSON(e.Kind.status, e.response(lang))
+}
+
+func (e *Error) response(lang language.Tag) errorResponse {
+	response := errorResponse{
+		Meta: Meta{
+			Success: false,
+			Message: e.Kind.LocalizedMessage(lang),
+		},
+		Error: e.Kind.code,
+	}
+	var validationErrors util.ValidationErrors
+	if errors.As(e.Err, &validationErrors) {
+		response.Errors = validationErrors.Localize(lang)
+	}
+	return response
 }
@@ -0,0 +1,131 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"student-service/pkg/i18n"
+	"student-service/pkg/util"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorIsKind(t *testing.T) {
+	var (
+		asserts = assert.New(t)
+		cause   = errors.New("record not found")
+		err     = fmt.Errorf("find student: %w", ErrorBuilder(ErrorConstant.NotFound, cause))
+	)
+
+	asserts.True(errors.Is(err, ErrorConstant.NotFound))
+	asserts.False(errors.Is(err, ErrorConstant.InternalServerError))
+	asserts.True(errors.Is(err, cause))
+	asserts.True(errors.Is(err, ErrorBuilder(ErrorConstant.NotFound, nil)))
+
+	var re *Error
+	if asserts.True(errors.As(err, &re)) {
+		asserts.Equal(http.StatusNotFound, re.Kind.Status())
+		asserts.Equal("error code 404", re.Error())
+	}
+	asserts.Same(re, ErrorResponse(err))
+}
+
+func TestErrorKindsAreInTheCatalog(t *testing.T) {
+	kinds := reflect.ValueOf(ErrorConstant)
+	for i := 0; i < kinds.NumField(); i++ {
+		kind := kinds.Field(i).Interface().(*Kind)
+		_, ok := i18n.Lookup(i18n.Default, kind.messageKey)
+		assert.True(t, ok, "%s has no message for %s", kinds.Type().Field(i).Name, kind.messageKey)
+	}
+}
+
+func TestErrorResponseDefaultsToInternalServerError(t *testing.T) {
+	err := ErrorResponse(errors.New("boom"))
+	assert.True(t, errors.Is(err, ErrorConstant.InternalServerError))
+}
+
+func TestValidationErrorBuilder(t *testing.T) {
+	var (
+		asserts          = assert.New(t)
+		validationErrors = util.ValidationErrors{{Field: "email", Tag: "required", Message: "email is required"}}
+	)
+
+	asserts.True(errors.Is(ValidationErrorBuilder(validationErrors), ErrorConstant.Validation))
+	asserts.True(errors.Is(ValidationErrorBuilder(errors.New("lookup failed")), ErrorConstant.InternalServerError))
+}
+
+func TestSendDoesNotShareStateBetweenRequests(t *testing.T) {
+	e := echo.New()
+	for i := 0; i < 50; i++ {
+		i := i
+		t.Run(fmt.Sprintf("request-%d", i), func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var err error
+			if i%2 == 0 {
+				err = ErrorBuilder(ErrorConstant.Validation, util.ValidationErrors{
+					{Field: fmt.Sprintf("field_%d", i), Tag: "required", Message: "required"},
+				}).Send(c)
+			} else {
+				err = SuccessResponse(map[string]int{"id": i}).Send(c)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if i%2 == 0 {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+				assert.Contains(t, rec.Body.String(), fmt.Sprintf(`"field":"field_%d"`, i))
+				assert.NotContains(t, body, "data")
+			} else {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, map[string]interface{}{"id": float64(i)}, body["data"])
+			}
+		})
+	}
+}
+
+func TestSendLocalizedEnvelope(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/signup", nil)
+	req.Header.Set("Accept-Language", "id-ID,id;q=0.9")
+	rec := httptest.NewRecorder()
+
+	err := ErrorBuilder(ErrorConstant.Validation, util.ValidationErrors{
+		{Field: "email", Tag: "required", Message: "email is required"},
+	}).Send(e.NewContext(req, rec))
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "id", rec.Header().Get(HeaderContentLanguage))
+		assert.JSONEq(t, `{
+			"meta": {"success": false, "message": "Parameter atau payload tidak valid", "info": null},
+			"error": "bad_request",
+			"errors": [{"field": "email", "tag": "required", "message": "email wajib diisi"}]
+		}`, rec.Body.String())
+	}
+}
+
+func TestSendLocalizedSuccess(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/students/1", nil)
+	req.Header.Set("Accept-Language", "id")
+	rec := httptest.NewRecorder()
+
+	if assert.NoError(t, SuccessResponse(nil).Send(e.NewContext(req, rec))) {
+		assert.Contains(t, rec.Body.String(), `"message":"Permintaan berhasil diproses"`)
+	}
+}
@@ -0,0 +1,60 @@
+package response
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	HeaderETag        = "ETag"
+	HeaderIfMatch     = "If-Match"
+	HeaderIfNoneMatch = "If-None-Match"
+)
+
+// ETag returns the strong entity tag of a record version, e.g. `"3"`. A representation that
+// embeds other records also has their versions, e.g. `"3.2.5"`, so that it changes when they
+// change too and a client does not keep an embedded record it revalidates with If-None-Match.
+func ETag(version uint, embedded ...uint) string {
+	versions := []string{strconv.FormatUint(uint64(version), 10)}
+	for _, v := range embedded {
+		versions = append(versions, strconv.FormatUint(uint64(v), 10))
+	}
+	return strconv.Quote(strings.Join(versions, "."))
+}
+
+// IfMatch reports whether the If-Match header value is satisfied by a record at version.
+// An empty header and "*" always match, weak tags never do (RFC 7232 strong comparison). Only
+// the version of the record itself is compared, the one before the versions of the records it
+// embeds: the precondition guards the record, which is not changed by changes to those.
+func IfMatch(header string, version uint) bool {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return true
+	}
+	etag := ETag(version)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if i := strings.Index(tag, "."); i > 0 && strings.HasPrefix(tag, `"`) {
+			tag = tag[:i] + `"`
+		}
+		if tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// IfNoneMatch reports whether the If-None-Match header value has the entity tag, so that the
+// client's copy is still current. "*" always matches, tags are compared weakly.
+func IfNoneMatch(header string, etag string) bool {
+	if strings.TrimSpace(header) == "" {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package response
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETag(t *testing.T) {
+	assert.Equal(t, `"1"`, ETag(1))
+	assert.Equal(t, `"42"`, ETag(42))
+	assert.Equal(t, `"3.0.5"`, ETag(3, 0, 5))
+}
+
+func TestIfMatch(t *testing.T) {
+	cases := []struct {
+		header string
+		want   bool
+	}{
+		{"", true},
+		{"*", true},
+		{`"3"`, true},
+		{`"1", "3"`, true},
+		{`"2"`, false},
+		{`W/"3"`, false},
+		{`3`, false},
+		{`"3.2.5"`, true},
+		{`"2.3.5"`, false},
+		{`W/"3.2.5"`, false},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, IfMatch(tc.header, 3), tc.header)
+	}
+}
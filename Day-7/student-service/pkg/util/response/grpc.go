@@ -0,0 +1,69 @@
+package response
+
+import (
+	"fmt"
+
+	"student-service/pkg/util"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/text/language"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcCodes maps the error codes of the kinds to gRPC status codes. Calls without a valid token are
+// rejected as Unauthenticated before reaching a service, so unauthorized means PermissionDenied.
+var grpcCodes = map[string]codes.Code{
+	E_DUPLICATE:              codes.AlreadyExists,
+	E_FAILED_DEPENDENCY:      codes.Aborted,
+	E_CONFLICT:               codes.Aborted,
+	E_NOT_FOUND:              codes.NotFound,
+	E_PRECONDITION_FAILED:    codes.FailedPrecondition,
+	E_UNPROCESSABLE_ENTITY:   codes.FailedPrecondition,
+	E_UNSUPPORTED_MEDIA_TYPE: codes.InvalidArgument,
+	E_PAYLOAD_TOO_LARGE:      codes.ResourceExhausted,
+	E_FORBIDDEN:              codes.PermissionDenied,
+	E_UNAUTHORIZED:           codes.PermissionDenied,
+	E_BAD_REQUEST:            codes.InvalidArgument,
+	E_SERVER_ERROR:           codes.Internal,
+}
+
+// GRPCCode returns the gRPC status code of the kind.
+func (k *Kind) GRPCCode() codes.Code {
+	if code, ok := grpcCodes[k.code]; ok {
+		return code
+	}
+	return codes.Unknown
+}
+
+// GRPCStatus returns the status sent to gRPC clients for err, an Error is reported with its
+// localized message and validation errors as BadRequest details, anything else as an internal
+// error. The cause is logged but never sent.
+func GRPCStatus(err error, lang language.Tag) *status.Status {
+	if s, ok := status.FromError(err); ok {
+		return s
+	}
+	e := ErrorResponse(err)
+	if e.Err != nil {
+		logrus.Error(fmt.Sprintf("%+v", errors.WithStack(e.Err)))
+	}
+
+	s := status.New(e.Kind.GRPCCode(), e.Kind.LocalizedMessage(lang))
+	var validationErrors util.ValidationErrors
+	if !errors.As(e.Err, &validationErrors) {
+		return s
+	}
+	details := &errdetails.BadRequest{}
+	for _, fieldError := range validationErrors.Localize(lang) {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       fieldError.Field,
+			Description: fieldError.Message,
+		})
+	}
+	if withDetails, err := s.WithDetails(details); err == nil {
+		return withDetails
+	}
+	return s
+}
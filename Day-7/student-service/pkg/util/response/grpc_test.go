@@ -0,0 +1,41 @@
+package response
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestKindGRPCCode(t *testing.T) {
+	cases := map[*Kind]codes.Code{
+		ErrorConstant.NotFound:                 codes.NotFound,
+		ErrorConstant.Duplicate:                codes.AlreadyExists,
+		ErrorConstant.PreconditionFailed:       codes.FailedPrecondition,
+		ErrorConstant.Validation:               codes.InvalidArgument,
+		ErrorConstant.Unauthorized:             codes.PermissionDenied,
+		ErrorConstant.CourseFull:               codes.Aborted,
+		NewKind("teapot", 418, "I'm a teapot"): codes.Unknown,
+	}
+	for kind, want := range cases {
+		assert.Equal(t, want, kind.GRPCCode(), kind.Code())
+	}
+}
+
+func TestGRPCStatus(t *testing.T) {
+	asserts := assert.New(t)
+
+	s := GRPCStatus(ErrorBuilder(ErrorConstant.NotFound, errors.New("record not found")), language.Indonesian)
+	asserts.Equal(codes.NotFound, s.Code())
+	asserts.Equal("Data tidak ditemukan", s.Message())
+
+	s = GRPCStatus(errors.New("connection refused"), language.English)
+	asserts.Equal(codes.Internal, s.Code())
+	asserts.Equal("Something bad happened", s.Message())
+
+	s = GRPCStatus(status.Error(codes.Unauthenticated, "no token"), language.English)
+	asserts.Equal(codes.Unauthenticated, s.Code())
+}
@@ -0,0 +1,138 @@
+package response
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"student-service/pkg/util"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"golang.org/x/text/language"
+)
+
+const (
+	MIMEApplicationProblemJSON = "application/problem+json"
+	HeaderContentLanguage      = "Content-Language"
+)
+
+// problemDetails is the RFC 7807 representation of an Error.
+type problemDetails struct {
+	Type      string                `json:"type"`
+	Title     string                `json:"title"`
+	Status    int                   `json:"status"`
+	Detail    string                `json:"detail,omitempty"`
+	Instance  string                `json:"instance,omitempty"`
+	Errors    util.ValidationErrors `json:"errors,omitempty"`
+	RequestID string                `json:"request_id,omitempty"`
+}
+
+// ProblemType returns the problem type URI of the kind, prefixed with PROBLEM_TYPE_BASE_URI,
+// e.g. "/problems/not-found". It is read on every call, the .env file is loaded after the
+// package is initialized.
+func (k *Kind) ProblemType() string {
+	if k.problemType == "" {
+		return "about:blank"
+	}
+	return util.Getenv("PROBLEM_TYPE_BASE_URI", "/problems/") + k.problemType
+}
+
+// Title returns the summary of the kind, the same for every occurrence and every language. Kinds
+// without a problem type are titled with the HTTP status phrase, as "about:blank" requires.
+func (k *Kind) Title() string {
+	if k.problemType == "" {
+		return http.StatusText(k.status)
+	}
+	return k.Message()
+}
+
+// WantsProblem reports whether the client asked for application/problem+json and does not
+// prefer application/json over it.
+func WantsProblem(r *http.Request) bool {
+	var problemQ, jsonQ float64
+	for _, accept := range strings.Split(r.Header.Get(echo.HeaderAccept), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		switch mediaType {
+		case MIMEApplicationProblemJSON:
+			problemQ = q
+		case echo.MIMEApplicationJSON:
+			jsonQ = q
+		}
+	}
+	return problemQ > 0 && problemQ >= jsonQ
+}
+
+func (e *Error) problem(c echo.Context, lang language.Tag) problemDetails {
+	problem := problemDetails{
+		Type:      e.Kind.ProblemType(),
+		Title:     e.Kind.Title(),
+		Status:    e.Kind.status,
+		Detail:    e.Kind.LocalizedMessage(lang),
+		Instance:  c.Request().URL.Path,
+		RequestID: requestID(c),
+	}
+	var validationErrors util.ValidationErrors
+	if errors.As(e.Err, &validationErrors) {
+		problem.Errors = validationErrors.Localize(lang)
+		problem.Detail = problem.Errors.Error()
+	}
+	return problem
+}
+
+func (e *Error) sendProblem(c echo.Context, lang language.Tag) error {
+	c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+	c.Response().WriteHeader(e.Kind.status)
+	return c.Echo().JSONSerializer.Serialize(c, e.problem(c, lang), "")
+}
+
+func requestID(c echo.Context) string {
+	if id := c.Response().Header().Get(echo.HeaderXRequestID); id != "" {
+		return id
+	}
+	return c.Request().Header.Get(echo.HeaderXRequestID)
+}
+
+// HTTPErrorHandler renders errors returned outside of the handlers (unknown routes, the JWT
+// middleware, ...) as problem details when the client asked for them, and falls back to the
+// echo default otherwise.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+	if !WantsProblem(c.Request()) {
+		c.Echo().DefaultHTTPErrorHandler(err, c)
+		return
+	}
+
+	kind := ErrorConstant.InternalServerError
+	var httpError *echo.HTTPError
+	if errors.As(err, &httpError) {
+		switch httpError.Code {
+		case http.StatusNotFound:
+			kind = ErrorConstant.RouteNotFound
+		case http.StatusUnauthorized:
+			kind = ErrorConstant.Unauthorized
+		case http.StatusBadRequest:
+			kind = ErrorConstant.BadRequest
+		case http.StatusInternalServerError:
+			// keep ErrorConstant.InternalServerError
+		default:
+			kind = NewKind(E_BAD_REQUEST, httpError.Code, http.StatusText(httpError.Code))
+		}
+	}
+
+	if err := ErrorBuilder(kind, err).Send(c); err != nil {
+		c.Logger().Error(err)
+	}
+}
@@ -0,0 +1,134 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"student-service/pkg/util"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWantsProblem(t *testing.T) {
+	cases := map[string]bool{
+		"":                         false,
+		"*/*":                      false,
+		"application/json":         false,
+		"application/problem+json": true,
+		"application/json, application/problem+json":           true,
+		"application/problem+json;q=0.5, application/json":     false,
+		"application/problem+json, application/json;q=0.9":     true,
+		"application/problem+json;q=0":                         false,
+		"text/html, application/problem+json;q=0.8, */*;q=0.1": true,
+	}
+	for accept, expected := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderAccept, accept)
+		assert.Equal(t, expected, WantsProblem(req), accept)
+	}
+}
+
+func TestProblemTypeBaseURI(t *testing.T) {
+	asserts := assert.New(t)
+	asserts.Equal("/problems/not-found", ErrorConstant.NotFound.ProblemType())
+	// set after the package is initialized, like the .env file
+	t.Setenv("PROBLEM_TYPE_BASE_URI", "https://example.com/problems/")
+	asserts.Equal("https://example.com/problems/not-found", ErrorConstant.NotFound.ProblemType())
+	asserts.Equal("about:blank", NewKind(E_BAD_REQUEST, http.StatusTeapot, "Teapot").ProblemType())
+}
+
+func TestSendProblemDetails(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/signup", nil)
+	req.Header.Set(echo.HeaderAccept, MIMEApplicationProblemJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Response().Header().Set(echo.HeaderXRequestID, "req-1")
+
+	err := ErrorBuilder(ErrorConstant.Validation, util.ValidationErrors{
+		{Field: "email", Tag: "required", Message: "email is required"},
+	}).Send(c)
+
+	asserts := assert.New(t)
+	if asserts.NoError(err) {
+		asserts.Equal(http.StatusBadRequest, rec.Code)
+		asserts.Equal(MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+		asserts.JSONEq(`{
+			"type": "/problems/validation",
+			"title": "Invalid parameters or payload",
+			"status": 400,
+			"detail": "email is required",
+			"instance": "/api/v1/auth/signup",
+			"errors": [{"field": "email", "tag": "required", "message": "email is required"}],
+			"request_id": "req-1"
+		}`, rec.Body.String())
+	}
+}
+
+func TestSendLocalizedProblemDetails(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/students/1", nil)
+	req.Header.Set(echo.HeaderAccept, MIMEApplicationProblemJSON)
+	req.Header.Set("Accept-Language", "id")
+	rec := httptest.NewRecorder()
+
+	// the title stays the one of the type, only the detail is translated
+	if assert.NoError(t, ErrorBuilder(ErrorConstant.NotFound, nil).Send(e.NewContext(req, rec))) {
+		assert.JSONEq(t, `{
+			"type": "/problems/not-found",
+			"title": "Data not found",
+			"status": 404,
+			"detail": "Data tidak ditemukan",
+			"instance": "/api/v1/students/1"
+		}`, rec.Body.String())
+	}
+}
+
+func TestProblemTitle(t *testing.T) {
+	asserts := assert.New(t)
+	asserts.Equal("Data not found", ErrorConstant.NotFound.Title())
+	asserts.Equal("I'm a teapot", NewKind(E_BAD_REQUEST, http.StatusTeapot, "Teapot").Title())
+}
+
+func TestSendKeepsEnvelopeForJSONClients(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/students/1", nil)
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	if assert.NoError(t, ErrorBuilder(ErrorConstant.NotFound, nil).Send(e.NewContext(req, rec))) {
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.JSONEq(t, `{"meta": {"success": false, "message": "Data not found", "info": null}, "error": "not_found"}`, rec.Body.String())
+	}
+}
+
+func TestHTTPErrorHandlerProblemDetails(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/unknown", nil)
+	req.Header.Set(echo.HeaderAccept, MIMEApplicationProblemJSON)
+	rec := httptest.NewRecorder()
+
+	HTTPErrorHandler(echo.ErrNotFound, e.NewContext(req, rec))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{
+		"type": "/problems/route-not-found",
+		"title": "Route not found",
+		"status": 404,
+		"detail": "Route not found",
+		"instance": "/api/v1/unknown"
+	}`, rec.Body.String())
+}
+
+func TestHTTPErrorHandlerDefault(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/unknown", nil)
+	rec := httptest.NewRecorder()
+
+	HTTPErrorHandler(echo.ErrNotFound, e.NewContext(req, rec))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"message": "Not Found"}`, rec.Body.String())
+}
@@ -4,25 +4,17 @@ import (
 	"net/http"
 
 	"student-service/pkg/dto"
+	"student-service/pkg/i18n"
 
 	"github.com/labstack/echo/v4"
 )
 
 type successConstant struct {
-	OK Success
+	OK *Kind
 }
 
-var SuccessConstant successConstant = successConstant{
-	OK: Success{
-		Response: successResponse{
-			Meta: Meta{
-				Success: true,
-				Message: "Request successfully proceed",
-			},
-			Data: nil,
-		},
-		Code: http.StatusOK,
-	},
+var SuccessConstant = successConstant{
+	OK: &Kind{status: http.StatusOK, messageKey: i18n.RequestSuccess},
 }
 
 type successResponse struct {
@@ -35,11 +27,12 @@ type Success struct {
 	Code     int             `json:"code"`
 }
 
-func SuccessBuilder(res *Success, data interface{}) *Success {
-	res.Response.Data = data
-	return res
+func SuccessBuilder(kind *Kind, data interface{}) *Success {
+	return CustomSuccessBuilder(kind.status, data, kind.messageKey, nil)
 }
 
+// CustomSuccessBuilder builds a success response. message is a key of the i18n catalog,
+// a message that is not in the catalog is sent as is.
 func CustomSuccessBuilder(code int, data interface{}, message string, info *dto.PaginationInfo) *Success {
 	return &Success{
 		Response: successResponse{
@@ -55,9 +48,14 @@ func CustomSuccessBuilder(code int, data interface{}, message string, info *dto.
 }
 
 func SuccessResponse(data interface{}) *Success {
-	return SuccessBuilder(&SuccessConstant.OK, data)
+	return SuccessBuilder(SuccessConstant.OK, data)
 }
 
 func (s *Success) Send(c echo.Context) error {
-	return c.JSON(s.Code, s.Response)
+	lang := i18n.FromRequest(c.Request())
+	c.Response().Header().Set(HeaderContentLanguage, lang.String())
+
+	response := s.Response
+	response.Meta.Message = i18n.Translate(lang, response.Meta.Message)
+	return c.JSON(s.Code, response)
 }
@@ -0,0 +1,78 @@
+package util
+
+import (
+	"strings"
+
+	"student-service/pkg/i18n"
+
+	"github.com/go-playground/validator"
+	"golang.org/x/text/language"
+)
+
+// FieldError describes one field that failed validation, named as the client sent it.
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors is returned by CustomValidator.Validate when the payload is invalid.
+type ValidationErrors []FieldError
+
+func NewValidationErrors(errs validator.ValidationErrors) ValidationErrors {
+	result := make(ValidationErrors, 0, len(errs))
+	for _, err := range errs {
+		field := trimStructName(err.Namespace())
+		result = append(result, FieldError{
+			Field:   field,
+			Tag:     err.Tag(),
+			Param:   err.Param(),
+			Message: FieldErrorMessage(field, err.Tag(), err.Param()),
+		})
+	}
+	return result
+}
+
+// trimStructName removes the name of the validated struct from namespace. The name of a generic
+// struct contains the package paths of its type arguments, e.g. "BatchRequest[a/dto.Item].items".
+func trimStructName(namespace string) string {
+	depth := 0
+	for i, r := range namespace {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				return namespace[i+1:]
+			}
+		}
+	}
+	return namespace
+}
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, 0, len(v))
+	for _, err := range v {
+		messages = append(messages, err.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// FieldErrorMessage returns a human readable message for a failed validation tag,
+// in the default language.
+func FieldErrorMessage(field, tag, param string) string {
+	return i18n.ValidationMessage(i18n.Default, field, tag, param)
+}
+
+// Localize returns a copy of v with the messages in lang.
+func (v ValidationErrors) Localize(lang language.Tag) ValidationErrors {
+	result := make(ValidationErrors, len(v))
+	for i, err := range v {
+		err.Message = i18n.ValidationMessage(lang, err.Field, err.Tag, err.Param)
+		result[i] = err
+	}
+	return result
+}
@@ -1,19 +1,208 @@
 package util
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-playground/validator"
 	"github.com/labstack/echo/v4"
 )
 
+// ExistsFunc reports whether a record with the given id exists.
+type ExistsFunc func(ctx context.Context, id uint) (bool, error)
+
+// DateLayout is the layout of dates in requests and responses, e.g. 2026-08-31.
+const DateLayout = "2006-01-02"
+
+// UniqueFunc reports whether value is already used by a record other than exceptID.
+// exceptID is 0 when the validated struct has no ID (e.g. on create).
+type UniqueFunc func(ctx context.Context, value string, exceptID uint) (bool, error)
+
 type CustomValidator struct {
 	Validator *validator.Validate
+	exists    map[string]ExistsFunc
+	unique    map[string]UniqueFunc
+}
+
+type lookupErrorKey struct{}
+
+// lookupError keeps the first error returned by a lookup function during one Validate call,
+// so a failing database is not reported as an invalid field.
+type lookupError struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (l *lookupError) set(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err == nil {
+		l.err = err
+	}
+}
+
+// NewCustomValidator returns a validator with the `exists`, `unique` and `date` tags registered.
+// Use RegisterExists and RegisterUnique to tell it how to look the values up.
+func NewCustomValidator() *CustomValidator {
+	cv := &CustomValidator{
+		Validator: validator.New(),
+		exists:    map[string]ExistsFunc{},
+		unique:    map[string]UniqueFunc{},
+	}
+	cv.Validator.RegisterTagNameFunc(fieldName)
+	cv.Validator.RegisterValidationCtx("exists", cv.validateExists)
+	cv.Validator.RegisterValidationCtx("unique", cv.validateUnique)
+	cv.Validator.RegisterValidation("date", validateDate)
+	return cv
+}
+
+// RegisterExists registers the lookup used by `exists=<table>`.
+func (cv *CustomValidator) RegisterExists(table string, fn ExistsFunc) {
+	cv.exists[table] = fn
 }
 
+// RegisterUnique registers the lookup used by `unique=<table>.<column>`.
+func (cv *CustomValidator) RegisterUnique(column string, fn UniqueFunc) {
+	cv.unique[column] = fn
+}
+
+// Validate validates i without a context, the handlers validate with the context of the
+// request, see ValidateCtx.
 func (cv *CustomValidator) Validate(i interface{}) error {
-	if err := cv.Validator.Struct(i); err != nil {
+	return cv.ValidateCtx(context.Background(), i)
+}
+
+// ValidateCtx validates i, ctx is passed to the `exists` and `unique` lookups.
+func (cv *CustomValidator) ValidateCtx(ctx context.Context, i interface{}) error {
+	lookup := new(lookupError)
+	ctx = context.WithValue(ctx, lookupErrorKey{}, lookup)
+
+	err := cv.Validator.StructCtx(ctx, i)
+	if lookup.err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, lookup.err.Error()).SetInternal(lookup.err)
+	}
+	if err != nil {
+		var fieldErrors validator.ValidationErrors
+		if errors.As(err, &fieldErrors) {
+			return NewValidationErrors(fieldErrors)
+		}
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// fieldName names struct fields the way clients send them: the json, param or query tag,
+// falling back to the Go field name.
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "param", "query", "form"} {
+		name := strings.SplitN(field.Tag.Get(key), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+func (cv *CustomValidator) validateExists(ctx context.Context, fl validator.FieldLevel) bool {
+	fn, ok := cv.exists[fl.Param()]
+	if !ok {
+		return reportLookupError(ctx, fmt.Errorf("no lookup registered for exists=%s", fl.Param()))
+	}
+
+	var id uint
+	switch field := fl.Field(); field.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		id = uint(field.Uint())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if field.Int() <= 0 {
+			return false
+		}
+		id = uint(field.Int())
+	default:
+		return reportLookupError(ctx, fmt.Errorf("exists=%s cannot be used on %s", fl.Param(), field.Kind()))
+	}
+
+	isExist, err := fn(ctx, id)
+	if err != nil {
+		return reportLookupError(ctx, err)
+	}
+	return isExist
+}
+
+func (cv *CustomValidator) validateUnique(ctx context.Context, fl validator.FieldLevel) bool {
+	fn, ok := cv.unique[fl.Param()]
+	if !ok {
+		return reportLookupError(ctx, fmt.Errorf("no lookup registered for unique=%s", fl.Param()))
+	}
+
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return reportLookupError(ctx, fmt.Errorf("unique=%s cannot be used on %s", fl.Param(), field.Kind()))
+	}
+
+	isExist, err := fn(ctx, field.String(), parentID(fl.Parent()))
+	if err != nil {
+		return reportLookupError(ctx, err)
+	}
+	return !isExist
+}
+
+// validateDate reports whether the string is a date in DateLayout.
+func validateDate(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return false
+	}
+	_, err := time.Parse(DateLayout, field.String())
+	return err == nil
+}
+
+// ParseDate parses a date validated with the `date` tag as midnight in the time zone of the
+// database connection.
+func ParseDate(value string) (time.Time, error) {
+	return time.ParseInLocation(DateLayout, value, time.Local)
+}
+
+// parentID returns the value of the `ID` field of the struct being validated, or 0.
+func parentID(parent reflect.Value) uint {
+	for parent.Kind() == reflect.Ptr {
+		if parent.IsNil() {
+			return 0
+		}
+		parent = parent.Elem()
+	}
+	if parent.Kind() != reflect.Struct {
+		return 0
+	}
+
+	id := parent.FieldByName("ID")
+	for id.Kind() == reflect.Ptr {
+		if id.IsNil() {
+			return 0
+		}
+		id = id.Elem()
+	}
+	switch id.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uint(id.Uint())
+	}
+	return 0
+}
+
+// reportLookupError records err for the running Validate call. The field is reported as valid,
+// Validate returns the lookup error instead of a validation error.
+func reportLookupError(ctx context.Context, err error) bool {
+	if lookup, ok := ctx.Value(lookupErrorKey{}).(*lookupError); ok {
+		lookup.set(err)
+	}
+	return true
+}
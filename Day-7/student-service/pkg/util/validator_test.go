@@ -0,0 +1,154 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testValidatorPayload struct {
+	ID      *uint   `param:"id" validate:"omitempty"`
+	MajorID *uint   `json:"major_id" validate:"omitempty,exists=majors"`
+	Email   *string `json:"email" validate:"omitempty,unique=students.email"`
+}
+
+func newTestValidator(majorErr error) *CustomValidator {
+	cv := NewCustomValidator()
+	cv.RegisterExists("majors", func(ctx context.Context, id uint) (bool, error) {
+		return id == 1, majorErr
+	})
+	cv.RegisterUnique("students.email", func(ctx context.Context, value string, exceptID uint) (bool, error) {
+		return value == "taken@edu.ac.id" && exceptID != 1, nil
+	})
+	return cv
+}
+
+func TestCustomValidatorExists(t *testing.T) {
+	var (
+		asserts   = assert.New(t)
+		cv        = newTestValidator(nil)
+		existID   = uint(1)
+		unknownID = uint(2)
+	)
+
+	asserts.NoError(cv.Validate(&testValidatorPayload{MajorID: &existID}))
+	asserts.NoError(cv.Validate(&testValidatorPayload{}))
+
+	err := cv.Validate(&testValidatorPayload{MajorID: &unknownID})
+	var fieldErrors ValidationErrors
+	if asserts.ErrorAs(err, &fieldErrors) && asserts.Len(fieldErrors, 1) {
+		asserts.Equal("major_id", fieldErrors[0].Field)
+		asserts.Equal("exists", fieldErrors[0].Tag)
+		asserts.Equal("majors", fieldErrors[0].Param)
+		asserts.Equal("major_id does not refer to an existing record in majors", fieldErrors[0].Message)
+	}
+}
+
+func TestCustomValidatorUniqueExceptOwnID(t *testing.T) {
+	var (
+		asserts = assert.New(t)
+		cv      = newTestValidator(nil)
+		email   = "taken@edu.ac.id"
+		ownerID = uint(1)
+		otherID = uint(2)
+	)
+
+	asserts.NoError(cv.Validate(&testValidatorPayload{ID: &ownerID, Email: &email}))
+
+	err := cv.Validate(&testValidatorPayload{ID: &otherID, Email: &email})
+	var fieldErrors ValidationErrors
+	if asserts.ErrorAs(err, &fieldErrors) && asserts.Len(fieldErrors, 1) {
+		asserts.Equal("email", fieldErrors[0].Field)
+		asserts.Equal("unique", fieldErrors[0].Tag)
+		asserts.Equal("email is already taken", fieldErrors[0].Message)
+	}
+}
+
+func TestCustomValidatorLookupError(t *testing.T) {
+	var (
+		asserts = assert.New(t)
+		cv      = newTestValidator(errors.New("connection refused"))
+		majorID = uint(2)
+	)
+
+	err := cv.Validate(&testValidatorPayload{MajorID: &majorID})
+	var httpErr *echo.HTTPError
+	if asserts.ErrorAs(err, &httpErr) {
+		asserts.Equal(http.StatusInternalServerError, httpErr.Code)
+	}
+}
+
+func TestCustomValidatorUnregisteredLookup(t *testing.T) {
+	type payload struct {
+		ClassID uint `validate:"exists=classes"`
+	}
+
+	err := NewCustomValidator().Validate(&payload{ClassID: 1})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "no lookup registered for exists=classes")
+	}
+}
+
+func TestCustomValidatorDate(t *testing.T) {
+	type payload struct {
+		StartDate *string `json:"start_date" validate:"omitempty,date"`
+	}
+	var (
+		asserts = assert.New(t)
+		cv      = NewCustomValidator()
+		valid   = "2026-08-31"
+		invalid = "2026-02-30"
+	)
+
+	asserts.NoError(cv.Validate(&payload{StartDate: &valid}))
+	asserts.NoError(cv.Validate(&payload{}))
+
+	err := cv.Validate(&payload{StartDate: &invalid})
+	var fieldErrors ValidationErrors
+	if asserts.ErrorAs(err, &fieldErrors) && asserts.Len(fieldErrors, 1) {
+		asserts.Equal("start_date must be a date formatted as YYYY-MM-DD", fieldErrors[0].Message)
+	}
+}
+
+func TestCustomValidatorFieldNames(t *testing.T) {
+	type nested struct {
+		Name string `json:"name" validate:"required"`
+	}
+	type payload struct {
+		ID       uint   `param:"id" validate:"required"`
+		Page     int    `query:"page" validate:"min=1"`
+		Fullname string `json:"fullname,omitempty" validate:"required"`
+		Major    nested `json:"major"`
+	}
+
+	err := NewCustomValidator().Validate(&payload{})
+	var fieldErrors ValidationErrors
+	if assert.ErrorAs(t, err, &fieldErrors) {
+		var fields []string
+		for _, fieldError := range fieldErrors {
+			fields = append(fields, fieldError.Field)
+		}
+		assert.Equal(t, []string{"id", "page", "fullname", "major.name"}, fields)
+		assert.Equal(t, "page must be at least 1", fieldErrors[1].Message)
+	}
+}
+
+type testGenericPayload[T any] struct {
+	Items []T `json:"items" validate:"required,dive"`
+}
+
+func TestCustomValidatorGenericStructFieldNames(t *testing.T) {
+	type item struct {
+		Name string `json:"name" validate:"required"`
+	}
+
+	err := NewCustomValidator().Validate(&testGenericPayload[item]{Items: []item{{Name: "A"}, {}}})
+	var fieldErrors ValidationErrors
+	if assert.ErrorAs(t, err, &fieldErrors) && assert.Len(t, fieldErrors, 1) {
+		assert.Equal(t, "items[1].name", fieldErrors[0].Field)
+	}
+}
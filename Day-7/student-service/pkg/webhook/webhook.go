@@ -0,0 +1,127 @@
+// Package webhook sends signed webhook requests and verifies their signatures.
+//
+// A request is a POST of a JSON body with the headers:
+//
+//	X-Webhook-Event: student.registered
+//	X-Webhook-Delivery: 42
+//	X-Webhook-Signature: t=1700000000,v1=<hex HMAC-SHA256 of "<t>.<body>" with the secret>
+//
+// Receivers verify the signature with Verify, and should reject old timestamps to prevent
+// replays.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+var (
+	// ErrSignature is returned when a signature header is malformed or does not match the body.
+	ErrSignature = errors.New("webhook: invalid signature")
+	// ErrTimestamp is returned when a signature is older than the tolerance.
+	ErrTimestamp = errors.New("webhook: signature timestamp out of tolerance")
+)
+
+// Sign returns the signature header of body sent at timestamp.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + signature(secret, t, body)
+}
+
+// Verify checks the signature header of body, it must be signed at most tolerance before or after
+// now.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var t, v1 string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			v1 = value
+		}
+	}
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || v1 == "" {
+		return ErrSignature
+	}
+	if !hmac.Equal([]byte(v1), []byte(signature(secret, t, body))) {
+		return ErrSignature
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrTimestamp
+	}
+	return nil
+}
+
+func signature(secret, t string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Request is a webhook request to send.
+type Request struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID uint
+	Body       []byte
+}
+
+// Sender sends webhook requests.
+type Sender struct {
+	Client *http.Client
+	now    func() time.Time
+}
+
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{Client: &http.Client{Timeout: timeout}, now: time.Now}
+}
+
+// maxErrorBody is how much of the body of a failed response is kept in its error.
+const maxErrorBody = 512
+
+// Send posts the request and returns the status code of the response. The request failed when
+// the error is not nil, which is also the case for a status outside of 2xx.
+func (s *Sender) Send(ctx context.Context, request Request) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, request.URL, bytes.NewReader(request.Body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "student-service-webhooks")
+	req.Header.Set(HeaderEvent, request.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(request.DeliveryID), 10))
+	req.Header.Set(HeaderSignature, Sign(request.Secret, s.now(), request.Body))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return resp.StatusCode, fmt.Errorf("webhook: response status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
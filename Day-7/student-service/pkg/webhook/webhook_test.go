@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	asserts := assert.New(t)
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"event":"student.deleted"}`)
+
+	header := Sign("secret", now, body)
+	asserts.Regexp(`^t=1700000000,v1=[0-9a-f]{64}$`, header)
+	asserts.NoError(Verify("secret", header, body, time.Minute, now.Add(30*time.Second)))
+
+	asserts.ErrorIs(Verify("other", header, body, time.Minute, now), ErrSignature)
+	asserts.ErrorIs(Verify("secret", header, []byte(`{}`), time.Minute, now), ErrSignature)
+	asserts.ErrorIs(Verify("secret", "v1=abc", body, time.Minute, now), ErrSignature)
+	asserts.ErrorIs(Verify("secret", header, body, time.Minute, now.Add(2*time.Minute)), ErrTimestamp)
+}
+
+func TestSenderSend(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		if r.URL.Path == "/fail" {
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	asserts := assert.New(t)
+	sender := NewSender(time.Second)
+	request := Request{URL: server.URL + "/ok", Secret: "secret", Event: "class.created", DeliveryID: 7, Body: []byte(`{"id":1}`)}
+
+	status, err := sender.Send(context.Background(), request)
+	if asserts.NoError(err) {
+		asserts.Equal(http.StatusOK, status)
+		asserts.Equal("class.created", received.Header.Get(HeaderEvent))
+		asserts.Equal("7", received.Header.Get(HeaderDelivery))
+		asserts.Equal(`{"id":1}`, string(body))
+		asserts.NoError(Verify("secret", received.Header.Get(HeaderSignature), body, time.Minute, time.Now()))
+	}
+
+	request.URL = server.URL + "/fail"
+	status, err = sender.Send(context.Background(), request)
+	asserts.Equal(http.StatusServiceUnavailable, status)
+	asserts.EqualError(err, "webhook: response status 503: try later")
+}